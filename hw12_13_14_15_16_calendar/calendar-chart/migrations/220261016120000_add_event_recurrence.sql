@@ -0,0 +1,18 @@
+-- +goose Up
+ALTER TABLE events
+    ADD COLUMN IF NOT EXISTS rrule TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS exdates BIGINT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS recurrence_end BIGINT,
+    ADD COLUMN IF NOT EXISTS recurring_event_id UUID REFERENCES events(id) ON DELETE CASCADE,
+    ADD COLUMN IF NOT EXISTS recurrence_id BIGINT;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_events_recurrence ON events(recurring_event_id, recurrence_id)
+    WHERE recurring_event_id IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_events_recurrence;
+ALTER TABLE events
+    DROP COLUMN IF EXISTS recurrence_id,
+    DROP COLUMN IF EXISTS recurring_event_id,
+    DROP COLUMN IF EXISTS recurrence_end,
+    DROP COLUMN IF EXISTS exdates,
+    DROP COLUMN IF EXISTS rrule;
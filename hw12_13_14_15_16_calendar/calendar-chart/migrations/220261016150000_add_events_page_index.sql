@@ -0,0 +1,7 @@
+-- +goose Up
+-- Индекс для постраничной выборки разовых событий пользователя по ключу (start_time, id) (ListEventsPage)
+CREATE INDEX IF NOT EXISTS idx_events_user_start_id ON events(user_id, start_time, id)
+    WHERE rrule = '' AND recurring_event_id IS NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_events_user_start_id;
@@ -0,0 +1,15 @@
+-- +goose Up
+-- Напоминание определяется событием, временем начала экземпляра и смещением уведомления;
+-- запись создаётся планировщиком (status = 'scheduled') и обновляется рассыльщиком (status = 'processed')
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS notify_before BIGINT NOT NULL DEFAULT 0;
+
+-- Повторные записи, созданные до появления ключа, не нужны
+DELETE FROM notifications a
+USING notifications b
+WHERE a.event_id = b.event_id AND a.event_time = b.event_time AND a.ctid > b.ctid;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_notifications_reminder ON notifications(event_id, event_time, notify_before);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_notifications_reminder;
+ALTER TABLE notifications DROP COLUMN IF EXISTS notify_before;
@@ -0,0 +1,27 @@
+-- +goose Up
+-- Участники события и их ответы на приглашение; изменённые экземпляры серии хранят копию участников серии
+CREATE TABLE IF NOT EXISTS event_attendees (
+    event_id UUID NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    user_id TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'needs-action'
+        CHECK (status IN ('needs-action', 'accepted', 'declined', 'tentative')),
+    PRIMARY KEY (event_id, user_id)
+);
+CREATE INDEX IF NOT EXISTS idx_event_attendees_user ON event_attendees(user_id, event_id);
+
+-- Напоминание отправляется владельцу и каждому участнику, принявшему приглашение
+DROP INDEX IF EXISTS idx_notifications_reminder;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_notifications_reminder
+    ON notifications(event_id, event_time, notify_before, channel, user_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_notifications_reminder;
+DELETE FROM notifications a
+USING notifications b
+WHERE a.event_id = b.event_id AND a.event_time = b.event_time AND a.notify_before = b.notify_before
+  AND a.channel = b.channel AND a.ctid > b.ctid;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_notifications_reminder
+    ON notifications(event_id, event_time, notify_before, channel);
+
+DROP INDEX IF EXISTS idx_event_attendees_user;
+DROP TABLE IF EXISTS event_attendees;
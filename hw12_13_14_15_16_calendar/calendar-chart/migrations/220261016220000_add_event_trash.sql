@@ -0,0 +1,40 @@
+-- +goose Up
+-- Корзина: удалённое событие хранится со временем удаления deleted_at, пока его не восстановят
+-- или не удалят навсегда. События в корзине не участвуют в проверке пересечения по времени.
+ALTER TABLE events ADD COLUMN IF NOT EXISTS deleted_at BIGINT;
+
+ALTER TABLE events DROP CONSTRAINT IF EXISTS events_no_overlap;
+ALTER TABLE events
+    ADD CONSTRAINT events_no_overlap EXCLUDE USING gist (
+        user_id WITH =,
+        int8range(start_time, end_time) WITH &&
+    ) WHERE (rrule = '' AND deleted_at IS NULL);
+
+DROP INDEX IF EXISTS idx_events_user_start_id;
+CREATE INDEX IF NOT EXISTS idx_events_user_start_id ON events(user_id, start_time, id)
+    WHERE rrule = '' AND recurring_event_id IS NULL AND deleted_at IS NULL;
+
+-- Индекс для просмотра корзины пользователя (ListTrash) и очистки корзины по сроку хранения (PurgeTrash)
+CREATE INDEX IF NOT EXISTS idx_events_trash ON events(user_id, deleted_at)
+    WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_events_deleted_at ON events(deleted_at)
+    WHERE deleted_at IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_events_deleted_at;
+DROP INDEX IF EXISTS idx_events_trash;
+
+DELETE FROM events WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_events_user_start_id;
+CREATE INDEX IF NOT EXISTS idx_events_user_start_id ON events(user_id, start_time, id)
+    WHERE rrule = '' AND recurring_event_id IS NULL;
+
+ALTER TABLE events DROP CONSTRAINT IF EXISTS events_no_overlap;
+ALTER TABLE events
+    ADD CONSTRAINT events_no_overlap EXCLUDE USING gist (
+        user_id WITH =,
+        int8range(start_time, end_time) WITH &&
+    ) WHERE (rrule = '');
+
+ALTER TABLE events DROP COLUMN IF EXISTS deleted_at;
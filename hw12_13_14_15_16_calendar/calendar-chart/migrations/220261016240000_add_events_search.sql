@@ -0,0 +1,20 @@
+-- +goose Up
+-- Полнотекстовый поиск по заголовку (вес A) и описанию (вес B) событий (SearchEvents).
+-- Векторы строятся в русской и английской конфигурациях, чтобы слова обоих языков приводились к основе.
+ALTER TABLE events ADD COLUMN IF NOT EXISTS search_ru tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('russian'::regconfig, title), 'A') ||
+    setweight(to_tsvector('russian'::regconfig, coalesce(description, '')), 'B')
+) STORED;
+ALTER TABLE events ADD COLUMN IF NOT EXISTS search_en tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('english'::regconfig, title), 'A') ||
+    setweight(to_tsvector('english'::regconfig, coalesce(description, '')), 'B')
+) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_events_search_ru ON events USING gin(search_ru) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_events_search_en ON events USING gin(search_en) WHERE deleted_at IS NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_events_search_en;
+DROP INDEX IF EXISTS idx_events_search_ru;
+ALTER TABLE events DROP COLUMN IF EXISTS search_en;
+ALTER TABLE events DROP COLUMN IF EXISTS search_ru;
@@ -0,0 +1,6 @@
+-- +goose Up
+-- Индекс для выборки записей о напоминаниях старше срока хранения (ListExpiredNotifications)
+CREATE INDEX IF NOT EXISTS idx_notifications_event_time ON notifications(event_time, id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_notifications_event_time;
@@ -0,0 +1,6 @@
+-- +goose Up
+-- Часовой пояс IANA, в котором разворачивается серия повторяющегося события; пусто — UTC
+ALTER TABLE events ADD COLUMN IF NOT EXISTS time_zone TEXT NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE events DROP COLUMN IF EXISTS time_zone;
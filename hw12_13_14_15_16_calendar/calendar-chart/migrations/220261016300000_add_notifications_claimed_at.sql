@@ -0,0 +1,7 @@
+-- +goose Up
+-- Время захвата напоминания рассыльщиком (status = 'processing'): после истечения срока захвата
+-- напоминание, которое рассыльщик не успел отправить, может захватить другой
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS claimed_at BIGINT;
+
+-- +goose Down
+ALTER TABLE notifications DROP COLUMN IF EXISTS claimed_at;
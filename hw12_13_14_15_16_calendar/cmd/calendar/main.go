@@ -14,6 +14,7 @@ import (
 	"database/sql"
 
 	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/app"
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/auth"
 	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/config"
 	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/logger"
 	internalhttp "github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/server/http"
@@ -85,11 +86,29 @@ func main() {
 		panic("unknown storage type: " + configData.Storage.Type)
 	}
 
+	// Настройки календарных периодов: часовые пояса и первый день недели
+	calendarOpts, err := calendarOptions(configData.Calendar)
+	if err != nil {
+		panic("invalid calendar config: " + err.Error())
+	}
+
 	// Инициализация бизнес-логики приложения с логгером и хранилищем
-	calendar := app.New(logg, storage)
+	calendar := app.New(logg, storage, calendarOpts...)
+
+	// Аутентификация REST API и CalDAV: JWT пользователей и API-ключи сервисов
+	authenticator, err := auth.FromConfig(configData.Auth)
+	if err != nil {
+		panic("invalid auth config: " + err.Error())
+	}
+	if authenticator == nil {
+		logg.Warn("authentication is disabled: every caller may act on behalf of any user")
+	}
 
-	// Создание и настройка HTTP-сервера
-	server := internalhttp.NewServer(logg, calendar, configData.Server.Host, configData.Server.Port)
+	// Создание и настройка HTTP-сервера с REST API
+	server, err := internalhttp.NewServer(logg, calendar, configData.Server.Host, configData.Server.Port, authenticator)
+	if err != nil {
+		panic("failed to create http server: " + err.Error())
+	}
 
 	// Настройка graceful shutdown через обработку системных сигналов
 	ctx, cancel := signal.NotifyContext(context.Background(),
@@ -142,3 +161,37 @@ func runMigrations(db *sql.DB) error {
 	}
 	return nil
 }
+
+// calendarOptions преобразует секцию calendar конфига в настройки App.
+func calendarOptions(conf config.CalendarConf) ([]app.Option, error) {
+	var opts []app.Option
+	if conf.TimeZone != "" {
+		loc, err := time.LoadLocation(conf.TimeZone)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, app.WithTimeZone(loc))
+	}
+	if conf.WeekStart != "" {
+		weekStart, err := app.ParseWeekday(conf.WeekStart)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, app.WithWeekStart(weekStart))
+	}
+	if len(conf.UserTimeZones) > 0 {
+		zones := make(map[string]*time.Location, len(conf.UserTimeZones))
+		for userID, zone := range conf.UserTimeZones {
+			loc, err := time.LoadLocation(zone)
+			if err != nil {
+				return nil, fmt.Errorf("user %s: %w", userID, err)
+			}
+			zones[userID] = loc
+		}
+		opts = append(opts, app.WithUserTimeZones(zones))
+	}
+	if conf.WatchIntervalSeconds > 0 {
+		opts = append(opts, app.WithWatchInterval(time.Duration(conf.WatchIntervalSeconds)*time.Second))
+	}
+	return opts, nil
+}
@@ -0,0 +1,138 @@
+// Package main содержит утилиту для работы с очередью недоставленных напоминаний.
+// Утилита читает параметры RabbitMQ из конфигурации рассыльщика и умеет показать недоставленные
+// сообщения (list) или вернуть их в очередь напоминаний для повторной обработки (replay).
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/config"
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/queue"
+)
+
+var (
+	configFile string
+	limit      int
+)
+
+func init() {
+	flag.StringVar(&configFile, "config", "configs/sender_config.yaml", "Path to configuration file")
+	flag.IntVar(&limit, "limit", 100, "Maximum number of dead letters to list or replay")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] list|replay\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+}
+
+// deadLetterQueue — операции с очередью недоставленных сообщений.
+type deadLetterQueue interface {
+	DeadLetters(ctx context.Context, queueName string, limit int) ([]queue.DeadLetter, error)
+	ReplayDeadLetters(ctx context.Context, queueName string, limit int) (int, error)
+}
+
+func main() {
+	flag.Parse()
+	if flag.NArg() != 1 || limit <= 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	cfg, err := config.NewConfigFromFile(configFile)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to load config: "+err.Error())
+		os.Exit(1)
+	}
+
+	rabbitURL := queue.BuildURL(
+		cfg.RabbitMQ.Host,
+		cfg.RabbitMQ.Port,
+		cfg.RabbitMQ.User,
+		cfg.RabbitMQ.Password,
+		cfg.RabbitMQ.VHost,
+	)
+	retryPolicy := queue.NewRetryPolicy(cfg.RabbitMQ.Retry.MaxRetries, cfg.RabbitMQ.Retry.DelaysSeconds)
+	queueConn, err := queue.NewConnection(rabbitURL, queue.WithRetryPolicy(retryPolicy))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to connect to RabbitMQ: "+err.Error())
+		os.Exit(1)
+	}
+	defer func() { _ = queueConn.Close() }()
+
+	queueName := cfg.RabbitMQ.Queue
+	if queueName == "" {
+		queueName = "notifications"
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	// Очередь недоставленных может ещё не существовать, если рассыльщик не запускался
+	if err := queueConn.DeclareQueue(ctx, queueName); err != nil {
+		fmt.Fprintln(os.Stderr, "failed to declare queue: "+err.Error())
+		os.Exit(1)
+	}
+
+	if err := run(ctx, queueConn, queueName, flag.Arg(0), limit, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+}
+
+// run выполняет команду command над очередью недоставленных сообщений очереди queueName и пишет результат в w.
+func run(ctx context.Context, dlq deadLetterQueue, queueName, command string, limit int, w io.Writer) error {
+	switch command {
+	case "list":
+		letters, err := dlq.DeadLetters(ctx, queueName, limit)
+		if err != nil {
+			return fmt.Errorf("failed to list dead letters: %w", err)
+		}
+		return printDeadLetters(w, letters)
+	case "replay":
+		replayed, err := dlq.ReplayDeadLetters(ctx, queueName, limit)
+		_, _ = fmt.Fprintf(w, "replayed %d dead letters to %s\n", replayed, queueName)
+		if err != nil {
+			return fmt.Errorf("failed to replay dead letters: %w", err)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown command %q, expected list or replay", command)
+	}
+}
+
+// printDeadLetters выводит недоставленные сообщения таблицей.
+func printDeadLetters(w io.Writer, letters []queue.DeadLetter) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "DEAD AT\tRETRIES\tEVENT\tUSER\tCHANNEL\tEVENT TIME\tERROR")
+	for _, l := range letters {
+		deadAt, eventTime := "-", "-"
+		if !l.DeadAt.IsZero() {
+			deadAt = l.DeadAt.UTC().Format(time.RFC3339)
+		}
+		if l.Notification.EventTime != 0 {
+			eventTime = time.Unix(l.Notification.EventTime, 0).UTC().Format(time.RFC3339)
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%d\t%s\t%s\t%s\t%s\t%s\n", deadAt, l.Retries,
+			orDash(l.Notification.EventID), orDash(l.Notification.UserID), orDash(l.Notification.Channel), eventTime, l.Error)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "%d dead letters\n", len(letters))
+	return err
+}
+
+// orDash возвращает s или "-", если s пусто.
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/queue"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDeadLetterQueue хранит недоставленные сообщения в памяти; replay переносит их в replayed.
+type fakeDeadLetterQueue struct {
+	letters  []queue.DeadLetter
+	replayed []queue.DeadLetter
+	fail     bool
+}
+
+func (q *fakeDeadLetterQueue) DeadLetters(_ context.Context, _ string, limit int) ([]queue.DeadLetter, error) {
+	return q.letters[:min(limit, len(q.letters))], nil
+}
+
+func (q *fakeDeadLetterQueue) ReplayDeadLetters(_ context.Context, _ string, limit int) (int, error) {
+	if q.fail {
+		return 0, errors.New("broker is unavailable")
+	}
+	n := min(limit, len(q.letters))
+	q.replayed = append(q.replayed, q.letters[:n]...)
+	q.letters = q.letters[n:]
+	return n, nil
+}
+
+func TestRun(t *testing.T) {
+	ctx := context.Background()
+	dlq := &fakeDeadLetterQueue{letters: []queue.DeadLetter{
+		{
+			Notification: queue.Notification{EventID: "event1", UserID: "user1", Channel: "webhook", EventTime: 1760616000},
+			Retries:      5,
+			Error:        "webhook responded with 503 Service Unavailable",
+			DeadAt:       time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC),
+		},
+		{Body: []byte("not json"), Error: "malformed message: invalid character 'o' in literal null (expecting 'u')"},
+	}}
+
+	var out bytes.Buffer
+	require.NoError(t, run(ctx, dlq, "notifications", "list", 10, &out))
+	require.Contains(t, out.String(), "2026-10-16T12:00:00Z  5        event1")
+	require.Contains(t, out.String(), "webhook responded with 503")
+	require.Contains(t, out.String(), "malformed message")
+	require.Contains(t, out.String(), "2 dead letters")
+	require.Len(t, dlq.letters, 2, "list must not remove dead letters")
+
+	out.Reset()
+	require.NoError(t, run(ctx, dlq, "notifications", "replay", 1, &out))
+	require.Equal(t, "replayed 1 dead letters to notifications\n", out.String())
+	require.Len(t, dlq.letters, 1)
+	require.Equal(t, "event1", dlq.replayed[0].Notification.EventID)
+
+	dlq.fail = true
+	require.Error(t, run(ctx, dlq, "notifications", "replay", 10, &out))
+	require.Error(t, run(ctx, dlq, "notifications", "purge", 10, &out))
+}
@@ -15,6 +15,7 @@ import (
 
 	pb "github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/api/gen"
 	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/app"
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/auth"
 	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/config"
 	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/logger"
 	grpcserver "github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/server/grpc"
@@ -69,7 +70,17 @@ func main() {
 		grpcPort = ":50051"
 	}
 
-	grpcSrv := grpc.NewServer()
+	authenticator, err := auth.FromConfig(configData.Auth)
+	if err != nil {
+		panic("invalid auth config: " + err.Error())
+	}
+	if authenticator == nil {
+		logg.Warn("authentication is disabled: every caller may act on behalf of any user")
+	}
+	grpcSrv := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(grpcserver.UnaryAuthInterceptor(authenticator)),
+		grpc.ChainStreamInterceptor(grpcserver.StreamAuthInterceptor(authenticator)),
+	)
 	pb.RegisterEventServiceServer(grpcSrv, grpcserver.NewServer(calendar))
 
 	lis, err := net.Listen("tcp", grpcPort)
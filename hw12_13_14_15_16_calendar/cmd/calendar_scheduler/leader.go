@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/app"
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/config"
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/leader"
+)
+
+// leaseName — имя аренды лидерства планировщика.
+const leaseName = "calendar_scheduler"
+
+// newElector создаёт участника выбора лидера с идентификатором реплики «хост-PID».
+func newElector(store leader.LeaseStore, logg app.Logger, cfg config.LeaderConf) *leader.Elector {
+	ttl := time.Duration(cfg.LeaseSeconds) * time.Second
+	if ttl == 0 {
+		ttl = 15 * time.Second // по умолчанию 15 секунд
+	}
+	host, err := os.Hostname()
+	if err != nil {
+		host = "scheduler"
+	}
+	id := fmt.Sprintf("%s-%d", host, os.Getpid())
+	return leader.New(store, logg, leaseName, id, ttl, time.Duration(cfg.RenewSeconds)*time.Second)
+}
+
+// healthStatus — ответ проверки состояния планировщика.
+type healthStatus struct {
+	Status      string `json:"status"`
+	Leader      bool   `json:"leader"`
+	ReplicaID   string `json:"replicaId"`
+	Lease       string `json:"lease"`
+	LeaderSince string `json:"leaderSince,omitempty"` // RFC3339
+}
+
+// healthHandler возвращает обработчик проверок состояния планировщика:
+// /health отвечает 200, пока процесс работает, и описывает состояние реплики в выборе лидера;
+// /leader отвечает 200, если реплика — лидер, и 503 в противном случае.
+func healthHandler(elector *leader.Elector) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		writeHealth(w, http.StatusOK, elector.Status())
+	})
+	mux.HandleFunc("/leader", func(w http.ResponseWriter, r *http.Request) {
+		status := elector.Status()
+		code := http.StatusOK
+		if !status.Leader {
+			code = http.StatusServiceUnavailable
+		}
+		writeHealth(w, code, status)
+	})
+	return mux
+}
+
+// writeHealth записывает состояние реплики в ответ с кодом code.
+func writeHealth(w http.ResponseWriter, code int, status leader.Status) {
+	resp := healthStatus{Status: "ok", Leader: status.Leader, ReplicaID: status.ID, Lease: status.Name}
+	if status.Leader {
+		resp.LeaderSince = status.Since.UTC().Format(time.RFC3339)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// serveHealth обслуживает проверки состояния на addr до отмены ctx.
+func serveHealth(ctx context.Context, logg app.Logger, addr string, elector *leader.Elector) {
+	srv := &http.Server{Addr: addr, Handler: healthHandler(elector), ReadHeaderTimeout: 5 * time.Second}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logg.Error(fmt.Sprintf("health server failed: %v", err))
+	}
+}
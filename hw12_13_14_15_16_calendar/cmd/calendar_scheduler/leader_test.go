@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/config"
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/leader"
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/logger"
+	memorystorage "github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/storage/memory"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthHandler(t *testing.T) {
+	store := memorystorage.New()
+	logg := logger.New("ERROR")
+	ctx := context.Background()
+	leaderReplica := newElector(store, logg, config.LeaderConf{})
+	require.True(t, leaderReplica.Campaign(ctx))
+
+	get := func(h http.Handler, path string) (int, healthStatus) {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		var status healthStatus
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &status))
+		return rec.Code, status
+	}
+
+	code, status := get(healthHandler(leaderReplica), "/health")
+	require.Equal(t, http.StatusOK, code)
+	require.True(t, status.Leader)
+	require.Equal(t, leaseName, status.Lease)
+	since, err := time.Parse(time.RFC3339, status.LeaderSince)
+	require.NoError(t, err)
+	require.WithinDuration(t, time.Now(), since, time.Minute)
+	code, _ = get(healthHandler(leaderReplica), "/leader")
+	require.Equal(t, http.StatusOK, code)
+
+	// Не лидер работает, но на /leader отвечает 503
+	other := leader.New(store, logg, leaseName, "other", time.Minute, 0)
+	require.False(t, other.Campaign(ctx))
+	code, status = get(healthHandler(other), "/health")
+	require.Equal(t, http.StatusOK, code)
+	require.False(t, status.Leader)
+	require.Empty(t, status.LeaderSince)
+	code, _ = get(healthHandler(other), "/leader")
+	require.Equal(t, http.StatusServiceUnavailable, code)
+}
@@ -1,6 +1,7 @@
 // Package main содержит точку входа для процесса планировщика календаря.
 // Планировщик периодически сканирует базу данных, выбирает события для уведомления
-// и отправляет их в очередь RabbitMQ, а также очищает старые события.
+// и отправляет их в очередь RabbitMQ, а также по отдельному расписанию удаляет и архивирует старые данные.
+// Из нескольких реплик работу выполняет только лидер, держащий аренду в базе данных.
 package main
 
 import (
@@ -16,6 +17,7 @@ import (
 	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/config"
 	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/logger"
 	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/queue"
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/storage"
 	sqlstorage "github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/storage/sql"
 	"github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq"
@@ -60,7 +62,7 @@ func main() {
 	}
 
 	// Создание хранилища
-	storage := sqlstorage.NewWithDB(sqlx.NewDb(db, "postgres"))
+	eventStorage := sqlstorage.NewWithDB(sqlx.NewDb(db, "postgres"))
 
 	// Подключение к RabbitMQ
 	rabbitURL := queue.BuildURL(
@@ -71,7 +73,8 @@ func main() {
 		cfg.RabbitMQ.VHost,
 	)
 
-	queueConn, err := queue.NewConnection(rabbitURL)
+	retryPolicy := queue.NewRetryPolicy(cfg.RabbitMQ.Retry.MaxRetries, cfg.RabbitMQ.Retry.DelaysSeconds)
+	queueConn, err := queue.NewConnection(rabbitURL, queue.WithRetryPolicy(retryPolicy))
 	if err != nil {
 		panic("failed to connect to RabbitMQ: " + err.Error())
 	}
@@ -96,7 +99,7 @@ func main() {
 	defer func() { _ = publisher.Close() }()
 
 	// Инициализация приложения
-	calendarApp := app.New(logg, storage)
+	calendarApp := app.New(logg, eventStorage)
 
 	// Настройка интервала проверки
 	interval := time.Duration(cfg.Scheduler.IntervalSeconds) * time.Second
@@ -104,72 +107,142 @@ func main() {
 		interval = 60 * time.Second // по умолчанию 60 секунд
 	}
 
-	logg.Info(fmt.Sprintf("scheduler started with interval %v", interval))
+	// Настройка публикации outbox
+	relayInterval := time.Duration(cfg.Scheduler.RelayIntervalSeconds) * time.Second
+	if relayInterval == 0 {
+		relayInterval = 5 * time.Second // по умолчанию 5 секунд
+	}
+	batchSize := cfg.Scheduler.RelayBatchSize
+	if batchSize == 0 {
+		batchSize = 100
+	}
+
+	// Настройка сроков хранения и архивирования
+	retention, err := newRetentionPolicy(cfg.Scheduler)
+	if err != nil {
+		panic("failed to configure retention: " + err.Error())
+	}
+
+	// Выбор лидера среди реплик
+	elector := newElector(eventStorage, logg, cfg.Scheduler.Leader)
+
+	logg.Info(fmt.Sprintf("scheduler %s started with interval %v, outbox relay interval %v, retention: %v",
+		elector.Status().ID, interval, relayInterval, retention))
 
 	// Настройка graceful shutdown
 	ctx, cancel := signal.NotifyContext(context.Background(),
 		syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
 	defer cancel()
 
+	// Проверки состояния
+	if cfg.Scheduler.HealthAddr != "" {
+		go serveHealth(ctx, logg, cfg.Scheduler.HealthAddr, elector)
+	}
+
 	// Запуск периодической проверки
 	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
+	relayTicker := time.NewTicker(relayInterval)
+	defer relayTicker.Stop()
+	cleanupTicker := time.NewTicker(retention.interval)
+	defer cleanupTicker.Stop()
+
+	// Аренда продлевается отдельно от работы, чтобы долгая очистка не лишила реплику лидерства
+	elector.Campaign(ctx)
+	electorDone := make(chan struct{})
+	go func() {
+		elector.Run(ctx)
+		close(electorDone)
+	}()
 
-	// Первый запуск сразу
-	processNotifications(ctx, logg, calendarApp, publisher, queueName)
-
-	// Периодический запуск
+	// Периодический запуск только на лидере; проверка событий, публикация outbox и очистка выполняются последовательно
 	go func() {
 		for {
 			select {
 			case <-ctx.Done():
 				return
+			case <-elector.Elected():
+				// Полный запуск сразу после получения лидерства: сообщения, оставшиеся в outbox
+				// после сбоя прежнего лидера, публикуются до новых
+				relayOutbox(ctx, logg, calendarApp, publisher, batchSize)
+				processNotifications(ctx, logg, calendarApp)
+				relayOutbox(ctx, logg, calendarApp, publisher, batchSize)
+				applyRetention(ctx, logg, calendarApp, retention)
 			case <-ticker.C:
-				processNotifications(ctx, logg, calendarApp, publisher, queueName)
+				if elector.IsLeader() {
+					processNotifications(ctx, logg, calendarApp)
+					relayOutbox(ctx, logg, calendarApp, publisher, batchSize)
+				}
+			case <-relayTicker.C:
+				if elector.IsLeader() {
+					relayOutbox(ctx, logg, calendarApp, publisher, batchSize)
+				}
+			case <-cleanupTicker.C:
+				if elector.IsLeader() {
+					applyRetention(ctx, logg, calendarApp, retention)
+				}
 			}
 		}
 	}()
 
 	<-ctx.Done()
+	// Дожидаемся освобождения аренды до закрытия подключения к базе данных
+	<-electorDone
 	logg.Info("scheduler stopped")
 }
 
-// processNotifications обрабатывает уведомления: выбирает события и отправляет в очередь.
-func processNotifications(ctx context.Context, logg app.Logger, calendarApp *app.App, publisher queue.Publisher, queueName string) {
+// processNotifications выбирает наступившие напоминания и записывает их в outbox.
+// Отметка о напоминании и сообщение outbox записываются в одной транзакции, поэтому повторные
+// запуски и сбои не приводят к потере или дублированию напоминаний в outbox.
+func processNotifications(ctx context.Context, logg app.Logger, calendarApp *app.App) {
 	currentTime := time.Now().Unix()
 
-	// Получение событий, требующих уведомления
-	events, err := calendarApp.GetEventsForNotification(ctx, currentTime)
+	enqueued, err := calendarApp.EnqueueDueReminders(ctx, currentTime)
 	if err != nil {
-		logg.Error(fmt.Sprintf("failed to get events for notification: %v", err))
-		return
+		logg.Error(fmt.Sprintf("failed to enqueue reminders: %v", err))
 	}
+	logg.Info(fmt.Sprintf("enqueued %d reminders for notification", enqueued))
+}
 
-	logg.Info(fmt.Sprintf("found %d events for notification", len(events)))
+// purgeTrash удаляет навсегда события, пролежавшие в корзине дольше retention.
+func purgeTrash(ctx context.Context, logg app.Logger, calendarApp *app.App, retention time.Duration) {
+	purged, err := calendarApp.PurgeTrash(ctx, time.Now().Add(-retention).Unix())
+	if err != nil {
+		logg.Error(fmt.Sprintf("failed to purge trash: %v", err))
+		return
+	}
+	if purged > 0 {
+		logg.Info(fmt.Sprintf("purged %d events from trash", purged))
+	}
+}
 
-	// Отправка уведомлений в очередь
-	for _, event := range events {
-		notification := queue.Notification{
-			EventID:   event.ID,
-			Title:     event.Title,
-			EventTime: event.StartTime,
-			UserID:    event.UserID,
+// relayOutbox публикует сообщения outbox в очередь и отмечает их отправленными.
+// Сообщение отмечается после подтверждения брокером, поэтому доставка — at-least-once;
+// рассыльщик обрабатывает повторы напоминания идемпотентно.
+func relayOutbox(ctx context.Context, logg app.Logger, calendarApp *app.App, publisher queue.Publisher, batchSize int) {
+	publish := func(ctx context.Context, reminder storage.Reminder) error {
+		return publisher.Publish(ctx, queue.Notification{
+			EventID:      reminder.EventID,
+			Title:        reminder.Title,
+			EventTime:    reminder.EventTime,
+			UserID:       reminder.UserID,
+			NotifyBefore: reminder.NotifyBefore,
+			Channel:      reminder.Channel,
+		})
+	}
+	for {
+		sent, err := calendarApp.RelayOutbox(ctx, batchSize, publish)
+		if sent > 0 {
+			logg.Info(fmt.Sprintf("published %d notifications from outbox", sent))
 		}
-
-		if err := publisher.Publish(ctx, notification); err != nil {
-			logg.Error(fmt.Sprintf("failed to publish notification for event %s: %v", event.ID, err))
-			continue
+		if err != nil {
+			logg.Error(fmt.Sprintf("failed to publish notifications from outbox: %v", err))
+			return
+		}
+		// Неполная пачка означает, что outbox опустел
+		if sent < batchSize || ctx.Err() != nil {
+			return
 		}
-
-		logg.Info(fmt.Sprintf("notification sent for event %s (user: %s, time: %d)", event.ID, event.UserID, event.StartTime))
-	}
-
-	// Очистка старых событий (более 1 года назад)
-	oneYearAgo := currentTime - 365*24*60*60
-	if err := calendarApp.DeleteOldEvents(ctx, oneYearAgo); err != nil {
-		logg.Error(fmt.Sprintf("failed to delete old events: %v", err))
-	} else {
-		logg.Info("old events cleanup completed")
 	}
 }
 
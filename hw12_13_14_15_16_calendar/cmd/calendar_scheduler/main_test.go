@@ -0,0 +1,240 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/app"
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/auth"
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/logger"
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/queue"
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/storage"
+	memorystorage "github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/storage/memory"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePublisher запоминает опубликованные уведомления; пока fail установлен, публикация завершается ошибкой.
+type fakePublisher struct {
+	published []queue.Notification
+	fail      bool
+}
+
+func (p *fakePublisher) Publish(_ context.Context, notification queue.Notification) error {
+	if p.fail {
+		return errors.New("broker is unavailable")
+	}
+	p.published = append(p.published, notification)
+	return nil
+}
+
+func (p *fakePublisher) Close() error {
+	return nil
+}
+
+// testContext возвращает контекст внутреннего вызова: события в тестах создаются от имени сервиса.
+func testContext() context.Context {
+	return auth.WithIdentity(context.Background(), auth.Identity{Service: "test"})
+}
+
+// tick выполняет один запуск планировщика: запись напоминаний в outbox и его публикацию.
+func tick(ctx context.Context, calendarApp *app.App, publisher queue.Publisher) {
+	processNotifications(ctx, calendarApp.Logger(), calendarApp)
+	relayOutbox(ctx, calendarApp.Logger(), calendarApp, publisher, 100)
+}
+
+func newTestApp(t *testing.T, events ...storage.Event) *app.App {
+	t.Helper()
+	calendarApp := app.New(logger.New("ERROR"), memorystorage.New())
+	for _, e := range events {
+		_, err := calendarApp.CreateEvent(testContext(), e)
+		require.NoError(t, err)
+	}
+	return calendarApp
+}
+
+func TestProcessNotificationsPublishesOnce(t *testing.T) {
+	now := time.Now().Unix()
+	reminders := []storage.EventReminder{{Offset: 3600}}
+	eventID := uuid.NewString()
+	calendarApp := newTestApp(t, storage.Event{
+		ID:        eventID,
+		Title:     "Meeting",
+		UserID:    "user1",
+		StartTime: now + 600,
+		EndTime:   now + 1200,
+		Reminders: reminders,
+	})
+	publisher := &fakePublisher{}
+	ctx := testContext()
+
+	// Два последовательных запуска внутри окна уведомления
+	tick(ctx, calendarApp, publisher)
+	tick(ctx, calendarApp, publisher)
+
+	require.Len(t, publisher.published, 1)
+	require.Equal(t, eventID, publisher.published[0].EventID)
+	require.Equal(t, now+600, publisher.published[0].EventTime)
+	require.Equal(t, int64(3600), publisher.published[0].NotifyBefore)
+}
+
+func TestProcessNotificationsMultipleReminders(t *testing.T) {
+	now := time.Now().Unix()
+	eventID := uuid.NewString()
+	calendarApp := newTestApp(t, storage.Event{
+		ID:        eventID,
+		Title:     "Meeting",
+		UserID:    "user1",
+		StartTime: now + 1200,
+		EndTime:   now + 1800,
+		Reminders: []storage.EventReminder{
+			{Offset: 600}, // ещё не наступило
+			{Offset: 24 * 3600, Channel: "email"},
+			{Offset: 1800},
+		},
+	})
+	publisher := &fakePublisher{}
+	ctx := testContext()
+
+	// Каждое наступившее напоминание публикуется отдельно и один раз
+	tick(ctx, calendarApp, publisher)
+	tick(ctx, calendarApp, publisher)
+
+	require.Len(t, publisher.published, 2)
+	require.Equal(t, int64(24*3600), publisher.published[0].NotifyBefore)
+	require.Equal(t, "email", publisher.published[0].Channel)
+	require.Equal(t, int64(1800), publisher.published[1].NotifyBefore)
+	require.Empty(t, publisher.published[1].Channel)
+	for _, n := range publisher.published {
+		require.Equal(t, eventID, n.EventID)
+		require.Equal(t, now+1200, n.EventTime)
+	}
+}
+
+func TestProcessNotificationsRecurringEventReminders(t *testing.T) {
+	now := time.Now().Unix()
+	calendarApp := newTestApp(t, storage.Event{
+		ID:        uuid.NewString(),
+		Title:     "Standup",
+		UserID:    "user1",
+		StartTime: now + 600,
+		EndTime:   now + 1200,
+		RRule:     "FREQ=DAILY;COUNT=5",
+		Reminders: []storage.EventReminder{{Offset: 24 * 3600}, {Offset: 900}},
+	})
+	publisher := &fakePublisher{}
+	tick(testContext(), calendarApp, publisher)
+
+	// Для ближайшего экземпляра наступили оба напоминания, для следующего — ещё ни одного
+	require.Len(t, publisher.published, 2)
+	for _, n := range publisher.published {
+		require.Equal(t, now+600, n.EventTime)
+	}
+}
+
+func TestProcessNotificationsAcceptedAttendees(t *testing.T) {
+	now := time.Now().Unix()
+	eventID := uuid.NewString()
+	calendarApp := newTestApp(t, storage.Event{
+		ID:        eventID,
+		Title:     "Meeting",
+		UserID:    "owner",
+		StartTime: now + 600,
+		EndTime:   now + 1200,
+		Reminders: []storage.EventReminder{{Offset: 3600}},
+		Attendees: []storage.Attendee{{UserID: "accepted"}, {UserID: "declined"}, {UserID: "silent"}},
+	})
+	ctx := testContext()
+	_, err := calendarApp.RespondToInvitation(ctx, eventID, "accepted", storage.AttendeeAccepted)
+	require.NoError(t, err)
+	_, err = calendarApp.RespondToInvitation(ctx, eventID, "declined", storage.AttendeeDeclined)
+	require.NoError(t, err)
+
+	publisher := &fakePublisher{}
+	tick(ctx, calendarApp, publisher)
+	tick(ctx, calendarApp, publisher)
+
+	// Напоминание получают владелец и участники, принявшие приглашение
+	recipients := make([]string, 0, len(publisher.published))
+	for _, n := range publisher.published {
+		recipients = append(recipients, n.UserID)
+	}
+	require.ElementsMatch(t, []string{"owner", "accepted"}, recipients)
+}
+
+func TestProcessNotificationsRetriesFailedPublish(t *testing.T) {
+	now := time.Now().Unix()
+	reminders := []storage.EventReminder{{Offset: 3600}}
+	calendarApp := newTestApp(t, storage.Event{
+		ID:        uuid.NewString(),
+		Title:     "Meeting",
+		UserID:    "user1",
+		StartTime: now + 600,
+		EndTime:   now + 1200,
+		Reminders: reminders,
+	})
+	publisher := &fakePublisher{fail: true}
+	ctx := testContext()
+
+	tick(ctx, calendarApp, publisher)
+	require.Empty(t, publisher.published)
+
+	// Напоминание, которое не удалось опубликовать, отправляется при следующем запуске
+	publisher.fail = false
+	tick(ctx, calendarApp, publisher)
+	tick(ctx, calendarApp, publisher)
+	require.Len(t, publisher.published, 1)
+}
+
+func TestProcessNotificationsRescheduledEvent(t *testing.T) {
+	now := time.Now().Unix()
+	reminders := []storage.EventReminder{{Offset: 3600}}
+	event := storage.Event{
+		ID:        uuid.NewString(),
+		Title:     "Meeting",
+		UserID:    "user1",
+		StartTime: now + 600,
+		EndTime:   now + 1200,
+		Reminders: reminders,
+	}
+	calendarApp := newTestApp(t, event)
+	publisher := &fakePublisher{}
+	ctx := testContext()
+
+	tick(ctx, calendarApp, publisher)
+
+	// После переноса события напоминание о новом времени отправляется ещё раз
+	event.StartTime, event.EndTime = now+1800, now+2400
+	_, err := calendarApp.UpdateEvent(ctx, event)
+	require.NoError(t, err)
+	tick(ctx, calendarApp, publisher)
+	tick(ctx, calendarApp, publisher)
+
+	require.Len(t, publisher.published, 2)
+	require.Equal(t, now+1800, publisher.published[1].EventTime)
+}
+
+func TestRelayOutboxAfterCrash(t *testing.T) {
+	now := time.Now().Unix()
+	reminders := []storage.EventReminder{{Offset: 3600}}
+	calendarApp := newTestApp(t,
+		storage.Event{ID: uuid.NewString(), Title: "First", UserID: "user1",
+			StartTime: now + 600, EndTime: now + 1200, Reminders: reminders},
+		storage.Event{ID: uuid.NewString(), Title: "Second", UserID: "user1",
+			StartTime: now + 1800, EndTime: now + 2400, Reminders: reminders},
+	)
+	ctx := testContext()
+
+	// Планировщик записал напоминания в outbox и остановился до публикации
+	processNotifications(ctx, calendarApp.Logger(), calendarApp)
+
+	// После перезапуска сообщения публикуются из outbox пачками и не ставятся повторно
+	publisher := &fakePublisher{}
+	relayOutbox(ctx, calendarApp.Logger(), calendarApp, publisher, 1)
+	tick(ctx, calendarApp, publisher)
+	require.Len(t, publisher.published, 2)
+	require.Equal(t, "First", publisher.published[0].Title)
+	require.Equal(t, "Second", publisher.published[1].Title)
+}
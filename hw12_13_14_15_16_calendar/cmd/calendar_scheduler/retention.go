@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/app"
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/archive"
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/config"
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/storage"
+)
+
+// retentionPolicy — параметры очистки старых данных планировщиком (см. config.RetentionConf).
+type retentionPolicy struct {
+	interval      time.Duration   // интервал очистки
+	events        time.Duration   // срок хранения прошедших событий; 0 — бессрочно
+	notifications time.Duration   // срок хранения записей о напоминаниях; 0 — бессрочно
+	trash         time.Duration   // срок хранения событий в корзине
+	batchSize     int             // число строк, архивируемых и удаляемых за раз
+	dryRun        bool            // только подсчитывать, что было бы удалено
+	archive       *archive.Writer // nil — без архивирования
+}
+
+// newRetentionPolicy заполняет параметры очистки из конфигурации планировщика значениями по умолчанию
+// и создаёт каталог архивов.
+func newRetentionPolicy(cfg config.SchedulerConf) (retentionPolicy, error) {
+	policy := retentionPolicy{
+		interval:      time.Duration(cfg.Retention.IntervalSeconds) * time.Second,
+		events:        retentionDays(cfg.Retention.EventsDays, 365),
+		notifications: retentionDays(cfg.Retention.NotificationsDays, 365),
+		trash:         retentionDays(cfg.TrashRetentionDays, 30),
+		batchSize:     cfg.Retention.BatchSize,
+		dryRun:        cfg.Retention.DryRun,
+	}
+	if policy.interval == 0 {
+		policy.interval = time.Hour // по умолчанию раз в час
+	}
+	if policy.batchSize == 0 {
+		policy.batchSize = 1000
+	}
+	if policy.trash == 0 {
+		policy.trash = 30 * 24 * time.Hour // корзина не хранится бессрочно
+	}
+	if cfg.Retention.ArchiveDir != "" {
+		w, err := archive.New(cfg.Retention.ArchiveDir)
+		if err != nil {
+			return retentionPolicy{}, err
+		}
+		policy.archive = w
+	}
+	return policy, nil
+}
+
+// retentionDays переводит срок хранения в днях в длительность: 0 — defaultDays, отрицательное значение — 0 (бессрочно).
+func retentionDays(days, defaultDays int) time.Duration {
+	switch {
+	case days < 0:
+		return 0
+	case days == 0:
+		days = defaultDays
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// String описывает параметры очистки для лога запуска.
+func (p retentionPolicy) String() string {
+	keep := func(d time.Duration) string {
+		if d == 0 {
+			return "forever"
+		}
+		return d.String()
+	}
+	return fmt.Sprintf("interval %v, events %s, notifications %s, trash %v, archive %t, dry run %t",
+		p.interval, keep(p.events), keep(p.notifications), p.trash, p.archive != nil, p.dryRun)
+}
+
+// applyRetention удаляет события и записи о напоминаниях старше срока хранения, предварительно записав их
+// в архив, а также старые изменения ленты и события, пролежавшие в корзине дольше срока. В режиме dry run
+// только логирует, сколько строк было бы удалено; корзина при этом не очищается.
+func applyRetention(ctx context.Context, logg app.Logger, calendarApp *app.App, policy retentionPolicy) {
+	now := time.Now()
+	if policy.events > 0 {
+		before := now.Add(-policy.events).Unix()
+		n, err := calendarApp.ExpireEvents(ctx, before, policy.batchSize, policy.dryRun,
+			archiveTo[storage.Event](logg, policy.archive, "events"))
+		logRetention(logg, "events", n, err, policy.dryRun)
+		if err == nil && !policy.dryRun {
+			if err := calendarApp.DeleteOldChanges(ctx, before); err != nil {
+				logg.Error(fmt.Sprintf("failed to delete old changes: %v", err))
+			}
+		}
+	}
+	if policy.notifications > 0 {
+		n, err := calendarApp.ExpireNotifications(ctx, now.Add(-policy.notifications).Unix(), policy.batchSize,
+			policy.dryRun, archiveTo[storage.NotificationRecord](logg, policy.archive, "notifications"))
+		logRetention(logg, "notifications", n, err, policy.dryRun)
+	}
+	if !policy.dryRun {
+		purgeTrash(ctx, logg, calendarApp, policy.trash)
+	}
+}
+
+// archiveTo возвращает функцию, записывающую пачку строк таблицы table в архив w; nil, если архив не задан.
+func archiveTo[T any](logg app.Logger, w *archive.Writer, table string) func([]T) error {
+	if w == nil {
+		return nil
+	}
+	return func(rows []T) error {
+		path, err := archive.Write(w, table, rows)
+		if err != nil {
+			return fmt.Errorf("archive %s: %w", table, err)
+		}
+		logg.Debug(fmt.Sprintf("archived %d %s to %s", len(rows), table, path))
+		return nil
+	}
+}
+
+// logRetention логирует результат очистки таблицы table.
+func logRetention(logg app.Logger, table string, n int, err error, dryRun bool) {
+	switch {
+	case err != nil:
+		logg.Error(fmt.Sprintf("failed to expire %s (%d processed): %v", table, n, err))
+	case dryRun:
+		logg.Info(fmt.Sprintf("retention dry run: %d %s would be deleted", n, table))
+	case n > 0:
+		logg.Info(fmt.Sprintf("deleted %d expired %s", n, table))
+	}
+}
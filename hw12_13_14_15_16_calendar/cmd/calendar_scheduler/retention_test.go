@@ -0,0 +1,109 @@
+package main
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/app"
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/config"
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/logger"
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/storage"
+	memorystorage "github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/storage/memory"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// readArchive читает строки всех файлов архива таблицы table.
+func readArchive[T any](t *testing.T, dir, table string) []T {
+	t.Helper()
+	paths, err := filepath.Glob(filepath.Join(dir, table+"-*.ndjson.gz"))
+	require.NoError(t, err)
+	var rows []T
+	for _, path := range paths {
+		f, err := os.Open(path)
+		require.NoError(t, err)
+		zr, err := gzip.NewReader(f)
+		require.NoError(t, err)
+		scanner := bufio.NewScanner(zr)
+		for scanner.Scan() {
+			var row T
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &row))
+			rows = append(rows, row)
+		}
+		require.NoError(t, f.Close())
+	}
+	return rows
+}
+
+func TestApplyRetention(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	old := now.Add(-400 * 24 * time.Hour).Unix()
+	store := memorystorage.New()
+	calendarApp := app.New(logger.New("ERROR"), store)
+	oldEvent := storage.Event{ID: uuid.NewString(), Title: "Old", UserID: "user1", StartTime: old, EndTime: old + 3600}
+	recent := storage.Event{ID: uuid.NewString(), Title: "Recent", UserID: "user1",
+		StartTime: now.Add(-24 * time.Hour).Unix(), EndTime: now.Unix()}
+	for _, e := range []storage.Event{oldEvent, recent} {
+		require.NoError(t, store.CreateEvent(ctx, e))
+	}
+	_, err := store.EnqueueReminder(ctx, storage.Reminder{EventID: oldEvent.ID, UserID: "user1", Title: "Old", EventTime: old})
+	require.NoError(t, err)
+
+	dir := filepath.Join(t.TempDir(), "archive")
+	cfg := config.SchedulerConf{Retention: config.RetentionConf{DryRun: true, ArchiveDir: dir, BatchSize: 1}}
+	policy, err := newRetentionPolicy(cfg)
+	require.NoError(t, err)
+
+	// Dry run ничего не удаляет и не архивирует
+	applyRetention(ctx, calendarApp.Logger(), calendarApp, policy)
+	_, err = store.GetEvent(ctx, oldEvent.ID)
+	require.NoError(t, err)
+	require.Empty(t, readArchive[storage.Event](t, dir, "events"))
+
+	policy.dryRun = false
+	applyRetention(ctx, calendarApp.Logger(), calendarApp, policy)
+	_, err = store.GetEvent(ctx, oldEvent.ID)
+	require.ErrorIs(t, err, storage.ErrNotFound)
+	_, err = store.GetEvent(ctx, recent.ID)
+	require.NoError(t, err)
+
+	archived := readArchive[storage.Event](t, dir, "events")
+	require.Len(t, archived, 1)
+	require.Equal(t, oldEvent.ID, archived[0].ID)
+	require.Equal(t, "Old", archived[0].Title)
+	notifications := readArchive[storage.NotificationRecord](t, dir, "notifications")
+	require.Len(t, notifications, 1)
+	require.Equal(t, oldEvent.ID, notifications[0].Reminder.EventID)
+	require.Equal(t, storage.NotificationScheduled, notifications[0].Status)
+	left, err := store.ListExpiredNotifications(ctx, now.Unix(), "", 10)
+	require.NoError(t, err)
+	require.Empty(t, left)
+}
+
+func TestNewRetentionPolicy(t *testing.T) {
+	policy, err := newRetentionPolicy(config.SchedulerConf{})
+	require.NoError(t, err)
+	require.Equal(t, time.Hour, policy.interval)
+	require.Equal(t, 365*24*time.Hour, policy.events)
+	require.Equal(t, 30*24*time.Hour, policy.trash)
+	require.Equal(t, 1000, policy.batchSize)
+	require.Nil(t, policy.archive)
+
+	// Отрицательный срок — хранить бессрочно; корзина бессрочно не хранится
+	policy, err = newRetentionPolicy(config.SchedulerConf{
+		TrashRetentionDays: -1,
+		Retention:          config.RetentionConf{EventsDays: -1, NotificationsDays: 7, IntervalSeconds: 60},
+	})
+	require.NoError(t, err)
+	require.Zero(t, policy.events)
+	require.Equal(t, 7*24*time.Hour, policy.notifications)
+	require.Equal(t, 30*24*time.Hour, policy.trash)
+	require.Equal(t, time.Minute, policy.interval)
+}
@@ -1,5 +1,6 @@
 // Package main содержит точку входа для процесса рассыльщика календаря.
-// Рассыльщик читает уведомления из очереди RabbitMQ и выводит их в STDOUT.
+// Рассыльщик читает уведомления из очереди RabbitMQ и доставляет их по каналам, заданным в конфигурации
+// (электронная почта, webhook, файл или STDOUT).
 package main
 
 import (
@@ -14,15 +15,16 @@ import (
 
 	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/config"
 	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/logger"
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/notifier"
 	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/queue"
-	"github.com/google/uuid"
+	sqlstorage "github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/storage/sql"
 	"github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq"
 	"github.com/pressly/goose/v3"
 )
 
 var (
-	configFile    string
+	configFile     string
 	migrationsPath string
 )
 
@@ -43,8 +45,13 @@ func main() {
 	// Инициализация логгера
 	logg := logger.New(cfg.Logger.Level)
 
+	// Настройка graceful shutdown
+	ctx, cancel := signal.NotifyContext(context.Background(),
+		syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+	defer cancel()
+
 	// Подключение к базе данных для сохранения статуса уведомлений
-	var db *sqlx.DB
+	var store *sqlstorage.Storage
 	if cfg.DB.Host != "" {
 		dsn := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=disable",
 			cfg.DB.Host, cfg.DB.Port, cfg.DB.User, cfg.DB.Password, cfg.DB.DBName)
@@ -55,12 +62,12 @@ func main() {
 		}
 		defer func() { _ = sqlDB.Close() }()
 
-		// Применение миграций
+		// Применение миграций: без них запросы рассыльщика к notifications не работают
 		if err := goose.Up(sqlDB, migrationsPath); err != nil {
-			logg.Warn("failed to apply migrations: " + err.Error())
+			panic("failed to apply migrations: " + err.Error())
 		}
 
-		db = sqlx.NewDb(sqlDB, "postgres")
+		store = sqlstorage.NewWithDB(sqlx.NewDb(sqlDB, "postgres"))
 	}
 
 	// Подключение к RabbitMQ
@@ -72,7 +79,8 @@ func main() {
 		cfg.RabbitMQ.VHost,
 	)
 
-	queueConn, err := queue.NewConnection(rabbitURL)
+	retryPolicy := queue.NewRetryPolicy(cfg.RabbitMQ.Retry.MaxRetries, cfg.RabbitMQ.Retry.DelaysSeconds)
+	queueConn, err := queue.NewConnection(rabbitURL, queue.WithRetryPolicy(retryPolicy))
 	if err != nil {
 		panic("failed to connect to RabbitMQ: " + err.Error())
 	}
@@ -84,7 +92,6 @@ func main() {
 		queueName = "notifications"
 	}
 
-	ctx := context.Background()
 	if err := queueConn.DeclareQueue(ctx, queueName); err != nil {
 		panic("failed to declare queue: " + err.Error())
 	}
@@ -96,44 +103,72 @@ func main() {
 	}
 	defer func() { _ = consumer.Close() }()
 
-	logg.Info("sender started, waiting for notifications...")
+	// Каналы доставки напоминаний; с базой данных напоминания отправляются на адреса, зарегистрированные
+	// пользователями, а попытки доставки учитываются в notifications
+	var notifierOpts []notifier.Option
+	if store != nil {
+		notifierOpts = append(notifierOpts, notifier.WithWebhookRegistry(store), notifier.WithAttemptRecorder(store))
+	}
+	notifiers, err := notifier.New(cfg.Sender, logg, notifierOpts...)
+	if err != nil {
+		panic("failed to configure delivery channels: " + err.Error())
+	}
+	defer func() { _ = notifiers.Close() }()
+
+	// Срок захвата напоминания на время отправки
+	claimTimeout := time.Duration(cfg.Sender.ClaimTimeoutSeconds) * time.Second
+	if claimTimeout <= 0 {
+		claimTimeout = 5 * time.Minute // по умолчанию 5 минут
+	}
 
-	// Настройка graceful shutdown
-	ctx, cancel := signal.NotifyContext(context.Background(),
-		syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
-	defer cancel()
+	logg.Info(fmt.Sprintf("sender started with channels %v, waiting for notifications...", notifiers.Channels()))
 
 	// Обработчик уведомлений
 	handler := func(notification queue.Notification) error {
 		eventTime := time.Unix(notification.EventTime, 0).Format(time.RFC3339)
-		now := time.Now().Unix()
+		reminder := notifier.Reminder(notification)
 
-		// Сохраняем статус уведомления в БД (если БД доступна)
-		if db != nil {
-			notificationID := uuid.New().String()
-			_, err := db.ExecContext(ctx,
-				`INSERT INTO notifications (id, event_id, user_id, title, event_time, status, created_at, processed_at) 
-				 VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
-				notificationID, notification.EventID, notification.UserID, notification.Title,
-				notification.EventTime, "processed", now, now)
+		// Напоминание захватывается до отправки: повторно доставленное из очереди напоминание, которое уже
+		// отправлено, не отправляется снова, а напоминание, которое отправляет другой рассыльщик,
+		// обрабатывается повторно после паузы
+		if store != nil {
+			claimed, err := store.ClaimNotification(ctx, reminder, claimTimeout)
 			if err != nil {
+				logg.Warn(fmt.Sprintf("failed to claim notification: event_id=%s, user_id=%s, time=%s: %v",
+					notification.EventID, notification.UserID, eventTime, err))
+				return err
+			}
+			if !claimed {
+				logg.Info(fmt.Sprintf("notification already processed: event_id=%s, user_id=%s, time=%s",
+					notification.EventID, notification.UserID, eventTime))
+				return nil
+			}
+		}
+
+		// Доставка по каналу напоминания; при ошибке захват снимается и сообщение обрабатывается повторно после паузы
+		if err := notifiers.Notify(ctx, notification); err != nil {
+			logg.Error(fmt.Sprintf("failed to deliver notification: event_id=%s, user_id=%s, channel=%s: %v",
+				notification.EventID, notification.UserID, notification.Channel, err))
+			if store != nil {
+				if err := store.ReleaseNotification(context.WithoutCancel(ctx), reminder); err != nil {
+					logg.Error(fmt.Sprintf("failed to release notification: %v", err))
+				}
+			}
+			return err
+		}
+
+		// Сохраняем статус уведомления в БД (если БД доступна)
+		if store != nil {
+			if err := store.CompleteNotification(context.WithoutCancel(ctx), reminder); err != nil {
 				logg.Error(fmt.Sprintf("failed to save notification status: %v", err))
 				// Продолжаем обработку даже если не удалось сохранить в БД
 			}
 		}
 
-		// Вывод уведомления в STDOUT (как требуется в задании)
-		fmt.Printf("[NOTIFICATION] Event: %s | Title: %s | User: %s | Time: %s\n",
-			notification.EventID,
-			notification.Title,
-			notification.UserID,
-			eventTime,
-		)
-
-		// Также логируем
-		logg.Info(fmt.Sprintf("notification processed: event_id=%s, user_id=%s, title=%s, time=%s",
+		logg.Info(fmt.Sprintf("notification processed: event_id=%s, user_id=%s, channel=%s, title=%s, time=%s",
 			notification.EventID,
 			notification.UserID,
+			notification.Channel,
 			notification.Title,
 			eventTime,
 		))
@@ -0,0 +1,70 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/auth"
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/storage"
+)
+
+// Проверки доступа опираются на вызывающую сторону из контекста (см. auth.WithIdentity).
+// Без вызывающей стороны доступ запрещён: серверы всегда кладут её в контекст (при выключенной
+// аутентификации — auth.Anonymous), а внутренние вызовы действуют от имени сервиса.
+// Сервисы действуют от имени любого пользователя.
+
+// errNoIdentity — ошибка проверки доступа для запроса без вызывающей стороны.
+var errNoIdentity = fmt.Errorf("%w: caller is not authenticated", ErrPermissionDenied)
+
+// checkUser проверяет, что вызывающая сторона действует от имени пользователя userID.
+func checkUser(ctx context.Context, userID string) error {
+	id, ok := auth.FromContext(ctx)
+	if !ok {
+		return errNoIdentity
+	}
+	if id.IsService() || id.UserID == userID {
+		return nil
+	}
+	return fmt.Errorf("%w: %s cannot act on behalf of user %s", ErrPermissionDenied, id, userID)
+}
+
+// checkSelf проверяет, что вызывающая сторона — сам пользователь userID. В отличие от checkUser,
+// сервисы (и auth.Anonymous при выключенной аутентификации) от имени пользователя не действуют.
+func checkSelf(ctx context.Context, userID string) error {
+	id, ok := auth.FromContext(ctx)
+	if !ok {
+		return errNoIdentity
+	}
+	if id.IsService() || id.UserID != userID {
+		return fmt.Errorf("%w: only user %s can do this, not %s", ErrPermissionDenied, userID, id)
+	}
+	return nil
+}
+
+// checkOwner проверяет, что вызывающая сторона — владелец события и может его изменять.
+func checkOwner(ctx context.Context, event storage.Event) error {
+	id, ok := auth.FromContext(ctx)
+	if !ok {
+		return errNoIdentity
+	}
+	if id.IsService() || id.UserID == event.UserID {
+		return nil
+	}
+	return fmt.Errorf("%w: %s is not the owner of event %s", ErrPermissionDenied, id, event.ID)
+}
+
+// checkReader проверяет, что вызывающая сторона может читать событие: владелец или приглашённый
+// участник (в том числе отклонивший приглашение — он может передумать).
+func checkReader(ctx context.Context, event storage.Event) error {
+	id, ok := auth.FromContext(ctx)
+	if !ok {
+		return errNoIdentity
+	}
+	if id.IsService() || id.UserID == event.UserID {
+		return nil
+	}
+	if _, invited := event.Attendee(id.UserID); invited {
+		return nil
+	}
+	return fmt.Errorf("%w: %s cannot read event %s", ErrPermissionDenied, id, event.ID)
+}
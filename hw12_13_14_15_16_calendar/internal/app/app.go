@@ -3,15 +3,21 @@ package app
 
 import (
 	"context"
-	"errors"
+	"fmt"
+	"slices"
+	"time"
 
 	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/storage"
 )
 
 // App — основной сервис календаря, объединяющий бизнес-логику, логгер и хранилище.
 type App struct {
-	logger  Logger  // интерфейс логгера
-	storage Storage // интерфейс хранилища событий
+	logger        Logger                    // интерфейс логгера
+	storage       Storage                   // интерфейс хранилища событий
+	location      *time.Location            // часовой пояс по умолчанию для границ периодов
+	weekStart     time.Weekday              // первый день недели
+	userLocations map[string]*time.Location // часовые пояса пользователей
+	watchInterval time.Duration             // интервал опроса ленты изменений в WatchEvents
 }
 
 // Logger — интерфейс для логирования событий приложения.
@@ -24,79 +30,268 @@ type Logger interface {
 
 // Storage — интерфейс для работы с хранилищем событий.
 type Storage interface {
-	CreateEvent(ctx context.Context, event storage.Event) error                               // Создать событие
-	UpdateEvent(ctx context.Context, event storage.Event) error                               // Обновить событие
-	DeleteEvent(ctx context.Context, id string) error                                         // Удалить событие
-	GetEvent(ctx context.Context, id string) (storage.Event, error)                           // Получить событие по ID
-	ListEvents(ctx context.Context, userID string) ([]storage.Event, error)                   // Получить все события пользователя
-	GetEventsForNotification(ctx context.Context, currentTime int64) ([]storage.Event, error) // Получить события, требующие уведомления
-	DeleteOldEvents(ctx context.Context, beforeTime int64) error                              // Удалить старые события
+	CreateEvent(ctx context.Context, event storage.Event) error                                                                      // Создать событие
+	UpdateEvent(ctx context.Context, event storage.Event) error                                                                      // Обновить событие, если его версия равна event.Version (0 — без проверки)
+	DeleteEvent(ctx context.Context, id string, version int64) error                                                                 // Удалить событие навсегда, если его версия равна version (0 — без проверки)
+	GetEvent(ctx context.Context, id string) (storage.Event, error)                                                                  // Получить событие по ID
+	TrashEvent(ctx context.Context, id string, version, deletedAt int64) error                                                       // Переместить событие и его экземпляры в корзину
+	GetTrashedEvent(ctx context.Context, id string) (storage.Event, error)                                                           // Получить событие из корзины по ID
+	ListTrash(ctx context.Context, userID string) ([]storage.Event, error)                                                           // Получить события пользователя в корзине
+	RestoreEvent(ctx context.Context, id string) error                                                                               // Вернуть событие и его экземпляры из корзины
+	PurgeEvent(ctx context.Context, id string) error                                                                                 // Удалить событие из корзины навсегда
+	PurgeTrash(ctx context.Context, beforeTime int64) (int, error)                                                                   // Удалить навсегда события, удалённые раньше beforeTime
+	ListEvents(ctx context.Context, userID string) ([]storage.Event, error)                                                          // Получить все события пользователя
+	ListEventsInRange(ctx context.Context, userID string, start, end int64) ([]storage.Event, error)                                 // Получить события пользователя, пересекающиеся с диапазоном
+	ListEventsPage(ctx context.Context, userID string, start, end int64, after storage.Cursor, limit int) (storage.EventPage, error) // Получить страницу событий пользователя за диапазон
+	GetEventsForNotification(ctx context.Context, currentTime int64) ([]storage.Event, error)                                        // Получить события, требующие уведомления
+	ListExpiredEvents(ctx context.Context, beforeTime int64, afterID string, limit int) ([]storage.Event, error)                     // Получить пачку событий, закончившихся раньше beforeTime
+	DeleteExpiredEvents(ctx context.Context, beforeTime int64, ids []string) (int, error)                                            // Удалить навсегда закончившиеся события по ID
+	ListExpiredNotifications(ctx context.Context, beforeTime int64, afterID string, limit int) ([]storage.NotificationRecord, error) // Получить пачку записей о прошедших напоминаниях
+	DeleteExpiredNotifications(ctx context.Context, beforeTime int64, ids []string) (int, error)                                     // Удалить записи о прошедших напоминаниях по ID
+	DeleteOldChanges(ctx context.Context, beforeTime int64) error                                                                    // Удалить старые изменения ленты и отправленные сообщения outbox
+	EnqueueReminder(ctx context.Context, reminder storage.Reminder) (bool, error)                                                    // Отметить напоминание и записать его в outbox (false — уже было)
+	RelayOutbox(ctx context.Context, limit int, publish func(context.Context, storage.OutboxMessage) error) (int, error)             // Опубликовать неотправленные сообщения outbox, не давая другим репликам опубликовать их же
+	SetAttendeeStatus(ctx context.Context, eventID, userID string, status storage.AttendeeStatus) error                              // Сохранить ответ участника на приглашение
+	ListAttendedEvents(ctx context.Context, userID string) ([]storage.Event, error)                                                  // Получить события, в которых участвует пользователь
+	ListChanges(ctx context.Context, userID string, after int64, limit int) ([]storage.Change, error)                                // Получить изменения событий пользователя после ревизии
+	LastChangeRevision(ctx context.Context, userID string) (int64, error)                                                            // Получить ревизию последнего изменения событий пользователя
+	ListEventAudit(ctx context.Context, eventID string, limit int) ([]storage.AuditEntry, error)                                     // Получить последние записи журнала аудита события
+	ListUserAudit(ctx context.Context, actorID string, limit int) ([]storage.AuditEntry, error)                                      // Получить последние действия пользователя из журнала аудита
+	SearchEvents(ctx context.Context, userID string, query storage.SearchQuery) ([]storage.SearchResult, error)                      // Найти события пользователя по словам заголовка и описания
+	CreateWebhook(ctx context.Context, webhook storage.Webhook) error                                                                // Зарегистрировать адрес для напоминаний
+	GetWebhook(ctx context.Context, id string) (storage.Webhook, error)                                                              // Получить зарегистрированный адрес по ID
+	ListWebhooks(ctx context.Context, userID string) ([]storage.Webhook, error)                                                      // Получить адреса пользователя
+	DeleteWebhook(ctx context.Context, id string) error                                                                              // Удалить зарегистрированный адрес
 }
 
-// ErrDateBusy — ошибка, если время уже занято другим событием.
-var ErrDateBusy = errors.New("date is busy by another event")
+// Ошибки бизнес-логики. Каждая сопоставима через errors.Is с одной из общих ошибок хранилища
+// (storage.ErrNotFound, storage.ErrDateBusy, storage.ErrValidation, storage.ErrPermissionDenied,
+// storage.ErrVersionConflict, storage.ErrAlreadyExists),
+// по которым серверы выбирают код ответа.
+var (
+	// ErrDateBusy — ошибка, если время уже занято другим событием.
+	ErrDateBusy = storage.ErrDateBusy
+	// ErrInvalidRecurrence — ошибка, если правило повторения события некорректно.
+	ErrInvalidRecurrence = storage.NewKindError(storage.ErrValidation, "invalid recurrence")
+	// ErrNoOccurrence — ошибка, если у повторяющегося события нет экземпляра с указанным временем.
+	ErrNoOccurrence = storage.NewKindError(storage.ErrNotFound, "no such occurrence of recurring event")
+	// ErrInvalidEvent — ошибка, если событие заполнено некорректно.
+	ErrInvalidEvent = storage.NewKindError(storage.ErrValidation, "invalid event")
+	// ErrNotFound — ошибка, если событие не найдено или принадлежит другому пользователю.
+	ErrNotFound = storage.ErrNotFound
+	// ErrInvalidPeriod — ошибка, если период выборки задан некорректно (например, неизвестный часовой пояс).
+	ErrInvalidPeriod = storage.NewKindError(storage.ErrValidation, "invalid period")
+	// ErrInvalidPage — ошибка, если параметры страницы заданы некорректно (например, чужой токен страницы).
+	ErrInvalidPage = storage.NewKindError(storage.ErrValidation, "invalid page request")
+	// ErrPermissionDenied — ошибка, если вызывающая сторона действует над чужими событиями.
+	ErrPermissionDenied = storage.ErrPermissionDenied
+	// ErrInvalidRevision — ошибка, если токен ревизии ленты изменений некорректен.
+	ErrInvalidRevision = storage.NewKindError(storage.ErrValidation, "invalid revision token")
+	// ErrVersionConflict — ошибка, если событие изменено после чтения (ожидаемая версия устарела).
+	ErrVersionConflict = storage.ErrVersionConflict
+	// ErrEventInTrash — ошибка, если событие с таким ID лежит в корзине: его нужно восстановить или удалить навсегда.
+	ErrEventInTrash = storage.NewKindError(storage.ErrValidation, "event is in trash")
+	// ErrEventExists — ошибка, если ID нового события уже занят событием, в том числе другого пользователя.
+	ErrEventExists = storage.NewKindError(storage.ErrAlreadyExists, "event already exists")
+	// ErrInvalidSearch — ошибка, если запрос полнотекстового поиска пуст или его конфигурация неизвестна.
+	ErrInvalidSearch = storage.NewKindError(storage.ErrValidation, "invalid search query")
+	// ErrInvalidWebhook — ошибка, если адрес для напоминаний некорректен или их у пользователя слишком много.
+	ErrInvalidWebhook = storage.NewKindError(storage.ErrValidation, "invalid webhook")
+)
+
+// ConflictError — ошибка пересечения события по времени с другими событиями пользователя
+// (см. storage.ConflictError).
+type ConflictError = storage.ConflictError
 
 // New создает новый экземпляр App.
-func New(logger Logger, storage Storage) *App {
-	return &App{logger: logger, storage: storage}
+// По умолчанию границы периодов считаются в UTC, неделя начинается с понедельника (ISO 8601),
+// лента изменений опрашивается раз в DefaultWatchInterval.
+func New(logger Logger, storage Storage, opts ...Option) *App {
+	a := &App{logger: logger, storage: storage, location: time.UTC, weekStart: time.Monday, watchInterval: DefaultWatchInterval}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
 }
 
-// CreateEvent создает новое событие в хранилище.
-func (a *App) CreateEvent(ctx context.Context, event storage.Event) error {
-	return a.storage.CreateEvent(ctx, event)
+// CreateEvent создает новое событие в хранилище и возвращает сохранённое событие с версией storage.InitialVersion.
+// Изменённые экземпляры серий создаются только через UpdateEventOccurrence.
+// Участники события получают приглашение со статусом needs-action.
+// Создание, изменение и удаление событий записываются в журнал аудита (см. GetEventHistory).
+// Если событие или экземпляры серии пересекаются по времени с другими событиями пользователя,
+// возвращается ConflictError.
+func (a *App) CreateEvent(ctx context.Context, event storage.Event) (storage.Event, error) {
+	if err := checkUser(ctx, event.UserID); err != nil {
+		return storage.Event{}, err
+	}
+	event.RecurringEventID, event.RecurrenceID = "", 0
+	if err := validateEvent(&event); err != nil {
+		return storage.Event{}, err
+	}
+	if err := prepareAttendees(&event, nil); err != nil {
+		return storage.Event{}, err
+	}
+	loc, _ := a.Location(event.UserID, "")
+	if err := prepareRecurrence(&event, loc); err != nil {
+		return storage.Event{}, err
+	}
+	if err := a.checkNotExists(ctx, event.ID); err != nil {
+		return storage.Event{}, err
+	}
+	if err := a.checkNotTrashed(ctx, event.ID); err != nil {
+		return storage.Event{}, err
+	}
+	if err := a.checkOverlaps(ctx, event, nil); err != nil {
+		return storage.Event{}, err
+	}
+	if err := a.storage.CreateEvent(withAudit(ctx, storage.AuditCreated, event.UserID, storage.Event{}, event), event); err != nil {
+		return storage.Event{}, err
+	}
+	event.Version = storage.InitialVersion
+	return event, nil
 }
 
-// UpdateEvent обновляет существующее событие.
-func (a *App) UpdateEvent(ctx context.Context, event storage.Event) error {
-	return a.storage.UpdateEvent(ctx, event)
+// UpdateEvent обновляет существующее событие и возвращает сохранённое событие с новой версией.
+// Изменять событие может только его владелец. Если event.Version не 0, событие обновляется, только
+// если не менялось с этой версии, иначе возвращается ErrVersionConflict; без версии изменение
+// основывается на текущем состоянии события.
+// Привязка изменённого экземпляра к серии и его участники (участники серии) сохраняются.
+// Ответы уже приглашённых участников сохраняются; участники повторяющегося события
+// переносятся в его изменённые экземпляры.
+func (a *App) UpdateEvent(ctx context.Context, event storage.Event) (storage.Event, error) {
+	existing, err := a.storage.GetEvent(ctx, event.ID)
+	if err != nil {
+		return storage.Event{}, err
+	}
+	if err := checkOwner(ctx, existing); err != nil {
+		return storage.Event{}, err
+	}
+	if err := checkUser(ctx, event.UserID); err != nil {
+		return storage.Event{}, err
+	}
+	if err := checkVersion(existing, event.Version); err != nil {
+		return storage.Event{}, err
+	}
+	// Хранилище сверяет версию при записи: изменение, сделанное после чтения existing, не затирается
+	event.Version = existing.Version
+	event.RecurringEventID, event.RecurrenceID = existing.RecurringEventID, existing.RecurrenceID
+	if event.RecurringEventID != "" && event.IsRecurring() {
+		return storage.Event{}, fmt.Errorf("%w: occurrence of recurring event cannot have its own rule", ErrInvalidRecurrence)
+	}
+	if err := validateEvent(&event); err != nil {
+		return storage.Event{}, err
+	}
+	if event.RecurringEventID != "" {
+		event.Attendees = existing.Attendees
+	} else if err := prepareAttendees(&event, existing.Attendees); err != nil {
+		return storage.Event{}, err
+	}
+	// Без часового пояса серия продолжает разворачиваться в прежнем поясе
+	if event.TimeZone == "" {
+		event.TimeZone = existing.TimeZone
+	}
+	loc, _ := a.Location(event.UserID, "")
+	if err := prepareRecurrence(&event, loc); err != nil {
+		return storage.Event{}, err
+	}
+	if err := a.checkOverlaps(ctx, event, nil); err != nil {
+		return storage.Event{}, err
+	}
+	if err := a.storage.UpdateEvent(withAudit(ctx, storage.AuditUpdated, existing.UserID, existing, event), event); err != nil {
+		return storage.Event{}, err
+	}
+	event.Version++
+	if !event.IsRecurring() {
+		return event, nil
+	}
+	overrides, err := a.overrides(ctx, event)
+	if err != nil {
+		return storage.Event{}, err
+	}
+	for _, o := range overrides {
+		if slices.Equal(o.Attendees, event.Attendees) {
+			continue
+		}
+		updated := o
+		updated.Attendees = event.Attendees
+		if err := a.storage.UpdateEvent(withAudit(ctx, storage.AuditUpdated, existing.UserID, o, updated), updated); err != nil {
+			return storage.Event{}, err
+		}
+	}
+	return event, nil
 }
 
-// DeleteEvent удаляет событие по ID.
-func (a *App) DeleteEvent(ctx context.Context, id string) error {
-	return a.storage.DeleteEvent(ctx, id)
+// DeleteEvent перемещает событие в корзину владельца (см. ListTrash, RestoreEvent, PurgeEvent).
+// Удалять событие может только его владелец. Если version не 0, событие удаляется, только если
+// не менялось с этой версии, иначе возвращается ErrVersionConflict. Для повторяющегося события
+// в корзину перемещаются и все его изменённые экземпляры.
+func (a *App) DeleteEvent(ctx context.Context, id string, version int64) error {
+	event, err := a.storage.GetEvent(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := checkOwner(ctx, event); err != nil {
+		return err
+	}
+	if err := checkVersion(event, version); err != nil {
+		return err
+	}
+	auditCtx := withAudit(ctx, storage.AuditDeleted, event.UserID, event, storage.Event{})
+	return a.storage.TrashEvent(auditCtx, id, event.Version, time.Now().Unix())
 }
 
-// GetEvent возвращает событие по ID.
-func (a *App) GetEvent(ctx context.Context, id string) (storage.Event, error) {
-	return a.storage.GetEvent(ctx, id)
+// checkVersion проверяет, что событие не менялось с ожидаемой версии; 0 — версия не проверяется.
+func checkVersion(event storage.Event, version int64) error {
+	if version != 0 && version != event.Version {
+		return storage.VersionConflictError(event.ID, event.Version)
+	}
+	return nil
 }
 
-// ListEvents возвращает все события пользователя.
-func (a *App) ListEvents(ctx context.Context, userID string) ([]storage.Event, error) {
-	return a.storage.ListEvents(ctx, userID)
+// validateEvent проверяет корректность интервала и напоминаний события и приводит напоминания к каноническому виду.
+func validateEvent(event *storage.Event) error {
+	if event.EndTime < event.StartTime {
+		return fmt.Errorf("%w: end time is before start time", ErrInvalidEvent)
+	}
+	reminders, err := normalizeReminders(event.Reminders)
+	if err != nil {
+		return err
+	}
+	event.Reminders = reminders
+	return nil
 }
 
-// ListEventsForPeriod возвращает события пользователя в заданном диапазоне времени (Unix timestamp).
-func (a *App) ListEventsForPeriod(ctx context.Context, userID string, start, end int64) ([]storage.Event, error) {
-	events, err := a.ListEvents(ctx, userID)
+// GetEvent возвращает событие по ID. Читать событие могут владелец и приглашённые участники.
+func (a *App) GetEvent(ctx context.Context, id string) (storage.Event, error) {
+	event, err := a.storage.GetEvent(ctx, id)
 	if err != nil {
-		return nil, err
+		return storage.Event{}, err
 	}
-	var filtered []storage.Event
-	for _, ev := range events {
-		if ev.StartTime >= start && ev.StartTime < end {
-			filtered = append(filtered, ev)
-		}
+	if err := checkReader(ctx, event); err != nil {
+		return storage.Event{}, err
 	}
-	return filtered, nil
+	return event, nil
 }
 
-// ListEventsForDay возвращает события пользователя за день.
-func (a *App) ListEventsForDay(ctx context.Context, userID string, dayStart int64) ([]storage.Event, error) {
-	const daySec = 24 * 60 * 60
-	return a.ListEventsForPeriod(ctx, userID, dayStart, dayStart+daySec)
+// ListEventsForDay возвращает события пользователя за календарный день, содержащий day.
+// Границы дня — полночь в часовом поясе day.
+func (a *App) ListEventsForDay(ctx context.Context, userID string, day time.Time, page PageRequest) (EventPage, error) {
+	start, end := dayBounds(day)
+	return a.ListEventsForPeriod(ctx, userID, start.Unix(), end.Unix(), page)
 }
 
-// ListEventsForWeek возвращает события пользователя за неделю.
-func (a *App) ListEventsForWeek(ctx context.Context, userID string, weekStart int64) ([]storage.Event, error) {
-	const weekSec = 7 * 24 * 60 * 60
-	return a.ListEventsForPeriod(ctx, userID, weekStart, weekStart+weekSec)
+// ListEventsForWeek возвращает события пользователя за неделю, содержащую day.
+// Неделя начинается в полночь первого дня недели (см. WithWeekStart) в часовом поясе day.
+func (a *App) ListEventsForWeek(ctx context.Context, userID string, day time.Time, page PageRequest) (EventPage, error) {
+	start, end := weekBounds(day, a.weekStart)
+	return a.ListEventsForPeriod(ctx, userID, start.Unix(), end.Unix(), page)
 }
 
-// ListEventsForMonth возвращает события пользователя за месяц (30 дней).
-func (a *App) ListEventsForMonth(ctx context.Context, userID string, monthStart int64) ([]storage.Event, error) {
-	const monthSec = 30 * 24 * 60 * 60
-	return a.ListEventsForPeriod(ctx, userID, monthStart, monthStart+monthSec)
+// ListEventsForMonth возвращает события пользователя за календарный месяц, содержащий day,
+// в часовом поясе day.
+func (a *App) ListEventsForMonth(ctx context.Context, userID string, day time.Time, page PageRequest) (EventPage, error) {
+	start, end := monthBounds(day)
+	return a.ListEventsForPeriod(ctx, userID, start.Unix(), end.Unix(), page)
 }
 
 // Logger возвращает логгер приложения.
@@ -105,11 +300,60 @@ func (a *App) Logger() Logger {
 }
 
 // GetEventsForNotification возвращает события, требующие уведомления.
+// Для повторяющихся событий возвращаются экземпляры, попавшие в окно хотя бы одного напоминания.
 func (a *App) GetEventsForNotification(ctx context.Context, currentTime int64) ([]storage.Event, error) {
-	return a.storage.GetEventsForNotification(ctx, currentTime)
+	events, err := a.storage.GetEventsForNotification(ctx, currentTime)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]storage.Event, 0, len(events))
+	for _, ev := range events {
+		if !ev.IsRecurring() {
+			result = append(result, ev)
+			continue
+		}
+		overrides, err := a.overrides(ctx, ev)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, notifiedOccurrences(ev, overrides, currentTime)...)
+	}
+	return result, nil
+}
+
+// EnqueueDueReminders ставит в outbox напоминания, время отправки которых наступило.
+// Каждое напоминание события (смещение и канал) проверяется и ставится отдельно
+// для владельца и каждого участника, принявшего приглашение.
+// Отметка о напоминании и сообщение outbox записываются хранилищем атомарно, поэтому каждое
+// напоминание попадает в outbox один раз. Возвращает число новых сообщений.
+func (a *App) EnqueueDueReminders(ctx context.Context, currentTime int64) (int, error) {
+	events, err := a.GetEventsForNotification(ctx, currentTime)
+	if err != nil {
+		return 0, err
+	}
+	enqueued := 0
+	for _, ev := range events {
+		for _, reminder := range dueReminders(ev, currentTime) {
+			ok, err := a.storage.EnqueueReminder(ctx, reminder)
+			if err != nil {
+				return enqueued, err
+			}
+			if ok {
+				enqueued++
+			}
+		}
+	}
+	return enqueued, nil
 }
 
-// DeleteOldEvents удаляет старые события.
-func (a *App) DeleteOldEvents(ctx context.Context, beforeTime int64) error {
-	return a.storage.DeleteOldEvents(ctx, beforeTime)
+// RelayOutbox публикует не более limit неотправленных сообщений outbox в порядке записи и отмечает
+// их отправленными. Сообщение отмечается только после подтверждения публикации, поэтому доставка —
+// at-least-once: после сбоя между публикацией и отметкой сообщение будет опубликовано повторно.
+// Публикуемые сообщения заблокированы в хранилище, поэтому бывший лидер, ещё не заметивший потерю
+// аренды, не публикует их одновременно с новым. При ошибке публикации попытка учитывается, а обработка
+// останавливается до следующего запуска. Возвращает число отправленных сообщений.
+func (a *App) RelayOutbox(ctx context.Context, limit int, publish func(context.Context, storage.Reminder) error) (int, error) {
+	return a.storage.RelayOutbox(ctx, limit, func(ctx context.Context, msg storage.OutboxMessage) error {
+		return publish(ctx, msg.Reminder)
+	})
 }
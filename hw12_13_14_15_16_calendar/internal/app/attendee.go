@@ -0,0 +1,143 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/storage"
+)
+
+// MaxAttendees — максимальное число участников события.
+const MaxAttendees = 100
+
+// Invitation — приглашение пользователя на событие.
+type Invitation struct {
+	Event  storage.Event          // событие или повторяющееся событие целиком
+	Status storage.AttendeeStatus // ответ пользователя на приглашение
+}
+
+// prepareAttendees приводит список участников события к каноническому виду: ID без пробелов,
+// без повторов и без владельца события, по возрастанию ID. Ответы участников, уже приглашённых
+// ранее (existing), сохраняются; новые участники получают статус needs-action.
+// Ответ на приглашение меняет только сам участник (см. RespondToInvitation).
+func prepareAttendees(event *storage.Event, existing []storage.Attendee) error {
+	if len(event.Attendees) == 0 {
+		event.Attendees = nil
+		return nil
+	}
+	statuses := make(map[string]storage.AttendeeStatus, len(existing))
+	for _, a := range existing {
+		statuses[a.UserID] = a.Status
+	}
+	attendees := make([]storage.Attendee, 0, len(event.Attendees))
+	for _, a := range event.Attendees {
+		userID := strings.TrimSpace(a.UserID)
+		if userID == "" {
+			return fmt.Errorf("%w: empty attendee user id", ErrInvalidEvent)
+		}
+		if userID == event.UserID {
+			continue
+		}
+		status, ok := statuses[userID]
+		if !ok {
+			status = storage.AttendeeNeedsAction
+		}
+		attendees = append(attendees, storage.Attendee{UserID: userID, Status: status})
+	}
+	slices.SortFunc(attendees, func(a, b storage.Attendee) int { return strings.Compare(a.UserID, b.UserID) })
+	attendees = slices.CompactFunc(attendees, func(a, b storage.Attendee) bool { return a.UserID == b.UserID })
+	if len(attendees) > MaxAttendees {
+		return fmt.Errorf("%w: too many attendees (max %d)", ErrInvalidEvent, MaxAttendees)
+	}
+	event.Attendees = attendees
+	return nil
+}
+
+// InviteAttendees приглашает пользователей на событие. Уже приглашённые пользователи и владелец
+// события пропускаются. Приглашение на повторяющееся событие распространяется на все его экземпляры.
+// Приглашать участников может только владелец события. Возвращает событие с обновлённым списком участников.
+func (a *App) InviteAttendees(ctx context.Context, eventID string, userIDs []string) (storage.Event, error) {
+	event, err := a.storage.GetEvent(ctx, eventID)
+	if err != nil {
+		return storage.Event{}, err
+	}
+	if event.RecurringEventID != "" {
+		return storage.Event{}, fmt.Errorf("%w: invite attendees to the recurring event %s", ErrInvalidEvent, event.RecurringEventID)
+	}
+	for _, userID := range userIDs {
+		event.Attendees = append(event.Attendees, storage.Attendee{UserID: userID})
+	}
+	return a.UpdateEvent(ctx, event)
+}
+
+// RespondToInvitation сохраняет ответ пользователя на приглашение. Ответ на приглашение
+// в экземпляр серии относится ко всей серии. Возвращает ErrNotFound, если пользователь не приглашён.
+func (a *App) RespondToInvitation(ctx context.Context, eventID, userID string, status storage.AttendeeStatus) (storage.Event, error) {
+	if err := checkUser(ctx, userID); err != nil {
+		return storage.Event{}, err
+	}
+	if !status.Valid() {
+		return storage.Event{}, fmt.Errorf("%w: unknown attendee status %q", ErrInvalidEvent, status)
+	}
+	event, err := a.storage.GetEvent(ctx, eventID)
+	if err != nil {
+		return storage.Event{}, err
+	}
+	if event.RecurringEventID != "" {
+		eventID = event.RecurringEventID
+	}
+	if eventID != event.ID {
+		if event, err = a.storage.GetEvent(ctx, eventID); err != nil {
+			return storage.Event{}, err
+		}
+	}
+	responded := event
+	responded.Attendees = slices.Clone(event.Attendees)
+	for i := range responded.Attendees {
+		if responded.Attendees[i].UserID == userID {
+			responded.Attendees[i].Status = status
+		}
+	}
+	auditCtx := withAudit(ctx, storage.AuditUpdated, userID, event, responded)
+	if err := a.storage.SetAttendeeStatus(auditCtx, eventID, userID, status); err != nil {
+		return storage.Event{}, err
+	}
+	return a.storage.GetEvent(ctx, eventID)
+}
+
+// ListInvitations возвращает приглашения пользователя, упорядоченные по времени начала события.
+// Если status задан, возвращаются только приглашения с этим ответом.
+func (a *App) ListInvitations(ctx context.Context, userID string, status storage.AttendeeStatus) ([]Invitation, error) {
+	if err := checkUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	if status != "" && !status.Valid() {
+		return nil, fmt.Errorf("%w: unknown attendee status %q", ErrInvalidEvent, status)
+	}
+	events, err := a.storage.ListAttendedEvents(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	var result []Invitation
+	for _, ev := range events {
+		attendee, ok := ev.Attendee(userID)
+		if !ok || (status != "" && attendee.Status != status) {
+			continue
+		}
+		result = append(result, Invitation{Event: ev, Status: attendee.Status})
+	}
+	return result, nil
+}
+
+// recipients возвращает получателей напоминаний о событии: владельца и участников, принявших приглашение.
+func recipients(ev storage.Event) []string {
+	result := []string{ev.UserID}
+	for _, a := range ev.Attendees {
+		if a.Status == storage.AttendeeAccepted {
+			result = append(result, a.UserID)
+		}
+	}
+	return result
+}
@@ -0,0 +1,153 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/auth"
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/storage"
+)
+
+// Число записей журнала аудита в ответе GetEventHistory и ListUserActivity.
+const (
+	DefaultAuditLimit = 50  // если limit не задан
+	MaxAuditLimit     = 500 // наибольший limit
+)
+
+// GetEventHistory возвращает не более limit последних записей журнала аудита события, от новых к старым.
+// История доступна владельцу события и после его удаления из корзины; если записей о событии нет,
+// возвращается ErrNotFound.
+func (a *App) GetEventHistory(ctx context.Context, eventID string, limit int) ([]storage.AuditEntry, error) {
+	limit, err := auditLimit(limit)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := a.storage.ListEventAudit(ctx, eventID, limit)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, storage.NotFoundError(eventID)
+	}
+	if err := checkUser(ctx, entries[0].OwnerID); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// ListUserActivity возвращает не более limit последних действий пользователя над событиями, от новых к старым:
+// свои изменения и изменения, сделанные сервисами от его имени.
+func (a *App) ListUserActivity(ctx context.Context, userID string, limit int) ([]storage.AuditEntry, error) {
+	if err := checkUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	limit, err := auditLimit(limit)
+	if err != nil {
+		return nil, err
+	}
+	return a.storage.ListUserAudit(ctx, userID, limit)
+}
+
+// auditLimit проверяет число запрашиваемых записей журнала; 0 — DefaultAuditLimit.
+func auditLimit(limit int) (int, error) {
+	switch {
+	case limit == 0:
+		return DefaultAuditLimit, nil
+	case limit < 0 || limit > MaxAuditLimit:
+		return 0, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidPage, MaxAuditLimit)
+	}
+	return limit, nil
+}
+
+// withAudit возвращает контекст для изменения в хранилище, которое хранилище запишет в журнал аудита
+// в той же транзакции (см. storage.WithAudit): before — событие до действия, after — после
+// (пустое событие — события не было или больше нет). userID — пользователь, от имени которого
+// выполняется действие; он считается автором, если вызывающая сторона не аутентифицирована
+// или это сервис. Изменение без изменённых полей не записывается. Контекст относится к одному
+// изменению и не передаётся дальше.
+func withAudit(ctx context.Context, action storage.AuditAction, userID string, before, after storage.Event) context.Context {
+	changes := diffEvents(before, after)
+	if action == storage.AuditUpdated && len(changes) == 0 {
+		return ctx
+	}
+	event := after
+	if event.ID == "" {
+		event = before
+	}
+	entry := storage.AuditEntry{
+		EventID:   event.ID,
+		OwnerID:   event.UserID,
+		ActorID:   userID,
+		Action:    action,
+		Changes:   changes,
+		CreatedAt: time.Now().Unix(),
+	}
+	if id, ok := auth.FromContext(ctx); ok {
+		if id.IsService() {
+			entry.Service = id.Service
+		} else {
+			entry.ActorID = id.UserID
+		}
+	}
+	return storage.WithAudit(ctx, entry)
+}
+
+// diffEvents возвращает поля, значения которых различаются у событий before и after.
+func diffEvents(before, after storage.Event) []storage.FieldChange {
+	old, cur := auditFields(before), auditFields(after)
+	var changes []storage.FieldChange
+	for i := range old {
+		if old[i].value != cur[i].value {
+			changes = append(changes, storage.FieldChange{Field: old[i].name, Before: old[i].value, After: cur[i].value})
+		}
+	}
+	return changes
+}
+
+// auditField — поле события в журнале аудита.
+type auditField struct {
+	name  string
+	value string
+}
+
+// auditFields возвращает поля события в текстовом виде для журнала аудита; у пустого события все поля пусты.
+func auditFields(e storage.Event) []auditField {
+	exists := e.ID != ""
+	formatTime := func(ts int64) string {
+		if !exists {
+			return ""
+		}
+		return time.Unix(ts, 0).UTC().Format(time.RFC3339)
+	}
+	exdates := make([]string, 0, len(e.ExDates))
+	for _, ts := range e.ExDates {
+		exdates = append(exdates, formatTime(ts))
+	}
+	reminders := make([]string, 0, len(e.Reminders))
+	for _, r := range e.Reminders {
+		reminder := strconv.FormatInt(r.Offset/60, 10) + "m"
+		if r.Channel != "" {
+			reminder += " " + r.Channel
+		}
+		reminders = append(reminders, reminder)
+	}
+	attendees := make([]string, 0, len(e.Attendees))
+	for _, at := range e.Attendees {
+		attendees = append(attendees, at.UserID+"="+string(at.Status))
+	}
+	return []auditField{
+		{"title", e.Title},
+		{"description", e.Description},
+		{"user_id", e.UserID},
+		{"start_time", formatTime(e.StartTime)},
+		{"end_time", formatTime(e.EndTime)},
+		{"rrule", e.RRule},
+		{"time_zone", e.TimeZone},
+		{"exdates", strings.Join(exdates, ", ")},
+		{"reminders", strings.Join(reminders, ", ")},
+		{"attendees", strings.Join(attendees, ", ")},
+	}
+}
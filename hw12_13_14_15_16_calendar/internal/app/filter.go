@@ -0,0 +1,190 @@
+package app
+
+import (
+	"cmp"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/storage"
+)
+
+// EventOrder — порядок событий в выборке ListEvents.
+type EventOrder int
+
+// Порядок событий.
+const (
+	OrderByStartTime     EventOrder = iota // по возрастанию времени начала, затем по ID
+	OrderByStartTimeDesc                   // по убыванию времени начала, затем по ID
+	OrderByTitle                           // по заголовку без учёта регистра, затем по времени начала и ID
+)
+
+// ReminderFilter — отбор событий по наличию напоминаний.
+type ReminderFilter int
+
+// Отбор по напоминаниям.
+const (
+	AnyReminders     ReminderFilter = iota // любые события
+	WithReminders                          // только события с напоминаниями
+	WithoutReminders                       // только события без напоминаний
+)
+
+// EventFilter — условия выборки событий пользователя.
+type EventFilter struct {
+	Start, End int64          // диапазон [Start, End) (Unix timestamp); оба 0 — без ограничения по времени
+	Query      string         // слова, каждое из которых должно встречаться в заголовке или описании
+	Reminders  ReminderFilter // отбор по наличию напоминаний
+	Order      EventOrder     // порядок событий
+}
+
+// ListEvents возвращает страницу событий пользователя, подходящих под фильтр.
+// Если задан диапазон, повторяющиеся события разворачиваются в экземпляры, пересекающиеся с ним;
+// без диапазона события возвращаются как хранятся: серии целиком, изменённые экземпляры — отдельно.
+// Страницы продолжаются с позиции последнего события предыдущей страницы в выбранном порядке.
+func (a *App) ListEvents(ctx context.Context, userID string, filter EventFilter, page PageRequest) (EventPage, error) {
+	if err := checkUser(ctx, userID); err != nil {
+		return EventPage{}, err
+	}
+	if err := filter.validate(); err != nil {
+		return EventPage{}, err
+	}
+	size, err := pageSize(page)
+	if err != nil {
+		return EventPage{}, err
+	}
+	after, err := decodeFilterToken(page.Token, filter)
+	if err != nil {
+		return EventPage{}, err
+	}
+
+	var events []storage.Event
+	if filter.Start < filter.End {
+		stored, err := a.storage.ListEventsInRange(ctx, userID, filter.Start, filter.End)
+		if err != nil {
+			return EventPage{}, err
+		}
+		events = expandEvents(stored, filter.Start, filter.End)
+	} else if events, err = a.storage.ListEvents(ctx, userID); err != nil {
+		return EventPage{}, err
+	}
+	terms := strings.Fields(strings.ToLower(filter.Query))
+	events = slices.DeleteFunc(events, func(ev storage.Event) bool { return !filter.matches(ev, terms) })
+	compare := filter.Order.compare
+	slices.SortStableFunc(events, compare)
+
+	result := EventPage{Total: len(events)}
+	if after != nil {
+		events = events[sort.Search(len(events), func(i int) bool { return compare(events[i], *after) > 0 }):]
+	}
+	if len(events) > size {
+		events = events[:size]
+		result.NextPageToken = encodeFilterToken(filter, events[size-1])
+	}
+	result.Events = events
+	return result, nil
+}
+
+// validate проверяет диапазон и значения перечислений фильтра.
+func (f EventFilter) validate() error {
+	switch {
+	case (f.Start == 0) != (f.End == 0) || f.Start > f.End:
+		return fmt.Errorf("%w: range must have both start and end, start before end", ErrInvalidPeriod)
+	case f.Reminders < AnyReminders || f.Reminders > WithoutReminders:
+		return fmt.Errorf("%w: unknown reminder filter %d", ErrInvalidEvent, f.Reminders)
+	case f.Order < OrderByStartTime || f.Order > OrderByTitle:
+		return fmt.Errorf("%w: unknown event order %d", ErrInvalidEvent, f.Order)
+	}
+	return nil
+}
+
+// matches сообщает, подходит ли событие под фильтр по напоминаниям и словам запроса terms
+// (в нижнем регистре).
+func (f EventFilter) matches(ev storage.Event, terms []string) bool {
+	switch f.Reminders {
+	case WithReminders:
+		if len(ev.Reminders) == 0 {
+			return false
+		}
+	case WithoutReminders:
+		if len(ev.Reminders) > 0 {
+			return false
+		}
+	}
+	if len(terms) == 0 {
+		return true
+	}
+	text := strings.ToLower(ev.Title + "\n" + ev.Description)
+	for _, term := range terms {
+		if !strings.Contains(text, term) {
+			return false
+		}
+	}
+	return true
+}
+
+// compare сравнивает события в порядке o. Порядок полный: при равенстве ключей сравниваются ID.
+func (o EventOrder) compare(a, b storage.Event) int {
+	switch o {
+	case OrderByStartTimeDesc:
+		return cmp.Or(cmp.Compare(b.StartTime, a.StartTime), strings.Compare(a.ID, b.ID))
+	case OrderByTitle:
+		return cmp.Or(strings.Compare(strings.ToLower(a.Title), strings.ToLower(b.Title)),
+			cmp.Compare(a.StartTime, b.StartTime), strings.Compare(a.ID, b.ID))
+	}
+	return cmp.Or(cmp.Compare(a.StartTime, b.StartTime), strings.Compare(a.ID, b.ID))
+}
+
+// pageSize возвращает размер страницы запроса с учётом значения по умолчанию и ограничения.
+func pageSize(page PageRequest) (int, error) {
+	switch {
+	case page.Size < 0:
+		return 0, fmt.Errorf("%w: negative page size", ErrInvalidPage)
+	case page.Size == 0:
+		return DefaultPageSize, nil
+	case page.Size > MaxPageSize:
+		return MaxPageSize, nil
+	}
+	return page.Size, nil
+}
+
+// fingerprint — отпечаток фильтра в токене страницы: токен действует только для того же фильтра.
+func (f EventFilter) fingerprint() string {
+	h := fnv.New64a()
+	_, _ = fmt.Fprintf(h, "%d:%d:%q:%d:%d", f.Start, f.End, strings.ToLower(f.Query), f.Reminders, f.Order)
+	return strconv.FormatUint(h.Sum64(), 36)
+}
+
+// encodeFilterToken формирует токен страницы: отпечаток фильтра и ключ сортировки последнего события.
+func encodeFilterToken(filter EventFilter, last storage.Event) string {
+	raw := fmt.Sprintf("%s:%d:%s:%s", filter.fingerprint(), last.StartTime, last.ID, last.Title)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeFilterToken разбирает токен страницы в событие-позицию (только ключ сортировки).
+// Пустой токен — первая страница (nil), токен другого фильтра некорректен.
+func decodeFilterToken(token string, filter EventFilter) (*storage.Event, error) {
+	if token == "" {
+		return nil, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed page token", ErrInvalidPage)
+	}
+	parts := strings.SplitN(string(raw), ":", 4)
+	if len(parts) != 4 {
+		return nil, fmt.Errorf("%w: malformed page token", ErrInvalidPage)
+	}
+	if parts[0] != filter.fingerprint() {
+		return nil, fmt.Errorf("%w: page token belongs to another filter", ErrInvalidPage)
+	}
+	start, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed page token", ErrInvalidPage)
+	}
+	return &storage.Event{StartTime: start, ID: parts[2], Title: parts[3]}, nil
+}
@@ -0,0 +1,139 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/ical"
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/storage"
+	"github.com/google/uuid"
+)
+
+// ImportReport — результат импорта календаря.
+type ImportReport struct {
+	Imported int          // число сохранённых событий и изменённых экземпляров
+	Skipped  []ical.Issue // пропущенные события с причинами
+}
+
+// ExportCalendar выгружает события пользователя в формате iCalendar.
+// Если start < end, выгружаются только события, пересекающиеся с диапазоном [start, end);
+// повторяющиеся события выгружаются целиком, вместе с изменёнными экземплярами.
+func (a *App) ExportCalendar(ctx context.Context, userID string, start, end int64) ([]byte, error) {
+	if err := checkUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	var (
+		events []storage.Event
+		err    error
+	)
+	if start < end {
+		events, err = a.storage.ListEventsInRange(ctx, userID, start, end)
+	} else {
+		events, err = a.storage.ListEvents(ctx, userID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	sortEvents(events)
+	var buf bytes.Buffer
+	if err := ical.Encode(&buf, events); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// ImportCalendar загружает события из календаря iCalendar в календарь пользователя.
+// UID события становится его ID (если UID не UUID, ID вычисляется из него детерминированно),
+// поэтому повторный импорт того же календаря обновляет ранее загруженные события.
+// События, которые не удалось разобрать или сохранить, пропускаются и попадают в отчёт.
+// Ошибка возвращается, только если данные не являются календарём.
+func (a *App) ImportCalendar(ctx context.Context, userID string, data []byte) (ImportReport, error) {
+	if err := checkUser(ctx, userID); err != nil {
+		return ImportReport{}, err
+	}
+	entries, issues, err := ical.Decode(bytes.NewReader(data))
+	if err != nil {
+		return ImportReport{}, fmt.Errorf("%w: %v", ErrInvalidEvent, err)
+	}
+	report := ImportReport{Skipped: issues}
+	skip := func(entry ical.Entry, err error) {
+		report.Skipped = append(report.Skipped, ical.Issue{UID: entry.UID, Line: entry.Line, Reason: err.Error()})
+	}
+
+	// Сначала сохраняются серии и разовые события, затем изменённые экземпляры серий
+	var occurrences []ical.Entry
+	for _, entry := range entries {
+		if entry.RecurrenceID != 0 {
+			occurrences = append(occurrences, entry)
+			continue
+		}
+		if err := a.importEvent(ctx, userID, entry); err != nil {
+			skip(entry, err)
+			continue
+		}
+		report.Imported++
+	}
+	for _, entry := range occurrences {
+		if err := a.importOccurrence(ctx, userID, entry); err != nil {
+			skip(entry, err)
+			continue
+		}
+		report.Imported++
+	}
+	sort.SliceStable(report.Skipped, func(i, j int) bool { return report.Skipped[i].Line < report.Skipped[j].Line })
+	return report, nil
+}
+
+// importEvent создаёт событие из календаря или обновляет ранее импортированное.
+func (a *App) importEvent(ctx context.Context, userID string, entry ical.Entry) error {
+	event := entry.Event
+	event.UserID = userID
+	var exists bool
+	event.ID, exists = a.importID(ctx, userID, entry.UID)
+	var err error
+	if exists {
+		_, err = a.UpdateEvent(ctx, event)
+	} else {
+		_, err = a.CreateEvent(ctx, event)
+	}
+	return err
+}
+
+// importOccurrence сохраняет изменённый экземпляр серии пользователя.
+func (a *App) importOccurrence(ctx context.Context, userID string, entry ical.Entry) error {
+	masterID, exists := a.importID(ctx, userID, entry.UID)
+	if !exists {
+		return fmt.Errorf("%w: recurring event %s not found", ErrNoOccurrence, entry.UID)
+	}
+	_, err := a.UpdateEventOccurrence(ctx, masterID, entry.RecurrenceID, entry.Event)
+	return err
+}
+
+// importUIDNamespace — пространство имён UUID v5 для ID событий, вычисляемых из UID.
+var importUIDNamespace = uuid.MustParse("6f1d3c5e-2b7a-5d4e-9c8f-1a2b3c4d5e6f")
+
+// importID возвращает ID события пользователя для UID из календаря и сообщает, существует ли уже такое событие.
+// UID в виде UUID используется как ID, остальные UID переводятся в UUID v5. Если событие с таким ID
+// принадлежит другому пользователю (например, календарь выгружен из чужого календаря), ID вычисляется
+// из пары пользователь/UID, чтобы не затронуть чужое событие.
+func (a *App) importID(ctx context.Context, userID, uid string) (string, bool) {
+	if uid == "" {
+		return uuid.New().String(), false
+	}
+	id := uuid.NewSHA1(importUIDNamespace, []byte(uid)).String()
+	if parsed, err := uuid.Parse(uid); err == nil {
+		id = parsed.String()
+	}
+	existing, err := a.storage.GetEvent(ctx, id)
+	if err != nil {
+		return id, false
+	}
+	if existing.UserID == userID {
+		return id, true
+	}
+	id = uuid.NewSHA1(importUIDNamespace, []byte(userID+"/"+uid)).String()
+	existing, err = a.storage.GetEvent(ctx, id)
+	return id, err == nil && existing.UserID == userID
+}
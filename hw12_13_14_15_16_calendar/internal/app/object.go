@@ -0,0 +1,234 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/ical"
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/storage"
+)
+
+// CalendarObject — объект календаря (ресурс .ics): разовое или повторяющееся событие
+// вместе с изменёнными экземплярами серии.
+type CalendarObject struct {
+	Event     storage.Event   // разовое или повторяющееся событие
+	Overrides []storage.Event // изменённые экземпляры серии, по возрастанию исходного времени
+}
+
+// ETag возвращает метку версии объекта: она меняется при любом изменении события или его экземпляров.
+func (o CalendarObject) ETag() string {
+	h := sha256.New()
+	for _, e := range append([]storage.Event{o.Event}, o.Overrides...) {
+		fmt.Fprintf(h, "%s|%q|%q|%s|%d|%d|%v|%v|%q|%q|%v|%s|%d\n", e.ID, e.Title, e.Description, e.UserID,
+			e.StartTime, e.EndTime, e.Reminders, e.Attendees, e.RRule, e.TimeZone, e.ExDates, e.RecurringEventID, e.RecurrenceID)
+	}
+	return `"` + hex.EncodeToString(h.Sum(nil)[:16]) + `"`
+}
+
+// ICal возвращает объект в формате iCalendar.
+func (o CalendarObject) ICal() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := ical.Encode(&buf, append([]storage.Event{o.Event}, o.Overrides...)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// CalendarObjects возвращает объекты календаря пользователя, отсортированные по времени начала.
+// Если start < end, возвращаются только объекты, у которых событие или хотя бы один экземпляр серии
+// пересекается с диапазоном [start, end).
+func (a *App) CalendarObjects(ctx context.Context, userID string, start, end int64) ([]CalendarObject, error) {
+	if err := checkUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	var (
+		events []storage.Event
+		err    error
+	)
+	if start < end {
+		events, err = a.storage.ListEventsInRange(ctx, userID, start, end)
+	} else {
+		events, err = a.storage.ListEvents(ctx, userID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	objects := make(map[string]*CalendarObject)
+	var order []string
+	add := func(e storage.Event) *CalendarObject {
+		if o, ok := objects[e.ID]; ok {
+			return o
+		}
+		objects[e.ID] = &CalendarObject{Event: e}
+		order = append(order, e.ID)
+		return objects[e.ID]
+	}
+	for _, e := range events {
+		if e.RecurringEventID == "" {
+			add(e)
+		}
+	}
+	for _, e := range events {
+		if e.RecurringEventID == "" {
+			continue
+		}
+		o, ok := objects[e.RecurringEventID]
+		if !ok {
+			// Экземпляр перенесён в диапазон, а сама серия в выборку не попала
+			master, err := a.storage.GetEvent(ctx, e.RecurringEventID)
+			if err != nil {
+				return nil, err
+			}
+			o = add(master)
+		}
+		o.Overrides = append(o.Overrides, e)
+	}
+
+	result := make([]CalendarObject, 0, len(order))
+	for _, id := range order {
+		o := objects[id]
+		sortOverrides(o.Overrides)
+		if start < end && o.Event.IsRecurring() &&
+			len(expandEvents(append([]storage.Event{o.Event}, o.Overrides...), start, end)) == 0 {
+			continue
+		}
+		result = append(result, *o)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Event.StartTime != result[j].Event.StartTime {
+			return result[i].Event.StartTime < result[j].Event.StartTime
+		}
+		return result[i].Event.ID < result[j].Event.ID
+	})
+	return result, nil
+}
+
+// GetCalendarObject возвращает объект календаря пользователя по ID события.
+// Событие другого пользователя и изменённый экземпляр серии объектами не являются (ErrNotFound).
+func (a *App) GetCalendarObject(ctx context.Context, userID, id string) (CalendarObject, error) {
+	if err := checkUser(ctx, userID); err != nil {
+		return CalendarObject{}, err
+	}
+	event, err := a.storage.GetEvent(ctx, id)
+	if err != nil {
+		return CalendarObject{}, err
+	}
+	if event.UserID != userID || event.RecurringEventID != "" {
+		return CalendarObject{}, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	object := CalendarObject{Event: event}
+	if event.IsRecurring() {
+		if object.Overrides, err = a.overrides(ctx, event); err != nil {
+			return CalendarObject{}, err
+		}
+		sortOverrides(object.Overrides)
+	}
+	return object, nil
+}
+
+// PutCalendarObject создаёт или заменяет объект календаря пользователя с ID id из данных iCalendar.
+// Данные должны содержать одно событие (все VEVENT с одним UID): само событие и, для серии,
+// изменённые экземпляры с RECURRENCE-ID. Экземпляры, отсутствующие в данных, возвращаются к исходным.
+// Возвращает сохранённый объект и признак того, что объект создан.
+func (a *App) PutCalendarObject(ctx context.Context, userID, id string, data []byte) (CalendarObject, bool, error) {
+	if err := checkUser(ctx, userID); err != nil {
+		return CalendarObject{}, false, err
+	}
+	entries, issues, err := ical.Decode(bytes.NewReader(data))
+	if err != nil {
+		return CalendarObject{}, false, fmt.Errorf("%w: %v", ErrInvalidEvent, err)
+	}
+	if len(issues) > 0 {
+		return CalendarObject{}, false, fmt.Errorf("%w: line %d: %s", ErrInvalidEvent, issues[0].Line, issues[0].Reason)
+	}
+	var (
+		master      *ical.Entry
+		occurrences []ical.Entry
+	)
+	for i, entry := range entries {
+		if entry.UID != entries[0].UID {
+			return CalendarObject{}, false, fmt.Errorf("%w: calendar object must contain a single UID", ErrInvalidEvent)
+		}
+		if entry.RecurrenceID != 0 {
+			occurrences = append(occurrences, entry)
+			continue
+		}
+		if master != nil {
+			return CalendarObject{}, false, fmt.Errorf("%w: duplicate VEVENT %s", ErrInvalidEvent, entry.UID)
+		}
+		master = &entries[i]
+	}
+	if master == nil {
+		return CalendarObject{}, false, fmt.Errorf("%w: VEVENT without RECURRENCE-ID is required", ErrInvalidEvent)
+	}
+
+	event := master.Event
+	event.ID, event.UserID = id, userID
+	existing, getErr := a.storage.GetEvent(ctx, id)
+	if getErr != nil && !errors.Is(getErr, ErrNotFound) {
+		return CalendarObject{}, false, getErr
+	}
+	created := getErr != nil
+	switch {
+	case created:
+		_, err = a.CreateEvent(ctx, event)
+	case existing.UserID != userID || existing.RecurringEventID != "":
+		return CalendarObject{}, false, fmt.Errorf("%w: %s", ErrNotFound, id)
+	default:
+		_, err = a.UpdateEvent(ctx, event)
+	}
+	if err != nil {
+		return CalendarObject{}, false, err
+	}
+
+	saved, err := a.GetCalendarObject(ctx, userID, id)
+	if err != nil {
+		return CalendarObject{}, false, err
+	}
+	keep := make(map[int64]bool, len(occurrences))
+	for _, occ := range occurrences {
+		keep[occ.RecurrenceID] = true
+	}
+	for _, o := range saved.Overrides {
+		if !keep[o.RecurrenceID] {
+			auditCtx := withAudit(ctx, storage.AuditDeleted, userID, o, storage.Event{})
+			if err := a.storage.DeleteEvent(auditCtx, o.ID, o.Version); err != nil {
+				return CalendarObject{}, false, err
+			}
+		}
+	}
+	for _, occ := range occurrences {
+		if _, err := a.UpdateEventOccurrence(ctx, id, occ.RecurrenceID, occ.Event); err != nil {
+			if created {
+				// Новый объект сохраняется целиком или не сохраняется вовсе
+				if a.DeleteEvent(ctx, id, 0) == nil {
+					_ = a.storage.PurgeEvent(ctx, id)
+				}
+			}
+			return CalendarObject{}, false, err
+		}
+	}
+	saved, err = a.GetCalendarObject(ctx, userID, id)
+	return saved, created, err
+}
+
+// DeleteCalendarObject удаляет объект календаря пользователя вместе с изменёнными экземплярами серии.
+// Событие, изменённое после чтения объекта, не удаляется (ErrVersionConflict).
+func (a *App) DeleteCalendarObject(ctx context.Context, userID, id string) error {
+	object, err := a.GetCalendarObject(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	return a.DeleteEvent(ctx, id, object.Event.Version)
+}
+
+// sortOverrides упорядочивает изменённые экземпляры по исходному времени начала.
+func sortOverrides(overrides []storage.Event) {
+	sort.Slice(overrides, func(i, j int) bool { return overrides[i].RecurrenceID < overrides[j].RecurrenceID })
+}
@@ -0,0 +1,86 @@
+package app
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/storage"
+)
+
+// overlapHorizon — насколько вперёд от текущего момента проверяются на пересечение экземпляры
+// бесконечных и длинных серий.
+const overlapHorizon = 366 * 24 * time.Hour
+
+// checkOverlaps проверяет, что экземпляры сохраняемого события не пересекаются по времени с событиями
+// и экземплярами серий пользователя; overrides — изменённые экземпляры события, которых нет среди
+// событий хранилища (например, в корзине). Пересечения разовых событий и изменённых экземпляров между
+// собой проверяет хранилище (ограничение events_no_overlap), поэтому здесь учитываются только пары,
+// в которых хотя бы одно событие — экземпляр серии. Серии проверяются от начала до окончания,
+// но не дальше overlapHorizon от текущего момента. Возвращает ConflictError.
+func (a *App) checkOverlaps(ctx context.Context, event storage.Event, overrides []storage.Event) error {
+	start, end := overlapWindow(event, time.Now().Unix())
+	if start >= end {
+		return nil
+	}
+	stored, err := a.storage.ListEventsInRange(ctx, event.UserID, start, end)
+	if err != nil {
+		return err
+	}
+	events := append([]storage.Event{event}, overrides...)
+	for _, ev := range stored {
+		if ev.ID != event.ID {
+			events = append(events, ev)
+		}
+	}
+	if !slices.ContainsFunc(events, storage.Event.IsRecurring) {
+		return nil
+	}
+
+	var own, other []storage.Event
+	for _, ev := range expandEvents(events, start, end) {
+		if ev.ID == event.ID || ev.RecurringEventID == event.ID {
+			own = append(own, ev)
+		} else {
+			other = append(other, ev)
+		}
+	}
+	var ids []string
+	for _, o := range other {
+		if slices.Contains(ids, o.ID) {
+			continue
+		}
+		for _, ev := range own {
+			if ev.StartTime >= o.EndTime {
+				break // own отсортированы по времени начала
+			}
+			if (ev.IsRecurring() || o.IsRecurring()) && overlaps(ev, o) {
+				ids = append(ids, o.ID)
+				break
+			}
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	return &ConflictError{EventIDs: ids}
+}
+
+// overlapWindow возвращает диапазон, в котором проверяются пересечения события: интервал разового события
+// или серия от начала до окончания, но не дальше overlapHorizon от момента now.
+func overlapWindow(event storage.Event, now int64) (start, end int64) {
+	if !event.IsRecurring() {
+		return event.StartTime, event.EndTime
+	}
+	end = max(event.StartTime, now) + int64(overlapHorizon/time.Second)
+	if event.RecurrenceEnd != 0 {
+		end = min(end, event.RecurrenceEnd)
+	}
+	return event.StartTime, end
+}
+
+// overlaps сообщает, пересекаются ли непустые полуинтервалы [StartTime, EndTime) двух событий.
+func overlaps(a, b storage.Event) bool {
+	return a.StartTime < a.EndTime && b.StartTime < b.EndTime &&
+		a.StartTime < b.EndTime && b.StartTime < a.EndTime
+}
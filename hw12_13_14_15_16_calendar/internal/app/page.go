@@ -0,0 +1,104 @@
+package app
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/storage"
+	"github.com/google/uuid"
+)
+
+// Размеры страницы выборки событий.
+const (
+	DefaultPageSize = 100  // размер страницы, если он не указан
+	MaxPageSize     = 1000 // максимальный размер страницы
+)
+
+// PageRequest — параметры постраничной выборки событий.
+type PageRequest struct {
+	Size  int    // размер страницы; 0 — DefaultPageSize, больше MaxPageSize — MaxPageSize
+	Token string // токен из предыдущего ответа; пусто — первая страница
+}
+
+// EventPage — страница событий за период.
+type EventPage struct {
+	Events        []storage.Event // события и экземпляры серий, упорядоченные по времени начала и ID
+	NextPageToken string          // токен следующей страницы; пусто, если страница последняя
+	Total         int             // число событий и экземпляров серий за период
+}
+
+// ListEventsForPeriod возвращает страницу событий пользователя, пересекающихся с диапазоном времени
+// [start, end) (Unix timestamp). Повторяющиеся события разворачиваются в экземпляры, пересекающиеся
+// с диапазоном. События упорядочены по времени начала, затем по ID; страницы продолжаются с позиции
+// последнего события предыдущей страницы, поэтому изменения календаря между запросами не приводят
+// к пропускам и повторам уже прочитанных событий.
+func (a *App) ListEventsForPeriod(ctx context.Context, userID string, start, end int64, page PageRequest) (EventPage, error) {
+	if err := checkUser(ctx, userID); err != nil {
+		return EventPage{}, err
+	}
+	size, err := pageSize(page)
+	if err != nil {
+		return EventPage{}, err
+	}
+	after, err := decodePageToken(page.Token, start, end)
+	if err != nil {
+		return EventPage{}, err
+	}
+
+	// Лишнее событие показывает, что за страницей есть продолжение
+	stored, err := a.storage.ListEventsPage(ctx, userID, start, end, after, size+1)
+	if err != nil {
+		return EventPage{}, err
+	}
+	occurrences := expandEvents(stored.Series, start, end)
+	result := EventPage{Total: stored.Total + len(occurrences)}
+
+	events := stored.Events
+	for _, occ := range occurrences {
+		if after.Before(occ) {
+			events = append(events, occ)
+		}
+	}
+	sortEvents(events)
+	if len(events) > size {
+		events = events[:size]
+		last := events[size-1]
+		result.NextPageToken = encodePageToken(start, end, storage.Cursor{StartTime: last.StartTime, ID: last.ID})
+	}
+	result.Events = events
+	return result, nil
+}
+
+// encodePageToken формирует токен страницы: границы периода и позицию последнего события.
+func encodePageToken(start, end int64, cursor storage.Cursor) string {
+	raw := fmt.Sprintf("%d:%d:%d:%s", start, end, cursor.StartTime, cursor.ID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodePageToken разбирает токен страницы. Токен, выданный для другого периода, некорректен.
+func decodePageToken(token string, start, end int64) (storage.Cursor, error) {
+	if token == "" {
+		return storage.Cursor{}, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return storage.Cursor{}, fmt.Errorf("%w: malformed page token", ErrInvalidPage)
+	}
+	parts := strings.SplitN(string(raw), ":", 4)
+	if len(parts) != 4 || uuid.Validate(parts[3]) != nil {
+		return storage.Cursor{}, fmt.Errorf("%w: malformed page token", ErrInvalidPage)
+	}
+	var values [3]int64
+	for i := range values {
+		if values[i], err = strconv.ParseInt(parts[i], 10, 64); err != nil {
+			return storage.Cursor{}, fmt.Errorf("%w: malformed page token", ErrInvalidPage)
+		}
+	}
+	if values[0] != start || values[1] != end {
+		return storage.Cursor{}, fmt.Errorf("%w: page token belongs to another period", ErrInvalidPage)
+	}
+	return storage.Cursor{StartTime: values[2], ID: parts[3]}, nil
+}
@@ -0,0 +1,84 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Option — необязательная настройка App.
+type Option func(*App)
+
+// WithTimeZone задаёт часовой пояс по умолчанию для границ дня, недели и месяца.
+func WithTimeZone(loc *time.Location) Option {
+	return func(a *App) {
+		if loc != nil {
+			a.location = loc
+		}
+	}
+}
+
+// WithWeekStart задаёт первый день недели для выборки за неделю.
+func WithWeekStart(weekday time.Weekday) Option {
+	return func(a *App) {
+		a.weekStart = weekday
+	}
+}
+
+// WithUserTimeZones задаёт часовые пояса пользователей, ключ — ID пользователя.
+func WithUserTimeZones(zones map[string]*time.Location) Option {
+	return func(a *App) {
+		a.userLocations = zones
+	}
+}
+
+// Location возвращает часовой пояс для выборки событий пользователя за период:
+// зону из запроса (имя IANA), если она указана, иначе зону пользователя, иначе зону по умолчанию.
+func (a *App) Location(userID, zone string) (*time.Location, error) {
+	if zone != "" {
+		loc, err := time.LoadLocation(zone)
+		if err != nil {
+			return nil, fmt.Errorf("%w: unknown time zone %q", ErrInvalidPeriod, zone)
+		}
+		return loc, nil
+	}
+	if loc, ok := a.userLocations[userID]; ok {
+		return loc, nil
+	}
+	return a.location, nil
+}
+
+// dayBounds возвращает границы календарного дня, содержащего t, в часовом поясе t.
+func dayBounds(t time.Time) (time.Time, time.Time) {
+	y, m, d := t.Date()
+	start := time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+	return start, start.AddDate(0, 0, 1)
+}
+
+// weekBounds возвращает границы недели, содержащей t, в часовом поясе t.
+// Неделя начинается в полночь дня weekStart.
+func weekBounds(t time.Time, weekStart time.Weekday) (time.Time, time.Time) {
+	offset := (int(t.Weekday()) - int(weekStart) + 7) % 7
+	y, m, d := t.Date()
+	start := time.Date(y, m, d-offset, 0, 0, 0, 0, t.Location())
+	return start, start.AddDate(0, 0, 7)
+}
+
+// monthBounds возвращает границы календарного месяца, содержащего t, в часовом поясе t.
+func monthBounds(t time.Time) (time.Time, time.Time) {
+	y, m, _ := t.Date()
+	start := time.Date(y, m, 1, 0, 0, 0, 0, t.Location())
+	return start, start.AddDate(0, 1, 0)
+}
+
+// ParseWeekday разбирает название дня недели на английском ("monday", "Mon", "MO").
+func ParseWeekday(s string) (time.Weekday, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	for wd := time.Sunday; wd <= time.Saturday; wd++ {
+		name := strings.ToLower(wd.String())
+		if s == name || s == name[:3] || s == name[:2] {
+			return wd, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown weekday %q", s)
+}
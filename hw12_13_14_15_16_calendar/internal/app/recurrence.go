@@ -0,0 +1,301 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/recurrence"
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/storage"
+	"github.com/google/uuid"
+)
+
+// occurrenceKey идентифицирует экземпляр серии: ID повторяющегося события и исходное время начала.
+type occurrenceKey struct {
+	eventID      string
+	recurrenceID int64
+}
+
+// UpdateEventOccurrence изменяет один экземпляр повторяющегося события.
+// Изменённый экземпляр хранится как отдельное событие, привязанное к серии, и заменяет
+// исходный экземпляр при развёртывании. Версия в occurrence (если не 0) сверяется с версией
+// уже изменённого экземпляра, как в UpdateEvent. Возвращает сохранённый экземпляр.
+func (a *App) UpdateEventOccurrence(ctx context.Context, eventID string, recurrenceID int64, occurrence storage.Event) (storage.Event, error) {
+	master, err := a.occurrenceMaster(ctx, eventID, recurrenceID)
+	if err != nil {
+		return storage.Event{}, err
+	}
+	existing, found, err := a.findOverride(ctx, master, recurrenceID)
+	if err != nil {
+		return storage.Event{}, err
+	}
+
+	if err := validateEvent(&occurrence); err != nil {
+		return storage.Event{}, err
+	}
+	occurrence.UserID, occurrence.Attendees = master.UserID, master.Attendees
+	occurrence.RRule, occurrence.ExDates, occurrence.RecurrenceEnd, occurrence.TimeZone = "", nil, 0, ""
+	occurrence.RecurringEventID, occurrence.RecurrenceID = master.ID, recurrenceID
+	if found {
+		if err := checkVersion(existing, occurrence.Version); err != nil {
+			return storage.Event{}, err
+		}
+		occurrence.ID, occurrence.Version = existing.ID, existing.Version
+		if err := a.checkOverlaps(ctx, occurrence, nil); err != nil {
+			return storage.Event{}, err
+		}
+		if err := a.storage.UpdateEvent(withAudit(ctx, storage.AuditUpdated, master.UserID, existing, occurrence), occurrence); err != nil {
+			return storage.Event{}, err
+		}
+		occurrence.Version++
+		return occurrence, nil
+	}
+	if occurrence.ID == "" {
+		occurrence.ID = uuid.New().String()
+	}
+	if err := a.checkOverlaps(ctx, occurrence, nil); err != nil {
+		return storage.Event{}, err
+	}
+	if err := a.storage.CreateEvent(withAudit(ctx, storage.AuditCreated, master.UserID, storage.Event{}, occurrence), occurrence); err != nil {
+		return storage.Event{}, err
+	}
+	occurrence.Version = storage.InitialVersion
+	return occurrence, nil
+}
+
+// CancelEventOccurrence отменяет один экземпляр повторяющегося события:
+// время экземпляра добавляется в EXDATE, а его изменённая копия (если есть) удаляется.
+func (a *App) CancelEventOccurrence(ctx context.Context, eventID string, recurrenceID int64) error {
+	master, err := a.occurrenceMaster(ctx, eventID, recurrenceID)
+	if err != nil {
+		return err
+	}
+	if existing, found, err := a.findOverride(ctx, master, recurrenceID); err != nil {
+		return err
+	} else if found {
+		auditCtx := withAudit(ctx, storage.AuditDeleted, master.UserID, existing, storage.Event{})
+		if err := a.storage.DeleteEvent(auditCtx, existing.ID, existing.Version); err != nil {
+			return err
+		}
+	}
+	if slices.Contains(master.ExDates, recurrenceID) {
+		return nil
+	}
+	updated := master
+	updated.ExDates = append(slices.Clone(master.ExDates), recurrenceID)
+	return a.storage.UpdateEvent(withAudit(ctx, storage.AuditUpdated, master.UserID, master, updated), updated)
+}
+
+// occurrenceMaster возвращает повторяющееся событие, если recurrenceID — один из его экземпляров.
+// Изменять экземпляры серии может только её владелец.
+func (a *App) occurrenceMaster(ctx context.Context, eventID string, recurrenceID int64) (storage.Event, error) {
+	master, err := a.storage.GetEvent(ctx, eventID)
+	if err != nil {
+		return storage.Event{}, err
+	}
+	if err := checkOwner(ctx, master); err != nil {
+		return storage.Event{}, err
+	}
+	if !master.IsRecurring() {
+		return storage.Event{}, fmt.Errorf("%w: event %s is not recurring", ErrNoOccurrence, eventID)
+	}
+	rule, err := recurrence.Parse(master.RRule)
+	if err != nil {
+		return storage.Event{}, fmt.Errorf("%w: %v", ErrInvalidRecurrence, err)
+	}
+	loc := seriesLocation(master)
+	if !rule.Contains(eventTime(master.StartTime, loc), eventTime(recurrenceID, loc)) {
+		return storage.Event{}, fmt.Errorf("%w: %s at %d", ErrNoOccurrence, eventID, recurrenceID)
+	}
+	return master, nil
+}
+
+// overrides возвращает изменённые экземпляры повторяющегося события.
+func (a *App) overrides(ctx context.Context, master storage.Event) ([]storage.Event, error) {
+	events, err := a.storage.ListEvents(ctx, master.UserID)
+	if err != nil {
+		return nil, err
+	}
+	var result []storage.Event
+	for _, ev := range events {
+		if ev.RecurringEventID == master.ID {
+			result = append(result, ev)
+		}
+	}
+	return result, nil
+}
+
+// findOverride ищет изменённый экземпляр серии с исходным временем начала recurrenceID.
+func (a *App) findOverride(ctx context.Context, master storage.Event, recurrenceID int64) (storage.Event, bool, error) {
+	overrides, err := a.overrides(ctx, master)
+	if err != nil {
+		return storage.Event{}, false, err
+	}
+	for _, o := range overrides {
+		if o.RecurrenceID == recurrenceID {
+			return o, true, nil
+		}
+	}
+	return storage.Event{}, false, nil
+}
+
+// prepareRecurrence проверяет правило повторения и часовой пояс серии, нормализует правило и вычисляет
+// окончание серии. Серия без часового пояса разворачивается в поясе владельца defaultLoc.
+func prepareRecurrence(event *storage.Event, defaultLoc *time.Location) error {
+	if !event.IsRecurring() {
+		event.ExDates, event.RecurrenceEnd, event.TimeZone = nil, 0, ""
+		return nil
+	}
+	rule, err := recurrence.Parse(event.RRule)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidRecurrence, err)
+	}
+	if event.TimeZone == "" {
+		event.TimeZone = defaultLoc.String()
+	}
+	loc, err := loadLocation(event.TimeZone)
+	if err != nil {
+		return fmt.Errorf("%w: unknown time zone %q", ErrInvalidRecurrence, event.TimeZone)
+	}
+	event.RRule = rule.String()
+	event.RecurrenceEnd = 0
+	if last, ok := rule.Last(eventTime(event.StartTime, loc)); ok {
+		event.RecurrenceEnd = last.Unix() + event.EndTime - event.StartTime
+	}
+	return nil
+}
+
+// expandEvents разворачивает повторяющиеся события в экземпляры и оставляет только
+// события, пересекающиеся с [start, end). Экземпляры из EXDATE пропускаются,
+// изменённые экземпляры заменяют исходные. Результат отсортирован по времени начала.
+func expandEvents(events []storage.Event, start, end int64) []storage.Event {
+	overridden := make(map[occurrenceKey]bool)
+	for _, ev := range events {
+		if ev.RecurringEventID != "" {
+			overridden[occurrenceKey{ev.RecurringEventID, ev.RecurrenceID}] = true
+		}
+	}
+
+	var result []storage.Event
+	for _, ev := range events {
+		if !ev.IsRecurring() {
+			if inRange(ev.StartTime, ev.EndTime, start, end) {
+				result = append(result, ev)
+			}
+			continue
+		}
+		// Экземпляр пересекается с диапазоном, если начинается не раньше start - duration + 1
+		from := start
+		if duration := ev.EndTime - ev.StartTime; duration > 0 {
+			from = start - duration + 1
+		}
+		for _, occ := range occurrences(ev, from, end) {
+			if !overridden[occurrenceKey{ev.ID, occ.RecurrenceID}] {
+				result = append(result, occ)
+			}
+		}
+	}
+	sortEvents(result)
+	return result
+}
+
+// sortEvents упорядочивает события по времени начала, затем по ID.
+func sortEvents(events []storage.Event) {
+	sort.SliceStable(events, func(i, j int) bool {
+		if events[i].StartTime != events[j].StartTime {
+			return events[i].StartTime < events[j].StartTime
+		}
+		return events[i].ID < events[j].ID
+	})
+}
+
+// occurrences возвращает экземпляры повторяющегося события, начинающиеся в [start, end), без учёта EXDATE.
+// Некорректное правило не разворачивается.
+func occurrences(ev storage.Event, start, end int64) []storage.Event {
+	rule, err := recurrence.Parse(ev.RRule)
+	if err != nil {
+		return nil
+	}
+	duration := ev.EndTime - ev.StartTime
+	loc := seriesLocation(ev)
+	var result []storage.Event
+	for _, t := range rule.Between(eventTime(ev.StartTime, loc), eventTime(start, loc), eventTime(end, loc)) {
+		occStart := t.Unix()
+		if slices.Contains(ev.ExDates, occStart) {
+			continue
+		}
+		occ := ev
+		occ.StartTime, occ.EndTime = occStart, occStart+duration
+		occ.RecurrenceID = occStart
+		result = append(result, occ)
+	}
+	return result
+}
+
+// notifiedOccurrences возвращает экземпляры серии, для которых сейчас наступило время хотя бы
+// одного напоминания: (start - offset) <= currentTime < start. Изменённые экземпляры пропускаются:
+// у них свои напоминания, и хранилище возвращает их как разовые события.
+func notifiedOccurrences(ev storage.Event, overrides []storage.Event, currentTime int64) []storage.Event {
+	offset := maxReminderOffset(ev)
+	if offset == 0 {
+		return nil
+	}
+	overridden := make(map[int64]bool, len(overrides))
+	for _, o := range overrides {
+		overridden[o.RecurrenceID] = true
+	}
+	var result []storage.Event
+	for _, occ := range occurrences(ev, currentTime+1, currentTime+offset+1) {
+		if !overridden[occ.RecurrenceID] {
+			result = append(result, occ)
+		}
+	}
+	return result
+}
+
+// inRange сообщает, пересекается ли [startTime, endTime) с диапазоном [start, end).
+// Событие нулевой длительности попадает в диапазон, если начинается внутри него.
+func inRange(startTime, endTime, start, end int64) bool {
+	if startTime == endTime {
+		return startTime >= start && startTime < end
+	}
+	return startTime < end && endTime > start
+}
+
+// eventTime переводит Unix timestamp во время в часовом поясе серии loc: в нём правило определяет
+// дни и время суток экземпляров.
+func eventTime(ts int64, loc *time.Location) time.Time {
+	return time.Unix(ts, 0).In(loc)
+}
+
+// locations — загруженные часовые пояса серий, ключ — имя IANA.
+var locations sync.Map
+
+// loadLocation возвращает часовой пояс по имени IANA, загружая его из базы часовых поясов один раз.
+func loadLocation(name string) (*time.Location, error) {
+	if loc, ok := locations.Load(name); ok {
+		return loc.(*time.Location), nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, err
+	}
+	locations.Store(name, loc)
+	return loc, nil
+}
+
+// seriesLocation возвращает часовой пояс, в котором разворачивается серия ev. Серии, созданные
+// до появления часовых поясов, и серии с неизвестным поясом разворачиваются в UTC.
+func seriesLocation(ev storage.Event) *time.Location {
+	if ev.TimeZone == "" {
+		return time.UTC
+	}
+	loc, err := loadLocation(ev.TimeZone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
@@ -0,0 +1,95 @@
+package app
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/storage"
+)
+
+// Ограничения напоминаний события.
+const (
+	MaxReminders     = 10 // максимальное число напоминаний у события
+	maxChannelLength = 32 // максимальная длина названия канала доставки
+)
+
+// normalizeReminders проверяет напоминания события и приводит их к каноническому виду:
+// каналы в нижнем регистре, повторы удалены, напоминания упорядочены от самого раннего
+// (наибольшее смещение) к самому позднему, затем по каналу.
+func normalizeReminders(reminders []storage.EventReminder) ([]storage.EventReminder, error) {
+	if len(reminders) == 0 {
+		return nil, nil
+	}
+	result := make([]storage.EventReminder, 0, len(reminders))
+	for _, r := range reminders {
+		r.Channel = strings.ToLower(strings.TrimSpace(r.Channel))
+		if r.Offset <= 0 {
+			return nil, fmt.Errorf("%w: reminder offset must be positive", ErrInvalidEvent)
+		}
+		if !validChannel(r.Channel) {
+			return nil, fmt.Errorf("%w: invalid reminder channel %q", ErrInvalidEvent, r.Channel)
+		}
+		result = append(result, r)
+	}
+	slices.SortFunc(result, func(a, b storage.EventReminder) int {
+		if c := cmp.Compare(b.Offset, a.Offset); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Channel, b.Channel)
+	})
+	result = slices.Compact(result)
+	if len(result) > MaxReminders {
+		return nil, fmt.Errorf("%w: too many reminders (max %d)", ErrInvalidEvent, MaxReminders)
+	}
+	return result, nil
+}
+
+// validChannel сообщает, что название канала состоит из латинских букв, цифр, '-' и '_'.
+func validChannel(channel string) bool {
+	if len(channel) > maxChannelLength {
+		return false
+	}
+	for _, c := range channel {
+		if (c < 'a' || c > 'z') && (c < '0' || c > '9') && c != '-' && c != '_' {
+			return false
+		}
+	}
+	return true
+}
+
+// maxReminderOffset возвращает наибольшее смещение напоминаний события, 0 — напоминаний нет.
+func maxReminderOffset(ev storage.Event) int64 {
+	var offset int64
+	for _, r := range ev.Reminders {
+		offset = max(offset, r.Offset)
+	}
+	return offset
+}
+
+// dueReminders возвращает напоминания о событии (или экземпляре серии), время отправки которых
+// наступило: (start - offset) <= currentTime < start. Каждое напоминание события проверяется отдельно
+// и адресуется владельцу и каждому участнику, принявшему приглашение.
+func dueReminders(ev storage.Event, currentTime int64) []storage.Reminder {
+	if currentTime >= ev.StartTime {
+		return nil
+	}
+	var result []storage.Reminder
+	for _, r := range ev.Reminders {
+		if ev.StartTime-r.Offset > currentTime {
+			continue
+		}
+		for _, userID := range recipients(ev) {
+			result = append(result, storage.Reminder{
+				EventID:      ev.ID,
+				UserID:       userID,
+				Title:        ev.Title,
+				EventTime:    ev.StartTime,
+				NotifyBefore: r.Offset,
+				Channel:      r.Channel,
+			})
+		}
+	}
+	return result
+}
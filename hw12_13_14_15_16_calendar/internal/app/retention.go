@@ -0,0 +1,84 @@
+package app
+
+import (
+	"cmp"
+	"context"
+
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/storage"
+)
+
+// ExpireEvents удаляет навсегда события, закончившиеся раньше beforeTime (повторяющиеся — по окончании серии
+// и всех её изменённых экземпляров), включая события в корзине, пачками по batchSize и возвращает их число.
+// Каждая пачка вместе с изменёнными экземплярами серий перед удалением передаётся archive (nil — без
+// архивирования); ошибка archive прерывает очистку, и пачка не удаляется.
+// Если dryRun, события только подсчитываются: archive не вызывается, ничего не удаляется.
+// Удаление в ленту изменений и журнал аудита не записывается.
+func (a *App) ExpireEvents(ctx context.Context, beforeTime int64, batchSize int, dryRun bool,
+	archive func([]storage.Event) error,
+) (int, error) {
+	return expire(ctx, batchSize, dryRun, archive,
+		func(afterID string) ([]storage.Event, error) {
+			return a.storage.ListExpiredEvents(ctx, beforeTime, afterID, batchSize)
+		},
+		// Изменённый экземпляр удаляется вместе с серией, поэтому курсором и ID для удаления служит ID серии
+		func(e storage.Event) string { return cmp.Or(e.RecurringEventID, e.ID) },
+		func(ids []string) (int, error) { return a.storage.DeleteExpiredEvents(ctx, beforeTime, ids) })
+}
+
+// ExpireNotifications удаляет записи о напоминаниях на экземпляры, начавшиеся раньше beforeTime, пачками
+// по batchSize и возвращает их число. archive и dryRun — как в ExpireEvents.
+func (a *App) ExpireNotifications(ctx context.Context, beforeTime int64, batchSize int, dryRun bool,
+	archive func([]storage.NotificationRecord) error,
+) (int, error) {
+	return expire(ctx, batchSize, dryRun, archive,
+		func(afterID string) ([]storage.NotificationRecord, error) {
+			return a.storage.ListExpiredNotifications(ctx, beforeTime, afterID, batchSize)
+		},
+		func(n storage.NotificationRecord) string { return n.ID },
+		func(ids []string) (int, error) { return a.storage.DeleteExpiredNotifications(ctx, beforeTime, ids) })
+}
+
+// DeleteOldChanges удаляет изменения из ленты изменений и отправленные сообщения outbox, записанные раньше beforeTime.
+// Подписчик, отставший больше чем на срок хранения, получит ErrInvalidRevision.
+func (a *App) DeleteOldChanges(ctx context.Context, beforeTime int64) error {
+	return a.storage.DeleteOldChanges(ctx, beforeTime)
+}
+
+// expire читает строки пачками через list (по возрастанию ID, начиная после afterID), передаёт каждую пачку
+// archive и удаляет её через remove. Возвращает число удалённых (при dryRun — найденных) строк.
+func expire[T any](ctx context.Context, batchSize int, dryRun bool, archive func([]T) error,
+	list func(afterID string) ([]T, error), id func(T) string, remove func(ids []string) (int, error),
+) (int, error) {
+	total := 0
+	afterID := ""
+	for ctx.Err() == nil {
+		rows, err := list(afterID)
+		if err != nil || len(rows) == 0 {
+			return total, err
+		}
+		ids := make([]string, len(rows))
+		for i, row := range rows {
+			ids[i] = id(row)
+		}
+		afterID = ids[len(ids)-1]
+		if dryRun {
+			total += len(rows)
+		} else {
+			if archive != nil {
+				if err := archive(rows); err != nil {
+					return total, err
+				}
+			}
+			n, err := remove(ids)
+			total += n
+			if err != nil {
+				return total, err
+			}
+		}
+		// Неполная пачка означает, что строк больше нет
+		if len(rows) < batchSize {
+			return total, nil
+		}
+	}
+	return total, ctx.Err()
+}
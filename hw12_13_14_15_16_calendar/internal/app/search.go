@@ -0,0 +1,40 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/storage"
+)
+
+// Число результатов SearchEvents.
+const (
+	DefaultSearchLimit = 20  // если limit не задан
+	MaxSearchLimit     = 100 // наибольший limit
+)
+
+// SearchEvents ищет события пользователя и события, в которых он участвует, по словам заголовка и описания.
+// Результаты упорядочены по убыванию релевантности; совпадения в заголовке и фрагменте описания выделены
+// метками storage.HighlightStart и storage.HighlightStop. Повторяющееся событие находится один раз,
+// без разворачивания в экземпляры.
+func (a *App) SearchEvents(ctx context.Context, userID string, query storage.SearchQuery) ([]storage.SearchResult, error) {
+	if err := checkUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	query.Text = strings.TrimSpace(query.Text)
+	switch query.Language {
+	case storage.SearchAnyLanguage, storage.SearchRussian, storage.SearchEnglish:
+	default:
+		return nil, fmt.Errorf("%w: unknown language %q", ErrInvalidSearch, query.Language)
+	}
+	switch {
+	case query.Text == "":
+		return nil, fmt.Errorf("%w: query is empty", ErrInvalidSearch)
+	case query.Limit == 0:
+		query.Limit = DefaultSearchLimit
+	case query.Limit < 0 || query.Limit > MaxSearchLimit:
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidSearch, MaxSearchLimit)
+	}
+	return a.storage.SearchEvents(ctx, userID, query)
+}
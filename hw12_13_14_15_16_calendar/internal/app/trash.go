@@ -0,0 +1,102 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/storage"
+)
+
+// ListTrash возвращает события пользователя в корзине (без изменённых экземпляров серий),
+// от удалённых последними к удалённым первыми.
+func (a *App) ListTrash(ctx context.Context, userID string) ([]storage.Event, error) {
+	if err := checkUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	return a.storage.ListTrash(ctx, userID)
+}
+
+// RestoreEvent возвращает событие из корзины вместе с изменёнными экземплярами серии и возвращает
+// восстановленное событие. Восстанавливать событие может только его владелец. Если за время
+// нахождения в корзине его время заняли другие события, возвращается ConflictError.
+func (a *App) RestoreEvent(ctx context.Context, id string) (storage.Event, error) {
+	trashed, err := a.trashedEvent(ctx, id)
+	if err != nil {
+		return storage.Event{}, err
+	}
+	// Изменённые экземпляры серии в корзине недоступны, поэтому с экземплярами серий сверяются
+	// только разовые события; пересечения разовых событий проверяет хранилище
+	if !trashed.IsRecurring() {
+		if err := a.checkOverlaps(ctx, trashed, nil); err != nil {
+			return storage.Event{}, err
+		}
+	}
+	if err := a.storage.RestoreEvent(withAudit(ctx, storage.AuditRestored, trashed.UserID, storage.Event{}, trashed), id); err != nil {
+		return storage.Event{}, err
+	}
+	return a.storage.GetEvent(ctx, id)
+}
+
+// PurgeEvent удаляет событие из корзины навсегда вместе с изменёнными экземплярами серии.
+// Удалять событие может только его владелец.
+func (a *App) PurgeEvent(ctx context.Context, id string) error {
+	trashed, err := a.trashedEvent(ctx, id)
+	if err != nil {
+		return err
+	}
+	return a.storage.PurgeEvent(withAudit(ctx, storage.AuditPurged, trashed.UserID, trashed, storage.Event{}), id)
+}
+
+// PurgeTrash удаляет навсегда события, перемещённые в корзину раньше beforeTime, и возвращает их число.
+// Очистка корзины по сроку хранения в журнал аудита не записывается.
+func (a *App) PurgeTrash(ctx context.Context, beforeTime int64) (int, error) {
+	return a.storage.PurgeTrash(ctx, beforeTime)
+}
+
+// trashedEvent возвращает событие из корзины, если вызывающая сторона — его владелец.
+// Изменённые экземпляры серий восстанавливаются и удаляются только вместе с серией, поэтому
+// для них возвращается ErrNotFound.
+func (a *App) trashedEvent(ctx context.Context, id string) (storage.Event, error) {
+	event, err := a.storage.GetTrashedEvent(ctx, id)
+	if err != nil {
+		return storage.Event{}, err
+	}
+	if err := checkOwner(ctx, event); err != nil {
+		return storage.Event{}, err
+	}
+	if event.RecurringEventID != "" {
+		return storage.Event{}, storage.NotFoundError(id)
+	}
+	return event, nil
+}
+
+// checkNotExists проверяет, что ID нового события не занят другим событием, в том числе чужим.
+func (a *App) checkNotExists(ctx context.Context, id string) error {
+	if id == "" {
+		return nil
+	}
+	_, err := a.storage.GetEvent(ctx, id)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return fmt.Errorf("%w: %s", ErrEventExists, id)
+}
+
+// checkNotTrashed проверяет, что ID нового события не занят событием в корзине.
+func (a *App) checkNotTrashed(ctx context.Context, id string) error {
+	if id == "" {
+		return nil
+	}
+	_, err := a.storage.GetTrashedEvent(ctx, id)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return fmt.Errorf("%w: %s", ErrEventInTrash, id)
+}
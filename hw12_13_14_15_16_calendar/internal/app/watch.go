@@ -0,0 +1,167 @@
+package app
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/storage"
+)
+
+// DefaultWatchInterval — интервал опроса ленты изменений, если хранилище не сообщает об изменениях само.
+const DefaultWatchInterval = time.Second
+
+// watchBatchSize — наибольшее число изменений, возвращаемых EventWatch.Next за один раз.
+const watchBatchSize = 100
+
+// EventChange — изменение события в календаре пользователя.
+type EventChange struct {
+	Type      storage.ChangeType // вид изменения
+	EventID   string             // ID события
+	Event     storage.Event      // событие после изменения; пусто для удалённого события
+	Revision  string             // токен ревизии: продолжить подписку после этого изменения
+	ChangedAt int64              // время изменения (Unix timestamp)
+}
+
+// ChangeNotifier — необязательный интерфейс хранилища, которое само сообщает о записи изменений в ленту
+// (например, memorystorage). Хранилища без него опрашиваются раз в интервал (см. WithWatchInterval).
+type ChangeNotifier interface {
+	Changed() <-chan struct{} // канал закрывается при записи следующего изменения
+}
+
+// WithWatchInterval задаёт интервал опроса ленты изменений в WatchEvents.
+func WithWatchInterval(d time.Duration) Option {
+	return func(a *App) {
+		if d > 0 {
+			a.watchInterval = d
+		}
+	}
+}
+
+// EventWatch — подписка на изменения событий пользователя (см. App.WatchEvents).
+type EventWatch struct {
+	app      *App
+	userID   string
+	after    int64          // ревизия последнего полученного изменения
+	notifier ChangeNotifier // nil, если хранилище не сообщает об изменениях
+}
+
+// WatchEvents подписывает на изменения событий пользователя: созданные, изменённые и удалённые события,
+// в том числе события, в которых он участвует. Пустой revision — только изменения после вызова;
+// revision из EventChange или EventWatch.Revision — продолжение после этой ревизии, например после переподключения.
+// Событие, из участников которого пользователь исключён, приходит ему как удалённое.
+func (a *App) WatchEvents(ctx context.Context, userID, revision string) (*EventWatch, error) {
+	if err := checkUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	after, err := decodeRevision(revision)
+	if err != nil {
+		return nil, err
+	}
+	if revision == "" {
+		if after, err = a.storage.LastChangeRevision(ctx, userID); err != nil {
+			return nil, err
+		}
+	}
+	notifier, _ := a.storage.(ChangeNotifier)
+	return &EventWatch{app: a, userID: userID, after: after, notifier: notifier}, nil
+}
+
+// Revision возвращает токен ревизии, после которой подписка продолжит чтение изменений.
+func (w *EventWatch) Revision() string {
+	return encodeRevision(w.after)
+}
+
+// Next ждёт очередные изменения и возвращает их в порядке записи, пока не отменён ctx.
+// Хранилище без ChangeNotifier опрашивается раз в интервал (см. WithWatchInterval).
+func (w *EventWatch) Next(ctx context.Context) ([]EventChange, error) {
+	for {
+		// Канал берётся до чтения ленты, чтобы не пропустить изменение, записанное между чтением и ожиданием
+		var changed <-chan struct{}
+		if w.notifier != nil {
+			changed = w.notifier.Changed()
+		}
+		changes, err := w.app.storage.ListChanges(ctx, w.userID, w.after, watchBatchSize)
+		if err != nil {
+			return nil, err
+		}
+		if len(changes) > 0 {
+			result := make([]EventChange, 0, len(changes))
+			for _, c := range changes {
+				change, err := w.app.eventChange(ctx, w.userID, c)
+				if err != nil {
+					return nil, err
+				}
+				result = append(result, change)
+			}
+			w.after = changes[len(changes)-1].Revision
+			return result, nil
+		}
+		timer := time.NewTimer(w.app.watchInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-changed:
+		case <-timer.C:
+		}
+		timer.Stop()
+	}
+}
+
+// eventChange дополняет запись ленты текущим состоянием события. Если событие уже удалено или
+// пользователь больше не владелец и не участник, изменение передаётся как удаление.
+func (a *App) eventChange(ctx context.Context, userID string, c storage.Change) (EventChange, error) {
+	change := EventChange{
+		Type:      c.Type,
+		EventID:   c.EventID,
+		Revision:  encodeRevision(c.Revision),
+		ChangedAt: c.ChangedAt,
+	}
+	if c.Type == storage.ChangeDeleted {
+		return change, nil
+	}
+	event, err := a.storage.GetEvent(ctx, c.EventID)
+	if errors.Is(err, storage.ErrNotFound) {
+		change.Type = storage.ChangeDeleted
+		return change, nil
+	}
+	if err != nil {
+		return EventChange{}, err
+	}
+	if _, invited := event.Attendee(userID); event.UserID != userID && !invited {
+		change.Type = storage.ChangeDeleted
+		return change, nil
+	}
+	change.Event = event
+	return change, nil
+}
+
+// encodeRevision формирует токен ревизии ленты изменений.
+func encodeRevision(revision int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte("rev:" + strconv.FormatInt(revision, 10)))
+}
+
+// decodeRevision разбирает токен ревизии; пустой токен — ревизия 0.
+func decodeRevision(token string) (int64, error) {
+	if token == "" {
+		return 0, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, fmt.Errorf("%w: malformed revision", ErrInvalidRevision)
+	}
+	value, ok := strings.CutPrefix(string(raw), "rev:")
+	if !ok {
+		return 0, fmt.Errorf("%w: malformed revision", ErrInvalidRevision)
+	}
+	revision, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || revision < 0 {
+		return 0, fmt.Errorf("%w: malformed revision", ErrInvalidRevision)
+	}
+	return revision, nil
+}
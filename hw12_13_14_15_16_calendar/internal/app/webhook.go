@@ -0,0 +1,89 @@
+package app
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/netguard"
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/storage"
+	"github.com/google/uuid"
+)
+
+// MaxWebhooksPerUser — наибольшее число адресов, которые может зарегистрировать пользователь.
+const MaxWebhooksPerUser = 10
+
+// webhookSecretPrefix — префикс ключа подписи, по которому его легко узнать в настройках получателя.
+const webhookSecretPrefix = "whsec_"
+
+// CreateWebhook регистрирует HTTP(S)-адрес, на который рассыльщик отправляет напоминания пользователя,
+// и возвращает его вместе с новым ключом подписи запросов. Регистрировать адрес может только сам пользователь.
+// Адреса внутренней сети, петли и локальные адреса канала (в том числе после разрешения имени) отклоняются.
+func (a *App) CreateWebhook(ctx context.Context, userID, rawURL string) (storage.Webhook, error) {
+	if err := checkSelf(ctx, userID); err != nil {
+		return storage.Webhook{}, err
+	}
+	if userID == "" {
+		return storage.Webhook{}, fmt.Errorf("%w: user_id is required", ErrInvalidWebhook)
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return storage.Webhook{}, fmt.Errorf("%w: url must be an absolute http or https URL", ErrInvalidWebhook)
+	}
+	if err := netguard.CheckHost(ctx, net.DefaultResolver, u.Hostname()); err != nil {
+		return storage.Webhook{}, fmt.Errorf("%w: %v", ErrInvalidWebhook, err)
+	}
+	existing, err := a.storage.ListWebhooks(ctx, userID)
+	if err != nil {
+		return storage.Webhook{}, err
+	}
+	if len(existing) >= MaxWebhooksPerUser {
+		return storage.Webhook{}, fmt.Errorf("%w: at most %d webhooks per user", ErrInvalidWebhook, MaxWebhooksPerUser)
+	}
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return storage.Webhook{}, fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	webhook := storage.Webhook{
+		ID:        uuid.New().String(),
+		UserID:    userID,
+		URL:       u.String(),
+		Secret:    webhookSecretPrefix + hex.EncodeToString(secret),
+		CreatedAt: time.Now().Unix(),
+	}
+	if err := a.storage.CreateWebhook(ctx, webhook); err != nil {
+		return storage.Webhook{}, err
+	}
+	return webhook, nil
+}
+
+// ListWebhooks возвращает адреса пользователя в порядке регистрации. Ключи подписи не возвращаются.
+func (a *App) ListWebhooks(ctx context.Context, userID string) ([]storage.Webhook, error) {
+	if err := checkUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	webhooks, err := a.storage.ListWebhooks(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range webhooks {
+		webhooks[i].Secret = ""
+	}
+	return webhooks, nil
+}
+
+// DeleteWebhook удаляет адрес. Удалять адрес может только пользователь, который его зарегистрировал.
+func (a *App) DeleteWebhook(ctx context.Context, id string) error {
+	webhook, err := a.storage.GetWebhook(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := checkUser(ctx, webhook.UserID); err != nil {
+		return err
+	}
+	return a.storage.DeleteWebhook(ctx, id)
+}
@@ -0,0 +1,72 @@
+// Package archive записывает удаляемые строки таблиц в сжатые файлы NDJSON (JSON по строке на запись, gzip).
+package archive
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Writer записывает пачки строк в каталог: каждая пачка — отдельный файл <table>-<время UTC>-<номер>.ndjson.gz.
+type Writer struct {
+	dir string
+	now func() time.Time
+
+	mu  sync.Mutex
+	seq int // номер последнего файла, записанного этим Writer
+}
+
+// New создаёт Writer, записывающий файлы в каталог dir, и создаёт каталог, если его нет.
+func New(dir string) (*Writer, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("create archive dir: %w", err)
+	}
+	return &Writer{dir: dir, now: time.Now}, nil
+}
+
+// Write записывает строки таблицы table в новый файл и возвращает его путь. Файл пишется под временным
+// именем и получает итоговое имя после записи на диск, поэтому после успешного Write строки можно удалять,
+// а файл с итоговым именем всегда полон.
+func Write[T any](w *Writer, table string, rows []T) (string, error) {
+	w.mu.Lock()
+	w.seq++
+	name := fmt.Sprintf("%s-%s-%06d.ndjson.gz", table, w.now().UTC().Format("20060102T150405Z"), w.seq)
+	w.mu.Unlock()
+
+	path := filepath.Join(w.dir, name)
+	tmp, err := os.CreateTemp(w.dir, name+".*.tmp")
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if err := writeRows(tmp, rows); err != nil {
+		_ = tmp.Close()
+		return "", fmt.Errorf("write archive %s: %w", name, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// writeRows записывает строки в файл в формате NDJSON со сжатием gzip и сбрасывает файл на диск.
+func writeRows[T any](f *os.File, rows []T) error {
+	zw := gzip.NewWriter(f)
+	enc := json.NewEncoder(zw)
+	for _, row := range rows {
+		if err := enc.Encode(row); err != nil {
+			return err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	return f.Sync()
+}
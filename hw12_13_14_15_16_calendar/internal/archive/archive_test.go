@@ -0,0 +1,63 @@
+package archive
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type row struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+}
+
+func TestWrite(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "archive")
+	w, err := New(dir)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	w.now = func() time.Time { return time.Date(2026, 10, 16, 12, 30, 0, 0, time.UTC) }
+
+	rows := []row{{ID: "1", Title: "Standup"}, {ID: "2", Title: "Планёрка"}}
+	path, err := Write(w, "events", rows)
+	if err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if filepath.Base(path) != "events-20261016T123000Z-000001.ndjson.gz" {
+		t.Fatalf("unexpected file name: %s", path)
+	}
+	// Следующая пачка в ту же секунду пишется в другой файл, временных файлов не остаётся
+	if next, err := Write(w, "events", rows[:1]); err != nil || next == path {
+		t.Fatalf("second batch must get its own file: %s, err=%v", next, err)
+	}
+	if files, _ := os.ReadDir(dir); len(files) != 2 {
+		t.Fatalf("unexpected files in archive dir: %v", files)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("open archive: %v", err)
+	}
+	defer func() { _ = f.Close() }()
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatalf("gzip reader: %v", err)
+	}
+	var got []row
+	scanner := bufio.NewScanner(zr)
+	for scanner.Scan() {
+		var r row
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			t.Fatalf("line %q is not JSON: %v", scanner.Text(), err)
+		}
+		got = append(got, r)
+	}
+	if len(got) != 2 || got[0] != rows[0] || got[1] != rows[1] {
+		t.Fatalf("unexpected archived rows: %+v", got)
+	}
+}
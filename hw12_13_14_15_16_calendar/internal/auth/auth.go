@@ -0,0 +1,171 @@
+// Package auth содержит аутентификацию вызывающих сторон календаря: проверку JWT, подписанных
+// локально настроенным ключом, и статических API-ключей сервисов.
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+)
+
+// ErrUnauthenticated — ошибка, если учётные данные не переданы или не прошли проверку.
+var ErrUnauthenticated = errors.New("unauthenticated")
+
+// Identity — аутентифицированная вызывающая сторона.
+type Identity struct {
+	UserID  string // ID пользователя (claim sub токена); пусто для сервисов
+	Service string // имя сервиса, аутентифицированного API-ключом; пусто для пользователей
+}
+
+// IsService сообщает, что вызывающая сторона — сервис. Сервисы действуют от имени любого пользователя.
+func (id Identity) IsService() bool {
+	return id.Service != ""
+}
+
+// String возвращает имя вызывающей стороны для логов.
+func (id Identity) String() string {
+	if id.IsService() {
+		return "service:" + id.Service
+	}
+	return "user:" + id.UserID
+}
+
+// Anonymous — вызывающая сторона, если аутентификация выключена (nil Authenticator).
+// Как и сервис, действует от имени любого пользователя.
+var Anonymous = Identity{Service: "anonymous"}
+
+type identityKey struct{}
+
+// WithIdentity возвращает контекст с вызывающей стороной.
+func WithIdentity(ctx context.Context, id Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, id)
+}
+
+// FromContext возвращает вызывающую сторону из контекста. false — запрос не аутентифицирован;
+// проверки доступа такому запросу отказывают.
+func FromContext(ctx context.Context) (Identity, bool) {
+	id, ok := ctx.Value(identityKey{}).(Identity)
+	return id, ok
+}
+
+// Config — параметры аутентификации.
+type Config struct {
+	JWTKey   string            // ключ HMAC-SHA256 для проверки подписи JWT
+	Issuer   string            // ожидаемый claim iss (не проверяется, если пуст)
+	Audience string            // ожидаемый claim aud (не проверяется, если пуст)
+	APIKeys  map[string]string // API-ключи сервисов: ключ — имя сервиса, значение — API-ключ
+	Leeway   time.Duration     // допустимое расхождение часов при проверке exp и nbf
+}
+
+// Authenticator проверяет учётные данные вызывающих сторон.
+type Authenticator struct {
+	jwtKey   []byte
+	issuer   string
+	audience string
+	apiKeys  []apiKey
+	leeway   time.Duration
+	now      func() time.Time
+}
+
+// apiKey — API-ключ сервиса; хранится хэш, чтобы сравнение занимало постоянное время.
+type apiKey struct {
+	service string
+	hash    [sha256.Size]byte
+}
+
+// New создаёт Authenticator. Без ключа JWT принимаются только API-ключи.
+func New(cfg Config) (*Authenticator, error) {
+	a := &Authenticator{
+		jwtKey:   []byte(cfg.JWTKey),
+		issuer:   cfg.Issuer,
+		audience: cfg.Audience,
+		leeway:   cfg.Leeway,
+		now:      time.Now,
+	}
+	for service, key := range cfg.APIKeys {
+		if service == "" || key == "" {
+			return nil, errors.New("auth: empty service name or api key")
+		}
+		a.apiKeys = append(a.apiKeys, apiKey{service: service, hash: sha256.Sum256([]byte(key))})
+	}
+	if len(a.jwtKey) == 0 && len(a.apiKeys) == 0 {
+		return nil, errors.New("auth: neither jwt key nor api keys configured")
+	}
+	return a, nil
+}
+
+// AuthenticateToken проверяет JWT, переданный в заголовке Authorization: Bearer.
+func (a *Authenticator) AuthenticateToken(token string) (Identity, error) {
+	if len(a.jwtKey) == 0 {
+		return Identity{}, ErrUnauthenticated
+	}
+	userID, err := a.verifyJWT(token)
+	if err != nil {
+		return Identity{}, err
+	}
+	return Identity{UserID: userID}, nil
+}
+
+// AuthenticateAPIKey проверяет API-ключ сервиса.
+func (a *Authenticator) AuthenticateAPIKey(key string) (Identity, error) {
+	hash := sha256.Sum256([]byte(key))
+	service := ""
+	for _, k := range a.apiKeys {
+		if subtle.ConstantTimeCompare(hash[:], k.hash[:]) == 1 {
+			service = k.service
+		}
+	}
+	if service == "" {
+		return Identity{}, ErrUnauthenticated
+	}
+	return Identity{Service: service}, nil
+}
+
+// Authenticate проверяет учётные данные из заголовков запроса: authorization (Bearer JWT,
+// Basic с JWT или API-ключом в качестве пароля) или x-api-key. nil Authenticator (аутентификация выключена)
+// возвращает Anonymous.
+func (a *Authenticator) Authenticate(authorization, apiKey string) (Identity, error) {
+	if a == nil {
+		return Anonymous, nil
+	}
+	if apiKey != "" {
+		return a.AuthenticateAPIKey(apiKey)
+	}
+	scheme, credentials, _ := strings.Cut(authorization, " ")
+	credentials = strings.TrimSpace(credentials)
+	switch {
+	case strings.EqualFold(scheme, "Bearer") && credentials != "":
+		return a.AuthenticateToken(credentials)
+	case strings.EqualFold(scheme, "Basic") && credentials != "":
+		return a.authenticateBasic(credentials)
+	}
+	return Identity{}, ErrUnauthenticated
+}
+
+// authenticateBasic проверяет Basic-авторизацию, которую используют CalDAV-клиенты: пароль — JWT
+// или API-ключ. Для JWT имя пользователя должно совпадать с claim sub.
+func (a *Authenticator) authenticateBasic(credentials string) (Identity, error) {
+	decoded, err := base64.StdEncoding.DecodeString(credentials)
+	if err != nil {
+		return Identity{}, ErrUnauthenticated
+	}
+	user, password, ok := strings.Cut(string(decoded), ":")
+	if !ok || password == "" {
+		return Identity{}, ErrUnauthenticated
+	}
+	if id, err := a.AuthenticateAPIKey(password); err == nil {
+		return id, nil
+	}
+	id, err := a.AuthenticateToken(password)
+	if err != nil {
+		return Identity{}, err
+	}
+	if user != "" && user != id.UserID {
+		return Identity{}, ErrUnauthenticated
+	}
+	return id, nil
+}
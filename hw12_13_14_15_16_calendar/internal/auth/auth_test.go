@@ -0,0 +1,157 @@
+package auth
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/config"
+	"github.com/stretchr/testify/require"
+)
+
+var testKey = []byte("test-secret")
+
+func newTestAuthenticator(t *testing.T, now time.Time) *Authenticator {
+	t.Helper()
+	a, err := New(Config{
+		JWTKey:   string(testKey),
+		Issuer:   "calendar-auth",
+		Audience: "calendar",
+		APIKeys:  map[string]string{"scheduler": "scheduler-key"},
+		Leeway:   time.Minute,
+	})
+	require.NoError(t, err)
+	a.now = func() time.Time { return now }
+	return a
+}
+
+func signTestToken(t *testing.T, key []byte, claims map[string]any) string {
+	t.Helper()
+	token, err := SignToken(key, claims)
+	require.NoError(t, err)
+	return token
+}
+
+func TestAuthenticateToken(t *testing.T) {
+	now := time.Date(2024, 7, 19, 10, 0, 0, 0, time.UTC)
+	a := newTestAuthenticator(t, now)
+	valid := map[string]any{
+		"sub": "user1",
+		"iss": "calendar-auth",
+		"aud": []string{"other", "calendar"},
+		"exp": now.Add(time.Hour).Unix(),
+	}
+
+	id, err := a.AuthenticateToken(signTestToken(t, testKey, valid))
+	require.NoError(t, err)
+	require.Equal(t, Identity{UserID: "user1"}, id)
+	require.False(t, id.IsService())
+
+	id, err = a.Authenticate("Bearer "+signTestToken(t, testKey, valid), "")
+	require.NoError(t, err)
+	require.Equal(t, "user1", id.UserID)
+
+	with := func(key string, value any) map[string]any {
+		claims := make(map[string]any, len(valid))
+		for k, v := range valid {
+			claims[k] = v
+		}
+		if value == nil {
+			delete(claims, key)
+		} else {
+			claims[key] = value
+		}
+		return claims
+	}
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"wrong key", signTestToken(t, []byte("other"), valid)},
+		{"expired", signTestToken(t, testKey, with("exp", now.Add(-2*time.Minute).Unix()))},
+		{"no expiration", signTestToken(t, testKey, with("exp", nil))},
+		{"not yet valid", signTestToken(t, testKey, with("nbf", now.Add(2*time.Minute).Unix()))},
+		{"no subject", signTestToken(t, testKey, with("sub", nil))},
+		{"wrong issuer", signTestToken(t, testKey, with("iss", "someone"))},
+		{"wrong audience", signTestToken(t, testKey, with("aud", "other"))},
+		{"malformed", "not-a-token"},
+		{"alg none", base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none"}`)) + "." +
+			base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"user1","exp":9999999999}`)) + "."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := a.AuthenticateToken(tt.token)
+			require.ErrorIs(t, err, ErrUnauthenticated)
+		})
+	}
+
+	// Расхождение часов в пределах leeway допускается
+	_, err = a.AuthenticateToken(signTestToken(t, testKey, with("exp", now.Add(-30*time.Second).Unix())))
+	require.NoError(t, err)
+}
+
+func TestAuthenticateAPIKey(t *testing.T) {
+	a := newTestAuthenticator(t, time.Now())
+
+	id, err := a.Authenticate("", "scheduler-key")
+	require.NoError(t, err)
+	require.Equal(t, Identity{Service: "scheduler"}, id)
+	require.True(t, id.IsService())
+
+	_, err = a.Authenticate("", "wrong-key")
+	require.ErrorIs(t, err, ErrUnauthenticated)
+	_, err = a.Authenticate("", "")
+	require.ErrorIs(t, err, ErrUnauthenticated)
+	_, err = a.Authenticate("Bearer", "")
+	require.ErrorIs(t, err, ErrUnauthenticated)
+}
+
+func TestAuthenticateBasic(t *testing.T) {
+	now := time.Now()
+	a := newTestAuthenticator(t, now)
+	token := signTestToken(t, testKey, map[string]any{
+		"sub": "user1", "iss": "calendar-auth", "aud": "calendar", "exp": now.Add(time.Hour).Unix(),
+	})
+	basic := func(user, password string) string {
+		return "Basic " + base64.StdEncoding.EncodeToString([]byte(user+":"+password))
+	}
+
+	id, err := a.Authenticate(basic("user1", token), "")
+	require.NoError(t, err)
+	require.Equal(t, "user1", id.UserID)
+
+	id, err = a.Authenticate(basic("scheduler", "scheduler-key"), "")
+	require.NoError(t, err)
+	require.Equal(t, "scheduler", id.Service)
+
+	_, err = a.Authenticate(basic("user2", token), "")
+	require.ErrorIs(t, err, ErrUnauthenticated)
+	_, err = a.Authenticate(basic("user1", "wrong"), "")
+	require.ErrorIs(t, err, ErrUnauthenticated)
+}
+
+func TestNewRequiresCredentials(t *testing.T) {
+	_, err := New(Config{})
+	require.Error(t, err)
+	_, err = New(Config{APIKeys: map[string]string{"scheduler": ""}})
+	require.Error(t, err)
+}
+
+func TestFromConfig(t *testing.T) {
+	// Выключенная аутентификация: каждая вызывающая сторона — Anonymous
+	a, err := FromConfig(config.AuthConf{Enabled: false})
+	require.NoError(t, err)
+	require.Nil(t, a)
+	id, err := a.Authenticate("", "")
+	require.NoError(t, err)
+	require.Equal(t, Anonymous, id)
+	require.True(t, id.IsService())
+
+	_, err = FromConfig(config.AuthConf{Enabled: true})
+	require.Error(t, err, "enabled auth requires credentials")
+
+	a, err = FromConfig(config.AuthConf{Enabled: true, APIKeys: map[string]string{"scheduler": "scheduler-key"}})
+	require.NoError(t, err)
+	_, err = a.Authenticate("", "")
+	require.ErrorIs(t, err, ErrUnauthenticated)
+}
@@ -0,0 +1,22 @@
+package auth
+
+import (
+	"time"
+
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/config"
+)
+
+// FromConfig создаёт проверку учётных данных из секции auth конфига.
+// Если аутентификация выключена, возвращает nil: такой Authenticator считает каждую вызывающую сторону Anonymous.
+func FromConfig(conf config.AuthConf) (*Authenticator, error) {
+	if !conf.Enabled {
+		return nil, nil
+	}
+	return New(Config{
+		JWTKey:   conf.JWTKey,
+		Issuer:   conf.JWTIssuer,
+		Audience: conf.JWTAudience,
+		APIKeys:  conf.APIKeys,
+		Leeway:   time.Duration(conf.LeewaySeconds) * time.Second,
+	})
+}
@@ -0,0 +1,114 @@
+package auth
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// jwtHeader — заголовок JWT.
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ"`
+}
+
+// jwtClaims — проверяемые claims JWT.
+type jwtClaims struct {
+	Subject   string   `json:"sub"`
+	Issuer    string   `json:"iss"`
+	Audience  audience `json:"aud"`
+	ExpiresAt *int64   `json:"exp"`
+	NotBefore *int64   `json:"nbf"`
+}
+
+// audience — claim aud: строка или массив строк (RFC 7519, раздел 4.1.3).
+type audience []string
+
+// UnmarshalJSON разбирает aud в виде строки или массива строк.
+func (a *audience) UnmarshalJSON(data []byte) error {
+	if bytes.HasPrefix(data, []byte(`"`)) {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		*a = audience{s}
+		return nil
+	}
+	return json.Unmarshal(data, (*[]string)(a))
+}
+
+// verifyJWT проверяет подпись HS256 и срок действия токена и возвращает claim sub.
+// Токены с другими алгоритмами (в том числе "none") отклоняются.
+func (a *Authenticator) verifyJWT(token string) (string, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", fmt.Errorf("%w: malformed token", ErrUnauthenticated)
+	}
+	var header jwtHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return "", fmt.Errorf("%w: malformed token header", ErrUnauthenticated)
+	}
+	if header.Alg != "HS256" {
+		return "", fmt.Errorf("%w: unsupported token algorithm %q", ErrUnauthenticated, header.Alg)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || !hmac.Equal(signature, sign(a.jwtKey, parts[0]+"."+parts[1])) {
+		return "", fmt.Errorf("%w: invalid token signature", ErrUnauthenticated)
+	}
+	var claims jwtClaims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return "", fmt.Errorf("%w: malformed token claims", ErrUnauthenticated)
+	}
+	now := a.now()
+	switch {
+	case claims.Subject == "":
+		return "", fmt.Errorf("%w: token has no subject", ErrUnauthenticated)
+	case claims.ExpiresAt == nil:
+		return "", fmt.Errorf("%w: token has no expiration time", ErrUnauthenticated)
+	case now.After(time.Unix(*claims.ExpiresAt, 0).Add(a.leeway)):
+		return "", fmt.Errorf("%w: token expired", ErrUnauthenticated)
+	case claims.NotBefore != nil && now.Before(time.Unix(*claims.NotBefore, 0).Add(-a.leeway)):
+		return "", fmt.Errorf("%w: token not valid yet", ErrUnauthenticated)
+	case a.issuer != "" && claims.Issuer != a.issuer:
+		return "", fmt.Errorf("%w: unexpected token issuer", ErrUnauthenticated)
+	case a.audience != "" && !slices.Contains(claims.Audience, a.audience):
+		return "", fmt.Errorf("%w: unexpected token audience", ErrUnauthenticated)
+	}
+	return claims.Subject, nil
+}
+
+// SignToken подписывает claims ключом HS256. Используется для выпуска токенов в тестах и утилитах.
+func SignToken(key []byte, claims map[string]any) (string, error) {
+	header, err := json.Marshal(jwtHeader{Alg: "HS256", Typ: "JWT"})
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(sign(key, unsigned)), nil
+}
+
+// sign вычисляет подпись HMAC-SHA256.
+func sign(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// decodeSegment декодирует base64url-сегмент токена как JSON.
+func decodeSegment(segment string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
@@ -15,6 +15,9 @@ type Config struct {
 	DB        DBConf        `yaml:"db"`                  // параметры БД
 	RabbitMQ  RabbitMQConf  `yaml:"rabbitmq,omitempty"`  // параметры RabbitMQ
 	Scheduler SchedulerConf `yaml:"scheduler,omitempty"` // параметры планировщика
+	Sender    SenderConf    `yaml:"sender,omitempty"`    // параметры рассыльщика
+	Calendar  CalendarConf  `yaml:"calendar,omitempty"`  // параметры календарных периодов
+	Auth      AuthConf      `yaml:"auth,omitempty"`      // параметры аутентификации
 }
 
 // LoggerConf содержит параметры логирования.
@@ -44,22 +47,117 @@ type DBConf struct {
 
 // RabbitMQConf содержит параметры подключения к RabbitMQ.
 type RabbitMQConf struct {
-	Host     string `yaml:"host"`     // адрес RabbitMQ
-	Port     int    `yaml:"port"`     // порт RabbitMQ
-	User     string `yaml:"user"`     // пользователь
-	Password string `yaml:"password"` // пароль
-	VHost    string `yaml:"vhost"`    // виртуальный хост
-	Queue    string `yaml:"queue"`    // имя очереди
+	Host     string    `yaml:"host"`     // адрес RabbitMQ
+	Port     int       `yaml:"port"`     // порт RabbitMQ
+	User     string    `yaml:"user"`     // пользователь
+	Password string    `yaml:"password"` // пароль
+	VHost    string    `yaml:"vhost"`    // виртуальный хост
+	Queue    string    `yaml:"queue"`    // имя очереди
+	Retry    RetryConf `yaml:"retry"`    // повторы обработки сообщений
+}
+
+// RetryConf содержит параметры повторной обработки сообщений, которые рассыльщик не смог обработать.
+// Параметры должны совпадать у планировщика и рассыльщика: оба объявляют очереди повтора.
+type RetryConf struct {
+	MaxRetries    int   `yaml:"max_retries"`    // число повторов до переноса в очередь недоставленных (5, если не задано; отрицательное — без повторов)
+	DelaysSeconds []int `yaml:"delays_seconds"` // паузы перед повторами в секундах, последняя — для всех следующих (10, 60, 300, если не заданы)
 }
 
 // SchedulerConf содержит параметры планировщика.
 type SchedulerConf struct {
-	IntervalSeconds int `yaml:"interval_seconds"` // интервал проверки событий в секундах
+	IntervalSeconds      int           `yaml:"interval_seconds"`       // интервал проверки событий в секундах
+	RelayIntervalSeconds int           `yaml:"relay_interval_seconds"` // интервал публикации сообщений outbox в секундах
+	RelayBatchSize       int           `yaml:"relay_batch_size"`       // число сообщений outbox, публикуемых за один запуск
+	TrashRetentionDays   int           `yaml:"trash_retention_days"`   // сколько дней удалённые события хранятся в корзине (30, если не задано)
+	Retention            RetentionConf `yaml:"retention"`              // удаление и архивирование старых событий и уведомлений
+	Leader               LeaderConf    `yaml:"leader"`                 // выбор лидера среди реплик планировщика
+	HealthAddr           string        `yaml:"health_addr"`            // адрес HTTP-сервера проверок состояния; пусто — не запускать
+}
+
+// LeaderConf содержит параметры выбора лидера: работу планировщика выполняет только реплика,
+// держащая аренду в базе данных.
+type LeaderConf struct {
+	LeaseSeconds int `yaml:"lease_seconds"` // срок аренды в секундах (15, если не задан)
+	RenewSeconds int `yaml:"renew_seconds"` // интервал продления аренды в секундах (треть срока, если не задан)
+}
+
+// RetentionConf содержит параметры удаления старых событий и записей о напоминаниях.
+// Сроки хранения в днях: 0 — значение по умолчанию, отрицательное значение — хранить бессрочно.
+type RetentionConf struct {
+	IntervalSeconds   int    `yaml:"interval_seconds"`   // интервал очистки в секундах (3600, если не задан)
+	EventsDays        int    `yaml:"events_days"`        // сколько дней хранятся прошедшие события (365, если не задано)
+	NotificationsDays int    `yaml:"notifications_days"` // сколько дней хранятся записи о напоминаниях (365, если не задано)
+	BatchSize         int    `yaml:"batch_size"`         // число строк, архивируемых и удаляемых за раз (1000, если не задано)
+	DryRun            bool   `yaml:"dry_run"`            // только подсчитывать и логировать, что было бы удалено
+	ArchiveDir        string `yaml:"archive_dir"`        // каталог архивов .ndjson.gz; пусто — удалять без архивирования
+}
+
+// CalendarConf содержит параметры вычисления границ дня, недели и месяца и подписки на изменения.
+type CalendarConf struct {
+	TimeZone             string            `yaml:"time_zone"`              // часовой пояс IANA по умолчанию (UTC, если не задан)
+	WeekStart            string            `yaml:"week_start"`             // первый день недели (monday, если не задан)
+	UserTimeZones        map[string]string `yaml:"user_time_zones"`        // часовые пояса пользователей, ключ — ID пользователя
+	WatchIntervalSeconds int               `yaml:"watch_interval_seconds"` // интервал опроса ленты изменений для WatchEvents (1, если не задан)
+}
+
+// AuthConf содержит параметры аутентификации REST API, gRPC и CalDAV.
+type AuthConf struct {
+	Enabled       bool              `yaml:"enabled"`        // требовать аутентификацию (true, если не задано)
+	JWTKey        string            `yaml:"jwt_key"`        // ключ HMAC-SHA256 для проверки подписи JWT (HS256)
+	JWTIssuer     string            `yaml:"jwt_issuer"`     // ожидаемый claim iss (не проверяется, если пуст)
+	JWTAudience   string            `yaml:"jwt_audience"`   // ожидаемый claim aud (не проверяется, если пуст)
+	LeewaySeconds int               `yaml:"leeway_seconds"` // допустимое расхождение часов при проверке срока действия токена
+	APIKeys       map[string]string `yaml:"api_keys"`       // API-ключи сервисов, ключ — имя сервиса
+}
+
+// SenderConf содержит параметры рассыльщика: каналы доставки напоминаний.
+// Если каналы не заданы, напоминания выводятся в STDOUT.
+type SenderConf struct {
+	DefaultChannel      string                 `yaml:"default_channel"`       // канал для напоминаний без канала или с ненастроенным каналом
+	Channels            map[string]ChannelConf `yaml:"channels"`              // каналы доставки, ключ — имя канала из напоминания
+	ClaimTimeoutSeconds int                    `yaml:"claim_timeout_seconds"` // срок захвата напоминания на время отправки (300, если не задан)
+}
+
+// ChannelConf описывает канал доставки напоминаний; используется секция, соответствующая типу.
+type ChannelConf struct {
+	Type    string      `yaml:"type"`    // smtp, webhook или file
+	SMTP    SMTPConf    `yaml:"smtp"`    // параметры отправки электронной почты
+	Webhook WebhookConf `yaml:"webhook"` // параметры отправки на HTTP-адрес
+	File    FileConf    `yaml:"file"`    // параметры записи в файл
+}
+
+// SMTPConf содержит параметры отправки напоминаний по электронной почте.
+type SMTPConf struct {
+	Host           string            `yaml:"host"`            // адрес SMTP-сервера
+	Port           int               `yaml:"port"`            // порт SMTP-сервера (25, если не задан)
+	Username       string            `yaml:"username"`        // пользователь для AUTH PLAIN; пусто — без аутентификации
+	Password       string            `yaml:"password"`        // пароль
+	From           string            `yaml:"from"`            // адрес отправителя
+	Domain         string            `yaml:"domain"`          // домен адресов <ID пользователя>@domain для пользователей не из recipients
+	Recipients     map[string]string `yaml:"recipients"`      // адреса получателей, ключ — ID пользователя
+	TimeoutSeconds int               `yaml:"timeout_seconds"` // таймаут отправки письма в секундах (10, если не задан)
+}
+
+// WebhookConf содержит параметры отправки напоминаний на HTTP-адреса. Если url не задан, напоминание
+// отправляется на адреса, зарегистрированные получателем через API (нужна база данных).
+type WebhookConf struct {
+	URL                string            `yaml:"url"`                  // адрес, на который напоминание отправляется POST-запросом
+	Secret             string            `yaml:"secret"`               // ключ подписи запросов на url; пусто — без подписи
+	Headers            map[string]string `yaml:"headers"`              // дополнительные заголовки запросов на url (на адреса пользователей не отправляются)
+	TimeoutSeconds     int               `yaml:"timeout_seconds"`      // таймаут запроса в секундах (10, если не задан)
+	BreakerFailures    int               `yaml:"breaker_failures"`     // число неудач подряд, после которого адрес отключается (5, если не задано)
+	BreakerOpenSeconds int               `yaml:"breaker_open_seconds"` // на сколько секунд отключается адрес (60, если не задано)
+}
+
+// FileConf содержит параметры записи напоминаний в файл.
+type FileConf struct {
+	Path string `yaml:"path"` // путь к файлу, в конец которого дописываются напоминания; пусто — STDOUT
 }
 
 // NewConfigFromFile читает и парсит YAML-конфиг из файла.
+// Аутентификация включена, если конфиг не выключает её явно (auth.enabled: false).
 func NewConfigFromFile(path string) (Config, error) {
-	var cfg Config
+	cfg := Config{Auth: AuthConf{Enabled: true}}
 	f, err := os.Open(path)
 	if err != nil {
 		return cfg, err
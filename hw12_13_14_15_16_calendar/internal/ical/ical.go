@@ -0,0 +1,603 @@
+// Package ical реализует преобразование событий календаря в формат iCalendar (RFC 5545) и обратно.
+// Поддерживаются компоненты VEVENT с SUMMARY, DESCRIPTION, DTSTART/DTEND/DURATION, RRULE, EXDATE,
+// RECURRENCE-ID, ATTENDEE (только пользователи календаря) и вложенными VALARM
+// (каждый VALARM переводится в напоминание события).
+package ical
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/recurrence"
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/storage"
+)
+
+// ProdID — идентификатор продукта в экспортируемых календарях.
+const ProdID = "-//hw12_13_14_15_16_calendar//EventService//RU"
+
+const (
+	utcLayout   = "20060102T150405Z"
+	localLayout = "20060102T150405"
+	dateLayout  = "20060102"
+	maxLineLen  = 75 // максимальная длина строки в октетах без CRLF
+)
+
+// channelProp — нестандартное свойство VALARM с каналом доставки напоминания.
+const channelProp = "X-CALENDAR-CHANNEL"
+
+// attendeePrefix — префикс адреса участника (ATTENDEE), который является пользователем календаря.
+// Участники с другими адресами (например, mailto:) при разборе пропускаются.
+const attendeePrefix = "urn:x-calendar-user:"
+
+// ErrInvalidCalendar — ошибка, если данные не являются календарём iCalendar.
+var ErrInvalidCalendar = errors.New("invalid iCalendar data")
+
+// Entry — событие, разобранное из компонента VEVENT.
+type Entry struct {
+	UID          string        // UID события из календаря
+	Line         int           // номер строки BEGIN:VEVENT
+	Event        storage.Event // событие без ID и UserID
+	RecurrenceID int64         // для изменённого экземпляра серии: исходное время начала (Unix timestamp), иначе 0
+}
+
+// Issue описывает пропущенный при разборе компонент VEVENT.
+type Issue struct {
+	UID    string // UID события, если удалось прочитать
+	Line   int    // номер строки BEGIN:VEVENT
+	Reason string // причина пропуска
+}
+
+// Encode записывает события в w как календарь VCALENDAR.
+// Изменённые экземпляры серий выгружаются с UID повторяющегося события и RECURRENCE-ID.
+func Encode(w io.Writer, events []storage.Event) error {
+	bw := bufio.NewWriter(w)
+	stamp := time.Now().UTC().Format(utcLayout)
+	writeLine(bw, "BEGIN:VCALENDAR")
+	writeLine(bw, "VERSION:2.0")
+	writeLine(bw, "PRODID:"+ProdID)
+	writeLine(bw, "CALSCALE:GREGORIAN")
+	for _, e := range events {
+		writeLine(bw, "BEGIN:VEVENT")
+		uid := e.ID
+		if e.RecurringEventID != "" {
+			uid = e.RecurringEventID
+		}
+		writeLine(bw, "UID:"+uid)
+		writeLine(bw, "DTSTAMP:"+stamp)
+		zone := ""
+		if e.IsRecurring() {
+			zone = e.TimeZone
+		}
+		writeLine(bw, "DTSTART"+formatDateTime(e.StartTime, zone))
+		writeLine(bw, "DTEND"+formatDateTime(e.EndTime, zone))
+		if e.RecurringEventID != "" {
+			writeLine(bw, "RECURRENCE-ID:"+formatTime(e.RecurrenceID))
+		}
+		writeLine(bw, "SUMMARY:"+escapeText(e.Title))
+		if e.Description != "" {
+			writeLine(bw, "DESCRIPTION:"+escapeText(e.Description))
+		}
+		if e.IsRecurring() {
+			writeLine(bw, "RRULE:"+e.RRule)
+			if len(e.ExDates) > 0 {
+				exdates := make([]string, 0, len(e.ExDates))
+				for _, ts := range e.ExDates {
+					exdates = append(exdates, formatTime(ts))
+				}
+				writeLine(bw, "EXDATE:"+strings.Join(exdates, ","))
+			}
+		}
+		for _, a := range e.Attendees {
+			writeLine(bw, "ATTENDEE;PARTSTAT="+strings.ToUpper(string(a.Status))+":"+attendeePrefix+a.UserID)
+		}
+		for _, r := range e.Reminders {
+			writeLine(bw, "BEGIN:VALARM")
+			writeLine(bw, "ACTION:DISPLAY")
+			writeLine(bw, "DESCRIPTION:"+escapeText(e.Title))
+			writeLine(bw, "TRIGGER:"+formatDuration(-r.Offset))
+			if r.Channel != "" {
+				writeLine(bw, channelProp+":"+escapeText(r.Channel))
+			}
+			writeLine(bw, "END:VALARM")
+		}
+		writeLine(bw, "END:VEVENT")
+	}
+	writeLine(bw, "END:VCALENDAR")
+	return bw.Flush()
+}
+
+// writeLine записывает строку содержимого, перенося её по 75 октетов (line folding), с окончанием CRLF.
+func writeLine(w *bufio.Writer, line string) {
+	for len(line) > maxLineLen {
+		cut := maxLineLen
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		_, _ = w.WriteString(line[:cut] + "\r\n ")
+		line = line[cut:]
+	}
+	_, _ = w.WriteString(line + "\r\n")
+}
+
+// property — строка содержимого: имя, параметры и значение.
+type property struct {
+	name   string
+	params map[string]string
+	value  string
+	line   int
+}
+
+// component — разбираемый компонент VEVENT с вложенными VALARM.
+type component struct {
+	line   int
+	props  []property
+	alarms [][]property
+}
+
+// Decode разбирает календарь и возвращает события из компонентов VEVENT.
+// Компоненты, которые не удалось преобразовать в событие, пропускаются и возвращаются в списке Issue.
+// Ошибка возвращается, только если данные не являются календарём VCALENDAR.
+func Decode(r io.Reader) ([]Entry, []Issue, error) {
+	props, err := readProperties(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		entries []Entry
+		issues  []Issue
+		stack   []string
+		current *component
+		alarm   []property
+		seenCal bool
+	)
+	for _, p := range props {
+		switch p.name {
+		case "BEGIN":
+			name := strings.ToUpper(p.value)
+			if len(stack) == 0 {
+				if name != "VCALENDAR" {
+					return nil, nil, fmt.Errorf("%w: line %d: expected BEGIN:VCALENDAR", ErrInvalidCalendar, p.line)
+				}
+				seenCal = true
+			}
+			switch {
+			case name == "VEVENT" && len(stack) == 1:
+				current = &component{line: p.line}
+			case name == "VALARM" && current != nil:
+				alarm = []property{}
+			}
+			stack = append(stack, name)
+			continue
+		case "END":
+			name := strings.ToUpper(p.value)
+			if len(stack) == 0 || stack[len(stack)-1] != name {
+				return nil, nil, fmt.Errorf("%w: line %d: unexpected END:%s", ErrInvalidCalendar, p.line, p.value)
+			}
+			stack = stack[:len(stack)-1]
+			switch {
+			case name == "VALARM" && current != nil && alarm != nil:
+				current.alarms = append(current.alarms, alarm)
+				alarm = nil
+			case name == "VEVENT" && current != nil && len(stack) == 1:
+				entry, err := current.entry()
+				if err != nil {
+					issues = append(issues, Issue{UID: current.value("UID"), Line: current.line, Reason: err.Error()})
+				} else {
+					entries = append(entries, entry)
+				}
+				current = nil
+			}
+			continue
+		}
+		switch {
+		case alarm != nil:
+			alarm = append(alarm, p)
+		case current != nil && len(stack) == 2:
+			current.props = append(current.props, p)
+		}
+	}
+	if !seenCal || len(stack) != 0 {
+		return nil, nil, fmt.Errorf("%w: missing VCALENDAR", ErrInvalidCalendar)
+	}
+	return entries, issues, nil
+}
+
+// readProperties читает строки содержимого, объединяя перенесённые строки (unfolding).
+func readProperties(r io.Reader) ([]property, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	var (
+		props   []property
+		logical strings.Builder
+		start   int
+		lineNo  int
+	)
+	flush := func() error {
+		if logical.Len() == 0 {
+			return nil
+		}
+		p, err := parseProperty(logical.String())
+		if err != nil {
+			return fmt.Errorf("%w: line %d: %v", ErrInvalidCalendar, start, err)
+		}
+		p.line = start
+		props = append(props, p)
+		logical.Reset()
+		return nil
+	}
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+			logical.WriteString(line[1:])
+			continue
+		}
+		if err := flush(); err != nil {
+			return nil, err
+		}
+		if line != "" {
+			start = lineNo
+			logical.WriteString(line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	return props, nil
+}
+
+// parseProperty разбирает строку вида NAME;PARAM=VALUE;PARAM="V1","V2":VALUE.
+func parseProperty(line string) (property, error) {
+	p := property{params: make(map[string]string)}
+	inQuotes := false
+	colon := -1
+	for i, c := range line {
+		if c == '"' {
+			inQuotes = !inQuotes
+		}
+		if c == ':' && !inQuotes {
+			colon = i
+			break
+		}
+	}
+	if colon < 0 {
+		return p, fmt.Errorf("malformed content line %q", line)
+	}
+	p.value = line[colon+1:]
+	parts := splitUnquoted(line[:colon], ';')
+	p.name = strings.ToUpper(parts[0])
+	if p.name == "" {
+		return p, fmt.Errorf("malformed content line %q", line)
+	}
+	for _, param := range parts[1:] {
+		name, value, _ := strings.Cut(param, "=")
+		p.params[strings.ToUpper(name)] = strings.Trim(value, `"`)
+	}
+	return p, nil
+}
+
+// splitUnquoted разбивает s по разделителю sep вне двойных кавычек.
+func splitUnquoted(s string, sep rune) []string {
+	var parts []string
+	inQuotes := false
+	last := 0
+	for i, c := range s {
+		switch {
+		case c == '"':
+			inQuotes = !inQuotes
+		case c == sep && !inQuotes:
+			parts = append(parts, s[last:i])
+			last = i + 1
+		}
+	}
+	return append(parts, s[last:])
+}
+
+// value возвращает значение первого свойства с указанным именем.
+func (c *component) value(name string) string {
+	if p, ok := c.prop(name); ok {
+		return p.value
+	}
+	return ""
+}
+
+func (c *component) prop(name string) (property, bool) {
+	return findProp(c.props, name)
+}
+
+func findProp(props []property, name string) (property, bool) {
+	for _, p := range props {
+		if p.name == name {
+			return p, true
+		}
+	}
+	return property{}, false
+}
+
+// entry преобразует компонент VEVENT в событие.
+func (c *component) entry() (Entry, error) {
+	entry := Entry{UID: c.value("UID"), Line: c.line}
+	if strings.EqualFold(c.value("STATUS"), "CANCELLED") {
+		return entry, errors.New("event is cancelled")
+	}
+
+	dtstart, ok := c.prop("DTSTART")
+	if !ok {
+		return entry, errors.New("DTSTART is required")
+	}
+	start, allDay, err := parseDateTime(dtstart)
+	if err != nil {
+		return entry, fmt.Errorf("DTSTART: %w", err)
+	}
+	end := start
+	switch {
+	case c.value("DTEND") != "":
+		dtend, _ := c.prop("DTEND")
+		if end, _, err = parseDateTime(dtend); err != nil {
+			return entry, fmt.Errorf("DTEND: %w", err)
+		}
+	case c.value("DURATION") != "":
+		d, err := parseDuration(c.value("DURATION"))
+		if err != nil {
+			return entry, fmt.Errorf("DURATION: %w", err)
+		}
+		end = start.Add(time.Duration(d) * time.Second)
+	case allDay:
+		// Событие на весь день без DTEND длится один день
+		end = start.AddDate(0, 0, 1)
+	}
+	if end.Before(start) {
+		return entry, errors.New("DTEND is before DTSTART")
+	}
+
+	ev := storage.Event{
+		Title:       unescapeText(c.value("SUMMARY")),
+		Description: unescapeText(c.value("DESCRIPTION")),
+		StartTime:   start.Unix(),
+		EndTime:     end.Unix(),
+	}
+	if rrule := c.value("RRULE"); rrule != "" {
+		rule, err := recurrence.Parse(rrule)
+		if err != nil {
+			return entry, fmt.Errorf("RRULE: %w", err)
+		}
+		ev.RRule = rule.String()
+		// Серия разворачивается в часовом поясе DTSTART
+		ev.TimeZone = strings.TrimPrefix(dtstart.params["TZID"], "/")
+		for _, p := range c.props {
+			if p.name != "EXDATE" {
+				continue
+			}
+			for _, value := range strings.Split(p.value, ",") {
+				exdate, _, err := parseDateTime(property{params: p.params, value: value})
+				if err != nil {
+					return entry, fmt.Errorf("EXDATE: %w", err)
+				}
+				ev.ExDates = append(ev.ExDates, exdate.Unix())
+			}
+		}
+	}
+	if p, ok := c.prop("RECURRENCE-ID"); ok {
+		recurrenceID, _, err := parseDateTime(p)
+		if err != nil {
+			return entry, fmt.Errorf("RECURRENCE-ID: %w", err)
+		}
+		entry.RecurrenceID = recurrenceID.Unix()
+	}
+	for _, p := range c.props {
+		if p.name != "ATTENDEE" || !strings.HasPrefix(p.value, attendeePrefix) {
+			continue
+		}
+		status := storage.AttendeeStatus(strings.ToLower(p.params["PARTSTAT"]))
+		if !status.Valid() {
+			status = storage.AttendeeNeedsAction
+		}
+		ev.Attendees = append(ev.Attendees, storage.Attendee{UserID: strings.TrimPrefix(p.value, attendeePrefix), Status: status})
+	}
+	for _, alarm := range c.alarms {
+		if offset, ok := alarmOffset(alarm, start, end); ok {
+			reminder := storage.EventReminder{Offset: offset}
+			if p, ok := findProp(alarm, channelProp); ok {
+				reminder.Channel = unescapeText(p.value)
+			}
+			ev.Reminders = append(ev.Reminders, reminder)
+		}
+	}
+	entry.Event = ev
+	return entry, nil
+}
+
+// alarmOffset вычисляет, за сколько секунд до начала события срабатывает VALARM.
+// Напоминания в момент начала и после начала события не поддерживаются.
+func alarmOffset(alarm []property, start, end time.Time) (int64, bool) {
+	trigger, ok := findProp(alarm, "TRIGGER")
+	if !ok {
+		return 0, false
+	}
+	var at time.Time
+	if strings.EqualFold(trigger.params["VALUE"], "DATE-TIME") {
+		t, _, err := parseDateTime(trigger)
+		if err != nil {
+			return 0, false
+		}
+		at = t
+	} else {
+		d, err := parseDuration(trigger.value)
+		if err != nil {
+			return 0, false
+		}
+		base := start
+		if strings.EqualFold(trigger.params["RELATED"], "END") {
+			base = end
+		}
+		at = base.Add(time.Duration(d) * time.Second)
+	}
+	offset := int64(start.Sub(at) / time.Second)
+	if offset <= 0 {
+		return 0, false
+	}
+	return offset, true
+}
+
+// parseDateTime разбирает значение DATE или DATE-TIME с учётом параметров TZID и VALUE.
+// Время без часового пояса (floating) считается временем UTC. Второй результат сообщает, что значение — дата.
+func parseDateTime(p property) (time.Time, bool, error) {
+	loc := time.UTC
+	if tzid := p.params["TZID"]; tzid != "" {
+		var err error
+		if loc, err = time.LoadLocation(strings.TrimPrefix(tzid, "/")); err != nil {
+			return time.Time{}, false, fmt.Errorf("unknown time zone %q", tzid)
+		}
+	}
+	value := strings.TrimSpace(p.value)
+	if strings.EqualFold(p.params["VALUE"], "DATE") || len(value) == len(dateLayout) {
+		t, err := time.ParseInLocation(dateLayout, value, loc)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("invalid date %q", value)
+		}
+		return t, true, nil
+	}
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse(utcLayout, value)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("invalid date-time %q", value)
+		}
+		return t, false, nil
+	}
+	t, err := time.ParseInLocation(localLayout, value, loc)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid date-time %q", value)
+	}
+	return t, false, nil
+}
+
+// parseDuration разбирает длительность RFC 5545 ([+-]PnW или [+-]PnDTnHnMnS) в секунды.
+func parseDuration(s string) (int64, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	sign := int64(1)
+	switch {
+	case strings.HasPrefix(s, "-"):
+		sign, s = -1, s[1:]
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	}
+	if !strings.HasPrefix(s, "P") || len(s) < 3 {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	var total int64
+	inTime := false
+	num := ""
+	for _, c := range s[1:] {
+		switch {
+		case c >= '0' && c <= '9':
+			num += string(c)
+			continue
+		case c == 'T':
+			inTime = true
+			continue
+		}
+		n, err := strconv.ParseInt(num, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		num = ""
+		switch {
+		case c == 'W' && !inTime:
+			total += n * 7 * 24 * 3600
+		case c == 'D' && !inTime:
+			total += n * 24 * 3600
+		case c == 'H' && inTime:
+			total += n * 3600
+		case c == 'M' && inTime:
+			total += n * 60
+		case c == 'S' && inTime:
+			total += n
+		default:
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+	}
+	if num != "" {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	return sign * total, nil
+}
+
+// formatDuration форматирует длительность в секундах в виде RFC 5545, например -PT15M или P1DT2H.
+func formatDuration(seconds int64) string {
+	var b strings.Builder
+	if seconds < 0 {
+		b.WriteByte('-')
+		seconds = -seconds
+	}
+	b.WriteByte('P')
+	days := seconds / (24 * 3600)
+	if days > 0 {
+		fmt.Fprintf(&b, "%dD", days)
+		seconds %= 24 * 3600
+	}
+	if seconds > 0 || days == 0 {
+		b.WriteByte('T')
+		h, m, s := seconds/3600, seconds%3600/60, seconds%60
+		if h > 0 {
+			fmt.Fprintf(&b, "%dH", h)
+		}
+		if m > 0 {
+			fmt.Fprintf(&b, "%dM", m)
+		}
+		if s > 0 || (h == 0 && m == 0) {
+			fmt.Fprintf(&b, "%dS", s)
+		}
+	}
+	return b.String()
+}
+
+func formatTime(ts int64) string {
+	return time.Unix(ts, 0).UTC().Format(utcLayout)
+}
+
+// formatDateTime возвращает параметры и значение свойства DATE-TIME: время UTC или, если задан часовой
+// пояс zone, местное время с параметром TZID.
+func formatDateTime(ts int64, zone string) string {
+	if loc, err := time.LoadLocation(zone); zone != "" && err == nil {
+		return ";TZID=" + zone + ":" + time.Unix(ts, 0).In(loc).Format(localLayout)
+	}
+	return ":" + formatTime(ts)
+}
+
+// escapeText экранирует значение типа TEXT.
+func escapeText(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
+
+// unescapeText восстанавливает значение типа TEXT.
+func unescapeText(s string) string {
+	var b strings.Builder
+	escaped := false
+	for _, c := range s {
+		if !escaped {
+			if c == '\\' {
+				escaped = true
+			} else {
+				b.WriteRune(c)
+			}
+			continue
+		}
+		escaped = false
+		if c == 'n' || c == 'N' {
+			b.WriteByte('\n')
+		} else {
+			b.WriteRune(c)
+		}
+	}
+	return b.String()
+}
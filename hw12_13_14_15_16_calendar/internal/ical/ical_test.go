@@ -0,0 +1,199 @@
+package ical
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/storage"
+	"github.com/stretchr/testify/require"
+)
+
+// TestEncodeDecode проверяет, что экспортированный календарь разбирается обратно без потерь.
+func TestEncodeDecode(t *testing.T) {
+	reminders := []storage.EventReminder{{Offset: 24 * 3600, Channel: "email"}, {Offset: 15 * 60}}
+	start := time.Date(2024, 7, 1, 9, 0, 0, 0, time.UTC).Unix()
+	events := []storage.Event{
+		{
+			ID:          "b3b1c2e0-1234-4a5b-8c2d-1e2f3a4b5c6d",
+			Title:       "Планёрка; обсуждение, итоги",
+			Description: "Строка 1\nСтрока 2 — " + strings.Repeat("длинное описание ", 10),
+			StartTime:   start,
+			EndTime:     start + 1800,
+			Reminders:   reminders,
+			Attendees: []storage.Attendee{
+				{UserID: "user2", Status: storage.AttendeeAccepted},
+				{UserID: "user3", Status: storage.AttendeeNeedsAction},
+			},
+			RRule:    "FREQ=WEEKLY;COUNT=10;BYDAY=MO,WE",
+			TimeZone: "Europe/Moscow",
+			ExDates:  []int64{start + 7*24*3600},
+		},
+		{
+			ID:               "0f5c3c1a-3e9b-4f0e-9a43-5a2b1d1e2f3a",
+			Title:            "Перенесённая планёрка",
+			StartTime:        start + 2*24*3600 + 7200,
+			EndTime:          start + 2*24*3600 + 9000,
+			RecurringEventID: "b3b1c2e0-1234-4a5b-8c2d-1e2f3a4b5c6d",
+			RecurrenceID:     start + 2*24*3600,
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, Encode(&buf, events))
+	for _, line := range strings.Split(buf.String(), "\r\n") {
+		require.LessOrEqual(t, len(line), maxLineLen+1)
+	}
+	// Серия выгружается в своём часовом поясе, изменённый экземпляр — в UTC
+	require.Contains(t, buf.String(), "DTSTART;TZID=Europe/Moscow:20240701T120000\r\n")
+	require.Contains(t, buf.String(), "DTSTART:20240703T110000Z\r\n")
+
+	entries, issues, err := Decode(&buf)
+	require.NoError(t, err)
+	require.Empty(t, issues)
+	require.Len(t, entries, 2)
+
+	master := entries[0]
+	require.Equal(t, events[0].ID, master.UID)
+	require.Equal(t, events[0].Title, master.Event.Title)
+	require.Equal(t, events[0].Description, master.Event.Description)
+	require.Equal(t, events[0].StartTime, master.Event.StartTime)
+	require.Equal(t, events[0].EndTime, master.Event.EndTime)
+	require.Equal(t, events[0].RRule, master.Event.RRule)
+	require.Equal(t, events[0].TimeZone, master.Event.TimeZone)
+	require.Equal(t, events[0].ExDates, master.Event.ExDates)
+	require.Equal(t, reminders, master.Event.Reminders)
+	require.Equal(t, events[0].Attendees, master.Event.Attendees)
+	require.Zero(t, master.RecurrenceID)
+
+	override := entries[1]
+	require.Equal(t, events[0].ID, override.UID)
+	require.Equal(t, events[1].RecurrenceID, override.RecurrenceID)
+	require.Equal(t, events[1].StartTime, override.Event.StartTime)
+	require.Empty(t, override.Event.TimeZone)
+	require.Empty(t, override.Event.Reminders)
+}
+
+// TestDecodeForeignCalendar проверяет разбор календаря стороннего клиента и отчёт о пропущенных событиях.
+func TestDecodeForeignCalendar(t *testing.T) {
+	data := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//Google Inc//Google Calendar 70.9054//EN",
+		"BEGIN:VTIMEZONE",
+		"TZID:Europe/Moscow",
+		"BEGIN:STANDARD",
+		"DTSTART:19700101T000000",
+		"TZOFFSETFROM:+0300",
+		"TZOFFSETTO:+0300",
+		"END:STANDARD",
+		"END:VTIMEZONE",
+		"BEGIN:VEVENT",
+		"UID:meeting@example.com",
+		"DTSTART;TZID=Europe/Moscow:20240719T100000",
+		"DURATION:PT1H30M",
+		"SUMMARY:Встреча с \\,клиентом",
+		"DESCRIPTION:Обсудить договор\\nи сроки, длинная строка, которая переносится по пра",
+		" вилам RFC 5545",
+		"ATTENDEE;PARTSTAT=ACCEPTED;CN=Client:mailto:client@example.com",
+		"BEGIN:VALARM",
+		"ACTION:DISPLAY",
+		"TRIGGER;RELATED=START:-P1DT2H",
+		"END:VALARM",
+		"BEGIN:VALARM",
+		"ACTION:DISPLAY",
+		"TRIGGER:-PT10M",
+		"END:VALARM",
+		"BEGIN:VALARM",
+		"ACTION:DISPLAY",
+		"TRIGGER;RELATED=END:PT0S",
+		"END:VALARM",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:holiday@example.com",
+		"DTSTART;VALUE=DATE:20240801",
+		"SUMMARY:Отпуск",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:broken@example.com",
+		"SUMMARY:Без начала",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:bad-rule@example.com",
+		"DTSTART:20240719T100000Z",
+		"RRULE:FREQ=SECONDLY",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:cancelled@example.com",
+		"DTSTART:20240719T100000Z",
+		"STATUS:CANCELLED",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+
+	entries, issues, err := Decode(strings.NewReader(data))
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+
+	meeting := entries[0]
+	require.Equal(t, "meeting@example.com", meeting.UID)
+	require.Equal(t, "Встреча с ,клиентом", meeting.Event.Title)
+	require.Equal(t, "Обсудить договор\nи сроки, длинная строка, которая переносится по правилам RFC 5545",
+		meeting.Event.Description)
+	require.Equal(t, time.Date(2024, 7, 19, 7, 0, 0, 0, time.UTC).Unix(), meeting.Event.StartTime)
+	require.Equal(t, meeting.Event.StartTime+5400, meeting.Event.EndTime)
+	// Участники, не являющиеся пользователями календаря, пропускаются
+	require.Empty(t, meeting.Event.Attendees)
+	// Напоминание после начала события пропускается
+	require.Equal(t, []storage.EventReminder{{Offset: 26 * 3600}, {Offset: 600}}, meeting.Event.Reminders)
+
+	holiday := entries[1]
+	require.Equal(t, time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC).Unix(), holiday.Event.StartTime)
+	require.Equal(t, holiday.Event.StartTime+24*3600, holiday.Event.EndTime)
+
+	require.Len(t, issues, 3)
+	require.Equal(t, "broken@example.com", issues[0].UID)
+	require.Contains(t, issues[0].Reason, "DTSTART")
+	require.Equal(t, "bad-rule@example.com", issues[1].UID)
+	require.Contains(t, issues[1].Reason, "RRULE")
+	require.Equal(t, "cancelled@example.com", issues[2].UID)
+	require.Positive(t, issues[2].Line)
+}
+
+// TestDecodeInvalid проверяет, что данные без VCALENDAR отклоняются целиком.
+func TestDecodeInvalid(t *testing.T) {
+	for _, data := range []string{
+		"",
+		"hello world",
+		"BEGIN:VEVENT\r\nEND:VEVENT",
+		"BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nEND:VCALENDAR",
+	} {
+		_, _, err := Decode(strings.NewReader(data))
+		require.True(t, errors.Is(err, ErrInvalidCalendar), data)
+	}
+}
+
+// TestDuration проверяет разбор и форматирование длительностей.
+func TestDuration(t *testing.T) {
+	for s, want := range map[string]int64{
+		"PT15M":    900,
+		"-PT15M":   -900,
+		"P1W":      7 * 24 * 3600,
+		"-P1DT2H":  -26 * 3600,
+		"+PT1H30M": 5400,
+		"PT0S":     0,
+	} {
+		got, err := parseDuration(s)
+		require.NoError(t, err, s)
+		require.Equal(t, want, got, s)
+		back, err := parseDuration(formatDuration(got))
+		require.NoError(t, err, s)
+		require.Equal(t, want, back, s)
+	}
+	for _, bad := range []string{"15M", "PT", "P1H", "PT1D", "PT15"} {
+		_, err := parseDuration(bad)
+		require.Error(t, err, bad)
+	}
+}
@@ -67,7 +67,11 @@ func TestMain(m *testing.M) {
 		os.Exit(1)
 	}
 
-	testServer = grpc.NewServer()
+	// Аутентификация выключена: вызывающая сторона — auth.Anonymous
+	testServer = grpc.NewServer(
+		grpc.ChainUnaryInterceptor(grpcserver.UnaryAuthInterceptor(nil)),
+		grpc.ChainStreamInterceptor(grpcserver.StreamAuthInterceptor(nil)),
+	)
 	pb.RegisterEventServiceServer(testServer, grpcserver.NewServer(testApp))
 
 	go func() {
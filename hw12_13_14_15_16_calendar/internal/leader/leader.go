@@ -0,0 +1,158 @@
+// Package leader реализует выбор лидера среди реплик сервиса через аренду в общем хранилище.
+// Лидер периодически продлевает аренду; если он остановился или потерял связь с хранилищем,
+// после истечения аренды её захватывает другая реплика.
+package leader
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// LeaseStore — хранилище аренд лидерства.
+type LeaseStore interface {
+	AcquireLease(ctx context.Context, name, holder string, ttl time.Duration) (bool, error) // захватить или продлить аренду
+	ReleaseLease(ctx context.Context, name, holder string) error                            // освободить свою аренду
+}
+
+// Logger определяет интерфейс для логирования.
+type Logger interface {
+	Info(msg string)
+	Error(msg string)
+}
+
+// Status — состояние реплики в выборе лидера.
+type Status struct {
+	Name   string    // имя аренды
+	ID     string    // идентификатор реплики
+	Leader bool      // реплика — лидер
+	Since  time.Time // время получения лидерства; нулевое, если реплика не лидер
+}
+
+// Elector участвует в выборе лидера от имени реплики.
+type Elector struct {
+	store LeaseStore
+	logg  Logger
+	name  string        // имя аренды
+	id    string        // идентификатор реплики
+	ttl   time.Duration // срок аренды
+	renew time.Duration // интервал продления и попыток захвата
+	now   func() time.Time
+
+	mu         sync.Mutex
+	validUntil time.Time     // до какого времени реплика остаётся лидером без продления
+	since      time.Time     // время получения лидерства
+	elected    chan struct{} // получает значение при получении лидерства (см. Elected)
+}
+
+// New создаёт участника выбора лидера за аренду name с идентификатором реплики id.
+// Аренда берётся на ttl и продлевается каждые renew (ttl/3, если renew не задан или не меньше ttl).
+func New(store LeaseStore, logg Logger, name, id string, ttl, renew time.Duration) *Elector {
+	if renew <= 0 || renew >= ttl {
+		renew = ttl / 3
+	}
+	return &Elector{
+		store:   store,
+		logg:    logg,
+		name:    name,
+		id:      id,
+		ttl:     ttl,
+		renew:   renew,
+		now:     time.Now,
+		elected: make(chan struct{}, 1),
+	}
+}
+
+// IsLeader сообщает, является ли реплика лидером. Реплика, не продлившая аренду вовремя,
+// перестаёт считать себя лидером до истечения аренды в хранилище.
+func (e *Elector) IsLeader() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.now().Before(e.validUntil)
+}
+
+// Status возвращает состояние реплики в выборе лидера.
+func (e *Elector) Status() Status {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	status := Status{Name: e.name, ID: e.id, Leader: e.now().Before(e.validUntil)}
+	if status.Leader {
+		status.Since = e.since
+	}
+	return status
+}
+
+// Elected возвращает канал, получающий значение каждый раз, когда реплика становится лидером.
+func (e *Elector) Elected() <-chan struct{} {
+	return e.elected
+}
+
+// Campaign делает одну попытку захватить или продлить аренду и сообщает, является ли реплика лидером.
+// Получение и потеря лидерства логируются.
+func (e *Elector) Campaign(ctx context.Context) bool {
+	// Срок отсчитывается от начала запроса: аренда в хранилище истекает не раньше
+	start := e.now()
+	acquired, err := e.store.AcquireLease(ctx, e.name, e.id, e.ttl)
+	if err != nil {
+		e.logg.Error(fmt.Sprintf("failed to acquire leader lease %q: %v", e.name, err))
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	wasLeader := start.Before(e.validUntil)
+	switch {
+	case acquired:
+		e.validUntil = start.Add(e.ttl)
+		if !wasLeader {
+			e.since = start
+			e.logg.Info(fmt.Sprintf("replica %s became the leader (lease %q)", e.id, e.name))
+			select {
+			case e.elected <- struct{}{}:
+			default:
+			}
+		}
+	case err == nil:
+		// Аренду держит другая реплика
+		e.validUntil = time.Time{}
+		if wasLeader {
+			e.logg.Info(fmt.Sprintf("replica %s lost the leadership (lease %q)", e.id, e.name))
+		}
+	case wasLeader && !e.now().Before(e.validUntil):
+		// Продлить аренду не удалось, и её срок истёк
+		e.logg.Info(fmt.Sprintf("replica %s lost the leadership: lease %q expired", e.id, e.name))
+	}
+	return e.now().Before(e.validUntil)
+}
+
+// Run продлевает или пытается захватить аренду каждые renew до отмены ctx, затем освобождает аренду,
+// чтобы другая реплика стала лидером, не дожидаясь её истечения.
+func (e *Elector) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.renew)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			e.resign()
+			return
+		case <-ticker.C:
+			e.Campaign(ctx)
+		}
+	}
+}
+
+// resign освобождает аренду, если реплика — лидер.
+func (e *Elector) resign() {
+	if !e.IsLeader() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), e.renew)
+	defer cancel()
+	if err := e.store.ReleaseLease(ctx, e.name, e.id); err != nil {
+		e.logg.Error(fmt.Sprintf("failed to release leader lease %q: %v", e.name, err))
+	}
+	e.mu.Lock()
+	e.validUntil = time.Time{}
+	e.mu.Unlock()
+	e.logg.Info(fmt.Sprintf("replica %s resigned the leadership (lease %q)", e.id, e.name))
+}
@@ -0,0 +1,112 @@
+package leader
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/logger"
+	memorystorage "github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/storage/memory"
+)
+
+// failingStore — хранилище аренд, к которому нет доступа.
+type failingStore struct{}
+
+func (failingStore) AcquireLease(context.Context, string, string, time.Duration) (bool, error) {
+	return false, errors.New("database is unavailable")
+}
+
+func (failingStore) ReleaseLease(context.Context, string, string) error {
+	return errors.New("database is unavailable")
+}
+
+func TestElectorFailover(t *testing.T) {
+	store := memorystorage.New()
+	logg := logger.New("ERROR")
+	ctx := context.Background()
+	a := New(store, logg, "scheduler", "a", 100*time.Millisecond, 0)
+	b := New(store, logg, "scheduler", "b", 100*time.Millisecond, 0)
+
+	if !a.Campaign(ctx) || b.Campaign(ctx) {
+		t.Fatal("exactly one replica must become the leader")
+	}
+	select {
+	case <-a.Elected():
+	default:
+		t.Fatal("leader must be notified about the election")
+	}
+	if !a.Campaign(ctx) {
+		t.Fatal("leader must renew its lease")
+	}
+	select {
+	case <-a.Elected():
+		t.Fatal("renewal must not notify about the election")
+	default:
+	}
+	if status := a.Status(); !status.Leader || status.ID != "a" || status.Since.IsZero() {
+		t.Fatalf("unexpected leader status: %+v", status)
+	}
+
+	// Лидер перестал продлевать аренду: после истечения её захватывает другая реплика
+	time.Sleep(150 * time.Millisecond)
+	if a.IsLeader() {
+		t.Fatal("replica must not stay the leader after the lease expired")
+	}
+	if !b.Campaign(ctx) || a.Campaign(ctx) {
+		t.Fatal("expired lease must move to another replica")
+	}
+	if status := a.Status(); status.Leader || !status.Since.IsZero() {
+		t.Fatalf("unexpected follower status: %+v", status)
+	}
+}
+
+func TestElectorStoreFailure(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	store := memorystorage.New()
+	e := New(store, logger.New("ERROR"), "scheduler", "a", 15*time.Second, 5*time.Second)
+	e.now = func() time.Time { return now }
+	if !e.Campaign(context.Background()) {
+		t.Fatal("replica must become the leader")
+	}
+
+	// Пока аренда не истекла, ошибка продления не лишает лидерства
+	e.store = failingStore{}
+	now = now.Add(10 * time.Second)
+	if !e.Campaign(context.Background()) {
+		t.Fatal("replica must stay the leader until the lease expires")
+	}
+	now = now.Add(5 * time.Second)
+	if e.Campaign(context.Background()) || e.IsLeader() {
+		t.Fatal("replica must lose the leadership when the lease expires")
+	}
+}
+
+func TestElectorRunResigns(t *testing.T) {
+	store := memorystorage.New()
+	logg := logger.New("ERROR")
+	a := New(store, logg, "scheduler", "a", time.Minute, 10*time.Millisecond)
+	b := New(store, logg, "scheduler", "b", time.Minute, 0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		a.Run(ctx)
+		close(done)
+	}()
+	select {
+	case <-a.Elected():
+	case <-time.After(time.Second):
+		t.Fatal("replica did not become the leader")
+	}
+	if b.Campaign(context.Background()) {
+		t.Fatal("lease held by the leader must not be acquired")
+	}
+
+	// Остановленный лидер освобождает аренду, не дожидаясь её истечения
+	cancel()
+	<-done
+	if a.IsLeader() || !b.Campaign(context.Background()) {
+		t.Fatal("lease must move to another replica after the leader stopped")
+	}
+}
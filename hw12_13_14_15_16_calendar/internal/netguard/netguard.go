@@ -0,0 +1,98 @@
+// Package netguard защищает исходящие запросы на адреса, заданные пользователями, от SSRF: такие запросы
+// не должны попадать на сам сервис, во внутреннюю сеть и к метаданным облака (169.254.169.254).
+package netguard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/netip"
+	"strings"
+	"syscall"
+)
+
+// ErrForbiddenAddress — адрес относится к внутренней сети или зарезервирован.
+var ErrForbiddenAddress = errors.New("address is not allowed")
+
+// reserved — диапазоны, не распознаваемые методами netip.Addr, на которые запросы также запрещены.
+var reserved = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),     // «этот» узел
+	netip.MustParsePrefix("100.64.0.0/10"), // адреса операторов связи (CGNAT)
+	netip.MustParsePrefix("192.0.0.0/24"),  // служебные адреса IETF
+	netip.MustParsePrefix("198.18.0.0/15"), // тестирование производительности сетей
+	netip.MustParsePrefix("240.0.0.0/4"),   // зарезервированы, включая широковещательный адрес
+}
+
+// internalZones — доменные зоны внутренних имён.
+var internalZones = []string{".localhost", ".local", ".internal", ".lan", ".home.arpa"}
+
+// Resolver разрешает имя узла в IP-адреса (реализуется *net.Resolver).
+type Resolver interface {
+	LookupNetIP(ctx context.Context, network, host string) ([]netip.Addr, error)
+}
+
+// CheckIP возвращает ErrForbiddenAddress, если ip — адрес петли, частной сети (RFC 1918, RFC 4193),
+// локальный адрес канала, групповой, неопределённый или зарезервированный.
+func CheckIP(ip netip.Addr) error {
+	ip = ip.Unmap()
+	if !ip.IsValid() || ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return fmt.Errorf("%w: %s is not a public address", ErrForbiddenAddress, ip)
+	}
+	for _, prefix := range reserved {
+		if prefix.Contains(ip) {
+			return fmt.Errorf("%w: %s is a reserved address", ErrForbiddenAddress, ip)
+		}
+	}
+	return nil
+}
+
+// CheckHostname проверяет имя узла без его разрешения: IP-адрес — по CheckIP, имя не должно быть
+// localhost, именем без домена или именем во внутренней зоне (.local, .internal и т. п.).
+func CheckHostname(host string) error {
+	if ip, err := netip.ParseAddr(host); err == nil {
+		return CheckIP(ip)
+	}
+	name := strings.TrimSuffix(strings.ToLower(host), ".")
+	if name == "localhost" || !strings.Contains(name, ".") {
+		return fmt.Errorf("%w: %s is an internal host name", ErrForbiddenAddress, host)
+	}
+	for _, zone := range internalZones {
+		if strings.HasSuffix(name, zone) {
+			return fmt.Errorf("%w: %s is an internal host name", ErrForbiddenAddress, host)
+		}
+	}
+	return nil
+}
+
+// CheckHost проверяет имя узла и все адреса, в которые оно разрешается. Имя, которое не удалось
+// разрешить, тоже считается ошибкой.
+func CheckHost(ctx context.Context, resolver Resolver, host string) error {
+	if err := CheckHostname(host); err != nil {
+		return err
+	}
+	if _, err := netip.ParseAddr(host); err == nil {
+		return nil
+	}
+	addrs, err := resolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s: %w", host, err)
+	}
+	for _, addr := range addrs {
+		if err := CheckIP(addr); err != nil {
+			return fmt.Errorf("%s: %w", host, err)
+		}
+	}
+	return nil
+}
+
+// Control проверяет адрес перед установкой соединения (net.Dialer.Control). Проверяется адрес,
+// к которому действительно подключается клиент, поэтому имя, разрешившееся после регистрации
+// во внутренний адрес, и перенаправления на внутренние адреса тоже отклоняются.
+func Control(_, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, address)
+	}
+	return CheckIP(addrPort.Addr())
+}
@@ -0,0 +1,55 @@
+package netguard
+
+import (
+	"context"
+	"errors"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeResolver разрешает имена по таблице.
+type fakeResolver map[string][]string
+
+func (r fakeResolver) LookupNetIP(_ context.Context, _, host string) ([]netip.Addr, error) {
+	addrs, ok := r[host]
+	if !ok {
+		return nil, errors.New("no such host")
+	}
+	result := make([]netip.Addr, len(addrs))
+	for i, a := range addrs {
+		result[i] = netip.MustParseAddr(a)
+	}
+	return result, nil
+}
+
+func TestCheckHost(t *testing.T) {
+	ctx := context.Background()
+	resolver := fakeResolver{
+		"hooks.example.com":  {"203.0.113.10", "2001:db8::10"},
+		"rebind.example.com": {"203.0.113.11", "10.0.0.5"},
+	}
+
+	for _, host := range []string{"hooks.example.com", "203.0.113.10", "2001:db8::10"} {
+		require.NoError(t, CheckHost(ctx, resolver, host), host)
+	}
+	for _, host := range []string{
+		"127.0.0.1", "::1", "10.1.2.3", "172.16.0.1", "192.168.1.1", "169.254.169.254", "fe80::1", "fd00::1",
+		"0.0.0.0", "100.64.0.1", "::ffff:127.0.0.1", "224.0.0.1",
+		"localhost", "LOCALHOST.", "db", "metadata.google.internal", "printer.local", "api.localhost",
+		"rebind.example.com", // одно из имён разрешается во внутренний адрес
+	} {
+		require.ErrorIs(t, CheckHost(ctx, resolver, host), ErrForbiddenAddress, host)
+	}
+	err := CheckHost(ctx, resolver, "missing.example.com")
+	require.Error(t, err)
+	require.NotErrorIs(t, err, ErrForbiddenAddress)
+}
+
+func TestControl(t *testing.T) {
+	require.NoError(t, Control("tcp4", "203.0.113.10:443", nil))
+	require.ErrorIs(t, Control("tcp4", "127.0.0.1:8080", nil), ErrForbiddenAddress)
+	require.ErrorIs(t, Control("tcp6", "[fe80::1%eth0]:80", nil), ErrForbiddenAddress)
+	require.ErrorIs(t, Control("tcp", "not an address", nil), ErrForbiddenAddress)
+}
@@ -0,0 +1,76 @@
+package notifier
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen — адрес временно отключён после серии неудачных попыток доставки.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// breaker — автоматические выключатели адресов. После failures неудачных попыток подряд адрес отключается
+// на open: попытки доставки на него сразу завершаются ErrCircuitOpen. Затем пропускается одна пробная
+// попытка: успех включает адрес, неудача снова отключает его на open.
+type breaker struct {
+	failures int
+	open     time.Duration
+	now      func() time.Time
+
+	mu       sync.Mutex
+	circuits map[string]*circuit // ключ — адрес
+}
+
+// circuit — состояние выключателя адреса.
+type circuit struct {
+	failures  int       // неудачных попыток подряд
+	openUntil time.Time // до какого времени адрес отключён
+	probing   bool      // идёт пробная попытка
+}
+
+func newBreaker(failures int, open time.Duration) *breaker {
+	return &breaker{failures: failures, open: open, now: time.Now, circuits: make(map[string]*circuit)}
+}
+
+// allow сообщает, можно ли сделать попытку доставки на адрес.
+func (b *breaker) allow(endpoint string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	c := b.circuit(endpoint)
+	if c.failures < b.failures {
+		return nil
+	}
+	if c.probing || b.now().Before(c.openUntil) {
+		return ErrCircuitOpen
+	}
+	c.probing = true
+	return nil
+}
+
+// report учитывает результат попытки доставки на адрес и сообщает, отключён ли адрес после неё.
+func (b *breaker) report(endpoint string, healthy bool) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	c := b.circuit(endpoint)
+	c.probing = false
+	if healthy {
+		c.failures = 0
+		return false
+	}
+	c.failures++
+	if c.failures < b.failures {
+		return false
+	}
+	c.openUntil = b.now().Add(b.open)
+	return true
+}
+
+// circuit возвращает состояние выключателя адреса. Вызывается под блокировкой.
+func (b *breaker) circuit(endpoint string) *circuit {
+	c, ok := b.circuits[endpoint]
+	if !ok {
+		c = &circuit{}
+		b.circuits[endpoint] = c
+	}
+	return c
+}
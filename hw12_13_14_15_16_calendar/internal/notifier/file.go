@@ -0,0 +1,55 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/queue"
+)
+
+// File записывает напоминания по строке в файл или STDOUT.
+type File struct {
+	mu     sync.Mutex
+	w      io.Writer
+	closer io.Closer // nil для STDOUT
+}
+
+// NewFile создаёт канал, записывающий напоминания в w; nil — в STDOUT.
+func NewFile(w io.Writer) *File {
+	if w == nil {
+		w = os.Stdout
+	}
+	return &File{w: w}
+}
+
+// OpenFile открывает файл path для дописывания напоминаний; пустой путь — STDOUT.
+func OpenFile(path string) (*File, error) {
+	if path == "" {
+		return NewFile(nil), nil
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &File{w: f, closer: f}, nil
+}
+
+// Notify записывает напоминание строкой.
+func (f *File) Notify(ctx context.Context, notification queue.Notification) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	_, err := fmt.Fprintf(f.w, "[NOTIFICATION] Event: %s | Title: %s | User: %s | Time: %s\n",
+		notification.EventID, notification.Title, notification.UserID, eventTime(notification))
+	return err
+}
+
+// Close закрывает файл.
+func (f *File) Close() error {
+	if f.closer == nil {
+		return nil
+	}
+	return f.closer.Close()
+}
@@ -0,0 +1,166 @@
+// Package notifier доставляет напоминания о событиях получателям по каналам: электронной почтой (SMTP),
+// POST-запросом на HTTP-адрес (webhook) или записью в файл либо STDOUT.
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/config"
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/queue"
+)
+
+// Notifier доставляет напоминание получателю.
+type Notifier interface {
+	Notify(ctx context.Context, notification queue.Notification) error
+}
+
+// Logger определяет интерфейс для логирования.
+type Logger interface {
+	Warn(msg string)
+}
+
+// Типы каналов доставки в конфигурации.
+const (
+	TypeSMTP    = "smtp"
+	TypeWebhook = "webhook"
+	TypeFile    = "file"
+)
+
+// StdoutChannel — канал, выводящий напоминания в STDOUT, если каналы в конфигурации не заданы.
+const StdoutChannel = "stdout"
+
+// Option настраивает каналы доставки.
+type Option func(*options)
+
+// options — зависимости каналов доставки от хранилища.
+type options struct {
+	registry WebhookRegistry
+	recorder AttemptRecorder
+}
+
+// WithWebhookRegistry задаёт хранилище адресов, зарегистрированных пользователями: webhook-каналы
+// без url отправляют напоминания на них.
+func WithWebhookRegistry(registry WebhookRegistry) Option {
+	return func(o *options) {
+		o.registry = registry
+	}
+}
+
+// WithAttemptRecorder задаёт хранилище, в котором webhook-каналы учитывают попытки доставки.
+func WithAttemptRecorder(recorder AttemptRecorder) Option {
+	return func(o *options) {
+		o.recorder = recorder
+	}
+}
+
+// Router выбирает канал доставки по каналу напоминания.
+type Router struct {
+	logg           Logger
+	channels       map[string]Notifier // каналы доставки, ключ — имя канала
+	defaultChannel string              // канал для напоминаний без канала или с ненастроенным каналом
+}
+
+// New создаёт каналы доставки по конфигурации рассыльщика. Без каналов напоминания выводятся в STDOUT.
+func New(cfg config.SenderConf, logg Logger, opts ...Option) (*Router, error) {
+	if len(cfg.Channels) == 0 {
+		return &Router{
+			logg:           logg,
+			channels:       map[string]Notifier{StdoutChannel: NewFile(nil)},
+			defaultChannel: StdoutChannel,
+		}, nil
+	}
+	if _, ok := cfg.Channels[cfg.DefaultChannel]; !ok {
+		return nil, fmt.Errorf("default channel %q is not configured", cfg.DefaultChannel)
+	}
+	r := &Router{logg: logg, channels: make(map[string]Notifier, len(cfg.Channels)), defaultChannel: cfg.DefaultChannel}
+	for name, ch := range cfg.Channels {
+		n, err := newNotifier(ch, logg, opts)
+		if err != nil {
+			return nil, fmt.Errorf("channel %q: %w", name, err)
+		}
+		r.channels[name] = n
+	}
+	return r, nil
+}
+
+// newNotifier создаёт канал доставки типа ch.Type.
+func newNotifier(ch config.ChannelConf, logg Logger, opts []Option) (Notifier, error) {
+	switch ch.Type {
+	case TypeSMTP:
+		return NewSMTP(ch.SMTP)
+	case TypeWebhook:
+		return NewWebhook(ch.Webhook, logg, opts...)
+	case TypeFile:
+		return OpenFile(ch.File.Path)
+	default:
+		return nil, fmt.Errorf("unknown channel type %q", ch.Type)
+	}
+}
+
+// Channels возвращает имена настроенных каналов по алфавиту.
+func (r *Router) Channels() []string {
+	names := make([]string, 0, len(r.channels))
+	for name := range r.channels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Notify доставляет напоминание по его каналу. Напоминание без канала или с ненастроенным каналом
+// доставляется по каналу по умолчанию.
+func (r *Router) Notify(ctx context.Context, notification queue.Notification) error {
+	n, ok := r.channels[notification.Channel]
+	if !ok {
+		if notification.Channel != "" {
+			r.logg.Warn(fmt.Sprintf("channel %q is not configured, using %q", notification.Channel, r.defaultChannel))
+		}
+		n = r.channels[r.defaultChannel]
+	}
+	return n.Notify(ctx, notification)
+}
+
+// Close закрывает каналы доставки, которым это нужно (например, открытые файлы).
+func (r *Router) Close() error {
+	var errs []string
+	for name, n := range r.channels {
+		if c, ok := n.(interface{ Close() error }); ok {
+			if err := c.Close(); err != nil {
+				errs = append(errs, fmt.Sprintf("%s: %v", name, err))
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to close channels: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// subject возвращает тему напоминания.
+func subject(notification queue.Notification) string {
+	return "Reminder: " + notification.Title
+}
+
+// message возвращает текст напоминания.
+func message(notification queue.Notification) string {
+	return fmt.Sprintf("Event %q starts at %s (in %v).\nEvent ID: %s\n",
+		notification.Title, eventTime(notification),
+		time.Duration(notification.NotifyBefore)*time.Second, notification.EventID)
+}
+
+// eventTime возвращает время начала события в формате RFC3339 (UTC).
+func eventTime(notification queue.Notification) string {
+	return time.Unix(notification.EventTime, 0).UTC().Format(time.RFC3339)
+}
+
+// timeout возвращает таймаут в секундах или 10 секунд, если он не задан.
+func timeout(seconds int) time.Duration {
+	if seconds <= 0 {
+		return 10 * time.Second
+	}
+	return time.Duration(seconds) * time.Second
+}
@@ -0,0 +1,100 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/config"
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/logger"
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/queue"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouterNotify(t *testing.T) {
+	var hooked []queue.Notification
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		require.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		var n queue.Notification
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&n))
+		hooked = append(hooked, n)
+	}))
+	defer srv.Close()
+	path := filepath.Join(t.TempDir(), "notifications.log")
+
+	r, err := New(config.SenderConf{
+		DefaultChannel: "log",
+		Channels: map[string]config.ChannelConf{
+			"log": {Type: TypeFile, File: config.FileConf{Path: path}},
+			"webhook": {Type: TypeWebhook, Webhook: config.WebhookConf{
+				URL:     srv.URL,
+				Headers: map[string]string{"Authorization": "Bearer token"},
+			}},
+		},
+	}, logger.New("ERROR"))
+	require.NoError(t, err)
+	require.Equal(t, []string{"log", "webhook"}, r.Channels())
+
+	ctx := context.Background()
+	require.NoError(t, r.Notify(ctx, queue.Notification{EventID: "e1", Title: "Standup", EventTime: 1760616000, UserID: "user1", Channel: "webhook"}))
+	// Напоминание без канала и с ненастроенным каналом доставляется по каналу по умолчанию
+	require.NoError(t, r.Notify(ctx, queue.Notification{EventID: "e2", Title: "Review", EventTime: 1760616000, UserID: "user1"}))
+	require.NoError(t, r.Notify(ctx, queue.Notification{EventID: "e3", Title: "Retro", EventTime: 1760616000, UserID: "user2", Channel: "sms"}))
+	require.NoError(t, r.Close())
+
+	require.Len(t, hooked, 1)
+	require.Equal(t, "e1", hooked[0].EventID)
+	require.Equal(t, "webhook", hooked[0].Channel)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "[NOTIFICATION] Event: e2 | Title: Review | User: user1 | Time: 2025-10-16T12:00:00Z\n"+
+		"[NOTIFICATION] Event: e3 | Title: Retro | User: user2 | Time: 2025-10-16T12:00:00Z\n", string(data))
+}
+
+func TestRouterConfig(t *testing.T) {
+	// Без каналов напоминания выводятся в STDOUT
+	r, err := New(config.SenderConf{}, logger.New("ERROR"))
+	require.NoError(t, err)
+	require.Equal(t, []string{StdoutChannel}, r.Channels())
+
+	_, err = New(config.SenderConf{
+		DefaultChannel: "email",
+		Channels:       map[string]config.ChannelConf{"log": {Type: TypeFile}},
+	}, logger.New("ERROR"))
+	require.ErrorContains(t, err, `default channel "email" is not configured`)
+	_, err = New(config.SenderConf{
+		DefaultChannel: "log",
+		Channels:       map[string]config.ChannelConf{"log": {Type: "sms"}},
+	}, logger.New("ERROR"))
+	require.ErrorContains(t, err, `unknown channel type "sms"`)
+	// Без базы данных адреса пользователей недоступны, поэтому webhook-каналу нужен url
+	_, err = New(config.SenderConf{
+		DefaultChannel: "bot",
+		Channels:       map[string]config.ChannelConf{"bot": {Type: TypeWebhook}},
+	}, logger.New("ERROR"))
+	require.ErrorContains(t, err, "webhook url is required")
+}
+
+func TestWebhookNotifyError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+	w, err := NewWebhook(config.WebhookConf{URL: srv.URL}, logger.New("ERROR"))
+	require.NoError(t, err)
+	err = w.Notify(context.Background(), queue.Notification{EventID: "e1"})
+	require.ErrorContains(t, err, "502 Bad Gateway")
+}
+
+func TestFileNotify(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, NewFile(&buf).Notify(context.Background(), queue.Notification{EventID: "e1", Title: "Standup", UserID: "user1"}))
+	require.Equal(t, "[NOTIFICATION] Event: e1 | Title: Standup | User: user1 | Time: 1970-01-01T00:00:00Z\n", buf.String())
+}
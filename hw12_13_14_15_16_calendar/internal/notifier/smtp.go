@@ -0,0 +1,147 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"time"
+
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/config"
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/queue"
+)
+
+// SMTP отправляет напоминания письмами через SMTP-сервер. Если сервер поддерживает STARTTLS,
+// соединение шифруется до аутентификации.
+type SMTP struct {
+	addr       string
+	host       string
+	auth       smtp.Auth // nil — без аутентификации
+	from       string
+	domain     string
+	recipients map[string]string
+	timeout    time.Duration
+}
+
+// NewSMTP создаёт канал отправки напоминаний по электронной почте.
+func NewSMTP(cfg config.SMTPConf) (*SMTP, error) {
+	if cfg.Host == "" || cfg.From == "" {
+		return nil, errors.New("smtp host and from are required")
+	}
+	if _, err := mail.ParseAddress(cfg.From); err != nil {
+		return nil, fmt.Errorf("invalid from address: %w", err)
+	}
+	port := cfg.Port
+	if port == 0 {
+		port = 25
+	}
+	s := &SMTP{
+		addr:       net.JoinHostPort(cfg.Host, strconv.Itoa(port)),
+		host:       cfg.Host,
+		from:       cfg.From,
+		domain:     cfg.Domain,
+		recipients: cfg.Recipients,
+		timeout:    timeout(cfg.TimeoutSeconds),
+	}
+	if cfg.Username != "" {
+		s.auth = smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)
+	}
+	return s, nil
+}
+
+// recipient возвращает адрес получателя напоминания: из recipients или <ID пользователя>@domain.
+func (s *SMTP) recipient(userID string) (string, error) {
+	if addr, ok := s.recipients[userID]; ok {
+		return addr, nil
+	}
+	if s.domain == "" {
+		return "", fmt.Errorf("no email address for user %q", userID)
+	}
+	return userID + "@" + s.domain, nil
+}
+
+// Notify отправляет письмо с напоминанием.
+func (s *SMTP) Notify(ctx context.Context, notification queue.Notification) error {
+	to, err := s.recipient(notification.UserID)
+	if err != nil {
+		return err
+	}
+	msg, err := s.compose(to, notification)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", s.addr)
+	if err != nil {
+		return fmt.Errorf("failed to connect to smtp server: %w", err)
+	}
+	deadline, _ := ctx.Deadline()
+	_ = conn.SetDeadline(deadline)
+	c, err := smtp.NewClient(conn, s.host)
+	if err != nil {
+		_ = conn.Close()
+		return fmt.Errorf("failed to start smtp session: %w", err)
+	}
+	defer func() { _ = c.Close() }()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: s.host, MinVersion: tls.VersionTLS12}); err != nil {
+			return fmt.Errorf("smtp starttls failed: %w", err)
+		}
+	}
+	if s.auth != nil {
+		if err := c.Auth(s.auth); err != nil {
+			return fmt.Errorf("smtp auth failed: %w", err)
+		}
+	}
+	if err := c.Mail(s.from); err != nil {
+		return fmt.Errorf("smtp MAIL FROM failed: %w", err)
+	}
+	if err := c.Rcpt(to); err != nil {
+		return fmt.Errorf("smtp RCPT TO failed: %w", err)
+	}
+	w, err := c.Data()
+	if err != nil {
+		return fmt.Errorf("smtp DATA failed: %w", err)
+	}
+	if _, err := w.Write(msg); err != nil {
+		return fmt.Errorf("failed to write message: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("smtp server rejected message: %w", err)
+	}
+	return c.Quit()
+}
+
+// compose формирует письмо в кодировке UTF-8 (quoted-printable). Message-ID определяется напоминанием,
+// поэтому повторно отправленное письмо почтовые клиенты распознают как дубль.
+func (s *SMTP) compose(to string, notification queue.Notification) ([]byte, error) {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", s.from)
+	fmt.Fprintf(&b, "To: %s\r\n", to)
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject(notification)))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&b, "Message-ID: <%s.%d.%d@calendar>\r\n",
+		notification.EventID, notification.EventTime, notification.NotifyBefore)
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+	qp := quotedprintable.NewWriter(&b)
+	if _, err := qp.Write([]byte(message(notification))); err != nil {
+		return nil, err
+	}
+	if err := qp.Close(); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
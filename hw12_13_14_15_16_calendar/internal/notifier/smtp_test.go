@@ -0,0 +1,184 @@
+package notifier
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/config"
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/queue"
+	"github.com/stretchr/testify/require"
+)
+
+// smtpMessage — письмо, принятое тестовым SMTP-сервером.
+type smtpMessage struct {
+	auth string // учётные данные AUTH PLAIN: "\x00user\x00password"
+	from string
+	to   []string
+	data string
+}
+
+// smtpStub — минимальный SMTP-сервер в процессе теста: принимает письма без проверки
+// и отклоняет получателей из reject.
+type smtpStub struct {
+	ln     net.Listener
+	reject map[string]bool
+
+	mu       sync.Mutex
+	messages []smtpMessage
+}
+
+func startSMTPStub(t *testing.T, reject ...string) *smtpStub {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	s := &smtpStub{ln: ln, reject: make(map[string]bool)}
+	for _, addr := range reject {
+		s.reject[addr] = true
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+// conf возвращает параметры подключения к серверу.
+func (s *smtpStub) conf() config.SMTPConf {
+	host, port, _ := net.SplitHostPort(s.ln.Addr().String())
+	p, _ := strconv.Atoi(port)
+	return config.SMTPConf{Host: host, Port: p, From: "calendar@example.com"}
+}
+
+func (s *smtpStub) received() []smtpMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]smtpMessage(nil), s.messages...)
+}
+
+func (s *smtpStub) serve(conn net.Conn) {
+	defer func() { _ = conn.Close() }()
+	r := bufio.NewReader(conn)
+	reply := func(line string) { _, _ = io.WriteString(conn, line+"\r\n") }
+	reply("220 localhost ESMTP stub")
+	var msg smtpMessage
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		cmd := strings.ToUpper(line)
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"):
+			reply("250-localhost")
+			reply("250 AUTH PLAIN")
+		case strings.HasPrefix(cmd, "AUTH PLAIN "):
+			creds, _ := base64.StdEncoding.DecodeString(line[len("AUTH PLAIN "):])
+			msg.auth = string(creds)
+			reply("235 Authentication successful")
+		case strings.HasPrefix(cmd, "MAIL FROM:"):
+			msg.from = strings.Trim(line[len("MAIL FROM:"):], "<>")
+			reply("250 OK")
+		case strings.HasPrefix(cmd, "RCPT TO:"):
+			to := strings.Trim(line[len("RCPT TO:"):], "<>")
+			if s.reject[to] {
+				reply("550 No such user")
+				continue
+			}
+			msg.to = append(msg.to, to)
+			reply("250 OK")
+		case cmd == "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var data strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+				data.WriteString(strings.TrimPrefix(l, "."))
+			}
+			msg.data = data.String()
+			s.mu.Lock()
+			s.messages = append(s.messages, msg)
+			s.mu.Unlock()
+			msg = smtpMessage{}
+			reply("250 OK")
+		case cmd == "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+func TestSMTPNotify(t *testing.T) {
+	stub := startSMTPStub(t)
+	cfg := stub.conf()
+	cfg.Username, cfg.Password = "calendar", "secret"
+	cfg.Domain = "example.com"
+	cfg.Recipients = map[string]string{"user1": "ivan@example.org"}
+	n, err := NewSMTP(cfg)
+	require.NoError(t, err)
+
+	notification := queue.Notification{EventID: "e1", Title: "Планёрка", EventTime: 1760616000, UserID: "user1", NotifyBefore: 900}
+	require.NoError(t, n.Notify(context.Background(), notification))
+	notification.UserID = "user2"
+	require.NoError(t, n.Notify(context.Background(), notification))
+
+	messages := stub.received()
+	require.Len(t, messages, 2)
+	require.Equal(t, "\x00calendar\x00secret", messages[0].auth)
+	require.Equal(t, "calendar@example.com", messages[0].from)
+	require.Equal(t, []string{"ivan@example.org"}, messages[0].to)
+	// Адрес пользователя не из recipients строится по домену
+	require.Equal(t, []string{"user2@example.com"}, messages[1].to)
+
+	m, err := mail.ReadMessage(strings.NewReader(messages[0].data))
+	require.NoError(t, err)
+	subject, err := new(mime.WordDecoder).DecodeHeader(m.Header.Get("Subject"))
+	require.NoError(t, err)
+	require.Equal(t, "Reminder: Планёрка", subject)
+	require.Equal(t, "<e1.1760616000.900@calendar>", m.Header.Get("Message-ID"))
+	body, err := io.ReadAll(quotedprintable.NewReader(m.Body))
+	require.NoError(t, err)
+	require.Contains(t, string(body), `Event "Планёрка" starts at 2025-10-16T12:00:00Z (in 15m0s).`)
+}
+
+func TestSMTPNotifyErrors(t *testing.T) {
+	stub := startSMTPStub(t, "ivan@example.org")
+	cfg := stub.conf()
+	cfg.Recipients = map[string]string{"user1": "ivan@example.org"}
+	n, err := NewSMTP(cfg)
+	require.NoError(t, err)
+
+	// Без домена адрес пользователя не из recipients неизвестен
+	err = n.Notify(context.Background(), queue.Notification{EventID: "e1", Title: "Standup", UserID: "user2"})
+	require.ErrorContains(t, err, `no email address for user "user2"`)
+	// Отказ сервера принять получателя — ошибка доставки
+	err = n.Notify(context.Background(), queue.Notification{EventID: "e1", Title: "Standup", UserID: "user1"})
+	require.ErrorContains(t, err, "RCPT TO")
+	require.Empty(t, stub.received())
+
+	_, err = NewSMTP(config.SMTPConf{Host: "localhost", From: "not an address"})
+	require.Error(t, err)
+}
@@ -0,0 +1,286 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/config"
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/netguard"
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/queue"
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/storage"
+)
+
+// Заголовки запросов с напоминаниями.
+const (
+	SignatureHeader = "X-Calendar-Signature" // подпись тела запроса (см. Sign)
+	TimestampHeader = "X-Calendar-Timestamp" // время подписи (Unix timestamp)
+	DeliveryHeader  = "X-Calendar-Delivery"  // идентификатор напоминания: одинаков у повторных отправок
+)
+
+// WebhookRegistry — хранилище адресов, зарегистрированных пользователями.
+type WebhookRegistry interface {
+	ListWebhooks(ctx context.Context, userID string) ([]storage.Webhook, error)
+}
+
+// AttemptRecorder учитывает попытки доставки напоминаний по адресам и адреса, на которые напоминание
+// уже доставлено.
+type AttemptRecorder interface {
+	RecordNotificationAttempt(ctx context.Context, reminder storage.Reminder, endpoint, attemptErr string) error
+	DeliveredEndpoints(ctx context.Context, reminder storage.Reminder) ([]string, error)
+}
+
+// Webhook отправляет напоминания POST-запросом с JSON queue.Notification на HTTP-адрес из конфигурации
+// или на адреса, зарегистрированные получателем. На каждый адрес выполняется одна попытка: неудачная
+// доставка повторяется по политике повторов очереди, а адреса, на которые напоминание уже доставлено,
+// при повторе пропускаются. Адрес, не ответивший несколько раз подряд, временно отключается.
+type Webhook struct {
+	url        string            // адрес из конфигурации; пусто — адреса получателя из registry
+	secret     string            // ключ подписи запросов на url
+	headers    map[string]string // дополнительные заголовки запросов на url; на адреса пользователей не отправляются
+	client     *http.Client      // клиент для url из конфигурации
+	userClient *http.Client      // клиент для адресов пользователей: подключается только к публичным адресам
+	registry   WebhookRegistry
+	recorder   AttemptRecorder // nil — попытки и доставленные адреса не учитываются
+	logg       Logger
+	breaker    *breaker
+	now        func() time.Time
+}
+
+// endpoint — адрес доставки, ключ подписи запросов на него, дополнительные заголовки и клиент для отправки.
+type endpoint struct {
+	url     string
+	secret  string
+	headers map[string]string
+	client  *http.Client
+}
+
+// NewWebhook создаёт канал отправки напоминаний на HTTP-адреса. Без url в конфигурации нужен WithWebhookRegistry.
+func NewWebhook(cfg config.WebhookConf, logg Logger, opts ...Option) (*Webhook, error) {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if cfg.URL == "" && o.registry == nil {
+		return nil, errors.New("webhook url is required when user webhooks are unavailable (no database)")
+	}
+	return &Webhook{
+		url:        cfg.URL,
+		secret:     cfg.Secret,
+		headers:    cfg.Headers,
+		client:     &http.Client{Timeout: timeout(cfg.TimeoutSeconds)},
+		userClient: guardedClient(timeout(cfg.TimeoutSeconds)),
+		registry:   o.registry,
+		recorder:   o.recorder,
+		logg:       logg,
+		breaker:    newBreaker(orDefault(cfg.BreakerFailures, 5), time.Duration(orDefault(cfg.BreakerOpenSeconds, 60))*time.Second),
+		now:        time.Now,
+	}, nil
+}
+
+// Sign возвращает подпись тела запроса body, подписанного в момент timestamp: "sha256=" и HMAC-SHA256
+// строки "<timestamp>.<body>" с ключом secret в шестнадцатеричном виде. Получатель вычисляет подпись
+// заново и сравнивает её с заголовком SignatureHeader за постоянное время (hmac.Equal).
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Notify отправляет напоминание на адреса получателя, на которые оно ещё не доставлено. Ошибка возвращается,
+// если хотя бы на один адрес доставить напоминание не удалось; если все неудачи — отказы получателей
+// или запрещённые адреса, ошибка отмечается как постоянная (queue.Permanent) и не повторяется.
+func (w *Webhook) Notify(ctx context.Context, notification queue.Notification) error {
+	endpoints, err := w.endpoints(ctx, notification.UserID)
+	if err != nil {
+		return err
+	}
+	if len(endpoints) == 0 {
+		w.logg.Warn(fmt.Sprintf("no webhooks registered for user %s, notification for event %s is dropped",
+			notification.UserID, notification.EventID))
+		return nil
+	}
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return fmt.Errorf("failed to marshal notification: %w", err)
+	}
+	delivered, err := w.delivered(ctx, notification)
+	if err != nil {
+		return err
+	}
+	var (
+		errs      []error
+		permanent = true
+	)
+	for _, ep := range endpoints {
+		if slices.Contains(delivered, ep.url) {
+			continue
+		}
+		if err := w.deliver(ctx, ep, notification, body); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", ep.url, err))
+			permanent = permanent && !retryable(err)
+		}
+	}
+	err = errors.Join(errs...)
+	if err != nil && permanent {
+		return queue.Permanent(err)
+	}
+	return err
+}
+
+// delivered возвращает адреса, на которые напоминание уже доставлено при предыдущей обработке сообщения.
+func (w *Webhook) delivered(ctx context.Context, notification queue.Notification) ([]string, error) {
+	if w.recorder == nil {
+		return nil, nil
+	}
+	delivered, err := w.recorder.DeliveredEndpoints(ctx, Reminder(notification))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get delivered endpoints for event %s: %w", notification.EventID, err)
+	}
+	return delivered, nil
+}
+
+// endpoints возвращает адреса доставки напоминаний пользователя.
+func (w *Webhook) endpoints(ctx context.Context, userID string) ([]endpoint, error) {
+	if w.url != "" {
+		return []endpoint{{url: w.url, secret: w.secret, headers: w.headers, client: w.client}}, nil
+	}
+	webhooks, err := w.registry.ListWebhooks(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhooks of user %s: %w", userID, err)
+	}
+	endpoints := make([]endpoint, 0, len(webhooks))
+	for _, wh := range webhooks {
+		endpoints = append(endpoints, endpoint{url: wh.URL, secret: wh.Secret, client: w.userClient})
+	}
+	return endpoints, nil
+}
+
+// deliver выполняет одну попытку доставки напоминания на адрес и учитывает её.
+func (w *Webhook) deliver(ctx context.Context, ep endpoint, notification queue.Notification, body []byte) error {
+	if err := w.breaker.allow(ep.url); err != nil {
+		return err
+	}
+	err := w.post(ctx, ep, notification, body)
+	if w.breaker.report(ep.url, err == nil || !retryable(err)) {
+		w.logg.Warn(fmt.Sprintf("webhook %s is disabled for %v after repeated failures: %v", ep.url, w.breaker.open, err))
+	}
+	w.record(ctx, notification, ep, err)
+	return err
+}
+
+// post отправляет один запрос с напоминанием. Заголовки из конфигурации не могут заменить
+// служебные заголовки и подпись: они устанавливаются последними.
+func (w *Webhook) post(ctx context.Context, ep endpoint, notification queue.Notification, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, ep.url, bytes.NewReader(body))
+	if err != nil {
+		return &statusError{err: err}
+	}
+	for k, v := range ep.headers {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(DeliveryHeader, fmt.Sprintf("%s.%d.%d", notification.EventID, notification.EventTime, notification.NotifyBefore))
+	req.Header.Del(TimestampHeader)
+	req.Header.Del(SignatureHeader)
+	if ep.secret != "" {
+		timestamp := w.now().Unix()
+		req.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+		req.Header.Set(SignatureHeader, Sign(ep.secret, timestamp, body))
+	}
+	resp, err := ep.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return &statusError{code: resp.StatusCode, err: fmt.Errorf("webhook responded with %s", resp.Status)}
+	}
+	return nil
+}
+
+// record учитывает попытку доставки в записи о напоминании; после успешной попытки адрес отмечается
+// доставленным.
+func (w *Webhook) record(ctx context.Context, notification queue.Notification, ep endpoint, attemptErr error) {
+	if w.recorder == nil {
+		return
+	}
+	var text string
+	if attemptErr != nil {
+		text = fmt.Sprintf("%s: %v", ep.url, attemptErr)
+	}
+	if err := w.recorder.RecordNotificationAttempt(ctx, Reminder(notification), ep.url, text); err != nil {
+		w.logg.Warn(fmt.Sprintf("failed to record delivery attempt for event %s: %v", notification.EventID, err))
+	}
+}
+
+// Reminder возвращает напоминание, которое передаёт сообщение очереди.
+func Reminder(notification queue.Notification) storage.Reminder {
+	return storage.Reminder{
+		EventID:      notification.EventID,
+		UserID:       notification.UserID,
+		Title:        notification.Title,
+		EventTime:    notification.EventTime,
+		NotifyBefore: notification.NotifyBefore,
+		Channel:      notification.Channel,
+	}
+}
+
+// statusError — запрос, отклонённый до отправки или получателем; code — код ответа (0 — запрос не создан).
+type statusError struct {
+	code int
+	err  error
+}
+
+func (e *statusError) Error() string { return e.err.Error() }
+
+func (e *statusError) Unwrap() error { return e.err }
+
+// retryable сообщает, стоит ли повторить попытку: сетевые ошибки (кроме запрещённого адреса), ответы 5xx, 408 и 429.
+func retryable(err error) bool {
+	if errors.Is(err, netguard.ErrForbiddenAddress) {
+		return false
+	}
+	var se *statusError
+	if !errors.As(err, &se) {
+		return true
+	}
+	return se.code >= 500 || se.code == http.StatusRequestTimeout || se.code == http.StatusTooManyRequests
+}
+
+// guardedClient возвращает HTTP-клиент для адресов, зарегистрированных пользователями. Клиент подключается
+// только к публичным адресам (netguard.Control проверяет адрес каждого соединения, в том числе после
+// перенаправления) и не использует прокси из окружения.
+func guardedClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout, Control: netguard.Control}
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: timeout,
+			ForceAttemptHTTP2:   true,
+		},
+	}
+}
+
+// orDefault возвращает value или def, если value не задано.
+func orDefault(value, def int) int {
+	if value <= 0 {
+		return def
+	}
+	return value
+}
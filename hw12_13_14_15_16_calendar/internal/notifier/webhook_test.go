@@ -0,0 +1,217 @@
+package notifier
+
+import (
+	"context"
+	"crypto/hmac"
+	"encoding/json"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/config"
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/logger"
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/netguard"
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/queue"
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/storage"
+	memorystorage "github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/storage/memory"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestWebhook создаёт канал доставки на адреса пользователей из store, учитывающий попытки в store.
+func newTestWebhook(t *testing.T, store *memorystorage.Storage, cfg config.WebhookConf) *Webhook {
+	t.Helper()
+	w, err := NewWebhook(cfg, logger.New("ERROR"), WithWebhookRegistry(store), WithAttemptRecorder(store))
+	require.NoError(t, err)
+	return w
+}
+
+// deliveries возвращает записи о напоминаниях из store.
+func deliveries(t *testing.T, store *memorystorage.Storage) []storage.NotificationRecord {
+	t.Helper()
+	records, err := store.ListExpiredNotifications(context.Background(), math.MaxInt64, "", 10)
+	require.NoError(t, err)
+	return records
+}
+
+func TestWebhookSignedDelivery(t *testing.T) {
+	const secret = "whsec_test"
+	var calls, flakyCalls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		timestamp, err := strconv.ParseInt(r.Header.Get(TimestampHeader), 10, 64)
+		require.NoError(t, err)
+		require.True(t, hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(r.Header.Get(SignatureHeader))))
+		require.Equal(t, "e1.1760616000.900", r.Header.Get(DeliveryHeader))
+		// Заголовки из конфигурации на адреса пользователей не отправляются
+		require.Empty(t, r.Header.Get("Authorization"))
+		var n queue.Notification
+		require.NoError(t, json.Unmarshal(body, &n))
+		require.Equal(t, "Standup", n.Title)
+		if r.URL.Path == "/stable" {
+			calls.Add(1)
+			return
+		}
+		// Первая попытка на второй адрес завершается временной ошибкой
+		if flakyCalls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	store := memorystorage.New()
+	ctx := context.Background()
+	for i, path := range []string{"/stable", "/flaky"} {
+		require.NoError(t, store.CreateWebhook(ctx, storage.Webhook{
+			ID: "w" + strconv.Itoa(i), UserID: "user1", URL: srv.URL + path, Secret: secret,
+		}))
+	}
+	w := newTestWebhook(t, store, config.WebhookConf{Headers: map[string]string{"Authorization": "Bearer operator"}})
+	w.userClient = srv.Client() // тестовый сервер слушает адрес петли
+
+	// Неудача на одном адресе повторяется очередью, а не внутри Notify
+	notification := queue.Notification{EventID: "e1", Title: "Standup", EventTime: 1760616000, UserID: "user1", NotifyBefore: 900, Channel: "bot"}
+	err := w.Notify(ctx, notification)
+	require.ErrorContains(t, err, "503 Service Unavailable")
+	require.NotErrorIs(t, err, queue.ErrPermanent)
+	require.Equal(t, int32(1), calls.Load())
+	require.Equal(t, int32(1), flakyCalls.Load())
+
+	// При повторе напоминание отправляется только на адрес, на который оно ещё не доставлено
+	require.NoError(t, w.Notify(ctx, notification))
+	require.NoError(t, w.Notify(ctx, notification))
+	require.Equal(t, int32(1), calls.Load())
+	require.Equal(t, int32(2), flakyCalls.Load())
+	records := deliveries(t, store)
+	require.Len(t, records, 1)
+	require.Equal(t, 3, records[0].Attempts)
+	require.Empty(t, records[0].LastError)
+	require.ElementsMatch(t, []string{srv.URL + "/stable", srv.URL + "/flaky"}, records[0].DeliveredTo)
+	require.Equal(t, "bot", records[0].Reminder.Channel)
+
+	// Получатель без адресов напоминание не получает, но это не ошибка
+	notification.UserID = "user2"
+	require.NoError(t, w.Notify(ctx, notification))
+	require.Equal(t, int32(1), calls.Load())
+}
+
+func TestWebhookConfiguredHeaders(t *testing.T) {
+	const secret = "whsec_test"
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.Equal(t, "Bearer operator", r.Header.Get("Authorization"))
+		// Заголовки из конфигурации не заменяют служебные заголовки и подпись
+		require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		require.Equal(t, "e1.0.0", r.Header.Get(DeliveryHeader))
+		timestamp, err := strconv.ParseInt(r.Header.Get(TimestampHeader), 10, 64)
+		require.NoError(t, err)
+		require.True(t, hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(r.Header.Get(SignatureHeader))))
+	}))
+	defer srv.Close()
+
+	w := newTestWebhook(t, memorystorage.New(), config.WebhookConf{URL: srv.URL, Secret: secret, Headers: map[string]string{
+		"Authorization": "Bearer operator",
+		"Content-Type":  "text/plain",
+		DeliveryHeader:  "forged",
+		TimestampHeader: "0",
+		SignatureHeader: "sha256=forged",
+	}})
+	require.NoError(t, w.Notify(context.Background(), queue.Notification{EventID: "e1", UserID: "user1"}))
+	require.Equal(t, int32(1), calls.Load())
+}
+
+func TestWebhookRejected(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusGone)
+	}))
+	defer srv.Close()
+
+	store := memorystorage.New()
+	w := newTestWebhook(t, store, config.WebhookConf{URL: srv.URL})
+	err := w.Notify(context.Background(), queue.Notification{EventID: "e1", UserID: "user1"})
+	require.ErrorContains(t, err, "410 Gone")
+	// Отказ получателя не повторяется: сообщение сразу переносится в очередь недоставленных
+	require.ErrorIs(t, err, queue.ErrPermanent)
+	require.Equal(t, int32(1), calls.Load())
+	records := deliveries(t, store)
+	require.Len(t, records, 1)
+	require.Equal(t, 1, records[0].Attempts)
+	require.Contains(t, records[0].LastError, "410 Gone")
+}
+
+func TestWebhookCircuitBreaker(t *testing.T) {
+	var calls atomic.Int32
+	var healthy atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer srv.Close()
+
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	w := newTestWebhook(t, memorystorage.New(), config.WebhookConf{
+		URL:                srv.URL,
+		BreakerFailures:    3,
+		BreakerOpenSeconds: 60,
+	})
+	w.breaker.now = func() time.Time { return now }
+	ctx := context.Background()
+	notification := queue.Notification{EventID: "e1", UserID: "user1"}
+
+	for range 3 {
+		require.ErrorContains(t, w.Notify(ctx, notification), "500 Internal Server Error")
+	}
+	require.Equal(t, int32(3), calls.Load())
+	// Третья неудача подряд отключает адрес: запросы на него не отправляются, а сообщение повторяется позже
+	err := w.Notify(ctx, notification)
+	require.ErrorIs(t, err, ErrCircuitOpen)
+	require.NotErrorIs(t, err, queue.ErrPermanent)
+	require.Equal(t, int32(3), calls.Load())
+
+	// После паузы пробная попытка включает адрес; доставленное напоминание не отправляется повторно
+	now = now.Add(time.Minute)
+	healthy.Store(true)
+	require.NoError(t, w.Notify(ctx, notification))
+	require.NoError(t, w.Notify(ctx, notification))
+	require.Equal(t, int32(4), calls.Load())
+	require.NoError(t, w.Notify(ctx, queue.Notification{EventID: "e2", UserID: "user1"}))
+	require.Equal(t, int32(5), calls.Load())
+}
+
+func TestWebhookRefusesInternalAddress(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+	}))
+	defer srv.Close()
+
+	// Адрес пользователя, разрешающийся в адрес петли, отклоняется при подключении и не повторяется
+	store := memorystorage.New()
+	ctx := context.Background()
+	require.NoError(t, store.CreateWebhook(ctx, storage.Webhook{ID: "w1", UserID: "user1", URL: srv.URL}))
+	w := newTestWebhook(t, store, config.WebhookConf{})
+	err := w.Notify(ctx, queue.Notification{EventID: "e1", UserID: "user1"})
+	require.ErrorIs(t, err, netguard.ErrForbiddenAddress)
+	require.ErrorIs(t, err, queue.ErrPermanent)
+	require.Zero(t, calls.Load())
+	records := deliveries(t, store)
+	require.Len(t, records, 1)
+	require.Equal(t, 1, records[0].Attempts)
+
+	// Адрес из конфигурации задаёт оператор, он не ограничивается
+	w = newTestWebhook(t, store, config.WebhookConf{URL: srv.URL})
+	require.NoError(t, w.Notify(ctx, queue.Notification{EventID: "e1", UserID: "user1"}))
+	require.Equal(t, int32(1), calls.Load())
+}
@@ -0,0 +1,235 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// Заголовки сообщений, которые не удалось обработать.
+const (
+	RetriesHeader = "x-calendar-retries" // число выполненных повторов обработки
+	ErrorHeader   = "x-calendar-error"   // последняя ошибка обработки
+	DeadAtHeader  = "x-calendar-dead-at" // время переноса в очередь недоставленных
+)
+
+// ErrPermanent отмечает ошибку обработки, которую повтор не исправит: такое сообщение сразу
+// переносится в очередь недоставленных (см. Permanent).
+var ErrPermanent = errors.New("permanent failure")
+
+// Permanent отмечает ошибку обработки err как постоянную.
+func Permanent(err error) error {
+	return fmt.Errorf("%w: %w", ErrPermanent, err)
+}
+
+// maxErrorLength — наибольшая длина текста ошибки в заголовке ErrorHeader.
+const maxErrorLength = 1024
+
+// RetryPolicy определяет, сколько раз и с какими паузами повторяется обработка сообщения.
+type RetryPolicy struct {
+	MaxRetries int             // число повторов, после которого сообщение переносится в очередь недоставленных
+	Delays     []time.Duration // паузы перед повторами; последняя используется для всех следующих повторов
+}
+
+// NewRetryPolicy создаёт политику повторов из конфигурации: maxRetries 0 — 5 повторов, отрицательное —
+// без повторов; без delaysSeconds паузы — 10 секунд, минута и 5 минут. Паузы меньше секунды считаются секундой.
+func NewRetryPolicy(maxRetries int, delaysSeconds []int) RetryPolicy {
+	policy := RetryPolicy{MaxRetries: max(maxRetries, 0)}
+	if maxRetries == 0 {
+		policy.MaxRetries = 5
+	}
+	if len(delaysSeconds) == 0 {
+		delaysSeconds = []int{10, 60, 300}
+	}
+	for _, s := range delaysSeconds {
+		policy.Delays = append(policy.Delays, time.Duration(max(s, 1))*time.Second)
+	}
+	return policy
+}
+
+// delay возвращает паузу перед повтором номер retry (с 1).
+func (p RetryPolicy) delay(retry int) time.Duration {
+	return p.Delays[min(retry, len(p.Delays))-1]
+}
+
+// retryDelays возвращает различные паузы повторов, для каждой из которых нужна очередь повтора.
+func (p RetryPolicy) retryDelays() []time.Duration {
+	var delays []time.Duration
+	for retry := 1; retry <= min(p.MaxRetries, len(p.Delays)); retry++ {
+		if d := p.delay(retry); !slices.Contains(delays, d) {
+			delays = append(delays, d)
+		}
+	}
+	return delays
+}
+
+// RetryQueue возвращает имя очереди повтора для очереди queueName: сообщение лежит в ней delay
+// и затем возвращается в queueName.
+func RetryQueue(queueName string, delay time.Duration) string {
+	return fmt.Sprintf("%s.retry.%ds", queueName, int(delay.Seconds()))
+}
+
+// DeadLetterExchange возвращает имя обменника недоставленных сообщений очереди queueName.
+func DeadLetterExchange(queueName string) string {
+	return queueName + ".dlx"
+}
+
+// DeadLetterQueue возвращает имя очереди недоставленных сообщений очереди queueName.
+func DeadLetterQueue(queueName string) string {
+	return queueName + ".dead"
+}
+
+// DeadLetter — сообщение из очереди недоставленных.
+type DeadLetter struct {
+	Notification Notification // пусто, если тело сообщения не разобрано
+	Body         []byte       // тело сообщения
+	Retries      int          // число выполненных повторов обработки
+	Error        string       // последняя ошибка обработки
+	DeadAt       time.Time    // время переноса в очередь недоставленных
+}
+
+// newDeadLetter разбирает сообщение из очереди недоставленных.
+func newDeadLetter(d amqp.Delivery) DeadLetter {
+	letter := DeadLetter{
+		Body:    d.Body,
+		Retries: headerInt(d.Headers, RetriesHeader),
+	}
+	_ = json.Unmarshal(d.Body, &letter.Notification)
+	letter.Error, _ = d.Headers[ErrorHeader].(string)
+	letter.DeadAt, _ = d.Headers[DeadAtHeader].(time.Time)
+	return letter
+}
+
+// headerInt возвращает целочисленный заголовок сообщения (0, если его нет).
+func headerInt(headers amqp.Table, key string) int {
+	switch v := headers[key].(type) {
+	case int8:
+		return int(v)
+	case int16:
+		return int(v)
+	case int32:
+		return int(v)
+	case int64:
+		return int(v)
+	case int:
+		return v
+	}
+	return 0
+}
+
+// failureHeaders копирует заголовки сообщения без служебных заголовков брокера и повторов и добавляет
+// текст ошибки обработки.
+func failureHeaders(headers amqp.Table, cause error) amqp.Table {
+	result := amqp.Table{}
+	for k, v := range headers {
+		if k != "x-death" && k != RetriesHeader && k != DeadAtHeader {
+			result[k] = v
+		}
+	}
+	text := cause.Error()
+	if len(text) > maxErrorLength {
+		text = text[:maxErrorLength]
+	}
+	result[ErrorHeader] = text
+	return result
+}
+
+// reject переносит сообщение, которое не удалось обработать, в очередь повтора с паузой по политике,
+// а если повторы исчерпаны или повторять обработку бессмысленно (permanent) — в обменник недоставленных.
+// Исходное сообщение подтверждается только после того, как брокер принял копию; иначе оно возвращается в очередь.
+func (c *RabbitMQConsumer) reject(ctx context.Context, d amqp.Delivery, cause error, permanent bool) {
+	retries := headerInt(d.Headers, RetriesHeader)
+	headers := failureHeaders(d.Headers, cause)
+	exchange, key := DeadLetterExchange(c.queueName), c.queueName
+	if !permanent && retries < c.policy.MaxRetries {
+		headers[RetriesHeader] = int32(retries + 1)
+		exchange, key = "", RetryQueue(c.queueName, c.policy.delay(retries+1))
+	} else {
+		headers[RetriesHeader] = int32(retries)
+		headers[DeadAtHeader] = time.Now().UTC().Truncate(time.Second)
+	}
+	err := publish(ctx, c.channel, exchange, key, amqp.Publishing{
+		Headers:      headers,
+		ContentType:  d.ContentType,
+		DeliveryMode: amqp.Persistent,
+		Timestamp:    d.Timestamp,
+		Body:         d.Body,
+	})
+	if err != nil {
+		_ = d.Nack(false, true) // отклонить с повторной постановкой
+		return
+	}
+	_ = d.Ack(false)
+}
+
+// DeadLetters возвращает не более limit сообщений из очереди недоставленных очереди queueName,
+// не удаляя их: полученные сообщения возвращаются в очередь недоставленных.
+func (r *RabbitMQConnection) DeadLetters(ctx context.Context, queueName string, limit int) ([]DeadLetter, error) {
+	var (
+		letters []DeadLetter
+		lastTag uint64
+	)
+	defer func() {
+		if lastTag != 0 {
+			_ = r.channel.Nack(lastTag, true, true) // вернуть все полученные сообщения
+		}
+	}()
+	for len(letters) < limit && ctx.Err() == nil {
+		d, ok, err := r.channel.Get(DeadLetterQueue(queueName), false)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get dead letter: %w", err)
+		}
+		if !ok {
+			break
+		}
+		lastTag = d.DeliveryTag
+		letters = append(letters, newDeadLetter(d))
+	}
+	return letters, ctx.Err()
+}
+
+// ReplayDeadLetters возвращает не более limit сообщений из очереди недоставленных в очередь queueName
+// со сброшенным счётчиком повторов и возвращает их число. Сообщение удаляется из очереди недоставленных
+// только после того, как брокер принял его в queueName.
+func (r *RabbitMQConnection) ReplayDeadLetters(ctx context.Context, queueName string, limit int) (int, error) {
+	if err := r.channel.Confirm(false); err != nil {
+		return 0, fmt.Errorf("failed to enable publisher confirms: %w", err)
+	}
+	replayed := 0
+	for replayed < limit && ctx.Err() == nil {
+		d, ok, err := r.channel.Get(DeadLetterQueue(queueName), false)
+		if err != nil {
+			return replayed, fmt.Errorf("failed to get dead letter: %w", err)
+		}
+		if !ok {
+			break
+		}
+		headers := amqp.Table{}
+		for k, v := range d.Headers {
+			if k != "x-death" && k != RetriesHeader && k != ErrorHeader && k != DeadAtHeader {
+				headers[k] = v
+			}
+		}
+		err = publish(ctx, r.channel, "", queueName, amqp.Publishing{
+			Headers:      headers,
+			ContentType:  d.ContentType,
+			DeliveryMode: amqp.Persistent,
+			Timestamp:    d.Timestamp,
+			Body:         d.Body,
+		})
+		if err != nil {
+			_ = d.Nack(false, true)
+			return replayed, err
+		}
+		if err := d.Ack(false); err != nil {
+			return replayed, fmt.Errorf("failed to remove dead letter: %w", err)
+		}
+		replayed++
+	}
+	return replayed, ctx.Err()
+}
@@ -0,0 +1,67 @@
+package queue
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	policy := NewRetryPolicy(0, nil)
+	require.Equal(t, 5, policy.MaxRetries)
+	require.Equal(t, 10*time.Second, policy.delay(1))
+	require.Equal(t, time.Minute, policy.delay(2))
+	require.Equal(t, 5*time.Minute, policy.delay(3))
+	require.Equal(t, 5*time.Minute, policy.delay(5), "last delay is reused")
+	require.Equal(t, []time.Duration{10 * time.Second, time.Minute, 5 * time.Minute}, policy.retryDelays())
+
+	policy = NewRetryPolicy(2, []int{30, 30, 600, 0})
+	require.Equal(t, []time.Duration{30 * time.Second}, policy.retryDelays(), "only delays of allowed retries need queues")
+	require.Equal(t, time.Second, policy.Delays[3])
+
+	policy = NewRetryPolicy(-1, []int{5})
+	require.Zero(t, policy.MaxRetries)
+	require.Empty(t, policy.retryDelays())
+
+	require.Equal(t, "notifications.retry.60s", RetryQueue("notifications", time.Minute))
+	require.Equal(t, "notifications.dlx", DeadLetterExchange("notifications"))
+	require.Equal(t, "notifications.dead", DeadLetterQueue("notifications"))
+}
+
+func TestFailureHeaders(t *testing.T) {
+	headers := failureHeaders(amqp.Table{
+		"x-death":     []any{amqp.Table{"count": int64(1)}},
+		RetriesHeader: int32(2),
+		"trace-id":    "abc",
+	}, errors.New(strings.Repeat("x", 2*maxErrorLength)))
+	require.Equal(t, amqp.Table{"trace-id": "abc", ErrorHeader: strings.Repeat("x", maxErrorLength)}, headers)
+}
+
+func TestPermanent(t *testing.T) {
+	cause := errors.New("webhook responded with 410 Gone")
+	err := Permanent(cause)
+	require.ErrorIs(t, err, ErrPermanent)
+	require.ErrorIs(t, err, cause)
+	require.NotErrorIs(t, cause, ErrPermanent)
+}
+
+func TestNewDeadLetter(t *testing.T) {
+	deadAt := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	letter := newDeadLetter(amqp.Delivery{
+		Headers: amqp.Table{RetriesHeader: int64(5), ErrorHeader: "timeout", DeadAtHeader: deadAt},
+		Body:    []byte(`{"event_id":"e1","user_id":"user1","event_time":1000,"channel":"email"}`),
+	})
+	require.Equal(t, Notification{EventID: "e1", UserID: "user1", EventTime: 1000, Channel: "email"}, letter.Notification)
+	require.Equal(t, 5, letter.Retries)
+	require.Equal(t, "timeout", letter.Error)
+	require.Equal(t, deadAt, letter.DeadAt)
+
+	letter = newDeadLetter(amqp.Delivery{Body: []byte("not json")})
+	require.Zero(t, letter.Notification)
+	require.Zero(t, letter.Retries)
+	require.Equal(t, "not json", string(letter.Body))
+}
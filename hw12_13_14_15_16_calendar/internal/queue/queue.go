@@ -14,10 +14,12 @@ import (
 
 // Notification представляет уведомление о событии.
 type Notification struct {
-	EventID   string `json:"event_id"`   // ID события
-	Title     string `json:"title"`      // заголовок события
-	EventTime int64  `json:"event_time"` // время события (Unix timestamp)
-	UserID    string `json:"user_id"`    // ID пользователя
+	EventID      string `json:"event_id"`          // ID события
+	Title        string `json:"title"`             // заголовок события
+	EventTime    int64  `json:"event_time"`        // время события (Unix timestamp)
+	UserID       string `json:"user_id"`           // ID пользователя
+	NotifyBefore int64  `json:"notify_before"`     // за сколько секунд до события отправлено напоминание
+	Channel      string `json:"channel,omitempty"` // канал доставки; пусто — канал по умолчанию
 }
 
 // Publisher интерфейс для публикации сообщений в очередь.
@@ -37,6 +39,8 @@ type Connection interface {
 	DeclareQueue(ctx context.Context, queueName string) error
 	Publisher(queueName string) (Publisher, error)
 	Consumer(queueName string) (Consumer, error)
+	DeadLetters(ctx context.Context, queueName string, limit int) ([]DeadLetter, error)
+	ReplayDeadLetters(ctx context.Context, queueName string, limit int) (int, error)
 	Close() error
 }
 
@@ -45,10 +49,22 @@ type RabbitMQConnection struct {
 	conn    *amqp.Connection
 	channel *amqp.Channel
 	url     string
+	policy  RetryPolicy // политика повторов обработки сообщений
+}
+
+// Option настраивает соединение с очередью.
+type Option func(*RabbitMQConnection)
+
+// WithRetryPolicy задаёт политику повторов обработки сообщений (по умолчанию — NewRetryPolicy(0, nil)).
+// Политика должна совпадать у всех процессов, объявляющих очередь.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(r *RabbitMQConnection) {
+		r.policy = policy
+	}
 }
 
 // NewConnection создает новое соединение с RabbitMQ.
-func NewConnection(url string) (Connection, error) {
+func NewConnection(url string, opts ...Option) (Connection, error) {
 	conn, err := amqp.Dial(url)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to RabbitMQ: %w", err)
@@ -60,14 +76,21 @@ func NewConnection(url string) (Connection, error) {
 		return nil, fmt.Errorf("failed to open channel: %w", err)
 	}
 
-	return &RabbitMQConnection{
+	r := &RabbitMQConnection{
 		conn:    conn,
 		channel: ch,
 		url:     url,
-	}, nil
+		policy:  NewRetryPolicy(0, nil),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r, nil
 }
 
-// DeclareQueue объявляет очередь в RabbitMQ.
+// DeclareQueue объявляет очередь в RabbitMQ вместе с очередями повтора (по одной на паузу политики повторов:
+// сообщение лежит в ней до истечения TTL и возвращается в queueName) и обменником и очередью
+// недоставленных сообщений.
 func (r *RabbitMQConnection) DeclareQueue(ctx context.Context, queueName string) error {
 	_, err := r.channel.QueueDeclare(
 		queueName, // name
@@ -80,11 +103,38 @@ func (r *RabbitMQConnection) DeclareQueue(ctx context.Context, queueName string)
 	if err != nil {
 		return fmt.Errorf("failed to declare queue: %w", err)
 	}
+
+	for _, delay := range r.policy.retryDelays() {
+		_, err := r.channel.QueueDeclare(RetryQueue(queueName, delay), true, false, false, false, amqp.Table{
+			"x-message-ttl":             delay.Milliseconds(),
+			"x-dead-letter-exchange":    "", // по истечении TTL сообщение возвращается в queueName
+			"x-dead-letter-routing-key": queueName,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to declare retry queue: %w", err)
+		}
+	}
+
+	dlx, dlq := DeadLetterExchange(queueName), DeadLetterQueue(queueName)
+	if err := r.channel.ExchangeDeclare(dlx, amqp.ExchangeFanout, true, false, false, false, nil); err != nil {
+		return fmt.Errorf("failed to declare dead letter exchange: %w", err)
+	}
+	if _, err := r.channel.QueueDeclare(dlq, true, false, false, false, nil); err != nil {
+		return fmt.Errorf("failed to declare dead letter queue: %w", err)
+	}
+	if err := r.channel.QueueBind(dlq, "", dlx, false, nil); err != nil {
+		return fmt.Errorf("failed to bind dead letter queue: %w", err)
+	}
 	return nil
 }
 
 // Publisher возвращает Publisher для публикации сообщений.
+// Канал переводится в режим подтверждений (publisher confirms), поэтому Publish завершается
+// только после того, как брокер принял сообщение.
 func (r *RabbitMQConnection) Publisher(queueName string) (Publisher, error) {
+	if err := r.channel.Confirm(false); err != nil {
+		return nil, fmt.Errorf("failed to enable publisher confirms: %w", err)
+	}
 	return &RabbitMQPublisher{
 		channel:   r.channel,
 		queueName: queueName,
@@ -92,10 +142,15 @@ func (r *RabbitMQConnection) Publisher(queueName string) (Publisher, error) {
 }
 
 // Consumer возвращает Consumer для потребления сообщений.
+// Сообщения, которые не удалось обработать, публикуются в очереди повтора с подтверждениями брокера.
 func (r *RabbitMQConnection) Consumer(queueName string) (Consumer, error) {
+	if err := r.channel.Confirm(false); err != nil {
+		return nil, fmt.Errorf("failed to enable publisher confirms: %w", err)
+	}
 	return &RabbitMQConsumer{
 		channel:   r.channel,
 		queueName: queueName,
+		policy:    r.policy,
 	}, nil
 }
 
@@ -118,31 +173,45 @@ type RabbitMQPublisher struct {
 	queueName string
 }
 
-// Publish публикует уведомление в очередь.
+// Publish публикует уведомление в очередь и ожидает подтверждения от брокера.
+// Ошибка возвращается, если брокер не подтвердил сообщение (nack) или не ответил за отведённое время;
+// в этом случае сообщение могло быть как доставлено, так и потеряно, и его нужно опубликовать повторно.
 func (p *RabbitMQPublisher) Publish(ctx context.Context, notification Notification) error {
 	body, err := json.Marshal(notification)
 	if err != nil {
 		return fmt.Errorf("failed to marshal notification: %w", err)
 	}
 
+	return publish(ctx, p.channel, "", p.queueName, amqp.Publishing{
+		ContentType:  "application/json",
+		DeliveryMode: amqp.Persistent, // сообщение сохраняется на диск
+		Body:         body,
+		Timestamp:    time.Now(),
+	})
+}
+
+// publish публикует сообщение в канал в режиме подтверждений и ожидает подтверждения от брокера.
+func publish(ctx context.Context, channel *amqp.Channel, exchange, key string, msg amqp.Publishing) error {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	err = p.channel.PublishWithContext(ctx,
-		"",          // exchange (default)
-		p.queueName, // routing key (queue name)
-		false,       // mandatory
-		false,       // immediate
-		amqp.Publishing{
-			ContentType:  "application/json",
-			DeliveryMode: amqp.Persistent, // сообщение сохраняется на диск
-			Body:         body,
-			Timestamp:    time.Now(),
-		})
+	confirm, err := channel.PublishWithDeferredConfirmWithContext(ctx,
+		exchange, // exchange ("" — по умолчанию)
+		key,      // routing key (имя очереди для обменника по умолчанию)
+		false,    // mandatory
+		false,    // immediate
+		msg)
 	if err != nil {
 		return fmt.Errorf("failed to publish message: %w", err)
 	}
 
+	acked, err := confirm.WaitContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to wait for publisher confirm: %w", err)
+	}
+	if !acked {
+		return fmt.Errorf("%w: message was not acknowledged by broker", ErrPublishFailed)
+	}
 	return nil
 }
 
@@ -156,9 +225,12 @@ func (p *RabbitMQPublisher) Close() error {
 type RabbitMQConsumer struct {
 	channel   *amqp.Channel
 	queueName string
+	policy    RetryPolicy
 }
 
-// Consume начинает потребление сообщений из очереди.
+// Consume начинает потребление сообщений из очереди. Сообщение, которое handler не смог обработать,
+// повторяется через очередь повтора не более policy.MaxRetries раз, затем переносится в очередь
+// недоставленных; сообщение, которое не удалось разобрать, переносится туда сразу.
 func (c *RabbitMQConsumer) Consume(ctx context.Context, handler func(Notification) error) error {
 	// Устанавливаем prefetch для балансировки нагрузки
 	err := c.channel.Qos(
@@ -195,16 +267,15 @@ func (c *RabbitMQConsumer) Consume(ctx context.Context, handler func(Notificatio
 
 				var notification Notification
 				if err := json.Unmarshal(d.Body, &notification); err != nil {
-					// Логируем ошибку, но не подтверждаем сообщение
-					// В реальном приложении можно отправить в dead letter queue
-					_ = d.Nack(false, false) // отклонить без повторной постановки
+					// Повтор не поможет - сразу в очередь недоставленных
+					c.reject(ctx, d, fmt.Errorf("malformed message: %w", err), true)
 					continue
 				}
 
 				// Обрабатываем уведомление
 				if err := handler(notification); err != nil {
-					// Ошибка обработки - отклоняем сообщение
-					_ = d.Nack(false, true) // отклонить с повторной постановкой
+					// Ошибка обработки - повтор после паузы, если ошибка не постоянная
+					c.reject(ctx, d, err, errors.Is(err, ErrPermanent))
 					continue
 				}
 
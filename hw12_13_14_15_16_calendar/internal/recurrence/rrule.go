@@ -0,0 +1,504 @@
+// Package recurrence реализует разбор и развёртывание правил повторения событий (RRULE из RFC 5545).
+// Поддерживаются FREQ=DAILY/WEEKLY/MONTHLY/YEARLY, INTERVAL, COUNT, UNTIL, BYDAY, BYMONTHDAY, BYMONTH и WKST.
+package recurrence
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Frequency — частота повторения (FREQ).
+type Frequency int
+
+const (
+	Daily   Frequency = iota // FREQ=DAILY
+	Weekly                   // FREQ=WEEKLY
+	Monthly                  // FREQ=MONTHLY
+	Yearly                   // FREQ=YEARLY
+)
+
+// maxEmptyPeriods — число периодов подряд без экземпляров, после которого серия считается законченной.
+// Корректное правило даёт экземпляр хотя бы раз в несколько периодов (29 февраля — раз в 8 лет), а правило,
+// которое экземпляров больше не даёт (например, BYMONTHDAY=30 с BYMONTH=2), не зацикливает развёртывание.
+const maxEmptyPeriods = 1000
+
+// ErrInvalidRule — ошибка разбора правила повторения.
+var ErrInvalidRule = errors.New("invalid recurrence rule")
+
+// WeekdayNum — день недели в BYDAY с необязательным порядковым номером (например, 1MO или -1FR).
+type WeekdayNum struct {
+	N       int          // порядковый номер внутри месяца/года, 0 — все такие дни
+	Weekday time.Weekday // день недели
+}
+
+// Rule — разобранное правило повторения.
+type Rule struct {
+	Freq       Frequency    // частота
+	Interval   int          // интервал между периодами (по умолчанию 1)
+	Count      int          // общее число экземпляров, 0 — без ограничения
+	Until      time.Time    // время последнего допустимого экземпляра (включительно), нулевое — без ограничения
+	ByDay      []WeekdayNum // дни недели
+	ByMonthDay []int        // дни месяца (отрицательные — с конца месяца)
+	ByMonth    []time.Month // месяцы
+	WeekStart  time.Weekday // первый день недели (WKST), по умолчанию понедельник
+}
+
+var freqNames = map[string]Frequency{
+	"DAILY":   Daily,
+	"WEEKLY":  Weekly,
+	"MONTHLY": Monthly,
+	"YEARLY":  Yearly,
+}
+
+var weekdayNames = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+// Parse разбирает строку правила вида "FREQ=WEEKLY;BYDAY=MO,WE;COUNT=10".
+// Префикс "RRULE:" допускается и игнорируется.
+func Parse(s string) (Rule, error) {
+	r := Rule{Interval: 1, WeekStart: time.Monday}
+	s = strings.TrimPrefix(strings.TrimSpace(s), "RRULE:")
+	if s == "" {
+		return r, fmt.Errorf("%w: empty rule", ErrInvalidRule)
+	}
+	hasFreq := false
+	for _, part := range strings.Split(s, ";") {
+		name, value, ok := strings.Cut(part, "=")
+		if !ok || value == "" {
+			return r, fmt.Errorf("%w: malformed part %q", ErrInvalidRule, part)
+		}
+		var err error
+		switch strings.ToUpper(name) {
+		case "FREQ":
+			f, known := freqNames[strings.ToUpper(value)]
+			if !known {
+				return r, fmt.Errorf("%w: unsupported FREQ %q", ErrInvalidRule, value)
+			}
+			r.Freq, hasFreq = f, true
+		case "INTERVAL":
+			r.Interval, err = strconv.Atoi(value)
+			if err == nil && r.Interval < 1 {
+				err = errors.New("must be positive")
+			}
+		case "COUNT":
+			r.Count, err = strconv.Atoi(value)
+			if err == nil && r.Count < 1 {
+				err = errors.New("must be positive")
+			}
+		case "UNTIL":
+			r.Until, err = parseUntil(value)
+		case "BYDAY":
+			r.ByDay, err = parseByDay(value)
+		case "BYMONTHDAY":
+			r.ByMonthDay, err = parseIntList(value, 1, 31)
+		case "BYMONTH":
+			var months []int
+			months, err = parseIntList(value, 1, 12)
+			for _, m := range months {
+				if m < 0 {
+					err = errors.New("must be positive")
+				}
+				r.ByMonth = append(r.ByMonth, time.Month(m))
+			}
+		case "WKST":
+			wd, known := weekdayNames[strings.ToUpper(value)]
+			if !known {
+				err = errors.New("unknown weekday")
+			}
+			r.WeekStart = wd
+		default:
+			return r, fmt.Errorf("%w: unsupported part %q", ErrInvalidRule, name)
+		}
+		if err != nil {
+			return r, fmt.Errorf("%w: %s=%s: %v", ErrInvalidRule, name, value, err)
+		}
+	}
+	if !hasFreq {
+		return r, fmt.Errorf("%w: FREQ is required", ErrInvalidRule)
+	}
+	if r.Count > 0 && !r.Until.IsZero() {
+		return r, fmt.Errorf("%w: COUNT and UNTIL are mutually exclusive", ErrInvalidRule)
+	}
+	for _, d := range r.ByDay {
+		if d.N != 0 && r.Freq != Monthly && r.Freq != Yearly {
+			return r, fmt.Errorf("%w: ordinal BYDAY is allowed only for MONTHLY and YEARLY", ErrInvalidRule)
+		}
+	}
+	return r, nil
+}
+
+// String возвращает правило в нормализованном виде RFC 5545 (без префикса "RRULE:").
+func (r Rule) String() string {
+	var parts []string
+	for name, f := range freqNames {
+		if f == r.Freq {
+			parts = append(parts, "FREQ="+name)
+		}
+	}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if !r.Until.IsZero() {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, 0, len(r.ByDay))
+		for _, d := range r.ByDay {
+			day := weekdayName(d.Weekday)
+			if d.N != 0 {
+				day = strconv.Itoa(d.N) + day
+			}
+			days = append(days, day)
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if len(r.ByMonthDay) > 0 {
+		parts = append(parts, "BYMONTHDAY="+joinInts(r.ByMonthDay))
+	}
+	if len(r.ByMonth) > 0 {
+		months := make([]int, 0, len(r.ByMonth))
+		for _, m := range r.ByMonth {
+			months = append(months, int(m))
+		}
+		parts = append(parts, "BYMONTH="+joinInts(months))
+	}
+	if r.WeekStart != time.Monday {
+		parts = append(parts, "WKST="+weekdayName(r.WeekStart))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Between возвращает времена начала экземпляров серии, начинающихся в интервале [from, to).
+// dtstart — начало первого экземпляра; оно всегда считается экземпляром серии.
+// Время суток берётся из dtstart в его часовом поясе, поэтому переходы на летнее время учитываются.
+func (r Rule) Between(dtstart, from, to time.Time) []time.Time {
+	var result []time.Time
+	r.iterate(dtstart, from, func(t time.Time) bool {
+		if !t.Before(to) {
+			return false
+		}
+		if !t.Before(from) {
+			result = append(result, t)
+		}
+		return true
+	})
+	return result
+}
+
+// Last возвращает время начала последнего экземпляра серии.
+// Если серия не ограничена COUNT или UNTIL, возвращает false.
+func (r Rule) Last(dtstart time.Time) (time.Time, bool) {
+	if r.Count == 0 && r.Until.IsZero() {
+		return time.Time{}, false
+	}
+	last := dtstart
+	r.iterate(dtstart, dtstart, func(t time.Time) bool {
+		last = t
+		return true
+	})
+	return last, true
+}
+
+// Contains сообщает, является ли t временем начала одного из экземпляров серии.
+func (r Rule) Contains(dtstart, t time.Time) bool {
+	return len(r.Between(dtstart, t, t.Add(time.Second))) > 0
+}
+
+// iterate перебирает экземпляры серии по возрастанию, пока yield возвращает true, с учётом COUNT и UNTIL.
+// Перебор начинается с периода, содержащего from (см. seek): более ранние экземпляры могут быть пропущены.
+func (r Rule) iterate(dtstart, from time.Time, yield func(time.Time) bool) {
+	count := 0
+	emit := func(t time.Time) bool {
+		if !r.Until.IsZero() && t.After(r.Until) {
+			return false
+		}
+		count++
+		if !yield(t) {
+			return false
+		}
+		return r.Count == 0 || count < r.Count
+	}
+	first := r.seek(dtstart, from)
+	if first == 0 && !emit(dtstart) {
+		return
+	}
+	for period, empty := first, 0; empty < maxEmptyPeriods; period++ {
+		empty++
+		for _, t := range r.candidates(dtstart, period) {
+			if !t.After(dtstart) {
+				continue
+			}
+			empty = 0
+			if !emit(t) {
+				return
+			}
+		}
+	}
+}
+
+// seek возвращает номер периода, с которого перебор находит все экземпляры, начинающиеся не раньше from.
+// Серия с COUNT перебирается с первого периода: чтобы узнать, не исчерпан ли COUNT, нужно пересчитать
+// все предыдущие экземпляры.
+func (r Rule) seek(dtstart, from time.Time) int {
+	if r.Count > 0 || !from.After(dtstart) {
+		return 0
+	}
+	from = from.In(dtstart.Location())
+	days := civilDay(from) - civilDay(dtstart)
+	var elapsed int
+	switch r.Freq {
+	case Daily:
+		elapsed = days
+	case Weekly:
+		elapsed = days / 7
+	case Monthly:
+		elapsed = (from.Year()-dtstart.Year())*12 + int(from.Month()-dtstart.Month())
+	case Yearly:
+		elapsed = from.Year() - dtstart.Year()
+	}
+	// Границы периодов не совпадают с календарными (неделя начинается с WKST), поэтому берётся
+	// предыдущий период
+	return max(elapsed/r.Interval-1, 0)
+}
+
+// civilDay возвращает номер календарного дня t (в часовом поясе t), не зависящий от переходов на летнее время.
+func civilDay(t time.Time) int {
+	y, m, d := t.Date()
+	return int(time.Date(y, m, d, 0, 0, 0, 0, time.UTC).Unix() / 86400)
+}
+
+// candidates возвращает отсортированные экземпляры в периоде с номером period, отсчитанном от dtstart.
+func (r Rule) candidates(dtstart time.Time, period int) []time.Time {
+	loc := dtstart.Location()
+	hh, mm, ss := dtstart.Clock()
+	at := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, hh, mm, ss, 0, loc)
+	}
+	step := period * r.Interval
+	y, m, d := dtstart.Date()
+
+	var days []time.Time
+	switch r.Freq {
+	case Daily:
+		day := at(y, m, d+step)
+		if r.matchesWeekday(day) {
+			days = append(days, day)
+		}
+	case Weekly:
+		offset := (int(dtstart.Weekday()) - int(r.WeekStart) + 7) % 7
+		weekStart := at(y, m, d-offset+7*step)
+		if len(r.ByDay) == 0 {
+			days = append(days, at(y, m, d+7*step))
+			break
+		}
+		for i := 0; i < 7; i++ {
+			day := at(weekStart.Year(), weekStart.Month(), weekStart.Day()+i)
+			if r.matchesWeekday(day) {
+				days = append(days, day)
+			}
+		}
+	case Monthly:
+		first := at(y, m+time.Month(step), 1)
+		days = r.monthDays(first, d, at)
+	case Yearly:
+		year := y + step
+		switch {
+		case len(r.ByMonth) > 0:
+			for _, month := range r.ByMonth {
+				days = append(days, r.monthDays(at(year, month, 1), d, at)...)
+			}
+		case len(r.ByMonthDay) > 0:
+			for month := time.January; month <= time.December; month++ {
+				days = append(days, r.monthDays(at(year, month, 1), d, at)...)
+			}
+		case len(r.ByDay) > 0:
+			days = r.yearDays(year, at)
+		default:
+			days = r.monthDays(at(year, m, 1), d, at)
+		}
+	}
+	days = r.filterMonths(days)
+	sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+	return days
+}
+
+// monthDays возвращает экземпляры внутри месяца, начинающегося с first.
+// Без BYDAY и BYMONTHDAY используется день месяца dtstart (месяцы без такого дня пропускаются).
+func (r Rule) monthDays(first time.Time, startDay int, at func(int, time.Month, int) time.Time) []time.Time {
+	year, month := first.Year(), first.Month()
+	daysInMonth := at(year, month+1, 0).Day()
+
+	monthDays := r.ByMonthDay
+	if len(monthDays) == 0 && len(r.ByDay) == 0 {
+		monthDays = []int{startDay}
+	}
+	var result []time.Time
+	if len(monthDays) > 0 {
+		for _, md := range monthDays {
+			if md < 0 {
+				md = daysInMonth + md + 1
+			}
+			if md < 1 || md > daysInMonth {
+				continue
+			}
+			day := at(year, month, md)
+			if len(r.ByDay) == 0 || r.matchesWeekday(day) {
+				result = append(result, day)
+			}
+		}
+		return result
+	}
+	for _, wd := range r.ByDay {
+		result = append(result, nthWeekdays(wd, year, month, 1, daysInMonth, at)...)
+	}
+	return result
+}
+
+// yearDays возвращает экземпляры BYDAY в пределах всего года (порядковые номера считаются от начала/конца года).
+func (r Rule) yearDays(year int, at func(int, time.Month, int) time.Time) []time.Time {
+	daysInYear := at(year, time.December, 31).YearDay()
+	var result []time.Time
+	for _, wd := range r.ByDay {
+		result = append(result, nthWeekdays(wd, year, time.January, 1, daysInYear, at)...)
+	}
+	return result
+}
+
+// nthWeekdays возвращает дни недели wd.Weekday в диапазоне дней [firstDay, lastDay], отсчитанных от начала month.
+// При wd.N != 0 возвращается только N-й такой день (отрицательный N — с конца диапазона).
+func nthWeekdays(wd WeekdayNum, year int, month time.Month, firstDay, lastDay int,
+	at func(int, time.Month, int) time.Time,
+) []time.Time {
+	var all []time.Time
+	for d := firstDay; d <= lastDay; d++ {
+		day := at(year, month, d)
+		if day.Weekday() == wd.Weekday {
+			all = append(all, day)
+		}
+	}
+	switch {
+	case wd.N == 0:
+		return all
+	case wd.N > 0 && wd.N <= len(all):
+		return []time.Time{all[wd.N-1]}
+	case wd.N < 0 && -wd.N <= len(all):
+		return []time.Time{all[len(all)+wd.N]}
+	}
+	return nil
+}
+
+// matchesWeekday проверяет день по BYDAY без порядковых номеров.
+func (r Rule) matchesWeekday(t time.Time) bool {
+	if len(r.ByDay) == 0 {
+		return true
+	}
+	for _, d := range r.ByDay {
+		if d.Weekday == t.Weekday() {
+			return true
+		}
+	}
+	return false
+}
+
+// filterMonths оставляет только дни из BYMONTH (если он задан).
+func (r Rule) filterMonths(days []time.Time) []time.Time {
+	if len(r.ByMonth) == 0 {
+		return days
+	}
+	result := days[:0]
+	for _, day := range days {
+		for _, m := range r.ByMonth {
+			if day.Month() == m {
+				result = append(result, day)
+				break
+			}
+		}
+	}
+	return result
+}
+
+func parseUntil(value string) (time.Time, error) {
+	if t, err := time.Parse("20060102T150405Z", value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse("20060102", value)
+	if err != nil {
+		return time.Time{}, errors.New("expected YYYYMMDD or YYYYMMDDTHHMMSSZ")
+	}
+	// Дата без времени включает весь указанный день
+	return t.Add(24*time.Hour - time.Second), nil
+}
+
+func parseByDay(value string) ([]WeekdayNum, error) {
+	var result []WeekdayNum
+	for _, item := range strings.Split(value, ",") {
+		item = strings.ToUpper(strings.TrimSpace(item))
+		if len(item) < 2 {
+			return nil, fmt.Errorf("bad weekday %q", item)
+		}
+		wd, ok := weekdayNames[item[len(item)-2:]]
+		if !ok {
+			return nil, fmt.Errorf("bad weekday %q", item)
+		}
+		n := 0
+		if prefix := item[:len(item)-2]; prefix != "" {
+			var err error
+			n, err = strconv.Atoi(prefix)
+			if err != nil || n == 0 || n < -53 || n > 53 {
+				return nil, fmt.Errorf("bad weekday ordinal %q", item)
+			}
+		}
+		result = append(result, WeekdayNum{N: n, Weekday: wd})
+	}
+	return result, nil
+}
+
+func parseIntList(value string, minAbs, maxAbs int) ([]int, error) {
+	var result []int
+	for _, item := range strings.Split(value, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(item))
+		if err != nil {
+			return nil, err
+		}
+		abs := n
+		if abs < 0 {
+			abs = -abs
+		}
+		if abs < minAbs || abs > maxAbs {
+			return nil, fmt.Errorf("value %d out of range", n)
+		}
+		result = append(result, n)
+	}
+	return result, nil
+}
+
+func weekdayName(wd time.Weekday) string {
+	for name, d := range weekdayNames {
+		if d == wd {
+			return name
+		}
+	}
+	return ""
+}
+
+func joinInts(values []int) string {
+	parts := make([]string, 0, len(values))
+	for _, v := range values {
+		parts = append(parts, strconv.Itoa(v))
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,180 @@
+package recurrence
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func date(y int, m time.Month, d, hh int) time.Time {
+	return time.Date(y, m, d, hh, 0, 0, 0, time.UTC)
+}
+
+// TestParse проверяет разбор корректных и некорректных правил.
+func TestParse(t *testing.T) {
+	r, err := Parse("RRULE:FREQ=MONTHLY;INTERVAL=2;COUNT=5;BYDAY=1MO,-1FR")
+	require.NoError(t, err)
+	require.Equal(t, Monthly, r.Freq)
+	require.Equal(t, 2, r.Interval)
+	require.Equal(t, 5, r.Count)
+	require.Equal(t, []WeekdayNum{{N: 1, Weekday: time.Monday}, {N: -1, Weekday: time.Friday}}, r.ByDay)
+	require.Equal(t, "FREQ=MONTHLY;INTERVAL=2;COUNT=5;BYDAY=1MO,-1FR", r.String())
+
+	for _, bad := range []string{
+		"",
+		"INTERVAL=2",
+		"FREQ=HOURLY",
+		"FREQ=DAILY;COUNT=0",
+		"FREQ=DAILY;COUNT=3;UNTIL=20240101",
+		"FREQ=WEEKLY;BYDAY=1MO",
+		"FREQ=DAILY;BYHOUR=10",
+		"FREQ=DAILY;BYDAY=XX",
+	} {
+		_, err := Parse(bad)
+		require.True(t, errors.Is(err, ErrInvalidRule), bad)
+	}
+}
+
+// TestBetween проверяет развёртывание серий разных частот.
+func TestBetween(t *testing.T) {
+	cases := []struct {
+		name    string
+		rule    string
+		dtstart time.Time
+		from    time.Time
+		to      time.Time
+		want    []time.Time
+	}{
+		{
+			name:    "daily count",
+			rule:    "FREQ=DAILY;COUNT=3",
+			dtstart: date(2024, 7, 1, 10),
+			from:    date(2024, 6, 1, 0),
+			to:      date(2024, 8, 1, 0),
+			want:    []time.Time{date(2024, 7, 1, 10), date(2024, 7, 2, 10), date(2024, 7, 3, 10)},
+		},
+		{
+			name:    "weekly byday window",
+			rule:    "FREQ=WEEKLY;BYDAY=MO,WE",
+			dtstart: date(2024, 7, 1, 9), // понедельник
+			from:    date(2024, 7, 8, 0),
+			to:      date(2024, 7, 15, 0),
+			want:    []time.Time{date(2024, 7, 8, 9), date(2024, 7, 10, 9)},
+		},
+		{
+			name:    "biweekly until",
+			rule:    "FREQ=WEEKLY;INTERVAL=2;UNTIL=20240729T090000Z",
+			dtstart: date(2024, 7, 1, 9),
+			from:    date(2024, 7, 1, 0),
+			to:      date(2024, 9, 1, 0),
+			want:    []time.Time{date(2024, 7, 1, 9), date(2024, 7, 15, 9), date(2024, 7, 29, 9)},
+		},
+		{
+			name:    "monthly skips short months",
+			rule:    "FREQ=MONTHLY;COUNT=3",
+			dtstart: date(2024, 1, 31, 12),
+			from:    date(2024, 1, 1, 0),
+			to:      date(2025, 1, 1, 0),
+			want:    []time.Time{date(2024, 1, 31, 12), date(2024, 3, 31, 12), date(2024, 5, 31, 12)},
+		},
+		{
+			name:    "monthly last friday",
+			rule:    "FREQ=MONTHLY;BYDAY=-1FR;COUNT=3",
+			dtstart: date(2024, 7, 26, 18),
+			from:    date(2024, 7, 1, 0),
+			to:      date(2025, 1, 1, 0),
+			want:    []time.Time{date(2024, 7, 26, 18), date(2024, 8, 30, 18), date(2024, 9, 27, 18)},
+		},
+		{
+			name:    "yearly by month and day",
+			rule:    "FREQ=YEARLY;BYMONTH=11;BYDAY=4TH;COUNT=2",
+			dtstart: date(2024, 11, 28, 15),
+			from:    date(2024, 1, 1, 0),
+			to:      date(2030, 1, 1, 0),
+			want:    []time.Time{date(2024, 11, 28, 15), date(2025, 11, 27, 15)},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := Parse(tc.rule)
+			require.NoError(t, err)
+			require.Equal(t, tc.want, r.Between(tc.dtstart, tc.from, tc.to))
+		})
+	}
+}
+
+// TestBetweenDST проверяет, что локальное время экземпляров сохраняется при переходе на летнее время.
+func TestBetweenDST(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	r, err := Parse("FREQ=DAILY;COUNT=3")
+	require.NoError(t, err)
+
+	dtstart := time.Date(2024, 3, 30, 9, 0, 0, 0, loc)
+	got := r.Between(dtstart, dtstart, dtstart.AddDate(0, 0, 7))
+	require.Len(t, got, 3)
+	for _, occ := range got {
+		require.Equal(t, 9, occ.Hour())
+	}
+	require.Equal(t, 23*time.Hour, got[1].Sub(got[0]))
+}
+
+// TestLastAndContains проверяет вычисление конца серии и принадлежности экземпляра.
+func TestLastAndContains(t *testing.T) {
+	r, err := Parse("FREQ=WEEKLY;COUNT=4")
+	require.NoError(t, err)
+	dtstart := date(2024, 7, 1, 9)
+
+	last, ok := r.Last(dtstart)
+	require.True(t, ok)
+	require.Equal(t, date(2024, 7, 22, 9), last)
+	require.True(t, r.Contains(dtstart, date(2024, 7, 15, 9)))
+	require.False(t, r.Contains(dtstart, date(2024, 7, 16, 9)))
+	require.False(t, r.Contains(dtstart, date(2024, 7, 29, 9)))
+
+	infinite, err := Parse("FREQ=DAILY")
+	require.NoError(t, err)
+	_, ok = infinite.Last(dtstart)
+	require.False(t, ok)
+}
+
+// TestBetweenSeek проверяет, что развёртывание с середины серии совпадает с развёртыванием с её начала
+// и что длинные серии не обрезаются.
+func TestBetweenSeek(t *testing.T) {
+	dtstart := date(2024, 1, 31, 9) // среда
+	from, to := date(2031, 3, 1, 0), date(2031, 6, 1, 0)
+	for _, rule := range []string{
+		"FREQ=DAILY;INTERVAL=3",
+		"FREQ=DAILY;BYDAY=SA,SU",
+		"FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,SU;WKST=SU",
+		"FREQ=WEEKLY;INTERVAL=5",
+		"FREQ=MONTHLY;INTERVAL=2",
+		"FREQ=MONTHLY;BYDAY=-1FR",
+		"FREQ=YEARLY;BYMONTH=4;BYDAY=1MO",
+		"FREQ=DAILY;UNTIL=20310415T090000Z",
+	} {
+		r, err := Parse(rule)
+		require.NoError(t, err, rule)
+		var want []time.Time
+		for _, occ := range r.Between(dtstart, dtstart, to) {
+			if !occ.Before(from) {
+				want = append(want, occ)
+			}
+		}
+		require.NotEmpty(t, want, rule)
+		require.Equal(t, want, r.Between(dtstart, from, to), rule)
+	}
+
+	// Ежедневная серия длиннее 100000 дней (около 274 лет)
+	r, err := Parse("FREQ=DAILY")
+	require.NoError(t, err)
+	got := r.Between(date(1800, 1, 1, 8), date(2300, 1, 1, 0), date(2300, 1, 3, 0))
+	require.Equal(t, []time.Time{date(2300, 1, 1, 8), date(2300, 1, 2, 8)}, got)
+
+	// Правило без экземпляров после первого не зацикливает развёртывание
+	r, err = Parse("FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=30")
+	require.NoError(t, err)
+	require.Equal(t, []time.Time{dtstart}, r.Between(dtstart, dtstart, date(2100, 1, 1, 0)))
+}
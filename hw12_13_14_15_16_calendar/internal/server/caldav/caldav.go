@@ -0,0 +1,267 @@
+// Package caldav реализует сервер CalDAV (RFC 4791) поверх бизнес-логики календаря.
+//
+// Пространство ресурсов:
+//
+//	/caldav/                          — корень сервера
+//	/caldav/{user}/                   — принципал пользователя и домашняя коллекция календарей
+//	/caldav/{user}/calendar/          — календарь пользователя
+//	/caldav/{user}/calendar/{id}.ics  — событие (вместе с изменёнными экземплярами серии)
+//
+// Поддерживаются OPTIONS, PROPFIND (Depth 0 и 1), REPORT calendar-query (фильтр по time-range)
+// и calendar-multiget, а также GET, PUT и DELETE ресурсов событий с условиями If-Match / If-None-Match.
+package caldav
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/app"
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/storage"
+	"github.com/google/uuid"
+)
+
+// Prefix — путь, по которому обработчик монтируется в HTTP-сервере.
+const Prefix = "/caldav/"
+
+// calendarName — имя единственного календаря пользователя.
+const calendarName = "calendar"
+
+// Application определяет методы бизнес-логики, которые использует CalDAV-сервер.
+// Реализуется *app.App.
+type Application interface {
+	CalendarObjects(ctx context.Context, userID string, start, end int64) ([]app.CalendarObject, error)
+	GetCalendarObject(ctx context.Context, userID, id string) (app.CalendarObject, error)
+	PutCalendarObject(ctx context.Context, userID, id string, data []byte) (app.CalendarObject, bool, error)
+	DeleteCalendarObject(ctx context.Context, userID, id string) error
+	Logger() app.Logger
+}
+
+// Handler — HTTP-обработчик CalDAV.
+type Handler struct {
+	app Application
+}
+
+// NewHandler создаёт обработчик CalDAV с внедрённой бизнес-логикой.
+func NewHandler(app Application) *Handler {
+	return &Handler{app: app}
+}
+
+// resourceKind — тип ресурса, на который указывает путь запроса.
+type resourceKind int
+
+const (
+	rootResource resourceKind = iota
+	principalResource
+	calendarResource
+	objectResource
+)
+
+// resource — разобранный путь запроса.
+type resource struct {
+	kind   resourceKind
+	userID string
+	name   string // имя ресурса события без расширения .ics
+}
+
+// parsePath разбирает путь запроса в ресурс. Возвращает false, если такого ресурса нет.
+func parsePath(p string) (resource, bool) {
+	rest := strings.TrimPrefix(path.Clean("/"+p), strings.TrimSuffix(Prefix, "/"))
+	parts := strings.FieldsFunc(rest, func(r rune) bool { return r == '/' })
+	switch {
+	case len(parts) == 0:
+		return resource{kind: rootResource}, true
+	case len(parts) == 1:
+		return resource{kind: principalResource, userID: parts[0]}, true
+	case parts[1] != calendarName:
+		return resource{}, false
+	case len(parts) == 2:
+		return resource{kind: calendarResource, userID: parts[0]}, true
+	case len(parts) == 3 && strings.HasSuffix(parts[2], ".ics") && len(parts[2]) > len(".ics"):
+		return resource{kind: objectResource, userID: parts[0], name: strings.TrimSuffix(parts[2], ".ics")}, true
+	}
+	return resource{}, false
+}
+
+// objectID возвращает ID события для имени ресурса: имя в виде UUID используется как есть,
+// остальные имена детерминированно переводятся в UUID v5 в пространстве имён пользователя.
+func (r resource) objectID() string {
+	if id, err := uuid.Parse(r.name); err == nil {
+		return id.String()
+	}
+	return uuid.NewSHA1(uuid.NameSpaceURL, []byte(Prefix+r.userID+"/"+calendarName+"/"+r.name)).String()
+}
+
+func principalHref(userID string) string {
+	return Prefix + userID + "/"
+}
+
+func calendarHref(userID string) string {
+	return principalHref(userID) + calendarName + "/"
+}
+
+func objectHref(userID, id string) string {
+	return calendarHref(userID) + id + ".ics"
+}
+
+// ServeHTTP маршрутизирует запрос по методу.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	res, ok := parsePath(r.URL.Path)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("DAV", "1, 3, calendar-access")
+	switch r.Method {
+	case http.MethodOptions:
+		w.Header().Set("Allow", "OPTIONS, GET, HEAD, PUT, DELETE, PROPFIND, REPORT")
+		w.WriteHeader(http.StatusOK)
+	case "PROPFIND":
+		h.propfind(w, r, res)
+	case "REPORT":
+		h.report(w, r, res)
+	case http.MethodGet, http.MethodHead:
+		h.get(w, r, res)
+	case http.MethodPut:
+		h.put(w, r, res)
+	case http.MethodDelete:
+		h.delete(w, r, res)
+	default:
+		w.Header().Set("Allow", "OPTIONS, GET, HEAD, PUT, DELETE, PROPFIND, REPORT")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// get отдаёт событие в формате iCalendar.
+func (h *Handler) get(w http.ResponseWriter, r *http.Request, res resource) {
+	if res.kind != objectResource {
+		http.Error(w, "not a calendar object", http.StatusMethodNotAllowed)
+		return
+	}
+	object, err := h.app.GetCalendarObject(r.Context(), res.userID, res.objectID())
+	if err != nil {
+		h.error(w, "GET", err)
+		return
+	}
+	data, err := object.ICal()
+	if err != nil {
+		h.error(w, "GET", err)
+		return
+	}
+	w.Header().Set("Content-Type", calendarContentType)
+	w.Header().Set("ETag", object.ETag())
+	w.WriteHeader(http.StatusOK)
+	if r.Method != http.MethodHead {
+		_, _ = w.Write(data)
+	}
+}
+
+// put создаёт или заменяет событие. Поддерживаются условия If-Match и If-None-Match: *.
+func (h *Handler) put(w http.ResponseWriter, r *http.Request, res resource) {
+	if res.kind != objectResource {
+		http.Error(w, "not a calendar object", http.StatusMethodNotAllowed)
+		return
+	}
+	id := res.objectID()
+	if !h.checkPreconditions(w, r, res.userID, id) {
+		return
+	}
+	data, err := readBody(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	object, created, err := h.app.PutCalendarObject(r.Context(), res.userID, id, data)
+	if err != nil {
+		h.error(w, "PUT", err)
+		return
+	}
+	w.Header().Set("ETag", object.ETag())
+	if created {
+		w.Header().Set("Location", objectHref(res.userID, id))
+		w.WriteHeader(http.StatusCreated)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// delete удаляет событие вместе с изменёнными экземплярами серии.
+func (h *Handler) delete(w http.ResponseWriter, r *http.Request, res resource) {
+	if res.kind != objectResource {
+		http.Error(w, "only calendar objects can be deleted", http.StatusForbidden)
+		return
+	}
+	id := res.objectID()
+	if !h.checkPreconditions(w, r, res.userID, id) {
+		return
+	}
+	if err := h.app.DeleteCalendarObject(r.Context(), res.userID, id); err != nil {
+		h.error(w, "DELETE", err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// checkPreconditions проверяет заголовки If-Match и If-None-Match (RFC 7232).
+// При невыполненном условии отвечает 412 и возвращает false.
+func (h *Handler) checkPreconditions(w http.ResponseWriter, r *http.Request, userID, id string) bool {
+	ifMatch, ifNoneMatch := r.Header.Get("If-Match"), r.Header.Get("If-None-Match")
+	if ifMatch == "" && ifNoneMatch == "" {
+		return true
+	}
+	object, err := h.app.GetCalendarObject(r.Context(), userID, id)
+	exists := err == nil
+	if err != nil && !errors.Is(err, app.ErrNotFound) {
+		h.error(w, r.Method, err)
+		return false
+	}
+	failed := false
+	if ifMatch != "" {
+		failed = !exists || (ifMatch != "*" && !matchETag(ifMatch, object.ETag()))
+	}
+	if ifNoneMatch != "" && exists {
+		failed = failed || ifNoneMatch == "*" || matchETag(ifNoneMatch, object.ETag())
+	}
+	if failed {
+		http.Error(w, "precondition failed", http.StatusPreconditionFailed)
+		return false
+	}
+	return true
+}
+
+// matchETag сообщает, входит ли etag в список меток заголовка If-Match / If-None-Match.
+func matchETag(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(candidate), "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// error отвечает кодом HTTP, соответствующим ошибке бизнес-логики.
+func (h *Handler) error(w http.ResponseWriter, method string, err error) {
+	code := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, app.ErrNoOccurrence), errors.Is(err, storage.ErrValidation):
+		// RECURRENCE-ID, которого нет в серии, — ошибка в присланных данных
+		code = http.StatusBadRequest
+	case errors.Is(err, storage.ErrNotFound):
+		code = http.StatusNotFound
+	case errors.Is(err, storage.ErrPermissionDenied):
+		code = http.StatusForbidden
+	case errors.Is(err, storage.ErrDateBusy), errors.Is(err, storage.ErrAlreadyExists):
+		code = http.StatusConflict
+	case errors.Is(err, storage.ErrVersionConflict):
+		// Событие изменили между проверкой If-Match и записью
+		code = http.StatusPreconditionFailed
+	}
+	if code == http.StatusInternalServerError {
+		h.app.Logger().Error("CalDAV " + method + " error: " + err.Error())
+	} else {
+		h.app.Logger().Debug("CalDAV " + method + ": " + err.Error())
+	}
+	http.Error(w, err.Error(), code)
+}
@@ -0,0 +1,206 @@
+package caldav
+
+import (
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/app"
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/auth"
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/logger"
+	memorystorage "github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/storage/memory"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// multistatus — разбор ответа 207 в тестах.
+type multistatus struct {
+	Responses []struct {
+		Href     string `xml:"DAV: href"`
+		Status   string `xml:"DAV: status"`
+		Propstat []struct {
+			Status string `xml:"DAV: status"`
+			Prop   struct {
+				ETag         string `xml:"DAV: getetag"`
+				CalendarData string `xml:"urn:ietf:params:xml:ns:caldav calendar-data"`
+				HomeSet      struct {
+					Href string `xml:"DAV: href"`
+				} `xml:"urn:ietf:params:xml:ns:caldav calendar-home-set"`
+				ResourceType struct {
+					Calendar *struct{} `xml:"urn:ietf:params:xml:ns:caldav calendar"`
+				} `xml:"DAV: resourcetype"`
+			} `xml:"DAV: prop"`
+		} `xml:"DAV: propstat"`
+	} `xml:"DAV: response"`
+}
+
+type testClient struct {
+	t   *testing.T
+	url string
+}
+
+func (c testClient) do(method, path, body string, headers map[string]string) (*http.Response, string) {
+	c.t.Helper()
+	req, err := http.NewRequest(method, c.url+path, strings.NewReader(body))
+	require.NoError(c.t, err)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(c.t, err)
+	defer func() { _ = resp.Body.Close() }()
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(c.t, err)
+	return resp, string(data)
+}
+
+func (c testClient) multistatus(method, path, body, depth string) multistatus {
+	c.t.Helper()
+	resp, data := c.do(method, path, body, map[string]string{"Depth": depth, "Content-Type": "application/xml"})
+	require.Equal(c.t, http.StatusMultiStatus, resp.StatusCode, data)
+	var ms multistatus
+	require.NoError(c.t, xml.Unmarshal([]byte(data), &ms), data)
+	return ms
+}
+
+func newTestClient(t *testing.T) testClient {
+	t.Helper()
+	h := NewHandler(app.New(logger.New("ERROR"), memorystorage.New()))
+	// Аутентификацию выполняет HTTP-сервер; без неё вызывающая сторона — auth.Anonymous
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.ServeHTTP(w, r.WithContext(auth.WithIdentity(r.Context(), auth.Anonymous)))
+	}))
+	t.Cleanup(ts.Close)
+	return testClient{t: t, url: ts.URL}
+}
+
+func vevent(uid, summary, start, end string) string {
+	return strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//Example//Client//EN",
+		"BEGIN:VEVENT",
+		"UID:" + uid,
+		"DTSTART:" + start,
+		"DTEND:" + end,
+		"SUMMARY:" + summary,
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+}
+
+// TestCalDAVSync проверяет сценарий синхронизации стандартного клиента:
+// обнаружение календаря, создание, выборку по времени, изменение и удаление события.
+func TestCalDAVSync(t *testing.T) {
+	c := newTestClient(t)
+	id := uuid.NewString()
+	href := "/caldav/user1/calendar/" + id + ".ics"
+
+	// Обнаружение домашней коллекции и календаря
+	ms := c.multistatus("PROPFIND", "/caldav/user1/",
+		`<D:propfind xmlns:D="DAV:" xmlns:C="urn:ietf:params:xml:ns:caldav"><D:prop>`+
+			`<C:calendar-home-set/><D:resourcetype/><D:unknown-prop/></D:prop></D:propfind>`, "1")
+	require.Len(t, ms.Responses, 2)
+	require.Equal(t, "/caldav/user1/", ms.Responses[0].Propstat[0].Prop.HomeSet.Href)
+	require.Len(t, ms.Responses[0].Propstat, 2)
+	require.Contains(t, ms.Responses[0].Propstat[1].Status, "404")
+	require.Equal(t, "/caldav/user1/calendar/", ms.Responses[1].Href)
+	require.NotNil(t, ms.Responses[1].Propstat[0].Prop.ResourceType.Calendar)
+
+	// Создание события
+	resp, body := c.do(http.MethodPut, href, vevent(id, "Встреча", "20240719T100000Z", "20240719T110000Z"),
+		map[string]string{"If-None-Match": "*", "Content-Type": "text/calendar"})
+	require.Equal(t, http.StatusCreated, resp.StatusCode, body)
+	etag := resp.Header.Get("ETag")
+	require.NotEmpty(t, etag)
+
+	resp, _ = c.do(http.MethodPut, href, vevent(id, "Дубль", "20240719T100000Z", "20240719T110000Z"),
+		map[string]string{"If-None-Match": "*"})
+	require.Equal(t, http.StatusPreconditionFailed, resp.StatusCode)
+
+	// Список ресурсов календаря с метками версий
+	ms = c.multistatus("PROPFIND", "/caldav/user1/calendar/",
+		`<D:propfind xmlns:D="DAV:"><D:prop><D:getetag/></D:prop></D:propfind>`, "1")
+	require.Len(t, ms.Responses, 2)
+	require.Equal(t, href, ms.Responses[1].Href)
+	require.Equal(t, etag, ms.Responses[1].Propstat[0].Prop.ETag)
+
+	// Выборка по диапазону времени
+	query := func(start, end string) multistatus {
+		return c.multistatus("REPORT", "/caldav/user1/calendar/",
+			`<C:calendar-query xmlns:D="DAV:" xmlns:C="urn:ietf:params:xml:ns:caldav">`+
+				`<D:prop><D:getetag/><C:calendar-data/></D:prop>`+
+				`<C:filter><C:comp-filter name="VCALENDAR"><C:comp-filter name="VEVENT">`+
+				`<C:time-range start="`+start+`" end="`+end+`"/>`+
+				`</C:comp-filter></C:comp-filter></C:filter></C:calendar-query>`, "1")
+	}
+	ms = query("20240719T000000Z", "20240720T000000Z")
+	require.Len(t, ms.Responses, 1)
+	require.Contains(t, ms.Responses[0].Propstat[0].Prop.CalendarData, "SUMMARY:Встреча")
+	require.Empty(t, query("20240720T000000Z", "20240721T000000Z").Responses)
+
+	// Получение нескольких ресурсов по ссылкам
+	ms = c.multistatus("REPORT", "/caldav/user1/calendar/",
+		`<C:calendar-multiget xmlns:D="DAV:" xmlns:C="urn:ietf:params:xml:ns:caldav">`+
+			`<D:prop><D:getetag/><C:calendar-data/></D:prop>`+
+			`<D:href>`+href+`</D:href><D:href>/caldav/user1/calendar/missing.ics</D:href>`+
+			`</C:calendar-multiget>`, "1")
+	require.Len(t, ms.Responses, 2)
+	require.Contains(t, ms.Responses[0].Status, "404")
+	require.Equal(t, href, ms.Responses[1].Href)
+	require.Equal(t, etag, ms.Responses[1].Propstat[0].Prop.ETag)
+
+	// Изменение с проверкой версии
+	update := vevent(id, "Встреча перенесена", "20240720T100000Z", "20240720T110000Z")
+	resp, _ = c.do(http.MethodPut, href, update, map[string]string{"If-Match": `"stale"`})
+	require.Equal(t, http.StatusPreconditionFailed, resp.StatusCode)
+	resp, body = c.do(http.MethodPut, href, update, map[string]string{"If-Match": etag})
+	require.Equal(t, http.StatusNoContent, resp.StatusCode, body)
+	require.NotEqual(t, etag, resp.Header.Get("ETag"))
+	require.Len(t, query("20240720T000000Z", "20240721T000000Z").Responses, 1)
+
+	resp, body = c.do(http.MethodGet, href, "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Contains(t, body, "SUMMARY:Встреча перенесена")
+
+	// Чужой календарь не видит событие
+	resp, _ = c.do(http.MethodGet, "/caldav/user2/calendar/"+id+".ics", "", nil)
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	// Удаление
+	resp, _ = c.do(http.MethodDelete, href, "", nil)
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+	resp, _ = c.do(http.MethodGet, href, "", nil)
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+	resp, _ = c.do(http.MethodDelete, href, "", nil)
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+// TestCalDAVInvalidData проверяет ответы на некорректные данные и пересечение событий.
+func TestCalDAVInvalidData(t *testing.T) {
+	c := newTestClient(t)
+
+	resp, _ := c.do(http.MethodPut, "/caldav/user1/calendar/bad.ics", "not a calendar", nil)
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp, body := c.do(http.MethodPut, "/caldav/user1/calendar/first.ics",
+		vevent("first", "Первое", "20240719T100000Z", "20240719T110000Z"), nil)
+	require.Equal(t, http.StatusCreated, resp.StatusCode, body)
+	resp, _ = c.do(http.MethodPut, "/caldav/user1/calendar/second.ics",
+		vevent("second", "Второе", "20240719T103000Z", "20240719T113000Z"), nil)
+	require.Equal(t, http.StatusConflict, resp.StatusCode)
+
+	// Ресурс с именем не в виде UUID доступен по исходному имени
+	resp, body = c.do(http.MethodGet, "/caldav/user1/calendar/first.ics", "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Contains(t, body, "SUMMARY:Первое")
+
+	resp, _ = c.do(http.MethodOptions, "/caldav/", "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Contains(t, resp.Header.Get("DAV"), "calendar-access")
+	resp, _ = c.do(http.MethodGet, "/caldav/user1/other/", "", nil)
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
@@ -0,0 +1,293 @@
+package caldav
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"math"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/app"
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/auth"
+)
+
+// Имена свойств ресурсов.
+var (
+	propResourceType         = xml.Name{Space: nsDAV, Local: "resourcetype"}
+	propDisplayName          = xml.Name{Space: nsDAV, Local: "displayname"}
+	propCurrentUserPrincipal = xml.Name{Space: nsDAV, Local: "current-user-principal"}
+	propPrincipalURL         = xml.Name{Space: nsDAV, Local: "principal-URL"}
+	propPrivilegeSet         = xml.Name{Space: nsDAV, Local: "current-user-privilege-set"}
+	propSupportedReportSet   = xml.Name{Space: nsDAV, Local: "supported-report-set"}
+	propGetETag              = xml.Name{Space: nsDAV, Local: "getetag"}
+	propGetContentType       = xml.Name{Space: nsDAV, Local: "getcontenttype"}
+	propCalendarHomeSet      = xml.Name{Space: nsCalDAV, Local: "calendar-home-set"}
+	propSupportedComponents  = xml.Name{Space: nsCalDAV, Local: "supported-calendar-component-set"}
+	propCalendarData         = xml.Name{Space: nsCalDAV, Local: "calendar-data"}
+	propGetCTag              = xml.Name{Space: nsCS, Local: "getctag"}
+)
+
+// Имена отчётов REPORT.
+var (
+	reportCalendarQuery    = xml.Name{Space: nsCalDAV, Local: "calendar-query"}
+	reportCalendarMultiget = xml.Name{Space: nsCalDAV, Local: "calendar-multiget"}
+)
+
+const privileges = "<D:privilege><D:read/></D:privilege><D:privilege><D:write/></D:privilege>" +
+	"<D:privilege><D:write-content/></D:privilege><D:privilege><D:bind/></D:privilege>" +
+	"<D:privilege><D:unbind/></D:privilege>"
+
+// propfind отвечает на запрос PROPFIND свойствами ресурса и, при Depth: 1, его дочерних ресурсов.
+func (h *Handler) propfind(w http.ResponseWriter, r *http.Request, res resource) {
+	var req propfindRequest
+	hasBody, err := decodeXML(r, &req)
+	if err != nil {
+		http.Error(w, "invalid PROPFIND body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	// Пустое тело равносильно allprop (RFC 4918, раздел 9.1)
+	allProp := !hasBody || req.AllProp != nil
+	depth := r.Header.Get("Depth")
+
+	var responses []response
+	switch res.kind {
+	case rootResource:
+		responses = append(responses, selectProps(Prefix, rootProps(r), req.Prop, allProp, req.PropName != nil))
+	case principalResource:
+		responses = append(responses,
+			selectProps(principalHref(res.userID), principalProps(res.userID), req.Prop, allProp, req.PropName != nil))
+		if depth != "0" {
+			props, err := h.calendarProps(r, res.userID)
+			if err != nil {
+				h.error(w, "PROPFIND", err)
+				return
+			}
+			responses = append(responses,
+				selectProps(calendarHref(res.userID), props, req.Prop, allProp, req.PropName != nil))
+		}
+	case calendarResource:
+		objects, err := h.app.CalendarObjects(r.Context(), res.userID, 0, 0)
+		if err != nil {
+			h.error(w, "PROPFIND", err)
+			return
+		}
+		responses = append(responses, selectProps(calendarHref(res.userID),
+			calendarPropsFor(res.userID, objects), req.Prop, allProp, req.PropName != nil))
+		if depth != "0" {
+			for _, object := range objects {
+				responses = append(responses, selectProps(objectHref(res.userID, object.Event.ID),
+					objectProps(res.userID, object, false), req.Prop, allProp, req.PropName != nil))
+			}
+		}
+	case objectResource:
+		object, err := h.app.GetCalendarObject(r.Context(), res.userID, res.objectID())
+		if err != nil {
+			h.error(w, "PROPFIND", err)
+			return
+		}
+		responses = append(responses, selectProps(objectHref(res.userID, object.Event.ID),
+			objectProps(res.userID, object, false), req.Prop, allProp, req.PropName != nil))
+	}
+	writeMultistatus(w, responses)
+}
+
+// report выполняет REPORT calendar-query или calendar-multiget над календарём пользователя.
+func (h *Handler) report(w http.ResponseWriter, r *http.Request, res resource) {
+	if res.kind != calendarResource {
+		http.Error(w, "REPORT is supported only for calendar collections", http.StatusForbidden)
+		return
+	}
+	var req reportRequest
+	if _, err := decodeXML(r, &req); err != nil {
+		http.Error(w, "invalid REPORT body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	var objects []app.CalendarObject
+	var responses []response
+	switch req.XMLName {
+	case reportCalendarQuery:
+		start, end, matches, err := queryRange(req)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if matches {
+			if objects, err = h.app.CalendarObjects(r.Context(), res.userID, start, end); err != nil {
+				h.error(w, "REPORT", err)
+				return
+			}
+		}
+	case reportCalendarMultiget:
+		for _, href := range req.Hrefs {
+			target, ok := parseHref(href)
+			if !ok || target.kind != objectResource || target.userID != res.userID {
+				responses = append(responses, response{href: href, status: http.StatusNotFound})
+				continue
+			}
+			object, err := h.app.GetCalendarObject(r.Context(), res.userID, target.objectID())
+			if err != nil {
+				responses = append(responses, response{href: href, status: http.StatusNotFound})
+				continue
+			}
+			objects = append(objects, object)
+		}
+	default:
+		http.Error(w, "unsupported report "+req.XMLName.Local, http.StatusForbidden)
+		return
+	}
+	for _, object := range objects {
+		responses = append(responses, selectProps(objectHref(res.userID, object.Event.ID),
+			objectProps(res.userID, object, true), req.Prop, len(req.Prop) == 0, false))
+	}
+	writeMultistatus(w, responses)
+}
+
+// queryRange извлекает диапазон времени из фильтра calendar-query.
+// Поддерживается фильтр VCALENDAR > VEVENT с необязательным time-range; фильтр по другим компонентам
+// (например, VTODO) ничего не находит, что сообщается третьим результатом.
+func queryRange(req reportRequest) (int64, int64, bool, error) {
+	if req.Filter == nil {
+		return 0, 0, true, nil
+	}
+	root := req.Filter.CompFilter
+	if !strings.EqualFold(root.Name, "VCALENDAR") {
+		return 0, 0, false, nil
+	}
+	if len(root.CompFilters) == 0 {
+		return 0, 0, true, nil
+	}
+	event := root.CompFilters[0]
+	if !strings.EqualFold(event.Name, "VEVENT") {
+		return 0, 0, false, nil
+	}
+	if event.TimeRange == nil {
+		return 0, 0, true, nil
+	}
+	start, end := int64(math.MinInt32), int64(math.MaxInt32)
+	if event.TimeRange.Start != "" {
+		t, err := time.Parse("20060102T150405Z", event.TimeRange.Start)
+		if err != nil {
+			return 0, 0, false, err
+		}
+		start = t.Unix()
+	}
+	if event.TimeRange.End != "" {
+		t, err := time.Parse("20060102T150405Z", event.TimeRange.End)
+		if err != nil {
+			return 0, 0, false, err
+		}
+		end = t.Unix()
+	}
+	return start, end, start < end, nil
+}
+
+// parseHref разбирает ссылку на ресурс (абсолютный URL или путь).
+func parseHref(href string) (resource, bool) {
+	u, err := url.Parse(href)
+	if err != nil || !strings.HasPrefix(u.Path, Prefix) {
+		return resource{}, false
+	}
+	return parsePath(u.Path)
+}
+
+// selectProps отбирает запрошенные свойства ресурса. При allProp возвращаются все свойства,
+// кроме calendar-data; при propName — только имена свойств.
+func selectProps(href string, props []property, requested []xml.Name, allProp, propName bool) response {
+	resp := response{href: href}
+	if allProp || propName {
+		for _, p := range props {
+			if p.name == propCalendarData {
+				continue
+			}
+			if propName {
+				p.inner = ""
+			}
+			resp.found = append(resp.found, p)
+		}
+		return resp
+	}
+	for _, name := range requested {
+		found := false
+		for _, p := range props {
+			if p.name == name {
+				resp.found = append(resp.found, p)
+				found = true
+				break
+			}
+		}
+		if !found {
+			resp.missing = append(resp.missing, name)
+		}
+	}
+	return resp
+}
+
+// rootProps возвращает свойства корня. Аутентифицированному пользователю сообщается адрес
+// его принципала, чтобы клиент мог найти свой календарь (RFC 5397).
+func rootProps(r *http.Request) []property {
+	props := []property{
+		{propResourceType, "<D:collection/>"},
+		{propDisplayName, "Calendar"},
+	}
+	if id, ok := auth.FromContext(r.Context()); ok && !id.IsService() {
+		props = append(props, property{propCurrentUserPrincipal, hrefXML(principalHref(id.UserID))})
+	}
+	return props
+}
+
+func principalProps(userID string) []property {
+	return []property{
+		{propResourceType, "<D:collection/><D:principal/>"},
+		{propDisplayName, escape(userID)},
+		{propCurrentUserPrincipal, hrefXML(principalHref(userID))},
+		{propPrincipalURL, hrefXML(principalHref(userID))},
+		{propCalendarHomeSet, hrefXML(principalHref(userID))},
+	}
+}
+
+// calendarProps возвращает свойства календаря пользователя.
+func (h *Handler) calendarProps(r *http.Request, userID string) ([]property, error) {
+	objects, err := h.app.CalendarObjects(r.Context(), userID, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	return calendarPropsFor(userID, objects), nil
+}
+
+// calendarPropsFor возвращает свойства календаря; CS:getctag меняется при любом изменении событий.
+func calendarPropsFor(userID string, objects []app.CalendarObject) []property {
+	ctag := sha256.New()
+	for _, object := range objects {
+		ctag.Write([]byte(object.Event.ID + object.ETag()))
+	}
+	return []property{
+		{propResourceType, "<D:collection/><C:calendar/>"},
+		{propDisplayName, escape(userID)},
+		{propCurrentUserPrincipal, hrefXML(principalHref(userID))},
+		{propPrivilegeSet, privileges},
+		{propSupportedComponents, `<C:comp name="VEVENT"/>`},
+		{propSupportedReportSet, "<D:supported-report><D:report><C:calendar-query/></D:report></D:supported-report>" +
+			"<D:supported-report><D:report><C:calendar-multiget/></D:report></D:supported-report>"},
+		{propGetCTag, `"` + hex.EncodeToString(ctag.Sum(nil)[:16]) + `"`},
+	}
+}
+
+// objectProps возвращает свойства ресурса события; calendar-data формируется только при withData.
+func objectProps(userID string, object app.CalendarObject, withData bool) []property {
+	props := []property{
+		{propResourceType, ""},
+		{propGetETag, escape(object.ETag())},
+		{propGetContentType, "text/calendar; charset=utf-8; component=VEVENT"},
+		{propCurrentUserPrincipal, hrefXML(principalHref(userID))},
+		{propPrivilegeSet, privileges},
+	}
+	if withData {
+		if data, err := object.ICal(); err == nil {
+			props = append(props, property{propCalendarData, escape(string(data))})
+		}
+	}
+	return props
+}
@@ -0,0 +1,180 @@
+package caldav
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Пространства имён XML, используемые в ответах.
+const (
+	nsDAV    = "DAV:"
+	nsCalDAV = "urn:ietf:params:xml:ns:caldav"
+	nsCS     = "http://calendarserver.org/ns/"
+)
+
+// maxBodySize ограничивает размер тела запроса.
+const maxBodySize = 1 << 20
+
+const calendarContentType = "text/calendar; charset=utf-8"
+
+// prefixes — префиксы, объявленные в корне ответа multistatus.
+var prefixes = map[string]string{nsDAV: "D", nsCalDAV: "C", nsCS: "CS"}
+
+// propNames — список имён свойств из элемента DAV:prop запроса.
+type propNames []xml.Name
+
+// UnmarshalXML собирает имена дочерних элементов, пропуская их содержимое.
+func (p *propNames) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			*p = append(*p, t.Name)
+			if err := d.Skip(); err != nil {
+				return err
+			}
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+// propfindRequest — тело запроса PROPFIND (RFC 4918, раздел 14.20).
+type propfindRequest struct {
+	XMLName  xml.Name  `xml:"DAV: propfind"`
+	AllProp  *struct{} `xml:"DAV: allprop"`
+	PropName *struct{} `xml:"DAV: propname"`
+	Prop     propNames `xml:"DAV: prop"`
+}
+
+// reportRequest — тело запроса REPORT calendar-query или calendar-multiget (RFC 4791, разделы 7.8 и 7.9).
+type reportRequest struct {
+	XMLName xml.Name
+	Prop    propNames `xml:"DAV: prop"`
+	Hrefs   []string  `xml:"DAV: href"`
+	Filter  *struct {
+		CompFilter compFilter `xml:"urn:ietf:params:xml:ns:caldav comp-filter"`
+	} `xml:"urn:ietf:params:xml:ns:caldav filter"`
+}
+
+// compFilter — фильтр компонента calendar-query.
+type compFilter struct {
+	Name      string `xml:"name,attr"`
+	TimeRange *struct {
+		Start string `xml:"start,attr"`
+		End   string `xml:"end,attr"`
+	} `xml:"urn:ietf:params:xml:ns:caldav time-range"`
+	CompFilters []compFilter `xml:"urn:ietf:params:xml:ns:caldav comp-filter"`
+}
+
+// property — значение свойства ресурса: имя и XML-содержимое.
+type property struct {
+	name  xml.Name
+	inner string
+}
+
+// response — элемент DAV:response ответа multistatus.
+type response struct {
+	href    string
+	status  int        // код для ресурса целиком (например, 404 в calendar-multiget), 0 — по свойствам
+	found   []property // найденные свойства
+	missing []xml.Name // отсутствующие свойства
+}
+
+// readBody читает тело запроса с ограничением размера.
+func readBody(r *http.Request) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxBodySize {
+		return nil, errors.New("request body is too large")
+	}
+	return data, nil
+}
+
+// decodeXML разбирает тело запроса в v. Пустое тело не считается ошибкой и сообщается вторым результатом.
+func decodeXML(r *http.Request, v any) (bool, error) {
+	data, err := readBody(r)
+	if err != nil {
+		return false, err
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return false, nil
+	}
+	return true, xml.Unmarshal(data, v)
+}
+
+// writeMultistatus отвечает 207 Multi-Status со списком ресурсов.
+func writeMultistatus(w http.ResponseWriter, responses []response) {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<D:multistatus xmlns:D="DAV:" xmlns:C="` + nsCalDAV + `" xmlns:CS="` + nsCS + `">`)
+	for _, resp := range responses {
+		b.WriteString("<D:response><D:href>")
+		b.WriteString(escape(resp.href))
+		b.WriteString("</D:href>")
+		if resp.status != 0 {
+			b.WriteString("<D:status>" + statusLine(resp.status) + "</D:status>")
+		}
+		if len(resp.found) > 0 {
+			b.WriteString("<D:propstat><D:prop>")
+			for _, p := range resp.found {
+				writeElement(&b, p.name, p.inner)
+			}
+			b.WriteString("</D:prop><D:status>" + statusLine(http.StatusOK) + "</D:status></D:propstat>")
+		}
+		if len(resp.missing) > 0 {
+			b.WriteString("<D:propstat><D:prop>")
+			for _, name := range resp.missing {
+				writeElement(&b, name, "")
+			}
+			b.WriteString("</D:prop><D:status>" + statusLine(http.StatusNotFound) + "</D:status></D:propstat>")
+		}
+		b.WriteString("</D:response>")
+	}
+	b.WriteString("</D:multistatus>")
+
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.WriteHeader(http.StatusMultiStatus)
+	_, _ = io.WriteString(w, b.String())
+}
+
+// writeElement записывает элемент с известным префиксом или с локальным объявлением пространства имён.
+func writeElement(b *strings.Builder, name xml.Name, inner string) {
+	tag, decl := name.Local, ""
+	if prefix, ok := prefixes[name.Space]; ok {
+		tag = prefix + ":" + name.Local
+	} else if name.Space != "" {
+		tag, decl = "x:"+name.Local, ` xmlns:x="`+escape(name.Space)+`"`
+	}
+	if inner == "" {
+		b.WriteString("<" + tag + decl + "/>")
+		return
+	}
+	b.WriteString("<" + tag + decl + ">" + inner + "</" + tag + ">")
+}
+
+func statusLine(code int) string {
+	return "HTTP/1.1 " + strconv.Itoa(code) + " " + http.StatusText(code)
+}
+
+// escape экранирует текст для вставки в XML.
+func escape(s string) string {
+	var b bytes.Buffer
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// hrefXML возвращает содержимое свойства со ссылкой DAV:href.
+func hrefXML(href string) string {
+	return "<D:href>" + escape(href) + "</D:href>"
+}
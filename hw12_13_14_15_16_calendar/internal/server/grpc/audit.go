@@ -0,0 +1,69 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	pb "github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/api/gen"
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/storage"
+)
+
+// GetEventHistory реализует получение истории изменений события через GRPC.
+func (s *Server) GetEventHistory(ctx context.Context, req *pb.GetEventHistoryRequest) (*pb.AuditLogResponse, error) {
+	s.app.Logger().Info("GRPC GetEventHistory: " + req.GetId())
+	entries, err := s.app.GetEventHistory(ctx, req.GetId(), int(req.GetLimit()))
+	if err != nil {
+		s.app.Logger().Error("GetEventHistory error: " + err.Error())
+		return nil, appError(err)
+	}
+	return auditLogToProto(entries), nil
+}
+
+// ListUserActivity реализует получение последних действий пользователя через GRPC.
+func (s *Server) ListUserActivity(ctx context.Context, req *pb.ListUserActivityRequest) (*pb.AuditLogResponse, error) {
+	s.app.Logger().Info("GRPC ListUserActivity: " + req.GetUserId())
+	entries, err := s.app.ListUserActivity(ctx, req.GetUserId(), int(req.GetLimit()))
+	if err != nil {
+		s.app.Logger().Error("ListUserActivity error: " + err.Error())
+		return nil, appError(err)
+	}
+	return auditLogToProto(entries), nil
+}
+
+// auditLogToProto преобразует записи журнала аудита в pb.AuditLogResponse.
+func auditLogToProto(entries []storage.AuditEntry) *pb.AuditLogResponse {
+	resp := &pb.AuditLogResponse{}
+	for _, e := range entries {
+		changes := make([]*pb.FieldChange, 0, len(e.Changes))
+		for _, c := range e.Changes {
+			changes = append(changes, &pb.FieldChange{Field: c.Field, Before: c.Before, After: c.After})
+		}
+		resp.Entries = append(resp.Entries, &pb.AuditEntry{
+			EventId:   e.EventID,
+			OwnerId:   e.OwnerID,
+			ActorId:   e.ActorID,
+			Service:   e.Service,
+			Action:    auditActionToProto(e.Action),
+			Changes:   changes,
+			CreatedAt: time.Unix(e.CreatedAt, 0).Format(time.RFC3339),
+		})
+	}
+	return resp
+}
+
+// auditActionToProto преобразует действие журнала аудита в pb.AuditAction.
+func auditActionToProto(action storage.AuditAction) pb.AuditAction {
+	switch action {
+	case storage.AuditCreated:
+		return pb.AuditAction_EVENT_CREATED
+	case storage.AuditUpdated:
+		return pb.AuditAction_EVENT_UPDATED
+	case storage.AuditDeleted:
+		return pb.AuditAction_EVENT_DELETED
+	case storage.AuditRestored:
+		return pb.AuditAction_EVENT_RESTORED
+	case storage.AuditPurged:
+		return pb.AuditAction_EVENT_PURGED
+	}
+	return pb.AuditAction_AUDIT_ACTION_UNSPECIFIED
+}
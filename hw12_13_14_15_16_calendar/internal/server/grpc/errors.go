@@ -0,0 +1,97 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/storage"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+)
+
+// ErrorDomain — домен ошибок календаря в деталях google.rpc.ErrorInfo.
+const ErrorDomain = "calendar"
+
+// Причины ошибок (ErrorInfo.reason). По ним клиенты различают ошибки с одинаковым кодом gRPC.
+const (
+	ReasonNotFound         = "NOT_FOUND"         // событие не найдено
+	ReasonDateBusy         = "DATE_BUSY"         // время занято другим событием
+	ReasonValidation       = "VALIDATION"        // некорректные данные запроса
+	ReasonPermissionDenied = "PERMISSION_DENIED" // действие над чужими событиями
+	ReasonVersionConflict  = "VERSION_CONFLICT"  // событие изменено после чтения
+	ReasonAlreadyExists    = "ALREADY_EXISTS"    // ID нового события уже занят
+)
+
+// errorKinds сопоставляет общие ошибки хранилища с кодами gRPC и причинами ErrorInfo.
+var errorKinds = []struct {
+	kind   error
+	code   codes.Code
+	reason string
+}{
+	{storage.ErrNotFound, codes.NotFound, ReasonNotFound},
+	{storage.ErrDateBusy, codes.FailedPrecondition, ReasonDateBusy},
+	{storage.ErrValidation, codes.InvalidArgument, ReasonValidation},
+	{storage.ErrPermissionDenied, codes.PermissionDenied, ReasonPermissionDenied},
+	{storage.ErrVersionConflict, codes.Aborted, ReasonVersionConflict},
+	{storage.ErrAlreadyExists, codes.AlreadyExists, ReasonAlreadyExists},
+}
+
+// appError преобразует ошибки бизнес-логики и хранилища в gRPC-статусы — единое место сопоставления
+// для gRPC, REST (grpc-gateway переводит коды в статусы HTTP) и клиентов. Статус содержит
+// google.rpc.ErrorInfo с причиной ошибки; пересечение по времени дополнительно содержит
+// google.rpc.PreconditionFailure с ID пересекающихся событий. Ошибки gRPC возвращаются как есть,
+// прочие ошибки — как Internal.
+func appError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err).Err()
+	}
+	for _, k := range errorKinds {
+		if !errors.Is(err, k.kind) {
+			continue
+		}
+		// Детали пересечения идут первыми: их читали клиенты и до появления ErrorInfo
+		var details []protoadapt.MessageV1
+		var conflict *storage.ConflictError
+		if errors.As(err, &conflict) {
+			details = append(details, conflictDetails(conflict))
+		}
+		details = append(details, &errdetails.ErrorInfo{Reason: k.reason, Domain: ErrorDomain})
+		st, detailsErr := status.New(k.code, err.Error()).WithDetails(details...)
+		if detailsErr != nil {
+			return status.Error(k.code, err.Error())
+		}
+		return st.Err()
+	}
+	return status.Error(codes.Internal, err.Error())
+}
+
+// conflictDetails описывает пересекающиеся события как нарушения предусловия TIME_CONFLICT.
+func conflictDetails(conflict *storage.ConflictError) *errdetails.PreconditionFailure {
+	violations := make([]*errdetails.PreconditionFailure_Violation, 0, len(conflict.EventIDs))
+	for _, id := range conflict.EventIDs {
+		violations = append(violations, &errdetails.PreconditionFailure_Violation{
+			Type:        "TIME_CONFLICT",
+			Subject:     id,
+			Description: "event overlaps in time",
+		})
+	}
+	return &errdetails.PreconditionFailure{Violations: violations}
+}
+
+// ErrorReason возвращает причину ошибки из деталей ErrorInfo статуса, пусто — причины нет.
+func ErrorReason(st *status.Status) string {
+	for _, d := range st.Details() {
+		if info, ok := d.(*errdetails.ErrorInfo); ok && info.GetDomain() == ErrorDomain {
+			return info.GetReason()
+		}
+	}
+	return ""
+}
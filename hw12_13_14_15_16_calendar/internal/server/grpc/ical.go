@@ -0,0 +1,63 @@
+package grpc
+
+import (
+	context "context"
+	"time"
+
+	pb "github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/api/gen"
+	"google.golang.org/genproto/googleapis/api/httpbody"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// CalendarContentType — MIME-тип календаря iCalendar.
+const CalendarContentType = "text/calendar"
+
+// ExportEvents реализует выгрузку событий пользователя в формате iCalendar через GRPC.
+// Через REST календарь отдаётся как файл с Content-Type text/calendar.
+func (s *Server) ExportEvents(ctx context.Context, req *pb.ExportEventsRequest) (*httpbody.HttpBody, error) {
+	s.app.Logger().Info("GRPC ExportEvents: " + req.GetUserId())
+	var start, end int64
+	if req.GetPeriodStart() != "" || req.GetPeriodEnd() != "" {
+		from, err := time.Parse(time.RFC3339, req.GetPeriodStart())
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid period_start: %v", err)
+		}
+		to, err := time.Parse(time.RFC3339, req.GetPeriodEnd())
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid period_end: %v", err)
+		}
+		if !from.Before(to) {
+			return nil, status.Error(codes.InvalidArgument, "period_start must be before period_end")
+		}
+		start, end = from.Unix(), to.Unix()
+	}
+	data, err := s.app.ExportCalendar(ctx, req.GetUserId(), start, end)
+	if err != nil {
+		s.app.Logger().Error("ExportEvents error: " + err.Error())
+		return nil, appError(err)
+	}
+	return &httpbody.HttpBody{ContentType: CalendarContentType + "; charset=utf-8", Data: data}, nil
+}
+
+// ImportEvents реализует загрузку событий из календаря iCalendar через GRPC.
+func (s *Server) ImportEvents(ctx context.Context, req *pb.ImportEventsRequest) (*pb.ImportEventsResponse, error) {
+	s.app.Logger().Info("GRPC ImportEvents: " + req.GetUserId())
+	if req.GetUserId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+	report, err := s.app.ImportCalendar(ctx, req.GetUserId(), []byte(req.GetCalendar()))
+	if err != nil {
+		s.app.Logger().Error("ImportEvents error: " + err.Error())
+		return nil, appError(err)
+	}
+	resp := &pb.ImportEventsResponse{Imported: int32(report.Imported)}
+	for _, issue := range report.Skipped {
+		resp.Skipped = append(resp.Skipped, &pb.ImportIssue{
+			Uid:    issue.UID,
+			Line:   int32(issue.Line),
+			Reason: issue.Reason,
+		})
+	}
+	return resp, nil
+}
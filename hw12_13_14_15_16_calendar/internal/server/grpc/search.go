@@ -0,0 +1,45 @@
+package grpc
+
+import (
+	"context"
+
+	pb "github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/api/gen"
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/storage"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// SearchEvents реализует полнотекстовый поиск событий через GRPC.
+func (s *Server) SearchEvents(ctx context.Context, req *pb.SearchEventsRequest) (*pb.SearchEventsResponse, error) {
+	s.app.Logger().Info("GRPC SearchEvents: " + req.GetUserId())
+	var language storage.SearchLanguage
+	switch req.GetLanguage() {
+	case pb.SearchLanguage_SEARCH_LANGUAGE_UNSPECIFIED:
+		language = storage.SearchAnyLanguage
+	case pb.SearchLanguage_RUSSIAN:
+		language = storage.SearchRussian
+	case pb.SearchLanguage_ENGLISH:
+		language = storage.SearchEnglish
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unknown search language %d", req.GetLanguage())
+	}
+	results, err := s.app.SearchEvents(ctx, req.GetUserId(), storage.SearchQuery{
+		Text:     req.GetQuery(),
+		Language: language,
+		Limit:    int(req.GetLimit()),
+	})
+	if err != nil {
+		s.app.Logger().Error("SearchEvents error: " + err.Error())
+		return nil, appError(err)
+	}
+	resp := &pb.SearchEventsResponse{}
+	for _, r := range results {
+		resp.Results = append(resp.Results, &pb.SearchResult{
+			Event:   storageToProtoEvent(r.Event),
+			Rank:    r.Rank,
+			Title:   r.Title,
+			Snippet: r.Snippet,
+		})
+	}
+	return resp, nil
+}
@@ -9,21 +9,58 @@ import (
 	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/storage"
 	"github.com/google/uuid"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 )
 
+// RevisionHeader — заголовок (метаданные) ответа WatchEvents с ревизией начала потока.
+const RevisionHeader = "x-revision"
+
 // Server реализует pb.EventServiceServer и связывает GRPC с бизнес-логикой.
 type Server struct {
 	pb.UnimplementedEventServiceServer
-	app *app.App
+	app Application
+}
+
+// Application определяет методы бизнес-логики, которые использует grpc-сервер.
+// Реализуется *app.App.
+type Application interface {
+	CreateEvent(ctx context.Context, event storage.Event) (storage.Event, error)
+	UpdateEvent(ctx context.Context, event storage.Event) (storage.Event, error)
+	DeleteEvent(ctx context.Context, id string, version int64) error
+	GetEvent(ctx context.Context, id string) (storage.Event, error)
+	ListEvents(ctx context.Context, userID string, filter app.EventFilter, page app.PageRequest) (app.EventPage, error)
+	ListEventsForDay(ctx context.Context, userID string, day time.Time, page app.PageRequest) (app.EventPage, error)
+	ListEventsForWeek(ctx context.Context, userID string, day time.Time, page app.PageRequest) (app.EventPage, error)
+	ListEventsForMonth(ctx context.Context, userID string, day time.Time, page app.PageRequest) (app.EventPage, error)
+	Location(userID, zone string) (*time.Location, error)
+	ExportCalendar(ctx context.Context, userID string, start, end int64) ([]byte, error)
+	ImportCalendar(ctx context.Context, userID string, data []byte) (app.ImportReport, error)
+	UpdateEventOccurrence(ctx context.Context, eventID string, recurrenceID int64, occurrence storage.Event) (storage.Event, error)
+	CancelEventOccurrence(ctx context.Context, eventID string, recurrenceID int64) error
+	InviteAttendees(ctx context.Context, eventID string, userIDs []string) (storage.Event, error)
+	RespondToInvitation(ctx context.Context, eventID, userID string, status storage.AttendeeStatus) (storage.Event, error)
+	ListInvitations(ctx context.Context, userID string, status storage.AttendeeStatus) ([]app.Invitation, error)
+	WatchEvents(ctx context.Context, userID, revision string) (*app.EventWatch, error)
+	ListTrash(ctx context.Context, userID string) ([]storage.Event, error)
+	RestoreEvent(ctx context.Context, id string) (storage.Event, error)
+	PurgeEvent(ctx context.Context, id string) error
+	GetEventHistory(ctx context.Context, eventID string, limit int) ([]storage.AuditEntry, error)
+	ListUserActivity(ctx context.Context, userID string, limit int) ([]storage.AuditEntry, error)
+	SearchEvents(ctx context.Context, userID string, query storage.SearchQuery) ([]storage.SearchResult, error)
+	CreateWebhook(ctx context.Context, userID, url string) (storage.Webhook, error)
+	ListWebhooks(ctx context.Context, userID string) ([]storage.Webhook, error)
+	DeleteWebhook(ctx context.Context, id string) error
+	Logger() app.Logger
 }
 
 // NewServer создаёт новый grpc-сервер с внедрённой бизнес-логикой.
-func NewServer(app *app.App) *Server {
+func NewServer(app Application) *Server {
 	return &Server{app: app}
 }
 
-// CreateEvent реализует метод создания события через GRPC.
+// CreateEvent реализует метод создания события через GRPC и возвращает сохранённое событие:
+// с версией, нормализованными напоминаниями и правилом повторения.
 func (s *Server) CreateEvent(ctx context.Context, req *pb.CreateEventRequest) (*pb.CreateEventResponse, error) {
 	event := req.GetEvent()
 	// Валидация UUID
@@ -41,16 +78,17 @@ func (s *Server) CreateEvent(ctx context.Context, req *pb.CreateEventRequest) (*
 		return nil, err
 	}
 
-	err = s.app.CreateEvent(ctx, storageEvent)
+	saved, err := s.app.CreateEvent(ctx, storageEvent)
 	if err != nil {
 		s.app.Logger().Error("CreateEvent error: " + err.Error())
-		return nil, err
+		return nil, appError(err)
 	}
 
-	return &pb.CreateEventResponse{Event: event}, nil
+	return &pb.CreateEventResponse{Event: storageToProtoEvent(saved)}, nil
 }
 
-// UpdateEvent реализует обновление события через GRPC.
+// UpdateEvent реализует обновление события через GRPC и возвращает сохранённое событие.
+// Ожидаемая версия события берётся из event.version, а если она не задана — из метаданных if-match.
 func (s *Server) UpdateEvent(ctx context.Context, req *pb.UpdateEventRequest) (*pb.UpdateEventResponse, error) {
 	event := req.GetEvent()
 	s.app.Logger().Info("GRPC UpdateEvent: " + event.GetTitle())
@@ -59,25 +97,211 @@ func (s *Server) UpdateEvent(ctx context.Context, req *pb.UpdateEventRequest) (*
 		s.app.Logger().Error("UpdateEvent mapping error: " + err.Error())
 		return nil, err
 	}
-	err = s.app.UpdateEvent(ctx, storageEvent)
+	if storageEvent.Version, err = expectedVersion(ctx, storageEvent.Version); err != nil {
+		return nil, err
+	}
+	saved, err := s.app.UpdateEvent(ctx, storageEvent)
 	if err != nil {
 		s.app.Logger().Error("UpdateEvent error: " + err.Error())
+		return nil, appError(err)
+	}
+	return &pb.UpdateEventResponse{Event: storageToProtoEvent(saved)}, nil
+}
+
+// UpdateEventOccurrence реализует изменение одного экземпляра повторяющегося события через GRPC.
+func (s *Server) UpdateEventOccurrence(ctx context.Context, req *pb.UpdateEventOccurrenceRequest) (*pb.UpdateEventOccurrenceResponse, error) {
+	s.app.Logger().Info("GRPC UpdateEventOccurrence: " + req.GetEventId() + " at " + req.GetRecurrenceId())
+	recurrenceID, err := time.Parse(time.RFC3339, req.GetRecurrenceId())
+	if err != nil {
+		s.app.Logger().Error("UpdateEventOccurrence recurrence_id parse error: " + err.Error())
+		return nil, status.Errorf(codes.InvalidArgument, "invalid recurrence_id: %v", err)
+	}
+	if req.GetEvent() == nil {
+		return nil, status.Error(codes.InvalidArgument, "event is required")
+	}
+	storageEvent, err := protoToStorageEvent(req.GetEvent())
+	if err != nil {
+		s.app.Logger().Error("UpdateEventOccurrence mapping error: " + err.Error())
 		return nil, err
 	}
-	return &pb.UpdateEventResponse{Event: event}, nil
+	saved, err := s.app.UpdateEventOccurrence(ctx, req.GetEventId(), recurrenceID.Unix(), storageEvent)
+	if err != nil {
+		s.app.Logger().Error("UpdateEventOccurrence error: " + err.Error())
+		return nil, appError(err)
+	}
+	return &pb.UpdateEventOccurrenceResponse{Event: storageToProtoEvent(saved)}, nil
+}
+
+// CancelEventOccurrence реализует отмену одного экземпляра повторяющегося события через GRPC.
+func (s *Server) CancelEventOccurrence(ctx context.Context, req *pb.CancelEventOccurrenceRequest) (*pb.CancelEventOccurrenceResponse, error) {
+	s.app.Logger().Info("GRPC CancelEventOccurrence: " + req.GetEventId() + " at " + req.GetRecurrenceId())
+	recurrenceID, err := time.Parse(time.RFC3339, req.GetRecurrenceId())
+	if err != nil {
+		s.app.Logger().Error("CancelEventOccurrence recurrence_id parse error: " + err.Error())
+		return nil, status.Errorf(codes.InvalidArgument, "invalid recurrence_id: %v", err)
+	}
+	if err := s.app.CancelEventOccurrence(ctx, req.GetEventId(), recurrenceID.Unix()); err != nil {
+		s.app.Logger().Error("CancelEventOccurrence error: " + err.Error())
+		return nil, appError(err)
+	}
+	return &pb.CancelEventOccurrenceResponse{Success: true}, nil
+}
+
+// InviteAttendees реализует приглашение участников на событие через GRPC.
+func (s *Server) InviteAttendees(ctx context.Context, req *pb.InviteAttendeesRequest) (*pb.InviteAttendeesResponse, error) {
+	s.app.Logger().Info("GRPC InviteAttendees: " + req.GetEventId())
+	if len(req.GetUserIds()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "user_ids is required")
+	}
+	event, err := s.app.InviteAttendees(ctx, req.GetEventId(), req.GetUserIds())
+	if err != nil {
+		s.app.Logger().Error("InviteAttendees error: " + err.Error())
+		return nil, appError(err)
+	}
+	return &pb.InviteAttendeesResponse{Event: storageToProtoEvent(event)}, nil
+}
+
+// RespondToInvitation реализует ответ участника на приглашение через GRPC.
+func (s *Server) RespondToInvitation(ctx context.Context, req *pb.RespondToInvitationRequest) (*pb.RespondToInvitationResponse, error) {
+	s.app.Logger().Info("GRPC RespondToInvitation: " + req.GetEventId() + " by " + req.GetUserId())
+	if req.GetStatus() == pb.AttendeeStatus_ATTENDEE_STATUS_UNSPECIFIED {
+		return nil, status.Error(codes.InvalidArgument, "status is required")
+	}
+	event, err := s.app.RespondToInvitation(ctx, req.GetEventId(), req.GetUserId(), attendeeStatusFromProto(req.GetStatus()))
+	if err != nil {
+		s.app.Logger().Error("RespondToInvitation error: " + err.Error())
+		return nil, appError(err)
+	}
+	return &pb.RespondToInvitationResponse{Event: storageToProtoEvent(event)}, nil
+}
+
+// ListInvitations реализует получение приглашений пользователя через GRPC.
+func (s *Server) ListInvitations(ctx context.Context, req *pb.ListInvitationsRequest) (*pb.ListInvitationsResponse, error) {
+	s.app.Logger().Info("GRPC ListInvitations: " + req.GetUserId())
+	invitations, err := s.app.ListInvitations(ctx, req.GetUserId(), attendeeStatusFromProto(req.GetStatus()))
+	if err != nil {
+		s.app.Logger().Error("ListInvitations error: " + err.Error())
+		return nil, appError(err)
+	}
+	resp := &pb.ListInvitationsResponse{}
+	for _, inv := range invitations {
+		resp.Invitations = append(resp.Invitations, &pb.Invitation{
+			Event:  storageToProtoEvent(inv.Event),
+			Status: attendeeStatusToProto(inv.Status),
+		})
+	}
+	return resp, nil
 }
 
 // DeleteEvent реализует удаление события через GRPC.
+// Если указан user_id, событие удаляется, только если принадлежит этому пользователю.
+// Ожидаемая версия события берётся из version, а если она не задана — из метаданных if-match.
 func (s *Server) DeleteEvent(ctx context.Context, req *pb.DeleteEventRequest) (*pb.DeleteEventResponse, error) {
 	s.app.Logger().Info("GRPC DeleteEvent: " + req.GetId())
-	err := s.app.DeleteEvent(ctx, req.GetId())
+	version, err := expectedVersion(ctx, req.GetVersion())
 	if err != nil {
-		s.app.Logger().Error("DeleteEvent error: " + err.Error())
 		return nil, err
 	}
+	if req.GetUserId() != "" {
+		event, err := s.app.GetEvent(ctx, req.GetId())
+		if err != nil {
+			s.app.Logger().Error("DeleteEvent error: " + err.Error())
+			return nil, appError(err)
+		}
+		if event.UserID != req.GetUserId() {
+			return nil, appError(storage.NotFoundError(req.GetId()))
+		}
+	}
+	if err := s.app.DeleteEvent(ctx, req.GetId(), version); err != nil {
+		s.app.Logger().Error("DeleteEvent error: " + err.Error())
+		return nil, appError(err)
+	}
 	return &pb.DeleteEventResponse{Success: true}, nil
 }
 
+// GetEvent реализует получение события по ID через GRPC.
+func (s *Server) GetEvent(ctx context.Context, req *pb.GetEventRequest) (*pb.GetEventResponse, error) {
+	s.app.Logger().Info("GRPC GetEvent: " + req.GetId())
+	event, err := s.app.GetEvent(ctx, req.GetId())
+	if err != nil {
+		s.app.Logger().Error("GetEvent error: " + err.Error())
+		return nil, appError(err)
+	}
+	return &pb.GetEventResponse{Event: storageToProtoEvent(event)}, nil
+}
+
+// WatchEvents реализует поток изменений событий пользователя через GRPC.
+// Сразу после подписки сервер отправляет заголовок x-revision с ревизией, с которой начинается поток:
+// с ней можно переподключиться, даже если изменений ещё не было. Поток продолжается, пока клиент не закроет его.
+func (s *Server) WatchEvents(req *pb.WatchEventsRequest, stream pb.EventService_WatchEventsServer) error {
+	s.app.Logger().Info("GRPC WatchEvents: " + req.GetUserId())
+	ctx := stream.Context()
+	watch, err := s.app.WatchEvents(ctx, req.GetUserId(), req.GetRevision())
+	if err != nil {
+		s.app.Logger().Error("WatchEvents error: " + err.Error())
+		return appError(err)
+	}
+	if err := stream.SendHeader(metadata.Pairs(RevisionHeader, watch.Revision())); err != nil {
+		return err
+	}
+	for {
+		changes, err := watch.Next(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				s.app.Logger().Error("WatchEvents error: " + err.Error())
+			}
+			return appError(err)
+		}
+		for _, change := range changes {
+			if err := stream.Send(eventChangeToProto(change)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// ListEvents реализует выборку событий пользователя с фильтрами через GRPC.
+func (s *Server) ListEvents(ctx context.Context, req *pb.FilterEventsRequest) (*pb.ListEventsResponse, error) {
+	s.app.Logger().Info("GRPC ListEvents: " + req.GetUserId())
+	filter := app.EventFilter{Query: req.GetQuery()}
+	if req.GetPeriodStart() != "" || req.GetPeriodEnd() != "" {
+		start, err := time.Parse(time.RFC3339, req.GetPeriodStart())
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid period_start: %v", err)
+		}
+		end, err := time.Parse(time.RFC3339, req.GetPeriodEnd())
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid period_end: %v", err)
+		}
+		filter.Start, filter.End = start.Unix(), end.Unix()
+	}
+	switch req.GetReminders() {
+	case pb.ReminderFilter_WITH_REMINDERS:
+		filter.Reminders = app.WithReminders
+	case pb.ReminderFilter_WITHOUT_REMINDERS:
+		filter.Reminders = app.WithoutReminders
+	case pb.ReminderFilter_REMINDER_FILTER_UNSPECIFIED:
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unknown reminders filter %d", req.GetReminders())
+	}
+	switch req.GetOrderBy() {
+	case pb.EventOrder_START_TIME_DESC:
+		filter.Order = app.OrderByStartTimeDesc
+	case pb.EventOrder_TITLE_ASC:
+		filter.Order = app.OrderByTitle
+	case pb.EventOrder_EVENT_ORDER_UNSPECIFIED, pb.EventOrder_START_TIME_ASC:
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unknown order_by %d", req.GetOrderBy())
+	}
+	page, err := s.app.ListEvents(ctx, req.GetUserId(), filter,
+		app.PageRequest{Size: int(req.GetPageSize()), Token: req.GetPageToken()})
+	if err != nil {
+		s.app.Logger().Error("ListEvents error: " + err.Error())
+		return nil, appError(err)
+	}
+	return eventPageToProto(page), nil
+}
+
 // ListEventsForDay реализует получение событий за день через GRPC.
 func (s *Server) ListEventsForDay(ctx context.Context, req *pb.ListEventsRequest) (*pb.ListEventsResponse, error) {
 	return s.listEventsForPeriod(ctx, req, "day")
@@ -96,53 +320,106 @@ func (s *Server) ListEventsForMonth(ctx context.Context, req *pb.ListEventsReque
 // listEventsForPeriod — вспомогательный метод для выборки событий по периоду.
 func (s *Server) listEventsForPeriod(ctx context.Context, req *pb.ListEventsRequest, period string) (*pb.ListEventsResponse, error) {
 	s.app.Logger().Info("GRPC ListEventsFor" + period + ": " + req.GetUserId())
-	start, err := time.Parse(time.RFC3339, req.GetPeriodStart())
+	loc, err := s.app.Location(req.GetUserId(), req.GetTimeZone())
+	if err != nil {
+		s.app.Logger().Error("ListEventsFor" + period + " time zone error: " + err.Error())
+		return nil, appError(err)
+	}
+	start, err := parsePeriodStart(req.GetPeriodStart(), loc)
 	if err != nil {
 		s.app.Logger().Error("ListEventsFor" + period + " start parse error: " + err.Error())
 		return nil, err
 	}
-	var events []storage.Event
+	pageReq := app.PageRequest{Size: int(req.GetPageSize()), Token: req.GetPageToken()}
+	var page app.EventPage
 	switch period {
 	case "day":
-		events, err = s.app.ListEventsForDay(ctx, req.GetUserId(), start.Unix())
+		page, err = s.app.ListEventsForDay(ctx, req.GetUserId(), start, pageReq)
 	case "week":
-		events, err = s.app.ListEventsForWeek(ctx, req.GetUserId(), start.Unix())
+		page, err = s.app.ListEventsForWeek(ctx, req.GetUserId(), start, pageReq)
 	case "month":
-		events, err = s.app.ListEventsForMonth(ctx, req.GetUserId(), start.Unix())
+		page, err = s.app.ListEventsForMonth(ctx, req.GetUserId(), start, pageReq)
 	}
 	if err != nil {
 		s.app.Logger().Error("ListEventsFor" + period + " error: " + err.Error())
-		return nil, err
+		return nil, appError(err)
 	}
+	return eventPageToProto(page), nil
+}
+
+// eventPageToProto преобразует страницу событий в ответ со списком событий.
+func eventPageToProto(page app.EventPage) *pb.ListEventsResponse {
 	// Маппинг storage.Event -> pb.Event
 	var pbEvents []*pb.Event
-	for _, ev := range events {
+	for _, ev := range page.Events {
 		pbEvents = append(pbEvents, storageToProtoEvent(ev))
 	}
-	return &pb.ListEventsResponse{Events: pbEvents}, nil
+	return &pb.ListEventsResponse{
+		Events:        pbEvents,
+		NextPageToken: page.NextPageToken,
+		TotalSize:     int32(page.Total),
+	}
 }
 
-// protoToStorageEvent преобразует pb.Event в storage.Event
+// parsePeriodStart разбирает начало периода: момент времени в RFC3339 или дату YYYY-MM-DD.
+// Результат переводится в часовой пояс loc, в котором считаются границы периода.
+func parsePeriodStart(value string, loc *time.Location) (time.Time, error) {
+	if t, err := time.ParseInLocation(time.DateOnly, value, loc); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, status.Errorf(codes.InvalidArgument, "invalid period_start: %v", err)
+	}
+	return t.In(loc), nil
+}
+
+// protoToStorageEvent преобразует pb.Event в storage.Event.
+// Поля recurrence_id и recurring_event_id только для чтения и не переносятся.
 func protoToStorageEvent(e *pb.Event) (storage.Event, error) {
 	start, err := time.Parse(time.RFC3339, e.GetStartTime())
 	if err != nil {
-		return storage.Event{}, err
+		return storage.Event{}, status.Errorf(codes.InvalidArgument, "invalid start_time: %v", err)
+	}
+	var exdates []int64
+	for _, exdate := range e.GetExdates() {
+		t, err := time.Parse(time.RFC3339, exdate)
+		if err != nil {
+			return storage.Event{}, status.Errorf(codes.InvalidArgument, "invalid exdate: %v", err)
+		}
+		exdates = append(exdates, t.Unix())
 	}
 	dur := e.GetDurationSeconds()
 	end := start.Add(time.Duration(dur) * time.Second)
-	var notify *int64
-	if e.NotifyBeforeMinutes != 0 {
-		sec := int64(e.NotifyBeforeMinutes) * 60
-		notify = &sec
+	// Устаревшее поле notify_before_minutes учитывается, только если список напоминаний пуст
+	var reminders []storage.EventReminder
+	for _, r := range e.GetReminders() {
+		reminders = append(reminders, storage.EventReminder{
+			Offset:  int64(r.GetMinutesBefore()) * 60,
+			Channel: r.GetChannel(),
+		})
+	}
+	if len(reminders) == 0 && e.GetNotifyBeforeMinutes() != 0 {
+		reminders = []storage.EventReminder{{Offset: int64(e.GetNotifyBeforeMinutes()) * 60}}
+	}
+	// Ответы участников меняются только через RespondToInvitation
+	var attendees []storage.Attendee
+	for _, a := range e.GetAttendees() {
+		attendees = append(attendees, storage.Attendee{UserID: a.GetUserId()})
 	}
 	return storage.Event{
-		ID:           e.GetId(),
-		Title:        e.GetTitle(),
-		Description:  e.GetDescription(),
-		UserID:       e.GetUserId(),
-		StartTime:    start.Unix(),
-		EndTime:      end.Unix(),
-		NotifyBefore: notify,
+		ID:          e.GetId(),
+		Title:       e.GetTitle(),
+		Description: e.GetDescription(),
+		UserID:      e.GetUserId(),
+		StartTime:   start.Unix(),
+		EndTime:     end.Unix(),
+		Reminders:   reminders,
+		Attendees:   attendees,
+		RRule:       e.GetRrule(),
+		TimeZone:    e.GetTimeZone(),
+		ExDates:     exdates,
+		Version:     e.GetVersion(),
 	}, nil
 }
 
@@ -151,8 +428,28 @@ func storageToProtoEvent(e storage.Event) *pb.Event {
 	start := time.Unix(e.StartTime, 0).Format(time.RFC3339)
 	dur := e.EndTime - e.StartTime
 	var notify int32
-	if e.NotifyBefore != nil {
-		notify = int32(*e.NotifyBefore / 60)
+	reminders := make([]*pb.EventReminder, 0, len(e.Reminders))
+	for _, r := range e.Reminders {
+		reminders = append(reminders, &pb.EventReminder{MinutesBefore: int32(r.Offset / 60), Channel: r.Channel})
+	}
+	if len(e.Reminders) > 0 {
+		notify = int32(e.Reminders[0].Offset / 60)
+	}
+	attendees := make([]*pb.Attendee, 0, len(e.Attendees))
+	for _, a := range e.Attendees {
+		attendees = append(attendees, &pb.Attendee{UserId: a.UserID, Status: attendeeStatusToProto(a.Status)})
+	}
+	var exdates []string
+	for _, exdate := range e.ExDates {
+		exdates = append(exdates, time.Unix(exdate, 0).Format(time.RFC3339))
+	}
+	var recurrenceID string
+	if e.RecurrenceID != 0 {
+		recurrenceID = time.Unix(e.RecurrenceID, 0).Format(time.RFC3339)
+	}
+	var deletedAt string
+	if e.DeletedAt != 0 {
+		deletedAt = time.Unix(e.DeletedAt, 0).Format(time.RFC3339)
 	}
 	return &pb.Event{
 		Id:                  e.ID,
@@ -162,5 +459,65 @@ func storageToProtoEvent(e storage.Event) *pb.Event {
 		Description:         e.Description,
 		UserId:              e.UserID,
 		NotifyBeforeMinutes: notify,
+		Rrule:               e.RRule,
+		TimeZone:            e.TimeZone,
+		Exdates:             exdates,
+		RecurrenceId:        recurrenceID,
+		RecurringEventId:    e.RecurringEventID,
+		Reminders:           reminders,
+		Attendees:           attendees,
+		Version:             e.Version,
+		DeletedAt:           deletedAt,
+	}
+}
+
+// eventChangeToProto преобразует изменение события в pb.EventChange.
+func eventChangeToProto(change app.EventChange) *pb.EventChange {
+	result := &pb.EventChange{
+		EventId:   change.EventID,
+		Revision:  change.Revision,
+		ChangedAt: time.Unix(change.ChangedAt, 0).Format(time.RFC3339),
+	}
+	switch change.Type {
+	case storage.ChangeCreated:
+		result.Type = pb.ChangeType_CREATED
+	case storage.ChangeUpdated:
+		result.Type = pb.ChangeType_UPDATED
+	case storage.ChangeDeleted:
+		result.Type = pb.ChangeType_DELETED
+	}
+	if change.Type != storage.ChangeDeleted {
+		result.Event = storageToProtoEvent(change.Event)
+	}
+	return result
+}
+
+// attendeeStatusToProto преобразует ответ участника в pb.AttendeeStatus.
+func attendeeStatusToProto(status storage.AttendeeStatus) pb.AttendeeStatus {
+	switch status {
+	case storage.AttendeeNeedsAction:
+		return pb.AttendeeStatus_NEEDS_ACTION
+	case storage.AttendeeAccepted:
+		return pb.AttendeeStatus_ACCEPTED
+	case storage.AttendeeDeclined:
+		return pb.AttendeeStatus_DECLINED
+	case storage.AttendeeTentative:
+		return pb.AttendeeStatus_TENTATIVE
+	}
+	return pb.AttendeeStatus_ATTENDEE_STATUS_UNSPECIFIED
+}
+
+// attendeeStatusFromProto преобразует pb.AttendeeStatus в ответ участника; для неуказанного статуса — пустая строка.
+func attendeeStatusFromProto(status pb.AttendeeStatus) storage.AttendeeStatus {
+	switch status {
+	case pb.AttendeeStatus_NEEDS_ACTION:
+		return storage.AttendeeNeedsAction
+	case pb.AttendeeStatus_ACCEPTED:
+		return storage.AttendeeAccepted
+	case pb.AttendeeStatus_DECLINED:
+		return storage.AttendeeDeclined
+	case pb.AttendeeStatus_TENTATIVE:
+		return storage.AttendeeTentative
 	}
+	return ""
 }
@@ -8,19 +8,32 @@ import (
 
 	pb "github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/api/gen"
 	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/app"
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/auth"
 	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/logger"
 	grpcserver "github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/server/grpc"
 	memorystorage "github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/storage/memory"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 )
 
-func startTestGRPCServer(t *testing.T) (pb.EventServiceClient, func()) {
+func startTestGRPCServer(t *testing.T, opts ...grpc.ServerOption) (pb.EventServiceClient, func()) {
 	lis, err := net.Listen("tcp", ":0")
 	require.NoError(t, err)
 
-	s := grpc.NewServer()
+	if len(opts) == 0 {
+		// Как в cmd/calendar_grpc с выключенной аутентификацией: вызывающая сторона — auth.Anonymous
+		opts = []grpc.ServerOption{
+			grpc.ChainUnaryInterceptor(grpcserver.UnaryAuthInterceptor(nil)),
+			grpc.ChainStreamInterceptor(grpcserver.StreamAuthInterceptor(nil)),
+		}
+	}
+	s := grpc.NewServer(opts...)
 	appInstance := app.New(logger.New("DEBUG"), memorystorage.New())
 	pb.RegisterEventServiceServer(s, grpcserver.NewServer(appInstance))
 
@@ -119,6 +132,45 @@ func TestUpdateEvent(t *testing.T) {
 	require.True(t, found, "Updated event not found")
 }
 
+func TestEventVersions(t *testing.T) {
+	client, cleanup := startTestGRPCServer(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	event := &pb.Event{
+		Id: uuid.NewString(), Title: "Review", StartTime: "2024-07-20T10:00:00Z", DurationSeconds: 1800, UserId: "user2",
+	}
+	created, err := client.CreateEvent(ctx, &pb.CreateEventRequest{Event: event})
+	require.NoError(t, err)
+	require.EqualValues(t, 1, created.Event.Version)
+
+	// Оба клиента прочитали версию 1; изменение второго не затирает изменение первого
+	first := &pb.Event{Id: event.Id, Title: "First", StartTime: event.StartTime, UserId: "user2", Version: 1}
+	updated, err := client.UpdateEvent(ctx, &pb.UpdateEventRequest{Event: first})
+	require.NoError(t, err)
+	require.EqualValues(t, 2, updated.Event.Version)
+	second := &pb.Event{Id: event.Id, Title: "Second", StartTime: event.StartTime, UserId: "user2", Version: 1}
+	_, err = client.UpdateEvent(ctx, &pb.UpdateEventRequest{Event: second})
+	st := status.Convert(err)
+	require.Equal(t, codes.Aborted, st.Code(), st.Message())
+	require.Equal(t, grpcserver.ReasonVersionConflict, grpcserver.ErrorReason(st))
+
+	got, err := client.GetEvent(ctx, &pb.GetEventRequest{Id: event.Id})
+	require.NoError(t, err)
+	require.Equal(t, "First", got.Event.Title)
+	require.EqualValues(t, 2, got.Event.Version)
+
+	// Версию можно передать и в метаданных if-match
+	staleCtx := metadata.AppendToOutgoingContext(ctx, grpcserver.IfMatchHeader, `"1"`)
+	_, err = client.DeleteEvent(staleCtx, &pb.DeleteEventRequest{Id: event.Id})
+	require.Equal(t, codes.Aborted, status.Code(err))
+	badCtx := metadata.AppendToOutgoingContext(ctx, grpcserver.IfMatchHeader, "two")
+	_, err = client.DeleteEvent(badCtx, &pb.DeleteEventRequest{Id: event.Id})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = client.DeleteEvent(ctx, &pb.DeleteEventRequest{Id: event.Id, Version: 2})
+	require.NoError(t, err)
+}
+
 func TestDeleteEvent(t *testing.T) {
 	client, cleanup := startTestGRPCServer(t)
 	defer cleanup()
@@ -171,14 +223,14 @@ func TestListEventsForWeekAndMonth(t *testing.T) {
 		require.NoError(t, err)
 	}
 
-	// Неделя с 10 по 17 июля
+	// Неделя, содержащая среду 10 июля: с понедельника 8 по воскресенье 14 июля
 	respWeek, err := client.ListEventsForWeek(ctx, &pb.ListEventsRequest{
 		UserId:      userID,
 		PeriodStart: "2024-07-10T00:00:00Z",
 		PeriodEnd:   "2024-07-17T00:00:00Z",
 	})
 	require.NoError(t, err)
-	require.GreaterOrEqual(t, len(respWeek.Events), 7)
+	require.Len(t, respWeek.Events, 5)
 
 	// Месяц с 1 по 31 июля
 	respMonth, err := client.ListEventsForMonth(ctx, &pb.ListEventsRequest{
@@ -190,6 +242,87 @@ func TestListEventsForWeekAndMonth(t *testing.T) {
 	require.GreaterOrEqual(t, len(respMonth.Events), 10)
 }
 
+func TestListEventsTimeZoneBoundaries(t *testing.T) {
+	client, cleanup := startTestGRPCServer(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	userID := "userZone"
+	create := func(start string) {
+		_, err := client.CreateEvent(ctx, &pb.CreateEventRequest{
+			Event: &pb.Event{
+				Id:              uuid.NewString(),
+				Title:           "Event " + start,
+				StartTime:       start,
+				DurationSeconds: 1800,
+				UserId:          userID,
+			},
+		})
+		require.NoError(t, err)
+	}
+	create("2024-03-30T23:30:00Z") // 31 марта 00:30 по Берлину
+	create("2024-03-31T21:30:00Z") // 31 марта 23:30 по Берлину (после перехода на летнее время)
+	create("2024-03-31T22:30:00Z") // 1 апреля 00:30 по Берлину
+	create("2024-03-25T10:00:00Z") // понедельник
+
+	list := func(call func(context.Context, *pb.ListEventsRequest, ...grpc.CallOption) (*pb.ListEventsResponse, error),
+		periodStart, zone string,
+	) []string {
+		resp, err := call(ctx, &pb.ListEventsRequest{UserId: userID, PeriodStart: periodStart, TimeZone: zone})
+		require.NoError(t, err)
+		var starts []string
+		for _, ev := range resp.Events {
+			starts = append(starts, ev.StartTime)
+		}
+		return starts
+	}
+
+	// День короче 24 часов из-за перехода на летнее время
+	require.Equal(t, []string{"2024-03-30T23:30:00Z", "2024-03-31T21:30:00Z"},
+		list(client.ListEventsForDay, "2024-03-31", "Europe/Berlin"))
+	// Тот же день в UTC
+	require.Equal(t, []string{"2024-03-31T21:30:00Z", "2024-03-31T22:30:00Z"},
+		list(client.ListEventsForDay, "2024-03-31", ""))
+	// Календарный месяц (31 день) в часовом поясе запроса
+	require.Equal(t, []string{"2024-03-25T10:00:00Z", "2024-03-30T23:30:00Z", "2024-03-31T21:30:00Z"},
+		list(client.ListEventsForMonth, "2024-03-15T12:00:00+01:00", "Europe/Berlin"))
+	// Неделя с понедельника 25 по воскресенье 31 марта по Берлину
+	require.Equal(t, []string{"2024-03-25T10:00:00Z", "2024-03-30T23:30:00Z", "2024-03-31T21:30:00Z"},
+		list(client.ListEventsForWeek, "2024-03-31T20:00:00Z", "Europe/Berlin"))
+
+	_, err := client.ListEventsForDay(ctx, &pb.ListEventsRequest{
+		UserId: userID, PeriodStart: "2024-03-31", TimeZone: "Mars/Olympus",
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestListEventsForDayIncludesOverlapping(t *testing.T) {
+	client, cleanup := startTestGRPCServer(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	eventID := uuid.NewString()
+	// Событие начинается накануне и заканчивается в запрошенный день
+	_, err := client.CreateEvent(ctx, &pb.CreateEventRequest{
+		Event: &pb.Event{
+			Id:              eventID,
+			Title:           "Night shift",
+			StartTime:       "2024-07-18T23:00:00Z",
+			DurationSeconds: 2 * 3600,
+			UserId:          "userNight",
+		},
+	})
+	require.NoError(t, err)
+
+	resp, err := client.ListEventsForDay(ctx, &pb.ListEventsRequest{
+		UserId:      "userNight",
+		PeriodStart: "2024-07-19T00:00:00Z",
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.Events, 1)
+	require.Equal(t, eventID, resp.Events[0].Id)
+}
+
 func TestDeleteNonExistentEvent(t *testing.T) {
 	client, cleanup := startTestGRPCServer(t)
 	defer cleanup()
@@ -243,11 +376,12 @@ func TestCreateOverlappingEvents(t *testing.T) {
 	ctx := context.Background()
 	userID := "userOverlap"
 	startTime := "2024-07-26T10:00:00Z"
+	firstID := uuid.NewString()
 
 	// Первое событие
 	_, err := client.CreateEvent(ctx, &pb.CreateEventRequest{
 		Event: &pb.Event{
-			Id:              uuid.NewString(),
+			Id:              firstID,
 			Title:           "First",
 			StartTime:       startTime,
 			DurationSeconds: 3600,
@@ -268,4 +402,1092 @@ func TestCreateOverlappingEvents(t *testing.T) {
 	})
 	// Если пересечения запрещены, ожидаем ошибку. Если разрешены — замените на require.NoError.
 	require.Error(t, err, "expected error on overlapping event")
+	st := status.Convert(err)
+	require.Equal(t, codes.FailedPrecondition, st.Code())
+	require.Equal(t, grpcserver.ReasonDateBusy, grpcserver.ErrorReason(st))
+	require.Len(t, st.Details(), 2)
+	failure, ok := st.Details()[0].(*errdetails.PreconditionFailure)
+	require.True(t, ok)
+	require.Len(t, failure.Violations, 1)
+	require.Equal(t, firstID, failure.Violations[0].Subject)
+
+	// Событие, начинающееся сразу после окончания первого, не пересекается с ним
+	_, err = client.CreateEvent(ctx, &pb.CreateEventRequest{
+		Event: &pb.Event{
+			Id:              uuid.NewString(),
+			Title:           "Adjacent",
+			StartTime:       "2024-07-26T11:00:00Z",
+			DurationSeconds: 1800,
+			UserId:          userID,
+		},
+	})
+	require.NoError(t, err)
+
+	// Экземпляр серии по пятницам (19 и 26 июля) пересекается с первым событием
+	_, err = client.CreateEvent(ctx, &pb.CreateEventRequest{
+		Event: &pb.Event{
+			Id:              uuid.NewString(),
+			Title:           "Weekly",
+			StartTime:       "2024-07-19T10:30:00Z",
+			DurationSeconds: 600,
+			UserId:          userID,
+			Rrule:           "FREQ=WEEKLY;COUNT=3",
+		},
+	})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+	failure, ok = status.Convert(err).Details()[0].(*errdetails.PreconditionFailure)
+	require.True(t, ok)
+	require.Equal(t, firstID, failure.Violations[0].Subject)
+
+	// Серия по четвергам ни с чем не пересекается
+	seriesID := uuid.NewString()
+	_, err = client.CreateEvent(ctx, &pb.CreateEventRequest{
+		Event: &pb.Event{
+			Id:              seriesID,
+			Title:           "Weekly",
+			StartTime:       "2024-07-18T10:00:00Z",
+			DurationSeconds: 3600,
+			UserId:          userID,
+			Rrule:           "FREQ=WEEKLY",
+		},
+	})
+	require.NoError(t, err)
+
+	// Разовое событие и изменённый экземпляр другой серии не могут занять время её экземпляра
+	_, err = client.CreateEvent(ctx, &pb.CreateEventRequest{
+		Event: &pb.Event{
+			Id:              uuid.NewString(),
+			Title:           "Thursday",
+			StartTime:       "2024-08-01T10:30:00Z",
+			DurationSeconds: 600,
+			UserId:          userID,
+		},
+	})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+	failure, ok = status.Convert(err).Details()[0].(*errdetails.PreconditionFailure)
+	require.True(t, ok)
+	require.Equal(t, seriesID, failure.Violations[0].Subject)
+
+	// Экземпляр серии можно перенести на свободное время, но не на занятое
+	_, err = client.UpdateEventOccurrence(ctx, &pb.UpdateEventOccurrenceRequest{
+		EventId:      seriesID,
+		RecurrenceId: "2024-08-01T10:00:00Z",
+		Event:        &pb.Event{Title: "Moved", StartTime: "2024-08-01T12:00:00Z", DurationSeconds: 3600},
+	})
+	require.NoError(t, err)
+	_, err = client.UpdateEventOccurrence(ctx, &pb.UpdateEventOccurrenceRequest{
+		EventId:      seriesID,
+		RecurrenceId: "2024-08-08T10:00:00Z",
+		Event:        &pb.Event{Title: "Moved", StartTime: "2024-07-26T10:00:00Z", DurationSeconds: 3600},
+	})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	// После переноса экземпляра его исходное время свободно
+	_, err = client.CreateEvent(ctx, &pb.CreateEventRequest{
+		Event: &pb.Event{
+			Id:              uuid.NewString(),
+			Title:           "Thursday",
+			StartTime:       "2024-08-01T10:30:00Z",
+			DurationSeconds: 600,
+			UserId:          userID,
+		},
+	})
+	require.NoError(t, err)
+}
+
+func TestRecurringEventOccurrences(t *testing.T) {
+	client, cleanup := startTestGRPCServer(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	userID := "userRecurring"
+	eventID := uuid.NewString()
+	// Еженедельная встреча по понедельникам и средам, начиная с понедельника 1 июля
+	_, err := client.CreateEvent(ctx, &pb.CreateEventRequest{
+		Event: &pb.Event{
+			Id:              eventID,
+			Title:           "Standup",
+			StartTime:       "2024-07-01T09:00:00Z",
+			DurationSeconds: 900,
+			UserId:          userID,
+			Rrule:           "FREQ=WEEKLY;BYDAY=MO,WE;COUNT=10",
+		},
+	})
+	require.NoError(t, err)
+
+	listWeek := func() []*pb.Event {
+		resp, err := client.ListEventsForWeek(ctx, &pb.ListEventsRequest{
+			UserId:      userID,
+			PeriodStart: "2024-07-08T00:00:00Z",
+		})
+		require.NoError(t, err)
+		return resp.Events
+	}
+
+	events := listWeek()
+	require.Len(t, events, 2)
+	require.Equal(t, "2024-07-08T09:00:00Z", events[0].RecurrenceId)
+	require.Equal(t, "2024-07-10T09:00:00Z", events[1].RecurrenceId)
+
+	// Переносим экземпляр в понедельник на 11:00
+	resp, err := client.UpdateEventOccurrence(ctx, &pb.UpdateEventOccurrenceRequest{
+		EventId:      eventID,
+		RecurrenceId: "2024-07-08T09:00:00Z",
+		Event: &pb.Event{
+			Title:           "Standup (moved)",
+			StartTime:       "2024-07-08T11:00:00Z",
+			DurationSeconds: 900,
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, eventID, resp.Event.RecurringEventId)
+
+	// Отменяем экземпляр в среду
+	_, err = client.CancelEventOccurrence(ctx, &pb.CancelEventOccurrenceRequest{
+		EventId:      eventID,
+		RecurrenceId: "2024-07-10T09:00:00Z",
+		UserId:       userID,
+	})
+	require.NoError(t, err)
+
+	events = listWeek()
+	require.Len(t, events, 1)
+	require.Equal(t, "Standup (moved)", events[0].Title)
+	require.Equal(t, "2024-07-08T11:00:00Z", events[0].StartTime)
+
+	// Экземпляра во вторник в серии нет
+	_, err = client.CancelEventOccurrence(ctx, &pb.CancelEventOccurrenceRequest{
+		EventId:      eventID,
+		RecurrenceId: "2024-07-09T09:00:00Z",
+		UserId:       userID,
+	})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	// Некорректное правило отклоняется
+	_, err = client.CreateEvent(ctx, &pb.CreateEventRequest{
+		Event: &pb.Event{
+			Id:              uuid.NewString(),
+			Title:           "Broken",
+			StartTime:       "2024-07-01T12:00:00Z",
+			DurationSeconds: 900,
+			UserId:          userID,
+			Rrule:           "FREQ=HOURLY",
+		},
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestRecurringEventTimeZone(t *testing.T) {
+	client, cleanup := startTestGRPCServer(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	userID := "userRecurringZone"
+	// Встреча в 9:00 по Нью-Йорку по пятницам; 3 ноября 2024 года летнее время заканчивается
+	_, err := client.CreateEvent(ctx, &pb.CreateEventRequest{
+		Event: &pb.Event{
+			Id:              uuid.NewString(),
+			Title:           "Sync",
+			StartTime:       "2024-10-25T13:00:00Z",
+			DurationSeconds: 1800,
+			UserId:          userID,
+			Rrule:           "FREQ=WEEKLY;BYDAY=FR",
+			TimeZone:        "America/New_York",
+		},
+	})
+	require.NoError(t, err)
+
+	resp, err := client.ListEventsForMonth(ctx, &pb.ListEventsRequest{
+		UserId:      userID,
+		PeriodStart: "2024-11-01T00:00:00Z",
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.Events, 5)
+	require.Equal(t, "2024-11-01T13:00:00Z", resp.Events[0].StartTime)
+	require.Equal(t, "2024-11-08T14:00:00Z", resp.Events[1].StartTime, "09:00 local time after DST ends")
+	require.Equal(t, "America/New_York", resp.Events[0].TimeZone)
+
+	// Неизвестный часовой пояс отклоняется
+	_, err = client.CreateEvent(ctx, &pb.CreateEventRequest{
+		Event: &pb.Event{
+			Id:              uuid.NewString(),
+			Title:           "Broken",
+			StartTime:       "2024-10-25T13:00:00Z",
+			DurationSeconds: 1800,
+			UserId:          userID,
+			Rrule:           "FREQ=WEEKLY",
+			TimeZone:        "Mars/Olympus",
+		},
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestListEventsPagination(t *testing.T) {
+	client, cleanup := startTestGRPCServer(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	userID := "userPages"
+	create := func(start string, duration int64, rrule string) {
+		_, err := client.CreateEvent(ctx, &pb.CreateEventRequest{
+			Event: &pb.Event{
+				Id:              uuid.NewString(),
+				Title:           "Event " + start,
+				StartTime:       start,
+				DurationSeconds: duration,
+				UserId:          userID,
+				Rrule:           rrule,
+			},
+		})
+		require.NoError(t, err)
+	}
+	// Разовые события, два из них начинаются одновременно, и ежедневная серия из пяти экземпляров
+	for day := 1; day <= 7; day++ {
+		create(time.Date(2024, 7, day, 12, 0, 0, 0, time.UTC).Format(time.RFC3339), 3600, "")
+	}
+	create("2024-07-03T18:00:00Z", 0, "")
+	create("2024-07-03T18:00:00Z", 0, "")
+	create("2024-07-01T09:00:00Z", 900, "FREQ=DAILY;COUNT=5")
+
+	request := func(size int32, token string) *pb.ListEventsResponse {
+		resp, err := client.ListEventsForMonth(ctx, &pb.ListEventsRequest{
+			UserId:      userID,
+			PeriodStart: "2024-07-01",
+			PageSize:    size,
+			PageToken:   token,
+		})
+		require.NoError(t, err)
+		return resp
+	}
+
+	all := request(0, "")
+	require.Len(t, all.Events, 14)
+	require.Equal(t, int32(14), all.TotalSize)
+	require.Empty(t, all.NextPageToken)
+	for i := 1; i < len(all.Events); i++ {
+		prev, cur := all.Events[i-1], all.Events[i]
+		require.True(t, prev.StartTime < cur.StartTime || (prev.StartTime == cur.StartTime && prev.Id < cur.Id),
+			"events are not ordered: %v, %v", prev, cur)
+	}
+
+	// Постраничное чтение даёт ту же последовательность
+	var paged []*pb.Event
+	token, total := "", int32(14)
+	for pages := 0; ; pages++ {
+		require.Less(t, pages, 5)
+		resp := request(3, token)
+		require.Equal(t, total, resp.TotalSize)
+		require.LessOrEqual(t, len(resp.Events), 3)
+		paged = append(paged, resp.Events...)
+		if resp.NextPageToken == "" {
+			break
+		}
+		token = resp.NextPageToken
+		if pages == 0 {
+			// Событие перед уже прочитанной страницей не сдвигает следующие страницы
+			create("2024-07-01T00:00:00Z", 60, "")
+			total++
+		}
+	}
+	require.Len(t, paged, 14)
+	for i := range paged {
+		require.Equal(t, all.Events[i].Id, paged[i].Id)
+		require.Equal(t, all.Events[i].StartTime, paged[i].StartTime)
+	}
+
+	// Токен другого периода и некорректный размер страницы отклоняются
+	week, err := client.ListEventsForWeek(ctx, &pb.ListEventsRequest{UserId: userID, PeriodStart: "2024-07-01", PageSize: 1})
+	require.NoError(t, err)
+	require.NotEmpty(t, week.NextPageToken)
+	_, err = client.ListEventsForMonth(ctx, &pb.ListEventsRequest{
+		UserId: userID, PeriodStart: "2024-07-01", PageToken: week.NextPageToken,
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = client.ListEventsForMonth(ctx, &pb.ListEventsRequest{UserId: userID, PeriodStart: "2024-07-01", PageToken: "garbage"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = client.ListEventsForMonth(ctx, &pb.ListEventsRequest{UserId: userID, PeriodStart: "2024-07-01", PageSize: -1})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestExportImportRecurringCalendar(t *testing.T) {
+	client, cleanup := startTestGRPCServer(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	eventID := uuid.NewString()
+	_, err := client.CreateEvent(ctx, &pb.CreateEventRequest{
+		Event: &pb.Event{
+			Id:                  eventID,
+			Title:               "Standup",
+			StartTime:           "2024-07-01T09:00:00Z",
+			DurationSeconds:     900,
+			UserId:              "exporter",
+			NotifyBeforeMinutes: 5,
+			Rrule:               "FREQ=DAILY;COUNT=5",
+			Exdates:             []string{"2024-07-03T09:00:00Z"},
+		},
+	})
+	require.NoError(t, err)
+	_, err = client.UpdateEventOccurrence(ctx, &pb.UpdateEventOccurrenceRequest{
+		EventId:      eventID,
+		RecurrenceId: "2024-07-02T09:00:00Z",
+		Event: &pb.Event{
+			Title:           "Standup (moved)",
+			StartTime:       "2024-07-02T12:00:00Z",
+			DurationSeconds: 900,
+		},
+	})
+	require.NoError(t, err)
+
+	exported, err := client.ExportEvents(ctx, &pb.ExportEventsRequest{UserId: "exporter"})
+	require.NoError(t, err)
+	require.Contains(t, exported.GetContentType(), "text/calendar")
+
+	// Календарь загружается в другой календарь без изменения исходных событий
+	report, err := client.ImportEvents(ctx, &pb.ImportEventsRequest{UserId: "importer", Calendar: string(exported.GetData())})
+	require.NoError(t, err)
+	require.EqualValues(t, 2, report.GetImported())
+	require.Empty(t, report.GetSkipped())
+
+	list := func(userID string) []*pb.Event {
+		resp, err := client.ListEventsForWeek(ctx, &pb.ListEventsRequest{UserId: userID, PeriodStart: "2024-07-01"})
+		require.NoError(t, err)
+		return resp.Events
+	}
+	original, imported := list("exporter"), list("importer")
+	require.Len(t, imported, 4)
+	require.Len(t, imported, len(original))
+	for i := range original {
+		require.Equal(t, original[i].Title, imported[i].Title)
+		require.Equal(t, original[i].StartTime, imported[i].StartTime)
+		require.Equal(t, original[i].NotifyBeforeMinutes, imported[i].NotifyBeforeMinutes)
+		require.NotEqual(t, eventID, imported[i].Id)
+		require.Equal(t, "importer", imported[i].UserId)
+	}
+	require.Equal(t, "Standup (moved)", imported[1].Title)
+
+	_, err = client.ImportEvents(ctx, &pb.ImportEventsRequest{UserId: "importer", Calendar: "garbage"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestEventReminders(t *testing.T) {
+	client, cleanup := startTestGRPCServer(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	listDay := func() []*pb.Event {
+		resp, err := client.ListEventsForDay(ctx, &pb.ListEventsRequest{
+			UserId:      "user1",
+			PeriodStart: "2024-07-19T00:00:00Z",
+			PeriodEnd:   "2024-07-20T00:00:00Z",
+		})
+		require.NoError(t, err)
+		return resp.Events
+	}
+
+	// Напоминания упорядочиваются от самого раннего, повторы удаляются
+	event := &pb.Event{
+		Id:              uuid.NewString(),
+		Title:           "Meeting",
+		StartTime:       "2024-07-19T10:00:00Z",
+		DurationSeconds: 3600,
+		UserId:          "user1",
+		Reminders: []*pb.EventReminder{
+			{MinutesBefore: 10},
+			{MinutesBefore: 24 * 60, Channel: "Email"},
+			{MinutesBefore: 10},
+		},
+	}
+	created, err := client.CreateEvent(ctx, &pb.CreateEventRequest{Event: event})
+	require.NoError(t, err)
+	events := listDay()
+	require.Len(t, events, 1)
+	// Ответ содержит сохранённое событие, а не запрос
+	require.True(t, proto.Equal(events[0], created.Event), "created: %v, stored: %v", created.Event, events[0])
+	require.Len(t, events[0].Reminders, 2)
+	require.Equal(t, int32(24*60), events[0].Reminders[0].MinutesBefore)
+	require.Equal(t, "email", events[0].Reminders[0].Channel)
+	require.Equal(t, int32(10), events[0].Reminders[1].MinutesBefore)
+	require.Empty(t, events[0].Reminders[1].Channel)
+	require.Equal(t, int32(24*60), events[0].NotifyBeforeMinutes)
+
+	// Устаревшее поле notify_before_minutes задаёт одно напоминание
+	event.Reminders, event.NotifyBeforeMinutes = nil, 5
+	updated, err := client.UpdateEvent(ctx, &pb.UpdateEventRequest{Event: event})
+	require.NoError(t, err)
+	events = listDay()
+	require.True(t, proto.Equal(events[0], updated.Event), "updated: %v, stored: %v", updated.Event, events[0])
+	require.Len(t, events[0].Reminders, 1)
+	require.Equal(t, int32(5), events[0].Reminders[0].MinutesBefore)
+
+	event.Reminders = []*pb.EventReminder{{MinutesBefore: 0}}
+	_, err = client.UpdateEvent(ctx, &pb.UpdateEventRequest{Event: event})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestInvitations(t *testing.T) {
+	client, cleanup := startTestGRPCServer(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	eventID := uuid.NewString()
+	_, err := client.CreateEvent(ctx, &pb.CreateEventRequest{
+		Event: &pb.Event{
+			Id:              eventID,
+			Title:           "Team sync",
+			StartTime:       "2024-07-19T10:00:00Z",
+			DurationSeconds: 3600,
+			UserId:          "owner",
+			Attendees:       []*pb.Attendee{{UserId: "user2", Status: pb.AttendeeStatus_ACCEPTED}},
+		},
+	})
+	require.NoError(t, err)
+	listDay := func(userID string) []*pb.Event {
+		resp, err := client.ListEventsForDay(ctx, &pb.ListEventsRequest{UserId: userID, PeriodStart: "2024-07-19"})
+		require.NoError(t, err)
+		return resp.Events
+	}
+
+	// Владелец не может ответить за участника: приглашение создаётся без ответа
+	invitations, err := client.ListInvitations(ctx, &pb.ListInvitationsRequest{UserId: "user2"})
+	require.NoError(t, err)
+	require.Len(t, invitations.Invitations, 1)
+	require.Equal(t, pb.AttendeeStatus_NEEDS_ACTION, invitations.Invitations[0].Status)
+	require.Equal(t, eventID, invitations.Invitations[0].Event.Id)
+
+	// Событие участника показывается в его календаре
+	events := listDay("user2")
+	require.Len(t, events, 1)
+	require.Equal(t, "owner", events[0].UserId)
+
+	invited, err := client.InviteAttendees(ctx, &pb.InviteAttendeesRequest{EventId: eventID, UserIds: []string{"user3", "user2"}})
+	require.NoError(t, err)
+	require.Len(t, invited.Event.Attendees, 2)
+
+	resp, err := client.RespondToInvitation(ctx, &pb.RespondToInvitationRequest{
+		EventId: eventID, UserId: "user2", Status: pb.AttendeeStatus_ACCEPTED,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "user2", resp.Event.Attendees[0].UserId)
+	require.Equal(t, pb.AttendeeStatus_ACCEPTED, resp.Event.Attendees[0].Status)
+
+	accepted, err := client.ListInvitations(ctx, &pb.ListInvitationsRequest{UserId: "user2", Status: pb.AttendeeStatus_ACCEPTED})
+	require.NoError(t, err)
+	require.Len(t, accepted.Invitations, 1)
+	pending, err := client.ListInvitations(ctx, &pb.ListInvitationsRequest{UserId: "user2", Status: pb.AttendeeStatus_NEEDS_ACTION})
+	require.NoError(t, err)
+	require.Empty(t, pending.Invitations)
+
+	// Отказавшийся участник больше не видит событие в календаре
+	_, err = client.RespondToInvitation(ctx, &pb.RespondToInvitationRequest{
+		EventId: eventID, UserId: "user3", Status: pb.AttendeeStatus_DECLINED,
+	})
+	require.NoError(t, err)
+	require.Empty(t, listDay("user3"))
+	require.Len(t, listDay("user2"), 1)
+
+	_, err = client.RespondToInvitation(ctx, &pb.RespondToInvitationRequest{
+		EventId: eventID, UserId: "stranger", Status: pb.AttendeeStatus_ACCEPTED,
+	})
+	require.Equal(t, codes.NotFound, status.Code(err))
+	_, err = client.RespondToInvitation(ctx, &pb.RespondToInvitationRequest{EventId: eventID, UserId: "user2"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestAuthentication(t *testing.T) {
+	key := []byte("test-secret")
+	authenticator, err := auth.New(auth.Config{
+		JWTKey:  string(key),
+		APIKeys: map[string]string{"scheduler": "scheduler-key"},
+	})
+	require.NoError(t, err)
+	client, cleanup := startTestGRPCServer(t,
+		grpc.ChainUnaryInterceptor(grpcserver.UnaryAuthInterceptor(authenticator)))
+	defer cleanup()
+
+	as := func(userID string) context.Context {
+		token, err := auth.SignToken(key, map[string]any{"sub": userID, "exp": time.Now().Add(time.Hour).Unix()})
+		require.NoError(t, err)
+		return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+	}
+	newEvent := func(userID string) *pb.Event {
+		return &pb.Event{
+			Id:              uuid.NewString(),
+			Title:           "Auth",
+			StartTime:       "2024-07-19T10:00:00Z",
+			DurationSeconds: 3600,
+			UserId:          userID,
+		}
+	}
+
+	// Без учётных данных и с неверным токеном вызовы отклоняются
+	_, err = client.CreateEvent(context.Background(), &pb.CreateEventRequest{Event: newEvent("user1")})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+	badCtx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer bad.token.value")
+	_, err = client.CreateEvent(badCtx, &pb.CreateEventRequest{Event: newEvent("user1")})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// Пользователь не может создавать события и читать календарь от имени другого пользователя
+	_, err = client.CreateEvent(as("user2"), &pb.CreateEventRequest{Event: newEvent("user1")})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = client.ListEventsForDay(as("user2"), &pb.ListEventsRequest{UserId: "user1", PeriodStart: "2024-07-19"})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	event := newEvent("user1")
+	_, err = client.CreateEvent(as("user1"), &pb.CreateEventRequest{Event: event})
+	require.NoError(t, err)
+
+	// Чужое событие нельзя изменить или удалить, даже передав ID владельца в запросе
+	updated := newEvent("user2")
+	updated.Id = event.Id
+	_, err = client.UpdateEvent(as("user2"), &pb.UpdateEventRequest{Event: updated})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = client.DeleteEvent(as("user2"), &pb.DeleteEventRequest{Id: event.Id})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = client.DeleteEvent(as("user2"), &pb.DeleteEventRequest{Id: event.Id, UserId: "user1"})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	// Владелец не может передать событие другому пользователю
+	_, err = client.UpdateEvent(as("user1"), &pb.UpdateEventRequest{Event: updated})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	resp, err := client.ListEventsForDay(as("user1"), &pb.ListEventsRequest{UserId: "user1", PeriodStart: "2024-07-19"})
+	require.NoError(t, err)
+	require.Len(t, resp.Events, 1)
+
+	// Сервис с API-ключом действует от имени любого пользователя
+	serviceCtx := metadata.AppendToOutgoingContext(context.Background(), "x-api-key", "scheduler-key")
+	_, err = client.DeleteEvent(serviceCtx, &pb.DeleteEventRequest{Id: event.Id})
+	require.NoError(t, err)
+}
+
+func TestDeleteEventChecksUserID(t *testing.T) {
+	client, cleanup := startTestGRPCServer(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	eventID := uuid.NewString()
+	_, err := client.CreateEvent(ctx, &pb.CreateEventRequest{Event: &pb.Event{
+		Id:              eventID,
+		Title:           "Owned",
+		StartTime:       "2024-07-19T10:00:00Z",
+		DurationSeconds: 3600,
+		UserId:          "user1",
+	}})
+	require.NoError(t, err)
+
+	_, err = client.DeleteEvent(ctx, &pb.DeleteEventRequest{Id: eventID, UserId: "user2"})
+	require.Equal(t, codes.NotFound, status.Code(err))
+	_, err = client.DeleteEvent(ctx, &pb.DeleteEventRequest{Id: eventID, UserId: "user1"})
+	require.NoError(t, err)
+}
+
+func TestErrorCodes(t *testing.T) {
+	client, cleanup := startTestGRPCServer(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	requireReason := func(err error, code codes.Code, reason string) {
+		t.Helper()
+		st := status.Convert(err)
+		require.Equal(t, code, st.Code(), st.Message())
+		require.Equal(t, reason, grpcserver.ErrorReason(st))
+	}
+
+	_, err := client.DeleteEvent(ctx, &pb.DeleteEventRequest{Id: uuid.NewString()})
+	requireReason(err, codes.NotFound, grpcserver.ReasonNotFound)
+	_, err = client.UpdateEvent(ctx, &pb.UpdateEventRequest{Event: &pb.Event{
+		Id: uuid.NewString(), Title: "Missing", StartTime: "2024-07-19T10:00:00Z", UserId: "user1",
+	}})
+	requireReason(err, codes.NotFound, grpcserver.ReasonNotFound)
+
+	_, err = client.CreateEvent(ctx, &pb.CreateEventRequest{Event: &pb.Event{
+		Id: uuid.NewString(), Title: "Backwards", StartTime: "2024-07-19T10:00:00Z", DurationSeconds: -60, UserId: "user1",
+	}})
+	requireReason(err, codes.InvalidArgument, grpcserver.ReasonValidation)
+
+	_, err = client.CreateEvent(ctx, &pb.CreateEventRequest{Event: &pb.Event{
+		Id: uuid.NewString(), Title: "No start", StartTime: "tomorrow", UserId: "user1",
+	}})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	// Занятый ID не переиспользуется, даже другим пользователем
+	takenID := uuid.NewString()
+	_, err = client.CreateEvent(ctx, &pb.CreateEventRequest{Event: &pb.Event{
+		Id: takenID, Title: "Original", StartTime: "2024-07-19T10:00:00Z", DurationSeconds: 60, UserId: "user1",
+	}})
+	require.NoError(t, err)
+	_, err = client.CreateEvent(ctx, &pb.CreateEventRequest{Event: &pb.Event{
+		Id: takenID, Title: "Duplicate", StartTime: "2024-07-20T10:00:00Z", DurationSeconds: 60, UserId: "user2",
+	}})
+	requireReason(err, codes.AlreadyExists, grpcserver.ReasonAlreadyExists)
+	original, err := client.GetEvent(ctx, &pb.GetEventRequest{Id: takenID})
+	require.NoError(t, err)
+	require.Equal(t, "user1", original.Event.UserId)
+}
+
+func TestGetEvent(t *testing.T) {
+	key := []byte("test-secret")
+	authenticator, err := auth.New(auth.Config{JWTKey: string(key)})
+	require.NoError(t, err)
+	client, cleanup := startTestGRPCServer(t,
+		grpc.ChainUnaryInterceptor(grpcserver.UnaryAuthInterceptor(authenticator)))
+	defer cleanup()
+
+	as := func(userID string) context.Context {
+		token, err := auth.SignToken(key, map[string]any{"sub": userID, "exp": time.Now().Add(time.Hour).Unix()})
+		require.NoError(t, err)
+		return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+	}
+
+	event := &pb.Event{
+		Id:              uuid.NewString(),
+		Title:           "Review",
+		Description:     "Quarterly review",
+		StartTime:       "2024-07-19T10:00:00Z",
+		DurationSeconds: 3600,
+		UserId:          "user1",
+	}
+	_, err = client.CreateEvent(as("user1"), &pb.CreateEventRequest{Event: event})
+	require.NoError(t, err)
+
+	resp, err := client.GetEvent(as("user1"), &pb.GetEventRequest{Id: event.Id})
+	require.NoError(t, err)
+	require.Equal(t, event.Title, resp.Event.Title)
+	require.Equal(t, event.Description, resp.Event.Description)
+	require.Equal(t, event.StartTime, resp.Event.StartTime)
+
+	// Чужое событие недоступно, несуществующее и некорректный ID — не найдены
+	_, err = client.GetEvent(as("user2"), &pb.GetEventRequest{Id: event.Id})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = client.GetEvent(as("user1"), &pb.GetEventRequest{Id: uuid.NewString()})
+	require.Equal(t, codes.NotFound, status.Code(err))
+	_, err = client.GetEvent(as("user1"), &pb.GetEventRequest{Id: "not-a-uuid"})
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestListEventsFilters(t *testing.T) {
+	client, cleanup := startTestGRPCServer(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	userID := "userFilters"
+	create := func(title, description, start, rrule string, reminders ...int32) string {
+		event := &pb.Event{
+			Id:              uuid.NewString(),
+			Title:           title,
+			Description:     description,
+			StartTime:       start,
+			DurationSeconds: 1800,
+			UserId:          userID,
+			Rrule:           rrule,
+		}
+		for _, minutes := range reminders {
+			event.Reminders = append(event.Reminders, &pb.EventReminder{MinutesBefore: minutes})
+		}
+		_, err := client.CreateEvent(ctx, &pb.CreateEventRequest{Event: event})
+		require.NoError(t, err)
+		return event.Id
+	}
+	standup := create("Standup", "Daily sync", "2024-07-01T09:00:00Z", "FREQ=DAILY;COUNT=5", 5)
+	create("Budget review", "Q3 budget with finance", "2024-07-02T14:00:00Z", "", 30)
+	create("lunch", "Team lunch", "2024-07-03T12:00:00Z", "")
+	create("Budget planning", "Next year", "2024-08-01T10:00:00Z", "")
+	create("Someone else's", "Budget", "2024-07-02T10:00:00Z", "")
+	_, err := client.CreateEvent(ctx, &pb.CreateEventRequest{Event: &pb.Event{
+		Id: uuid.NewString(), Title: "Budget", StartTime: "2024-07-02T16:00:00Z", UserId: "otherUser",
+	}})
+	require.NoError(t, err)
+
+	list := func(req *pb.FilterEventsRequest) *pb.ListEventsResponse {
+		t.Helper()
+		req.UserId = userID
+		resp, err := client.ListEvents(ctx, req)
+		require.NoError(t, err)
+		return resp
+	}
+	titles := func(events []*pb.Event) []string {
+		result := make([]string, 0, len(events))
+		for _, ev := range events {
+			result = append(result, ev.Title)
+		}
+		return result
+	}
+
+	// Без диапазона серия возвращается одним событием
+	all := list(&pb.FilterEventsRequest{})
+	require.Equal(t, int32(5), all.TotalSize)
+	require.Equal(t, standup, all.Events[0].Id)
+	require.Empty(t, all.Events[0].RecurrenceId)
+
+	// В диапазоне серия разворачивается в экземпляры
+	july := list(&pb.FilterEventsRequest{PeriodStart: "2024-07-01T00:00:00Z", PeriodEnd: "2024-08-01T00:00:00Z"})
+	require.Equal(t, int32(8), july.TotalSize)
+	require.Equal(t, "2024-07-01T09:00:00Z", july.Events[0].RecurrenceId)
+
+	// Все слова запроса ищутся в заголовке и описании без учёта регистра
+	budget := list(&pb.FilterEventsRequest{Query: "BUDGET"})
+	require.Equal(t, []string{"Someone else's", "Budget review", "Budget planning"}, titles(budget.Events))
+	budget = list(&pb.FilterEventsRequest{Query: "budget finance"})
+	require.Equal(t, []string{"Budget review"}, titles(budget.Events))
+
+	// Отбор по напоминаниям
+	with := list(&pb.FilterEventsRequest{
+		PeriodStart: "2024-07-01T00:00:00Z", PeriodEnd: "2024-08-01T00:00:00Z", Reminders: pb.ReminderFilter_WITH_REMINDERS,
+	})
+	require.Equal(t, int32(6), with.TotalSize)
+	without := list(&pb.FilterEventsRequest{Reminders: pb.ReminderFilter_WITHOUT_REMINDERS})
+	require.Equal(t, []string{"Someone else's", "lunch", "Budget planning"}, titles(without.Events))
+
+	// Порядок по убыванию времени начала и по заголовку
+	desc := list(&pb.FilterEventsRequest{OrderBy: pb.EventOrder_START_TIME_DESC})
+	require.Equal(t, "Budget planning", desc.Events[0].Title)
+	require.Equal(t, "Standup", desc.Events[4].Title)
+	byTitle := list(&pb.FilterEventsRequest{OrderBy: pb.EventOrder_TITLE_ASC})
+	require.Equal(t, []string{"Budget planning", "Budget review", "lunch", "Someone else's", "Standup"}, titles(byTitle.Events))
+
+	// Постраничное чтение в выбранном порядке
+	var paged []string
+	token := ""
+	for pages := 0; ; pages++ {
+		require.Less(t, pages, 5)
+		resp := list(&pb.FilterEventsRequest{OrderBy: pb.EventOrder_TITLE_ASC, PageSize: 2, PageToken: token})
+		require.Equal(t, int32(5), resp.TotalSize)
+		paged = append(paged, titles(resp.Events)...)
+		if resp.NextPageToken == "" {
+			break
+		}
+		token = resp.NextPageToken
+	}
+	require.Equal(t, titles(byTitle.Events), paged)
+
+	// Токен другого фильтра и некорректные параметры отклоняются
+	first := list(&pb.FilterEventsRequest{PageSize: 1})
+	require.NotEmpty(t, first.NextPageToken)
+	for _, req := range []*pb.FilterEventsRequest{
+		{UserId: userID, OrderBy: pb.EventOrder_TITLE_ASC, PageToken: first.NextPageToken},
+		{UserId: userID, PeriodStart: "2024-07-01T00:00:00Z"},
+		{UserId: userID, PeriodStart: "2024-08-01T00:00:00Z", PeriodEnd: "2024-07-01T00:00:00Z"},
+		{UserId: userID, PeriodStart: "yesterday", PeriodEnd: "2024-07-01T00:00:00Z"},
+		{UserId: userID, OrderBy: pb.EventOrder(42)},
+	} {
+		_, err := client.ListEvents(ctx, req)
+		require.Equal(t, codes.InvalidArgument, status.Code(err), "request %v", req)
+	}
+}
+
+func TestWatchEvents(t *testing.T) {
+	client, cleanup := startTestGRPCServer(t)
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	userID := "userWatch"
+
+	// Изменение до подписки без revision не приходит
+	before := &pb.Event{Id: uuid.NewString(), Title: "Before", StartTime: "2024-07-19T08:00:00Z", UserId: userID}
+	_, err := client.CreateEvent(ctx, &pb.CreateEventRequest{Event: before})
+	require.NoError(t, err)
+
+	watchCtx, stopWatch := context.WithCancel(ctx)
+	stream, err := client.WatchEvents(watchCtx, &pb.WatchEventsRequest{UserId: userID})
+	require.NoError(t, err)
+	// Заголовок x-revision приходит сразу после подписки, до первого изменения
+	header, err := stream.Header()
+	require.NoError(t, err)
+	require.Len(t, header.Get(grpcserver.RevisionHeader), 1)
+
+	event := &pb.Event{
+		Id: uuid.NewString(), Title: "Planning", StartTime: "2024-07-19T10:00:00Z", DurationSeconds: 3600, UserId: userID,
+	}
+	_, err = client.CreateEvent(ctx, &pb.CreateEventRequest{Event: event})
+	require.NoError(t, err)
+	event.Title = "Planning moved"
+	event.StartTime = "2024-07-19T11:00:00Z"
+	_, err = client.UpdateEvent(ctx, &pb.UpdateEventRequest{Event: event})
+	require.NoError(t, err)
+	_, err = client.DeleteEvent(ctx, &pb.DeleteEventRequest{Id: event.Id})
+	require.NoError(t, err)
+	// Чужие события в поток не попадают
+	_, err = client.CreateEvent(ctx, &pb.CreateEventRequest{Event: &pb.Event{
+		Id: uuid.NewString(), Title: "Other", StartTime: "2024-07-19T10:00:00Z", UserId: "otherUser",
+	}})
+	require.NoError(t, err)
+
+	created, err := stream.Recv()
+	require.NoError(t, err)
+	require.Equal(t, pb.ChangeType_CREATED, created.Type)
+	require.Equal(t, event.Id, created.EventId)
+	require.NotEmpty(t, created.Revision)
+
+	updated, err := stream.Recv()
+	require.NoError(t, err)
+	require.Equal(t, pb.ChangeType_UPDATED, updated.Type)
+	require.Equal(t, "Planning moved", updated.Event.Title)
+	require.Equal(t, "2024-07-19T11:00:00Z", updated.Event.StartTime)
+
+	deleted, err := stream.Recv()
+	require.NoError(t, err)
+	require.Equal(t, pb.ChangeType_DELETED, deleted.Type)
+	require.Equal(t, event.Id, deleted.EventId)
+	require.Nil(t, deleted.Event)
+	stopWatch()
+
+	// После переподключения с revision поток продолжается со следующего изменения;
+	// событие уже удалено, поэтому изменение приходит как удаление
+	resumed, err := client.WatchEvents(ctx, &pb.WatchEventsRequest{UserId: userID, Revision: created.Revision})
+	require.NoError(t, err)
+	next, err := resumed.Recv()
+	require.NoError(t, err)
+	require.Equal(t, updated.Revision, next.Revision)
+	require.Equal(t, pb.ChangeType_DELETED, next.Type)
+	require.Nil(t, next.Event)
+
+	bad, err := client.WatchEvents(ctx, &pb.WatchEventsRequest{UserId: userID, Revision: "not-a-revision"})
+	require.NoError(t, err)
+	_, err = bad.Recv()
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestTrash(t *testing.T) {
+	client, cleanup := startTestGRPCServer(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	eventID := uuid.NewString()
+	event := &pb.Event{
+		Id:              eventID,
+		Title:           "Budget review",
+		StartTime:       "2024-07-21T10:00:00Z",
+		DurationSeconds: 3600,
+		UserId:          "user3",
+	}
+	_, err := client.CreateEvent(ctx, &pb.CreateEventRequest{Event: event})
+	require.NoError(t, err)
+	_, err = client.DeleteEvent(ctx, &pb.DeleteEventRequest{Id: eventID})
+	require.NoError(t, err)
+
+	_, err = client.GetEvent(ctx, &pb.GetEventRequest{Id: eventID})
+	require.Equal(t, codes.NotFound, status.Code(err))
+	trash, err := client.ListTrash(ctx, &pb.ListTrashRequest{UserId: "user3"})
+	require.NoError(t, err)
+	require.Len(t, trash.Events, 1)
+	require.Equal(t, eventID, trash.Events[0].Id)
+	require.NotEmpty(t, trash.Events[0].DeletedAt)
+
+	// ID события в корзине нельзя занять новым событием
+	_, err = client.CreateEvent(ctx, &pb.CreateEventRequest{Event: event})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	restored, err := client.RestoreEvent(ctx, &pb.RestoreEventRequest{Id: eventID})
+	require.NoError(t, err)
+	require.Equal(t, "Budget review", restored.Event.Title)
+	require.Empty(t, restored.Event.DeletedAt)
+	require.Equal(t, int64(3), restored.Event.Version)
+	day, err := client.ListEventsForDay(ctx, &pb.ListEventsRequest{UserId: "user3", PeriodStart: "2024-07-21"})
+	require.NoError(t, err)
+	require.Len(t, day.Events, 1)
+
+	_, err = client.DeleteEvent(ctx, &pb.DeleteEventRequest{Id: eventID})
+	require.NoError(t, err)
+	_, err = client.PurgeEvent(ctx, &pb.PurgeEventRequest{Id: eventID})
+	require.NoError(t, err)
+	trash, err = client.ListTrash(ctx, &pb.ListTrashRequest{UserId: "user3"})
+	require.NoError(t, err)
+	require.Empty(t, trash.Events)
+	_, err = client.RestoreEvent(ctx, &pb.RestoreEventRequest{Id: eventID})
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestEventHistory(t *testing.T) {
+	key := []byte("test-secret")
+	authenticator, err := auth.New(auth.Config{
+		JWTKey:  string(key),
+		APIKeys: map[string]string{"scheduler": "scheduler-key"},
+	})
+	require.NoError(t, err)
+	client, cleanup := startTestGRPCServer(t,
+		grpc.ChainUnaryInterceptor(grpcserver.UnaryAuthInterceptor(authenticator)))
+	defer cleanup()
+
+	as := func(userID string) context.Context {
+		token, err := auth.SignToken(key, map[string]any{"sub": userID, "exp": time.Now().Add(time.Hour).Unix()})
+		require.NoError(t, err)
+		return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+	}
+	eventID := uuid.NewString()
+	event := &pb.Event{
+		Id:              eventID,
+		Title:           "Planning",
+		StartTime:       "2024-07-22T10:00:00Z",
+		DurationSeconds: 3600,
+		UserId:          "owner",
+	}
+	_, err = client.CreateEvent(as("owner"), &pb.CreateEventRequest{Event: event})
+	require.NoError(t, err)
+	event.Title = "Quarter planning"
+	_, err = client.UpdateEvent(as("owner"), &pb.UpdateEventRequest{Event: event})
+	require.NoError(t, err)
+	_, err = client.InviteAttendees(as("owner"), &pb.InviteAttendeesRequest{EventId: eventID, UserIds: []string{"guest"}})
+	require.NoError(t, err)
+	_, err = client.RespondToInvitation(as("guest"), &pb.RespondToInvitationRequest{
+		EventId: eventID, UserId: "guest", Status: pb.AttendeeStatus_ACCEPTED,
+	})
+	require.NoError(t, err)
+	serviceCtx := metadata.AppendToOutgoingContext(context.Background(), "x-api-key", "scheduler-key")
+	_, err = client.DeleteEvent(serviceCtx, &pb.DeleteEventRequest{Id: eventID})
+	require.NoError(t, err)
+
+	history, err := client.GetEventHistory(as("owner"), &pb.GetEventHistoryRequest{Id: eventID})
+	require.NoError(t, err)
+	actions := make([]pb.AuditAction, 0, len(history.Entries))
+	for _, e := range history.Entries {
+		actions = append(actions, e.Action)
+	}
+	require.Equal(t, []pb.AuditAction{
+		pb.AuditAction_EVENT_DELETED, pb.AuditAction_EVENT_UPDATED, pb.AuditAction_EVENT_UPDATED,
+		pb.AuditAction_EVENT_UPDATED, pb.AuditAction_EVENT_CREATED,
+	}, actions)
+	// Удаление сервисом записано от имени владельца с указанием сервиса
+	require.Equal(t, "owner", history.Entries[0].ActorId)
+	require.Equal(t, "scheduler", history.Entries[0].Service)
+	// Ответ на приглашение записан от имени участника
+	require.Equal(t, "guest", history.Entries[1].ActorId)
+	require.Equal(t, "attendees", history.Entries[1].Changes[0].Field)
+	rename := history.Entries[3].Changes
+	require.Len(t, rename, 1)
+	require.Equal(t, "title", rename[0].Field)
+	require.Equal(t, "Planning", rename[0].Before)
+	require.Equal(t, "Quarter planning", rename[0].After)
+
+	last, err := client.GetEventHistory(as("owner"), &pb.GetEventHistoryRequest{Id: eventID, Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, last.Entries, 1)
+	_, err = client.GetEventHistory(as("owner"), &pb.GetEventHistoryRequest{Id: eventID, Limit: 1000})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	// Историю и действия пользователя видят только он сам и сервисы
+	_, err = client.GetEventHistory(as("guest"), &pb.GetEventHistoryRequest{Id: eventID})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = client.ListUserActivity(as("owner"), &pb.ListUserActivityRequest{UserId: "guest"})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	activity, err := client.ListUserActivity(as("guest"), &pb.ListUserActivityRequest{UserId: "guest"})
+	require.NoError(t, err)
+	require.Len(t, activity.Entries, 1)
+	require.Equal(t, eventID, activity.Entries[0].EventId)
+	_, err = client.GetEventHistory(as("owner"), &pb.GetEventHistoryRequest{Id: uuid.NewString()})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	// Перенос участников серии в изменённые экземпляры записывается в историю экземпляров
+	seriesID := uuid.NewString()
+	_, err = client.CreateEvent(as("owner"), &pb.CreateEventRequest{Event: &pb.Event{
+		Id:              seriesID,
+		Title:           "Standup",
+		StartTime:       "2024-07-29T09:00:00Z",
+		DurationSeconds: 900,
+		UserId:          "owner",
+		Rrule:           "FREQ=DAILY;COUNT=3",
+	}})
+	require.NoError(t, err)
+	occurrence, err := client.UpdateEventOccurrence(as("owner"), &pb.UpdateEventOccurrenceRequest{
+		EventId:      seriesID,
+		RecurrenceId: "2024-07-30T09:00:00Z",
+		Event:        &pb.Event{Title: "Standup (moved)", StartTime: "2024-07-30T12:00:00Z", DurationSeconds: 900},
+	})
+	require.NoError(t, err)
+	_, err = client.InviteAttendees(as("owner"), &pb.InviteAttendeesRequest{EventId: seriesID, UserIds: []string{"guest"}})
+	require.NoError(t, err)
+	history, err = client.GetEventHistory(as("owner"), &pb.GetEventHistoryRequest{Id: occurrence.Event.Id})
+	require.NoError(t, err)
+	require.Equal(t, pb.AuditAction_EVENT_UPDATED, history.Entries[0].Action)
+	require.Equal(t, "attendees", history.Entries[0].Changes[0].Field)
+}
+
+func TestSearchEvents(t *testing.T) {
+	client, cleanup := startTestGRPCServer(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	for _, e := range []*pb.Event{
+		{Title: "Q3 budget review", Description: "Marketing and hiring budget", StartTime: "2024-07-23T10:00:00Z"},
+		{Title: "Planning", Description: "Q3 goals and budget draft", StartTime: "2024-07-23T12:00:00Z"},
+		{Title: "Lunch", StartTime: "2024-07-23T13:00:00Z"},
+	} {
+		e.Id, e.UserId, e.DurationSeconds = uuid.NewString(), "user5", 1800
+		_, err := client.CreateEvent(ctx, &pb.CreateEventRequest{Event: e})
+		require.NoError(t, err)
+	}
+
+	resp, err := client.SearchEvents(ctx, &pb.SearchEventsRequest{UserId: "user5", Query: "q3 budget"})
+	require.NoError(t, err)
+	require.Len(t, resp.Results, 2)
+	require.Equal(t, "Q3 budget review", resp.Results[0].Event.Title)
+	require.Equal(t, "<b>Q3</b> <b>budget</b> review", resp.Results[0].Title)
+	require.Equal(t, "Marketing and hiring <b>budget</b>", resp.Results[0].Snippet)
+	require.Greater(t, resp.Results[0].Rank, resp.Results[1].Rank)
+
+	_, err = client.SearchEvents(ctx, &pb.SearchEventsRequest{UserId: "user5", Query: "  "})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = client.SearchEvents(ctx, &pb.SearchEventsRequest{UserId: "user5", Query: "budget", Limit: 1000})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = client.SearchEvents(ctx, &pb.SearchEventsRequest{UserId: "user5", Query: "budget", Language: 7})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestWebhooks(t *testing.T) {
+	key := []byte("test-secret")
+	authenticator, err := auth.New(auth.Config{JWTKey: string(key), APIKeys: map[string]string{"scheduler": "scheduler-key"}})
+	require.NoError(t, err)
+	client, cleanup := startTestGRPCServer(t,
+		grpc.ChainUnaryInterceptor(grpcserver.UnaryAuthInterceptor(authenticator)))
+	defer cleanup()
+
+	as := func(userID string) context.Context {
+		token, err := auth.SignToken(key, map[string]any{"sub": userID, "exp": time.Now().Add(time.Hour).Unix()})
+		require.NoError(t, err)
+		return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+	}
+
+	created, err := client.CreateWebhook(as("user1"), &pb.CreateWebhookRequest{UserId: "user1", Url: "https://203.0.113.10/hook"})
+	require.NoError(t, err)
+	require.Equal(t, "https://203.0.113.10/hook", created.Webhook.Url)
+	require.Regexp(t, "^whsec_[0-9a-f]{64}$", created.Webhook.Secret)
+
+	// Ключ подписи возвращается только при регистрации
+	list, err := client.ListWebhooks(as("user1"), &pb.ListWebhooksRequest{UserId: "user1"})
+	require.NoError(t, err)
+	require.Len(t, list.Webhooks, 1)
+	require.Equal(t, created.Webhook.Id, list.Webhooks[0].Id)
+	require.Empty(t, list.Webhooks[0].Secret)
+
+	_, err = client.CreateWebhook(as("user1"), &pb.CreateWebhookRequest{UserId: "user1", Url: "https://203.0.113.10/hook"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = client.CreateWebhook(as("user1"), &pb.CreateWebhookRequest{UserId: "user1", Url: "ftp://203.0.113.10"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = client.CreateWebhook(as("user2"), &pb.CreateWebhookRequest{UserId: "user1", Url: "https://203.0.113.66"})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = client.ListWebhooks(as("user2"), &pb.ListWebhooksRequest{UserId: "user1"})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	// Сервис не регистрирует адреса от имени пользователя: напоминания получает только сам пользователь
+	serviceCtx := metadata.AppendToOutgoingContext(context.Background(), "x-api-key", "scheduler-key")
+	_, err = client.CreateWebhook(serviceCtx, &pb.CreateWebhookRequest{UserId: "user1", Url: "https://203.0.113.66"})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	// Адреса самого сервиса, внутренней сети и метаданных облака не регистрируются
+	for _, internal := range []string{
+		"http://127.0.0.1:8080/", "http://[::1]/", "http://10.0.0.1/", "http://192.168.1.10/hook",
+		"http://169.254.169.254/latest/meta-data/", "http://localhost/", "http://calendar-db:5432/",
+		"https://metadata.google.internal/",
+	} {
+		_, err = client.CreateWebhook(as("user1"), &pb.CreateWebhookRequest{UserId: "user1", Url: internal})
+		require.Equal(t, codes.InvalidArgument, status.Code(err), internal)
+	}
+
+	// Удалить адрес может только пользователь, который его зарегистрировал
+	_, err = client.DeleteWebhook(as("user2"), &pb.DeleteWebhookRequest{Id: created.Webhook.Id})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = client.DeleteWebhook(as("user1"), &pb.DeleteWebhookRequest{Id: created.Webhook.Id})
+	require.NoError(t, err)
+	_, err = client.DeleteWebhook(as("user1"), &pb.DeleteWebhookRequest{Id: created.Webhook.Id})
+	require.Equal(t, codes.NotFound, status.Code(err))
 }
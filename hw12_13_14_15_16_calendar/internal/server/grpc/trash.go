@@ -0,0 +1,43 @@
+package grpc
+
+import (
+	"context"
+
+	pb "github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/api/gen"
+)
+
+// ListTrash реализует получение событий пользователя в корзине через GRPC.
+func (s *Server) ListTrash(ctx context.Context, req *pb.ListTrashRequest) (*pb.ListTrashResponse, error) {
+	s.app.Logger().Info("GRPC ListTrash: " + req.GetUserId())
+	events, err := s.app.ListTrash(ctx, req.GetUserId())
+	if err != nil {
+		s.app.Logger().Error("ListTrash error: " + err.Error())
+		return nil, appError(err)
+	}
+	resp := &pb.ListTrashResponse{}
+	for _, e := range events {
+		resp.Events = append(resp.Events, storageToProtoEvent(e))
+	}
+	return resp, nil
+}
+
+// RestoreEvent реализует восстановление события из корзины через GRPC.
+func (s *Server) RestoreEvent(ctx context.Context, req *pb.RestoreEventRequest) (*pb.RestoreEventResponse, error) {
+	s.app.Logger().Info("GRPC RestoreEvent: " + req.GetId())
+	event, err := s.app.RestoreEvent(ctx, req.GetId())
+	if err != nil {
+		s.app.Logger().Error("RestoreEvent error: " + err.Error())
+		return nil, appError(err)
+	}
+	return &pb.RestoreEventResponse{Event: storageToProtoEvent(event)}, nil
+}
+
+// PurgeEvent реализует удаление события из корзины навсегда через GRPC.
+func (s *Server) PurgeEvent(ctx context.Context, req *pb.PurgeEventRequest) (*pb.PurgeEventResponse, error) {
+	s.app.Logger().Info("GRPC PurgeEvent: " + req.GetId())
+	if err := s.app.PurgeEvent(ctx, req.GetId()); err != nil {
+		s.app.Logger().Error("PurgeEvent error: " + err.Error())
+		return nil, appError(err)
+	}
+	return &pb.PurgeEventResponse{Success: true}, nil
+}
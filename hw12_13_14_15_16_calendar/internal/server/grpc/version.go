@@ -0,0 +1,45 @@
+package grpc
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// IfMatchHeader — метаданные запроса с ожидаемой версией события в формате ETag ("3" или *).
+// REST API передаёт в них заголовок If-Match.
+const IfMatchHeader = "if-match"
+
+// ETag возвращает метку версии события для заголовков ETag и If-Match.
+func ETag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// expectedVersion возвращает ожидаемую версию события для UpdateEvent и DeleteEvent: версию из запроса,
+// а если она не задана — из метаданных if-match. 0 (в том числе If-Match: *) — версия не проверяется.
+func expectedVersion(ctx context.Context, version int64) (int64, error) {
+	if version != 0 {
+		return version, nil
+	}
+	values := metadata.ValueFromIncomingContext(ctx, IfMatchHeader)
+	if len(values) == 0 {
+		return 0, nil
+	}
+	value := strings.TrimSpace(values[0])
+	if value == "*" {
+		return 0, nil
+	}
+	unquoted, ok := strings.CutPrefix(value, `"`)
+	if ok {
+		unquoted, ok = strings.CutSuffix(unquoted, `"`)
+	}
+	parsed, err := strconv.ParseInt(unquoted, 10, 64)
+	if !ok || err != nil || parsed <= 0 {
+		return 0, status.Errorf(codes.InvalidArgument, "invalid %s: %s", IfMatchHeader, value)
+	}
+	return parsed, nil
+}
@@ -0,0 +1,56 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	pb "github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/api/gen"
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/storage"
+)
+
+// CreateWebhook реализует регистрацию адреса для напоминаний через GRPC.
+func (s *Server) CreateWebhook(ctx context.Context, req *pb.CreateWebhookRequest) (*pb.CreateWebhookResponse, error) {
+	s.app.Logger().Info("GRPC CreateWebhook: " + req.GetUserId())
+	webhook, err := s.app.CreateWebhook(ctx, req.GetUserId(), req.GetUrl())
+	if err != nil {
+		s.app.Logger().Error("CreateWebhook error: " + err.Error())
+		return nil, appError(err)
+	}
+	return &pb.CreateWebhookResponse{Webhook: webhookToProto(webhook)}, nil
+}
+
+// ListWebhooks реализует получение адресов пользователя через GRPC.
+func (s *Server) ListWebhooks(ctx context.Context, req *pb.ListWebhooksRequest) (*pb.ListWebhooksResponse, error) {
+	s.app.Logger().Info("GRPC ListWebhooks: " + req.GetUserId())
+	webhooks, err := s.app.ListWebhooks(ctx, req.GetUserId())
+	if err != nil {
+		s.app.Logger().Error("ListWebhooks error: " + err.Error())
+		return nil, appError(err)
+	}
+	resp := &pb.ListWebhooksResponse{}
+	for _, w := range webhooks {
+		resp.Webhooks = append(resp.Webhooks, webhookToProto(w))
+	}
+	return resp, nil
+}
+
+// DeleteWebhook реализует удаление адреса через GRPC.
+func (s *Server) DeleteWebhook(ctx context.Context, req *pb.DeleteWebhookRequest) (*pb.DeleteWebhookResponse, error) {
+	s.app.Logger().Info("GRPC DeleteWebhook: " + req.GetId())
+	if err := s.app.DeleteWebhook(ctx, req.GetId()); err != nil {
+		s.app.Logger().Error("DeleteWebhook error: " + err.Error())
+		return nil, appError(err)
+	}
+	return &pb.DeleteWebhookResponse{Success: true}, nil
+}
+
+// webhookToProto преобразует storage.Webhook в pb.Webhook.
+func webhookToProto(w storage.Webhook) *pb.Webhook {
+	return &pb.Webhook{
+		Id:        w.ID,
+		UserId:    w.UserID,
+		Url:       w.URL,
+		Secret:    w.Secret,
+		CreatedAt: time.Unix(w.CreatedAt, 0).Format(time.RFC3339),
+	}
+}
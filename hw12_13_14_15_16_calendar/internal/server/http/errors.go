@@ -0,0 +1,40 @@
+package internalhttp
+
+import (
+	"context"
+	"net/http"
+
+	grpcserver "github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/server/grpc"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc/status"
+)
+
+// errorHandler отвечает на ошибки REST API в формате grpc-gateway (код, сообщение и детали статуса).
+// Статус HTTP выбирается по коду gRPC, а для пересечения по времени (FAILED_PRECONDITION с причиной
+// DATE_BUSY) — 409 Conflict вместо 400, чтобы клиент отличал его от некорректного запроса.
+// Несовпадение версии события (ABORTED с причиной VERSION_CONFLICT) — 409 Conflict, а если версия
+// передана в If-Match — 412 Precondition Failed (RFC 9110).
+func errorHandler(ctx context.Context, mux *runtime.ServeMux, m runtime.Marshaler, w http.ResponseWriter, r *http.Request, err error) {
+	if st, ok := status.FromError(err); ok {
+		switch grpcserver.ErrorReason(st) {
+		case grpcserver.ReasonDateBusy:
+			w = &statusOverride{ResponseWriter: w, code: http.StatusConflict}
+		case grpcserver.ReasonVersionConflict:
+			if r.Header.Get("If-Match") != "" {
+				w = &statusOverride{ResponseWriter: w, code: http.StatusPreconditionFailed}
+			}
+		}
+	}
+	runtime.DefaultHTTPErrorHandler(ctx, mux, m, w, r, err)
+}
+
+// statusOverride подменяет статус HTTP ответа.
+type statusOverride struct {
+	http.ResponseWriter
+	code int
+}
+
+// WriteHeader записывает подменённый статус.
+func (w *statusOverride) WriteHeader(int) {
+	w.ResponseWriter.WriteHeader(w.code)
+}
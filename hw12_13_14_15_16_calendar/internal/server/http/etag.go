@@ -0,0 +1,32 @@
+package internalhttp
+
+import (
+	"context"
+	"net/http"
+
+	pb "github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/api/gen"
+	grpcserver "github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/server/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+)
+
+// ifMatchMetadata передаёт заголовок If-Match в метаданные if-match: по ним UpdateEvent и DeleteEvent
+// сверяют версию события, если она не задана в теле запроса.
+func ifMatchMetadata(_ context.Context, r *http.Request) metadata.MD {
+	if value := r.Header.Get("If-Match"); value != "" {
+		return metadata.Pairs(grpcserver.IfMatchHeader, value)
+	}
+	return nil
+}
+
+// eventETag отдаёт версию события из ответа в заголовке ETag, чтобы клиент мог передать её в If-Match.
+func eventETag(_ context.Context, w http.ResponseWriter, resp proto.Message) error {
+	withEvent, ok := resp.(interface{ GetEvent() *pb.Event })
+	if !ok {
+		return nil
+	}
+	if version := withEvent.GetEvent().GetVersion(); version != 0 {
+		w.Header().Set("ETag", grpcserver.ETag(version))
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package internalhttp
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	grpcserver "github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/server/grpc"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/genproto/googleapis/api/httpbody"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// maxCalendarSize ограничивает размер календаря в теле запроса, как и тело запросов CalDAV.
+const maxCalendarSize = 1 << 20
+
+// calendarMarshaler принимает календарь iCalendar в теле запроса как есть (Content-Type: text/calendar)
+// и записывает его в строковое поле запроса. Ответы кодируются так же, как в grpc-gateway по умолчанию.
+type calendarMarshaler struct {
+	runtime.Marshaler
+}
+
+func newCalendarMarshaler() calendarMarshaler {
+	return calendarMarshaler{Marshaler: &runtime.HTTPBodyMarshaler{
+		Marshaler: &runtime.JSONPb{
+			MarshalOptions:   protojson.MarshalOptions{EmitUnpopulated: true},
+			UnmarshalOptions: protojson.UnmarshalOptions{DiscardUnknown: true},
+		},
+	}}
+}
+
+// NewDecoder читает тело запроса целиком в строковое поле; остальные типы декодируются как JSON.
+// Тело больше maxCalendarSize отклоняется (grpc-gateway отвечает INVALID_ARGUMENT, HTTP 400).
+func (m calendarMarshaler) NewDecoder(r io.Reader) runtime.Decoder {
+	return runtime.DecoderFunc(func(v interface{}) error {
+		s, ok := v.(*string)
+		if !ok {
+			return m.Marshaler.NewDecoder(r).Decode(v)
+		}
+		data, err := io.ReadAll(io.LimitReader(r, maxCalendarSize+1))
+		if err != nil {
+			return err
+		}
+		if len(data) > maxCalendarSize {
+			return fmt.Errorf("calendar is too large: limit is %d bytes", maxCalendarSize)
+		}
+		*s = string(data)
+		return nil
+	})
+}
+
+// calendarAttachment отдаёт выгруженный календарь как файл calendar.ics.
+func calendarAttachment(_ context.Context, w http.ResponseWriter, resp proto.Message) error {
+	if body, ok := resp.(*httpbody.HttpBody); ok && strings.HasPrefix(body.GetContentType(), grpcserver.CalendarContentType) {
+		w.Header().Set("Content-Disposition", `attachment; filename="calendar.ics"`)
+	}
+	return nil
+}
@@ -1,10 +1,14 @@
 package internalhttp
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"strings"
 	"time"
+
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/auth"
+	"google.golang.org/grpc/codes"
 )
 
 // loggingMiddleware создает middleware для логирования HTTP-запросов.
@@ -66,3 +70,32 @@ func (rw *responseWriter) WriteHeader(code int) {
 	rw.statusCode = code
 	rw.ResponseWriter.WriteHeader(code)
 }
+
+// Unwrap возвращает исходный ResponseWriter, чтобы http.ResponseController мог сбрасывать буфер потоковых ответов.
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
+// authMiddleware аутентифицирует запросы по заголовкам Authorization (Bearer JWT или Basic,
+// где пароль — JWT или API-ключ) и X-API-Key и кладёт вызывающую сторону в контекст запроса.
+// Неаутентифицированные запросы отклоняются с кодом 401 в формате ошибок grpc-gateway.
+func authMiddleware(authenticator *auth.Authenticator) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id, err := authenticator.Authenticate(r.Header.Get("Authorization"), r.Header.Get("X-API-Key"))
+			if err != nil {
+				// Basic предлагается для CalDAV-клиентов, которые не умеют передавать Bearer-токен
+				w.Header().Add("WWW-Authenticate", `Bearer realm="calendar"`)
+				w.Header().Add("WWW-Authenticate", `Basic realm="calendar"`)
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusUnauthorized)
+				_ = json.NewEncoder(w).Encode(map[string]any{
+					"code":    codes.Unauthenticated,
+					"message": err.Error(),
+				})
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(auth.WithIdentity(r.Context(), id)))
+		})
+	}
+}
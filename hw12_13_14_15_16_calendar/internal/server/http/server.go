@@ -1,5 +1,5 @@
 // Package internalhttp предоставляет HTTP-сервер для приложения календаря.
-// Включает middleware для логирования запросов и graceful shutdown.
+// Включает REST API (через grpc-gateway), middleware для логирования запросов и graceful shutdown.
 package internalhttp
 
 import (
@@ -7,6 +7,12 @@ import (
 	"fmt"
 	"net/http"
 	"time"
+
+	pb "github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/api/gen"
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/auth"
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/server/caldav"
+	grpcserver "github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/server/grpc"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 )
 
 // Server представляет HTTP-сервер приложения календаря
@@ -24,14 +30,18 @@ type Logger interface {
 	Debug(msg string)
 }
 
-// Application определяет интерфейс к бизнес-логике приложения
-// Пока не используется, но может быть расширено для обработки запросов
+// Application определяет интерфейс к бизнес-логике приложения.
+// Объединяет интерфейсы grpc-сервера (REST API строится поверх тех же обработчиков) и CalDAV-сервера.
 type Application interface {
-	// пока не используется, но может быть расширено для бизнес-логики
+	grpcserver.Application
+	caldav.Application
 }
 
-// NewServer создает новый HTTP-сервер с настроенными маршрутами и middleware
-func NewServer(logger Logger, app Application, host string, port int) *Server {
+// NewServer создает новый HTTP-сервер с настроенными маршрутами и middleware.
+// Все методы EventService публикуются по REST через grpc-gateway с маршрутами из EventService.proto.
+// REST API и CalDAV доступны только аутентифицированным вызывающим сторонам; nil authenticator выключает
+// аутентификацию, и каждый запрос выполняется от имени auth.Anonymous.
+func NewServer(logger Logger, app Application, host string, port int, authenticator *auth.Authenticator) (*Server, error) {
 	// Создаем мультиплексор для маршрутизации запросов
 	mux := http.NewServeMux()
 
@@ -40,6 +50,29 @@ func NewServer(logger Logger, app Application, host string, port int) *Server {
 		_, _ = fmt.Fprintln(w, "hello world")
 	})
 
+	// Регистрируем REST API: grpc-gateway вызывает обработчики grpc-сервера напрямую, без сетевого вызова.
+	// Календарь iCalendar можно загрузить как есть с Content-Type text/calendar.
+	// Версия события отдаётся в ETag и принимается в If-Match.
+	gwMux := runtime.NewServeMux(
+		runtime.WithMarshalerOption(grpcserver.CalendarContentType, newCalendarMarshaler()),
+		runtime.WithForwardResponseOption(calendarAttachment),
+		runtime.WithForwardResponseOption(eventETag),
+		runtime.WithMetadata(ifMatchMetadata),
+		runtime.WithErrorHandler(errorHandler),
+	)
+	grpcSrv := grpcserver.NewServer(app)
+	if err := pb.RegisterEventServiceHandlerServer(context.Background(), gwMux, grpcSrv); err != nil {
+		return nil, fmt.Errorf("failed to register gateway handlers: %w", err)
+	}
+	protect := authMiddleware(authenticator)
+	mux.Handle("/v1/", protect(gwMux))
+	// Поток изменений (WatchEvents) grpc-gateway без сетевого вызова не обслуживает
+	mux.Handle("/v1/events/watch", protect(watchHandler(grpcSrv, gwMux)))
+
+	// Регистрируем CalDAV (RFC 4791) и адрес автообнаружения для клиентов (RFC 6764)
+	mux.Handle(caldav.Prefix, protect(caldav.NewHandler(app)))
+	mux.Handle("/.well-known/caldav", http.RedirectHandler(caldav.Prefix, http.StatusMovedPermanently))
+
 	// Оборачиваем мультиплексор в middleware для логирования
 	h := loggingMiddleware(logger)(mux)
 
@@ -54,7 +87,7 @@ func NewServer(logger Logger, app Application, host string, port int) *Server {
 		logger:  logger,
 		app:     app,
 		httpSrv: httpSrv,
-	}
+	}, nil
 }
 
 // Start запускает HTTP-сервер и начинает прослушивание входящих запросов
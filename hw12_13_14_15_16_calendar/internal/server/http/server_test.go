@@ -0,0 +1,555 @@
+package internalhttp
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/app"
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/auth"
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/logger"
+	memorystorage "github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/storage/memory"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func startTestHTTPServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	logg := logger.New("ERROR")
+	s, err := NewServer(logg, app.New(logg, memorystorage.New()), "localhost", 0, nil)
+	require.NoError(t, err)
+	ts := httptest.NewServer(s.httpSrv.Handler)
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func doJSON(t *testing.T, method, url string, body any) (int, map[string]any) {
+	t.Helper()
+	return doJSONWithHeaders(t, method, url, body, nil)
+}
+
+func doJSONWithHeaders(t *testing.T, method, url string, body any, headers map[string]string) (int, map[string]any) {
+	t.Helper()
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		require.NoError(t, err)
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, url, reader)
+	require.NoError(t, err)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	var out map[string]any
+	_ = json.NewDecoder(resp.Body).Decode(&out)
+	return resp.StatusCode, out
+}
+
+// TestRESTEventLifecycle проверяет создание, обновление, выборку и удаление события через REST API.
+func TestRESTEventLifecycle(t *testing.T) {
+	ts := startTestHTTPServer(t)
+	eventID := uuid.NewString()
+
+	code, body := doJSON(t, http.MethodPost, ts.URL+"/v1/events", map[string]any{
+		"id":              eventID,
+		"title":           "REST event",
+		"startTime":       "2024-07-19T10:00:00Z",
+		"durationSeconds": 3600,
+		"userId":          "user1",
+	})
+	require.Equal(t, http.StatusOK, code, body)
+	require.Equal(t, eventID, body["event"].(map[string]any)["id"])
+
+	code, body = doJSON(t, http.MethodPut, ts.URL+"/v1/events/"+eventID, map[string]any{
+		"title":           "REST event updated",
+		"startTime":       "2024-07-19T11:00:00Z",
+		"durationSeconds": 1800,
+		"userId":          "user1",
+	})
+	require.Equal(t, http.StatusOK, code, body)
+
+	for _, period := range []string{"day", "week", "month"} {
+		code, body = doJSON(t, http.MethodGet,
+			ts.URL+"/v1/events/"+period+"?userId=user1&periodStart=2024-07-19T00:00:00Z", nil)
+		require.Equal(t, http.StatusOK, code, body)
+		events := body["events"].([]any)
+		require.Len(t, events, 1, period)
+		require.Equal(t, "REST event updated", events[0].(map[string]any)["title"])
+	}
+
+	code, body = doJSON(t, http.MethodDelete, ts.URL+"/v1/events/"+eventID+"?userId=user1", nil)
+	require.Equal(t, http.StatusOK, code, body)
+	require.Equal(t, true, body["success"])
+
+	code, body = doJSON(t, http.MethodGet, ts.URL+"/v1/events/day?userId=user1&periodStart=2024-07-19T00:00:00Z", nil)
+	require.Equal(t, http.StatusOK, code, body)
+	require.Empty(t, body["events"])
+}
+
+// TestRESTInvalidUUID проверяет, что ошибка валидации возвращается клиенту как 400.
+func TestRESTInvalidUUID(t *testing.T) {
+	ts := startTestHTTPServer(t)
+
+	code, _ := doJSON(t, http.MethodPost, ts.URL+"/v1/events", map[string]any{
+		"id":        "not-a-uuid",
+		"title":     "Invalid",
+		"startTime": "2024-07-19T10:00:00Z",
+		"userId":    "user1",
+	})
+	require.Equal(t, http.StatusBadRequest, code)
+}
+
+// TestRESTCalendarImportExport проверяет загрузку файла .ics и выгрузку календаря через REST API.
+func TestRESTCalendarImportExport(t *testing.T) {
+	ts := startTestHTTPServer(t)
+	calendar := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//Example//Client//EN",
+		"BEGIN:VEVENT",
+		"UID:call@example.com",
+		"DTSTART:20240719T100000Z",
+		"DTEND:20240719T110000Z",
+		"SUMMARY:Созвон",
+		"BEGIN:VALARM",
+		"ACTION:DISPLAY",
+		"TRIGGER:-PT10M",
+		"END:VALARM",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:invalid@example.com",
+		"SUMMARY:Без даты",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+
+	resp, err := http.Post(ts.URL+"/v1/calendar/import?userId=user1", "text/calendar", strings.NewReader(calendar))
+	require.NoError(t, err)
+	var report map[string]any
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&report))
+	_ = resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode, report)
+	require.EqualValues(t, 1, report["imported"])
+	skipped := report["skipped"].([]any)
+	require.Len(t, skipped, 1)
+	require.Equal(t, "invalid@example.com", skipped[0].(map[string]any)["uid"])
+
+	code, body := doJSON(t, http.MethodGet, ts.URL+"/v1/events/day?userId=user1&periodStart=2024-07-19", nil)
+	require.Equal(t, http.StatusOK, code, body)
+	events := body["events"].([]any)
+	require.Len(t, events, 1)
+	require.Equal(t, "Созвон", events[0].(map[string]any)["title"])
+	require.EqualValues(t, 10, events[0].(map[string]any)["notifyBeforeMinutes"])
+
+	resp, err = http.Get(ts.URL + "/v1/calendar/export?userId=user1" +
+		"&periodStart=2024-07-01T00:00:00Z&periodEnd=2024-08-01T00:00:00Z")
+	require.NoError(t, err)
+	exported, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode, string(exported))
+	require.True(t, strings.HasPrefix(resp.Header.Get("Content-Type"), "text/calendar"))
+	require.Contains(t, resp.Header.Get("Content-Disposition"), "calendar.ics")
+	require.Contains(t, string(exported), "SUMMARY:Созвон")
+	require.Contains(t, string(exported), "TRIGGER:-PT10M")
+
+	// Повторный импорт обновляет ранее загруженное событие, а не создаёт копию
+	code, body = doJSON(t, http.MethodPost, ts.URL+"/v1/calendar/import?userId=user1", calendar)
+	require.Equal(t, http.StatusOK, code, body)
+	require.EqualValues(t, 1, body["imported"])
+	code, body = doJSON(t, http.MethodGet, ts.URL+"/v1/events/day?userId=user1&periodStart=2024-07-19", nil)
+	require.Equal(t, http.StatusOK, code, body)
+	require.Len(t, body["events"], 1)
+
+	resp, err = http.Post(ts.URL+"/v1/calendar/import?userId=user1", "text/calendar", strings.NewReader("not a calendar"))
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	// Слишком большое тело отклоняется, не дочитываясь до конца
+	resp, err = http.Post(ts.URL+"/v1/calendar/import?userId=user1", "text/calendar",
+		strings.NewReader(strings.Repeat("X", maxCalendarSize+1)))
+	require.NoError(t, err)
+	tooLarge, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	require.Contains(t, string(tooLarge), "calendar is too large")
+}
+
+// TestRESTAuthentication проверяет аутентификацию REST API и CalDAV и запрет действий над чужими событиями.
+func TestRESTAuthentication(t *testing.T) {
+	key := []byte("test-secret")
+	authenticator, err := auth.New(auth.Config{JWTKey: string(key)})
+	require.NoError(t, err)
+	logg := logger.New("ERROR")
+	s, err := NewServer(logg, app.New(logg, memorystorage.New()), "localhost", 0, authenticator)
+	require.NoError(t, err)
+	ts := httptest.NewServer(s.httpSrv.Handler)
+	t.Cleanup(ts.Close)
+
+	do := func(method, url, userID string, body any) *http.Response {
+		var reader io.Reader
+		if body != nil {
+			data, err := json.Marshal(body)
+			require.NoError(t, err)
+			reader = bytes.NewReader(data)
+		}
+		req, err := http.NewRequest(method, url, reader)
+		require.NoError(t, err)
+		if userID != "" {
+			token, err := auth.SignToken(key, map[string]any{"sub": userID, "exp": time.Now().Add(time.Hour).Unix()})
+			require.NoError(t, err)
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = resp.Body.Close() })
+		return resp
+	}
+
+	// Служебные адреса доступны без аутентификации
+	require.Equal(t, http.StatusOK, do(http.MethodGet, ts.URL+"/hello", "", nil).StatusCode)
+
+	resp := do(http.MethodGet, ts.URL+"/v1/events/day?userId=user1&periodStart=2024-07-19", "", nil)
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	require.Contains(t, resp.Header.Values("WWW-Authenticate"), `Bearer realm="calendar"`)
+	resp = do("PROPFIND", ts.URL+"/caldav/user1/calendar/", "", nil)
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	eventID := uuid.NewString()
+	event := map[string]any{
+		"id":              eventID,
+		"title":           "Private",
+		"startTime":       "2024-07-19T10:00:00Z",
+		"durationSeconds": 3600,
+		"userId":          "user1",
+	}
+	require.Equal(t, http.StatusOK, do(http.MethodPost, ts.URL+"/v1/events", "user1", event).StatusCode)
+
+	require.Equal(t, http.StatusForbidden, do(http.MethodPost, ts.URL+"/v1/events", "user2", event).StatusCode)
+	require.Equal(t, http.StatusForbidden, do(http.MethodPut, ts.URL+"/v1/events/"+eventID, "user2", event).StatusCode)
+	require.Equal(t, http.StatusForbidden, do(http.MethodDelete, ts.URL+"/v1/events/"+eventID, "user2", nil).StatusCode)
+	require.Equal(t, http.StatusForbidden,
+		do(http.MethodGet, ts.URL+"/v1/events/day?userId=user1&periodStart=2024-07-19", "user2", nil).StatusCode)
+	require.Equal(t, http.StatusForbidden, do("PROPFIND", ts.URL+"/caldav/user1/calendar/", "user2", nil).StatusCode)
+
+	resp = do(http.MethodGet, ts.URL+"/v1/events/day?userId=user1&periodStart=2024-07-19", "user1", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var page map[string]any
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&page))
+	require.Len(t, page["events"], 1)
+
+	require.Equal(t, http.StatusOK, do(http.MethodDelete, ts.URL+"/v1/events/"+eventID, "user1", nil).StatusCode)
+}
+
+// TestRESTErrorStatuses проверяет статусы HTTP для ошибок бизнес-логики.
+func TestRESTErrorStatuses(t *testing.T) {
+	ts := startTestHTTPServer(t)
+	event := func(id, start string) map[string]any {
+		return map[string]any{
+			"id":              id,
+			"title":           "Status",
+			"startTime":       start,
+			"durationSeconds": 3600,
+			"userId":          "user1",
+		}
+	}
+
+	code, body := doJSON(t, http.MethodPost, ts.URL+"/v1/events", event(uuid.NewString(), "2024-07-19T10:00:00Z"))
+	require.Equal(t, http.StatusOK, code, body)
+
+	code, body = doJSON(t, http.MethodPost, ts.URL+"/v1/events", event(uuid.NewString(), "2024-07-19T10:30:00Z"))
+	require.Equal(t, http.StatusConflict, code, body)
+	require.Len(t, body["details"], 2)
+
+	code, body = doJSON(t, http.MethodDelete, ts.URL+"/v1/events/"+uuid.NewString(), nil)
+	require.Equal(t, http.StatusNotFound, code, body)
+
+	invalid := event(uuid.NewString(), "2024-07-20T10:00:00Z")
+	invalid["durationSeconds"] = -1
+	code, body = doJSON(t, http.MethodPost, ts.URL+"/v1/events", invalid)
+	require.Equal(t, http.StatusBadRequest, code, body)
+}
+
+// TestRESTGetAndFilterEvents проверяет получение события по ID и выборку с фильтрами через REST API.
+func TestRESTGetAndFilterEvents(t *testing.T) {
+	ts := startTestHTTPServer(t)
+	ids := make(map[string]string)
+	for title, start := range map[string]string{
+		"Budget review": "2024-07-19T10:00:00Z",
+		"Lunch":         "2024-07-19T12:00:00Z",
+		"Budget plan":   "2024-07-20T10:00:00Z",
+	} {
+		ids[title] = uuid.NewString()
+		code, body := doJSON(t, http.MethodPost, ts.URL+"/v1/events", map[string]any{
+			"id":              ids[title],
+			"title":           title,
+			"startTime":       start,
+			"durationSeconds": 1800,
+			"userId":          "user1",
+		})
+		require.Equal(t, http.StatusOK, code, body)
+	}
+
+	code, body := doJSON(t, http.MethodGet, ts.URL+"/v1/events/"+ids["Lunch"], nil)
+	require.Equal(t, http.StatusOK, code, body)
+	require.Equal(t, "Lunch", body["event"].(map[string]any)["title"])
+	code, body = doJSON(t, http.MethodGet, ts.URL+"/v1/events/"+uuid.NewString(), nil)
+	require.Equal(t, http.StatusNotFound, code, body)
+
+	// Выборки за период по-прежнему обрабатываются своими методами
+	code, body = doJSON(t, http.MethodGet, ts.URL+"/v1/events/day?userId=user1&periodStart=2024-07-19T00:00:00Z", nil)
+	require.Equal(t, http.StatusOK, code, body)
+	require.Len(t, body["events"], 2)
+
+	code, body = doJSON(t, http.MethodGet,
+		ts.URL+"/v1/events?userId=user1&query=budget&orderBy=START_TIME_DESC", nil)
+	require.Equal(t, http.StatusOK, code, body)
+	events := body["events"].([]any)
+	require.Len(t, events, 2)
+	require.Equal(t, "Budget plan", events[0].(map[string]any)["title"])
+	require.Equal(t, "Budget review", events[1].(map[string]any)["title"])
+
+	code, body = doJSON(t, http.MethodGet,
+		ts.URL+"/v1/events?userId=user1&periodStart=2024-07-19T11:00:00Z&periodEnd=2024-07-20T00:00:00Z", nil)
+	require.Equal(t, http.StatusOK, code, body)
+	require.Len(t, body["events"], 1)
+
+	code, body = doJSON(t, http.MethodGet, ts.URL+"/v1/events?userId=user1&periodStart=2024-07-19T11:00:00Z", nil)
+	require.Equal(t, http.StatusBadRequest, code, body)
+}
+
+// TestRESTWatchEvents проверяет поток изменений по REST: заголовок ревизии до первого изменения и строки JSON на изменения.
+func TestRESTWatchEvents(t *testing.T) {
+	ts := startTestHTTPServer(t)
+	resp, err := http.Get(ts.URL + "/v1/events/watch?userId=user1")
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.NotEmpty(t, resp.Header.Get("Grpc-Metadata-X-Revision"))
+
+	id := uuid.NewString()
+	code, body := doJSON(t, http.MethodPost, ts.URL+"/v1/events", map[string]any{
+		"id":        id,
+		"title":     "Standup",
+		"startTime": "2024-07-19T10:00:00Z",
+		"userId":    "user1",
+	})
+	require.Equal(t, http.StatusOK, code, body)
+
+	var line struct {
+		Result map[string]any `json:"result"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&line))
+	require.Equal(t, "CREATED", line.Result["type"])
+	require.Equal(t, id, line.Result["eventId"])
+	require.Equal(t, "Standup", line.Result["event"].(map[string]any)["title"])
+
+	code, body = doJSON(t, http.MethodGet, ts.URL+"/v1/events/watch?userId=user1&revision=bad", nil)
+	require.Equal(t, http.StatusBadRequest, code, body)
+	code, _ = doJSON(t, http.MethodPost, ts.URL+"/v1/events/watch?userId=user1", nil)
+	require.Equal(t, http.StatusMethodNotAllowed, code)
+}
+
+// TestRESTIfMatch проверяет версию события в ETag и условные PUT и DELETE с If-Match.
+func TestRESTIfMatch(t *testing.T) {
+	ts := startTestHTTPServer(t)
+	id := uuid.NewString()
+	event := map[string]any{
+		"id":        id,
+		"title":     "Review",
+		"startTime": "2024-07-19T10:00:00Z",
+		"userId":    "user1",
+	}
+	code, body := doJSON(t, http.MethodPost, ts.URL+"/v1/events", event)
+	require.Equal(t, http.StatusOK, code, body)
+
+	do := func(method, ifMatch string, body any) *http.Response {
+		t.Helper()
+		var reader io.Reader
+		if body != nil {
+			data, err := json.Marshal(body)
+			require.NoError(t, err)
+			reader = bytes.NewReader(data)
+		}
+		req, err := http.NewRequest(method, ts.URL+"/v1/events/"+id, reader)
+		require.NoError(t, err)
+		if ifMatch != "" {
+			req.Header.Set("If-Match", ifMatch)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		return resp
+	}
+
+	resp := do(http.MethodGet, "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, `"1"`, resp.Header.Get("ETag"))
+
+	event["title"] = "Moved"
+	resp = do(http.MethodPut, `"1"`, event)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, `"2"`, resp.Header.Get("ETag"))
+	// Устаревшая версия в If-Match — 412, в теле запроса — 409
+	resp = do(http.MethodPut, `"1"`, event)
+	require.Equal(t, http.StatusPreconditionFailed, resp.StatusCode)
+	event["version"] = 1
+	resp = do(http.MethodPut, "", event)
+	require.Equal(t, http.StatusConflict, resp.StatusCode)
+
+	resp = do(http.MethodDelete, `"1"`, nil)
+	require.Equal(t, http.StatusPreconditionFailed, resp.StatusCode)
+	resp = do(http.MethodDelete, `"2"`, nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+// TestRESTTrash проверяет корзину через REST API: удалённое событие видно в корзине, восстанавливается и удаляется навсегда.
+func TestRESTTrash(t *testing.T) {
+	ts := startTestHTTPServer(t)
+	eventID := uuid.NewString()
+
+	code, body := doJSON(t, http.MethodPost, ts.URL+"/v1/events", map[string]any{
+		"id":              eventID,
+		"title":           "Trashed event",
+		"startTime":       "2024-07-19T10:00:00Z",
+		"durationSeconds": 3600,
+		"userId":          "user1",
+	})
+	require.Equal(t, http.StatusOK, code, body)
+	code, body = doJSON(t, http.MethodDelete, ts.URL+"/v1/events/"+eventID, nil)
+	require.Equal(t, http.StatusOK, code, body)
+
+	code, body = doJSON(t, http.MethodGet, ts.URL+"/v1/trash?userId=user1", nil)
+	require.Equal(t, http.StatusOK, code, body)
+	events := body["events"].([]any)
+	require.Len(t, events, 1)
+	require.Equal(t, eventID, events[0].(map[string]any)["id"])
+	require.NotEmpty(t, events[0].(map[string]any)["deletedAt"])
+
+	code, body = doJSON(t, http.MethodPost, ts.URL+"/v1/trash/"+eventID+"/restore", nil)
+	require.Equal(t, http.StatusOK, code, body)
+	require.Equal(t, "Trashed event", body["event"].(map[string]any)["title"])
+	code, body = doJSON(t, http.MethodGet, ts.URL+"/v1/events/"+eventID, nil)
+	require.Equal(t, http.StatusOK, code, body)
+
+	code, body = doJSON(t, http.MethodDelete, ts.URL+"/v1/events/"+eventID, nil)
+	require.Equal(t, http.StatusOK, code, body)
+	code, body = doJSON(t, http.MethodDelete, ts.URL+"/v1/trash/"+eventID, nil)
+	require.Equal(t, http.StatusOK, code, body)
+	require.Equal(t, true, body["success"])
+	code, body = doJSON(t, http.MethodDelete, ts.URL+"/v1/trash/"+eventID, nil)
+	require.Equal(t, http.StatusNotFound, code, body)
+}
+
+// TestRESTEventHistory проверяет историю изменений события и действия пользователя по REST.
+func TestRESTEventHistory(t *testing.T) {
+	ts := startTestHTTPServer(t)
+	eventID := uuid.NewString()
+
+	code, body := doJSON(t, http.MethodPost, ts.URL+"/v1/events", map[string]any{
+		"id":              eventID,
+		"title":           "Audited event",
+		"startTime":       "2024-07-19T10:00:00Z",
+		"durationSeconds": 3600,
+		"userId":          "user1",
+	})
+	require.Equal(t, http.StatusOK, code, body)
+	code, body = doJSON(t, http.MethodDelete, ts.URL+"/v1/events/"+eventID, nil)
+	require.Equal(t, http.StatusOK, code, body)
+
+	code, body = doJSON(t, http.MethodGet, ts.URL+"/v1/events/"+eventID+"/history", nil)
+	require.Equal(t, http.StatusOK, code, body)
+	entries := body["entries"].([]any)
+	require.Len(t, entries, 2)
+	require.Equal(t, "EVENT_DELETED", entries[0].(map[string]any)["action"])
+	created := entries[1].(map[string]any)
+	require.Equal(t, "EVENT_CREATED", created["action"])
+	require.Equal(t, "user1", created["actorId"])
+	require.NotEmpty(t, created["changes"])
+
+	code, body = doJSON(t, http.MethodGet, ts.URL+"/v1/activity?userId=user1&limit=1", nil)
+	require.Equal(t, http.StatusOK, code, body)
+	require.Len(t, body["entries"].([]any), 1)
+	code, body = doJSON(t, http.MethodGet, ts.URL+"/v1/events/"+uuid.NewString()+"/history", nil)
+	require.Equal(t, http.StatusNotFound, code, body)
+}
+
+// TestRESTSearchEvents проверяет полнотекстовый поиск по REST: путь /v1/events/search не совпадает с /v1/events/{id}.
+func TestRESTSearchEvents(t *testing.T) {
+	ts := startTestHTTPServer(t)
+
+	code, body := doJSON(t, http.MethodPost, ts.URL+"/v1/events", map[string]any{
+		"id":              uuid.NewString(),
+		"title":           "Q3 budget review",
+		"startTime":       "2024-07-19T10:00:00Z",
+		"durationSeconds": 3600,
+		"userId":          "user1",
+	})
+	require.Equal(t, http.StatusOK, code, body)
+
+	code, body = doJSON(t, http.MethodGet, ts.URL+"/v1/events/search?userId=user1&query=budget&language=ENGLISH", nil)
+	require.Equal(t, http.StatusOK, code, body)
+	results := body["results"].([]any)
+	require.Len(t, results, 1)
+	require.Equal(t, "Q3 <b>budget</b> review", results[0].(map[string]any)["title"])
+
+	code, body = doJSON(t, http.MethodGet, ts.URL+"/v1/events/search?userId=user1", nil)
+	require.Equal(t, http.StatusBadRequest, code, body)
+}
+
+// TestRESTWebhooks проверяет регистрацию, получение и удаление адресов для напоминаний по REST.
+func TestRESTWebhooks(t *testing.T) {
+	ts := startTestHTTPServer(t)
+
+	// Без аутентификации адреса не регистрируются: вызывающая сторона не может быть самим пользователем
+	code, body := doJSON(t, http.MethodPost, ts.URL+"/v1/webhooks", map[string]any{
+		"userId": "user1",
+		"url":    "https://203.0.113.10/hook",
+	})
+	require.Equal(t, http.StatusForbidden, code, body)
+
+	key := []byte("test-secret")
+	authenticator, err := auth.New(auth.Config{JWTKey: string(key)})
+	require.NoError(t, err)
+	logg := logger.New("ERROR")
+	s, err := NewServer(logg, app.New(logg, memorystorage.New()), "localhost", 0, authenticator)
+	require.NoError(t, err)
+	ts = httptest.NewServer(s.httpSrv.Handler)
+	t.Cleanup(ts.Close)
+	token, err := auth.SignToken(key, map[string]any{"sub": "user1", "exp": time.Now().Add(time.Hour).Unix()})
+	require.NoError(t, err)
+	doJSON := func(t *testing.T, method, url string, body any) (int, map[string]any) {
+		t.Helper()
+		return doJSONWithHeaders(t, method, url, body, map[string]string{"Authorization": "Bearer " + token})
+	}
+
+	code, body = doJSON(t, http.MethodPost, ts.URL+"/v1/webhooks", map[string]any{
+		"userId": "user1",
+		"url":    "https://203.0.113.10/hook",
+	})
+	require.Equal(t, http.StatusOK, code, body)
+	webhook := body["webhook"].(map[string]any)
+	require.NotEmpty(t, webhook["secret"])
+
+	code, body = doJSON(t, http.MethodGet, ts.URL+"/v1/webhooks?userId=user1", nil)
+	require.Equal(t, http.StatusOK, code, body)
+	require.Len(t, body["webhooks"].([]any), 1)
+
+	code, body = doJSON(t, http.MethodDelete, ts.URL+"/v1/webhooks/"+webhook["id"].(string), nil)
+	require.Equal(t, http.StatusOK, code, body)
+	code, body = doJSON(t, http.MethodPost, ts.URL+"/v1/webhooks", map[string]any{"userId": "user1", "url": "not a url"})
+	require.Equal(t, http.StatusBadRequest, code, body)
+	code, body = doJSON(t, http.MethodPost, ts.URL+"/v1/webhooks", map[string]any{"userId": "user1", "url": "http://169.254.169.254/"})
+	require.Equal(t, http.StatusBadRequest, code, body)
+}
@@ -0,0 +1,102 @@
+package internalhttp
+
+import (
+	"context"
+	"net/http"
+
+	pb "github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/api/gen"
+	grpcserver "github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/server/grpc"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/utilities"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// watchHandler обслуживает WatchEvents по REST (GET /v1/events/watch?userId=...&revision=...).
+// grpc-gateway не умеет вызывать потоковые методы без сетевого вызова, поэтому поток изменений
+// отдаётся этим обработчиком в формате потоковых ответов grpc-gateway: по строке JSON {"result": ...}
+// на изменение. Ревизия начала потока передаётся заголовком Grpc-Metadata-X-Revision, как метаданные
+// в grpc-gateway. Ошибка до начала потока возвращается обычным ответом с кодом HTTP, ошибка
+// посреди потока — последней строкой {"error": ...}.
+func watchHandler(server *grpcserver.Server, gwMux *runtime.ServeMux) http.Handler {
+	marshaler := newCalendarMarshaler()
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+		var req pb.WatchEventsRequest
+		if err := runtime.PopulateQueryParameters(&req, r.URL.Query(), utilities.NewDoubleArray(nil)); err != nil {
+			runtime.HTTPError(r.Context(), gwMux, marshaler, w, r, status.Error(codes.InvalidArgument, err.Error()))
+			return
+		}
+		stream := &watchStream{ctx: r.Context(), w: w, marshaler: marshaler}
+		err := server.WatchEvents(&req, stream)
+		if err == nil || r.Context().Err() != nil {
+			return
+		}
+		if !stream.started {
+			runtime.HTTPError(r.Context(), gwMux, marshaler, w, r, err)
+			return
+		}
+		st, _ := status.FromError(err)
+		_ = stream.write(map[string]any{"error": st.Proto()})
+	})
+}
+
+// watchStream — поток WatchEvents поверх ответа HTTP. Обработчик grpc-сервера использует только
+// Context, SendHeader и Send, остальные методы grpc.ServerStream не поддерживаются.
+type watchStream struct {
+	grpc.ServerStream
+	ctx       context.Context
+	w         http.ResponseWriter
+	marshaler runtime.Marshaler
+	started   bool // заголовки ответа отправлены
+}
+
+// Context возвращает контекст запроса HTTP.
+func (s *watchStream) Context() context.Context {
+	return s.ctx
+}
+
+// SendHeader отправляет заголовки ответа с метаданными, не дожидаясь первого изменения.
+func (s *watchStream) SendHeader(md metadata.MD) error {
+	for key, values := range md {
+		for _, value := range values {
+			s.w.Header().Add(runtime.MetadataHeaderPrefix+key, value)
+		}
+	}
+	s.start()
+	return http.NewResponseController(s.w).Flush()
+}
+
+// start отправляет код и заголовки ответа, если они ещё не отправлены.
+func (s *watchStream) start() {
+	if s.started {
+		return
+	}
+	s.w.Header().Set("Content-Type", s.marshaler.ContentType(nil))
+	s.w.WriteHeader(http.StatusOK)
+	s.started = true
+}
+
+// Send записывает изменение строкой JSON и сразу отправляет её клиенту.
+func (s *watchStream) Send(change *pb.EventChange) error {
+	return s.write(map[string]any{"result": change})
+}
+
+// write кодирует сообщение потока в строку JSON и отправляет её клиенту.
+func (s *watchStream) write(msg map[string]any) error {
+	data, err := s.marshaler.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	s.start()
+	if _, err := s.w.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	return http.NewResponseController(s.w).Flush()
+}
@@ -0,0 +1,47 @@
+package storage
+
+// AttendeeStatus — ответ участника на приглашение (PARTSTAT в RFC 5545).
+type AttendeeStatus string
+
+// Ответы участника на приглашение.
+const (
+	AttendeeNeedsAction AttendeeStatus = "needs-action" // участник ещё не ответил
+	AttendeeAccepted    AttendeeStatus = "accepted"     // участник придёт
+	AttendeeDeclined    AttendeeStatus = "declined"     // участник отказался
+	AttendeeTentative   AttendeeStatus = "tentative"    // участник, возможно, придёт
+)
+
+// Valid сообщает, что статус — один из известных ответов на приглашение.
+func (s AttendeeStatus) Valid() bool {
+	switch s {
+	case AttendeeNeedsAction, AttendeeAccepted, AttendeeDeclined, AttendeeTentative:
+		return true
+	}
+	return false
+}
+
+// Attendee — участник события, приглашённый владельцем.
+type Attendee struct {
+	UserID string         // ID приглашённого пользователя
+	Status AttendeeStatus // ответ на приглашение
+}
+
+// Attendee возвращает участника события с указанным ID пользователя.
+func (e Event) Attendee(userID string) (Attendee, bool) {
+	for _, a := range e.Attendees {
+		if a.UserID == userID {
+			return a, true
+		}
+	}
+	return Attendee{}, false
+}
+
+// VisibleTo сообщает, что событие показывается в календаре пользователя:
+// пользователь — владелец или участник, не отказавшийся от приглашения.
+func (e Event) VisibleTo(userID string) bool {
+	if e.UserID == userID {
+		return true
+	}
+	a, ok := e.Attendee(userID)
+	return ok && a.Status != AttendeeDeclined
+}
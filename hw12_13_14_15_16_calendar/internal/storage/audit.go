@@ -0,0 +1,51 @@
+package storage
+
+import "context"
+
+// AuditAction — действие над событием в журнале аудита.
+type AuditAction string
+
+// Действия над событием.
+const (
+	AuditCreated  AuditAction = "created"  // событие создано
+	AuditUpdated  AuditAction = "updated"  // событие изменено (в том числе ответ участника)
+	AuditDeleted  AuditAction = "deleted"  // событие перемещено в корзину или экземпляр серии удалён
+	AuditRestored AuditAction = "restored" // событие восстановлено из корзины
+	AuditPurged   AuditAction = "purged"   // событие удалено из корзины навсегда
+)
+
+// FieldChange — изменение одного поля события: значения до и после в текстовом виде.
+// Пустое значение — поле не задано (или события ещё нет / уже нет).
+type FieldChange struct {
+	Field  string // имя поля (как в EventService.proto, например start_time)
+	Before string // значение до изменения
+	After  string // значение после изменения
+}
+
+// AuditEntry — запись журнала аудита: кто, когда и как изменил событие.
+// Журнал не зависит от событий: записи остаются и после удаления события из корзины.
+type AuditEntry struct {
+	Seq       int64         // номер записи, возрастает в порядке записи
+	EventID   string        // ID события
+	OwnerID   string        // владелец события на момент действия
+	ActorID   string        // пользователь, выполнивший действие или от имени которого действовал сервис
+	Service   string        // сервис, выполнивший действие по API-ключу; пусто для пользователей
+	Action    AuditAction   // действие
+	Changes   []FieldChange // изменённые поля
+	CreatedAt int64         // время действия (Unix timestamp)
+}
+
+type auditKey struct{}
+
+// WithAudit возвращает контекст с записью журнала аудита о действии над событием. Хранилище записывает её
+// в журнал вместе с изменением, выполненным с этим контекстом, в одной транзакции: изменение не сохраняется
+// без записи в журнале, а запись — без изменения.
+func WithAudit(ctx context.Context, entry AuditEntry) context.Context {
+	return context.WithValue(ctx, auditKey{}, entry)
+}
+
+// AuditFromContext возвращает запись журнала аудита, переданную с изменением (см. WithAudit).
+func AuditFromContext(ctx context.Context) (AuditEntry, bool) {
+	entry, ok := ctx.Value(auditKey{}).(AuditEntry)
+	return entry, ok
+}
@@ -0,0 +1,40 @@
+package storage
+
+import "slices"
+
+// ChangeType — вид изменения события в ленте изменений.
+type ChangeType string
+
+// Виды изменений события.
+const (
+	ChangeCreated ChangeType = "created" // событие создано
+	ChangeUpdated ChangeType = "updated" // событие изменено (в том числе ответ участника)
+	ChangeDeleted ChangeType = "deleted" // событие удалено
+)
+
+// Change — запись ленты изменений событий. Хранилище записывает её вместе с изменением события
+// (в SQL хранилище — в той же транзакции). Ревизии возрастают в порядке записи, поэтому подписчик
+// продолжает чтение ленты с последней полученной ревизии без пропусков и повторов.
+// Удаление закончившихся событий по сроку хранения (DeleteExpiredEvents) в ленту не попадает.
+type Change struct {
+	Revision  int64      // номер изменения в ленте
+	Type      ChangeType // вид изменения
+	EventID   string     // ID изменённого события
+	UserIDs   []string   // пользователи, в календаре которых событие было до изменения или есть после: владелец и участники
+	ChangedAt int64      // время изменения (Unix timestamp)
+}
+
+// Touches сообщает, что изменение касается календаря пользователя.
+func (c Change) Touches(userID string) bool {
+	return slices.Contains(c.UserIDs, userID)
+}
+
+// ChangeUsers возвращает пользователей, в календаре которых есть событие: владельца и всех приглашённых участников.
+func (e Event) ChangeUsers() []string {
+	users := make([]string, 0, len(e.Attendees)+1)
+	users = append(users, e.UserID)
+	for _, a := range e.Attendees {
+		users = append(users, a.UserID)
+	}
+	return users
+}
@@ -0,0 +1,79 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Общие ошибки предметной области. Их возвращают оба хранилища и бизнес-логика, а серверы
+// сопоставляют с кодами gRPC и HTTP через errors.Is. Конкретные ошибки (например, app.ErrInvalidEvent)
+// сопоставимы с одной из них.
+var (
+	// ErrNotFound — событие не найдено.
+	ErrNotFound = errors.New("event not found")
+	// ErrDateBusy — время уже занято другим событием пользователя.
+	ErrDateBusy = errors.New("date is busy by another event")
+	// ErrValidation — данные запроса некорректны.
+	ErrValidation = errors.New("validation failed")
+	// ErrPermissionDenied — вызывающая сторона действует над чужими событиями.
+	ErrPermissionDenied = errors.New("permission denied")
+	// ErrVersionConflict — событие изменено после чтения: ожидаемая версия не совпадает с текущей.
+	ErrVersionConflict = errors.New("event version conflict")
+	// ErrAlreadyExists — событие с таким ID уже есть (в том числе в корзине или у другого пользователя).
+	ErrAlreadyExists = errors.New("event already exists")
+)
+
+// KindError — ошибка с собственным текстом, относящаяся к одной из общих ошибок (Kind).
+// Позволяет заводить конкретные ошибки, сопоставимые через errors.Is и с собой, и с общей ошибкой.
+type KindError struct {
+	Msg  string // текст ошибки
+	Kind error  // общая ошибка: ErrNotFound, ErrDateBusy, ErrValidation, ErrPermissionDenied, ErrVersionConflict или ErrAlreadyExists
+}
+
+// NewKindError создаёт ошибку с текстом msg, сопоставимую с kind.
+func NewKindError(kind error, msg string) error {
+	return &KindError{Msg: msg, Kind: kind}
+}
+
+// Error возвращает текст ошибки.
+func (e *KindError) Error() string {
+	return e.Msg
+}
+
+// Unwrap позволяет сравнивать ошибку с общей ошибкой.
+func (e *KindError) Unwrap() error {
+	return e.Kind
+}
+
+// NotFoundError возвращает ErrNotFound с ID события.
+func NotFoundError(id string) error {
+	return fmt.Errorf("%w: %s", ErrNotFound, id)
+}
+
+// AlreadyExistsError возвращает ErrAlreadyExists с ID события.
+func AlreadyExistsError(id string) error {
+	return fmt.Errorf("%w: %s", ErrAlreadyExists, id)
+}
+
+// VersionConflictError возвращает ErrVersionConflict с ID и текущей версией события.
+func VersionConflictError(id string, version int64) error {
+	return fmt.Errorf("%w: %s is at version %d", ErrVersionConflict, id, version)
+}
+
+// ConflictError — ошибка пересечения события по времени с другими событиями пользователя.
+// Пересечение определяется для полуинтервалов [StartTime, EndTime) разовых событий, изменённых
+// экземпляров и экземпляров серий (для экземпляра в EventIDs — ID серии). Сопоставима с ErrDateBusy через errors.Is.
+type ConflictError struct {
+	EventIDs []string // ID событий, с которыми пересекается сохраняемое событие
+}
+
+// Error возвращает текст ошибки со списком пересекающихся событий.
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("%s: conflicts with %s", ErrDateBusy, strings.Join(e.EventIDs, ", "))
+}
+
+// Unwrap позволяет сравнивать ошибку с ErrDateBusy.
+func (e *ConflictError) Unwrap() error {
+	return ErrDateBusy
+}
@@ -4,11 +4,37 @@ package storage
 // Event представляет событие в календаре.
 // Содержит всю необходимую информацию о событии пользователя.
 type Event struct {
-	ID           string // уникальный идентификатор события (UUID)
-	Title        string // заголовок события
-	Description  string // описание события
-	UserID       string // идентификатор пользователя, владельца события
-	StartTime    int64  // время начала события (Unix timestamp)
-	EndTime      int64  // время окончания события (Unix timestamp)
-	NotifyBefore *int64 // количество секунд до события для уведомления (опционально)
+	ID          string          // уникальный идентификатор события (UUID)
+	Title       string          // заголовок события
+	Description string          // описание события
+	UserID      string          // идентификатор пользователя, владельца события
+	StartTime   int64           // время начала события (Unix timestamp)
+	EndTime     int64           // время окончания события (Unix timestamp)
+	Reminders   []EventReminder // напоминания о событии (опционально)
+	Attendees   []Attendee      // приглашённые участники (опционально)
+	Version     int64           // версия события: InitialVersion при создании, увеличивается при каждой записи
+	DeletedAt   int64           // время перемещения в корзину (Unix timestamp), 0 — событие не удалено
+
+	// Повторение (RFC 5545)
+	RRule            string  // правило повторения RRULE, пусто для разовых событий
+	TimeZone         string  // часовой пояс IANA, в котором разворачивается серия; пусто — UTC
+	ExDates          []int64 // исключённые экземпляры серии (время начала, Unix timestamp)
+	RecurrenceEnd    int64   // время окончания последнего экземпляра серии, 0 — серия не ограничена
+	RecurringEventID string  // для изменённого экземпляра: ID повторяющегося события
+	RecurrenceID     int64   // для экземпляра серии: исходное время начала экземпляра (Unix timestamp)
+}
+
+// InitialVersion — версия только что созданного события.
+const InitialVersion int64 = 1
+
+// IsRecurring сообщает, является ли событие повторяющимся.
+func (e Event) IsRecurring() bool {
+	return e.RRule != ""
+}
+
+// EventReminder — напоминание, настроенное для события.
+// Событие может иметь несколько напоминаний, например за день и за 10 минут до начала.
+type EventReminder struct {
+	Offset  int64  // за сколько секунд до начала события отправляется напоминание
+	Channel string // канал доставки (email, webhook, ...); пусто — канал по умолчанию
 }
@@ -0,0 +1,56 @@
+package memorystorage
+
+import (
+	"context"
+	"slices"
+
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/storage"
+)
+
+// AppendAudit добавляет запись в журнал аудита и присваивает ей номер.
+func (s *Storage) AppendAudit(ctx context.Context, entry storage.AuditEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.appendAudit(entry)
+	return nil
+}
+
+// recordAudit записывает запись журнала аудита, переданную с изменением (см. storage.WithAudit);
+// без записи ничего не делает. Вызывается под блокировкой на запись вместе с изменением.
+func (s *Storage) recordAudit(ctx context.Context) {
+	if entry, ok := storage.AuditFromContext(ctx); ok {
+		s.appendAudit(entry)
+	}
+}
+
+// appendAudit добавляет запись в журнал аудита и присваивает ей номер. Вызывается под блокировкой на запись.
+func (s *Storage) appendAudit(entry storage.AuditEntry) {
+	entry.Seq = int64(len(s.audit)) + 1
+	entry.Changes = slices.Clone(entry.Changes)
+	s.audit = append(s.audit, entry)
+}
+
+// ListEventAudit возвращает не более limit последних записей журнала аудита события, от новых к старым.
+func (s *Storage) ListEventAudit(ctx context.Context, eventID string, limit int) ([]storage.AuditEntry, error) {
+	return s.listAudit(func(e storage.AuditEntry) bool { return e.EventID == eventID }, limit), nil
+}
+
+// ListUserAudit возвращает не более limit последних действий пользователя из журнала аудита, от новых к старым.
+func (s *Storage) ListUserAudit(ctx context.Context, actorID string, limit int) ([]storage.AuditEntry, error) {
+	return s.listAudit(func(e storage.AuditEntry) bool { return e.ActorID == actorID }, limit), nil
+}
+
+// listAudit возвращает не более limit последних записей журнала, подходящих под match, от новых к старым.
+func (s *Storage) listAudit(match func(storage.AuditEntry) bool, limit int) []storage.AuditEntry {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var result []storage.AuditEntry
+	for i := len(s.audit) - 1; i >= 0 && len(result) < limit; i-- {
+		if match(s.audit[i]) {
+			result = append(result, s.audit[i])
+		}
+	}
+	return result
+}
@@ -0,0 +1,84 @@
+package memorystorage
+
+import (
+	"sort"
+
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/storage"
+)
+
+// indexEntry — элемент упорядоченного индекса: время начала и ID события.
+type indexEntry struct {
+	start int64
+	id    string
+}
+
+// less задаёт порядок индекса: по времени начала, затем по ID.
+func (e indexEntry) less(other indexEntry) bool {
+	if e.start != other.start {
+		return e.start < other.start
+	}
+	return e.id < other.id
+}
+
+// timeIndex — интервальный индекс событий одного пользователя.
+// Разовые события и изменённые экземпляры хранятся в срезе, упорядоченном по времени начала;
+// вместе с максимальной длительностью это позволяет найти пересечения с диапазоном бинарным поиском.
+// Повторяющиеся события хранятся отдельно, т.к. их серия не описывается одним интервалом.
+type timeIndex struct {
+	entries     []indexEntry        // разовые события, отсортированные по (start, id)
+	maxDuration int64               // максимальная длительность события в entries (при удалении не уменьшается)
+	series      map[string]struct{} // ID повторяющихся событий
+}
+
+func newTimeIndex() *timeIndex {
+	return &timeIndex{series: make(map[string]struct{})}
+}
+
+// insert добавляет событие в индекс.
+func (ix *timeIndex) insert(e storage.Event) {
+	if e.IsRecurring() {
+		ix.series[e.ID] = struct{}{}
+		return
+	}
+	entry := indexEntry{start: e.StartTime, id: e.ID}
+	i := sort.Search(len(ix.entries), func(i int) bool { return !ix.entries[i].less(entry) })
+	ix.entries = append(ix.entries, indexEntry{})
+	copy(ix.entries[i+1:], ix.entries[i:])
+	ix.entries[i] = entry
+	if d := e.EndTime - e.StartTime; d > ix.maxDuration {
+		ix.maxDuration = d
+	}
+}
+
+// remove удаляет событие из индекса.
+func (ix *timeIndex) remove(e storage.Event) {
+	if e.IsRecurring() {
+		delete(ix.series, e.ID)
+		return
+	}
+	entry := indexEntry{start: e.StartTime, id: e.ID}
+	i := sort.Search(len(ix.entries), func(i int) bool { return !ix.entries[i].less(entry) })
+	if i < len(ix.entries) && ix.entries[i] == entry {
+		ix.entries = append(ix.entries[:i], ix.entries[i+1:]...)
+	}
+}
+
+// candidates возвращает ID разовых событий, которые могут пересекаться с [start, end):
+// начинающихся раньше end, но не раньше start - maxDuration. Результат упорядочен по времени начала.
+func (ix *timeIndex) candidates(start, end int64) []string {
+	from := sort.Search(len(ix.entries), func(i int) bool { return ix.entries[i].start >= start-ix.maxDuration })
+	to := sort.Search(len(ix.entries), func(i int) bool { return ix.entries[i].start >= end })
+	if to <= from {
+		return nil
+	}
+	ids := make([]string, 0, to-from)
+	for _, entry := range ix.entries[from:to] {
+		ids = append(ids, entry.id)
+	}
+	return ids
+}
+
+// empty сообщает, что в индексе не осталось событий.
+func (ix *timeIndex) empty() bool {
+	return len(ix.entries) == 0 && len(ix.series) == 0
+}
@@ -0,0 +1,37 @@
+package memorystorage
+
+import (
+	"context"
+	"time"
+)
+
+// lease — аренда лидерства.
+type lease struct {
+	holder    string    // владелец аренды
+	expiresAt time.Time // время истечения
+}
+
+// AcquireLease захватывает или продлевает аренду name для holder на ttl и сообщает, принадлежит ли аренда holder.
+// Чужая аренда захватывается, только если она истекла.
+func (s *Storage) AcquireLease(ctx context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if l, ok := s.leases[name]; ok && l.holder != holder && now.Before(l.expiresAt) {
+		return false, nil
+	}
+	s.leases[name] = lease{holder: holder, expiresAt: now.Add(ttl)}
+	return true, nil
+}
+
+// ReleaseLease освобождает аренду name, если она принадлежит holder.
+func (s *Storage) ReleaseLease(ctx context.Context, name, holder string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if l, ok := s.leases[name]; ok && l.holder == holder {
+		delete(s.leases, name)
+	}
+	return nil
+}
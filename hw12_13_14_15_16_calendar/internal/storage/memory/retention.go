@@ -0,0 +1,153 @@
+package memorystorage
+
+import (
+	"context"
+	"slices"
+	"sort"
+
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/storage"
+)
+
+// expired сообщает, закончилось ли событие раньше beforeTime: разовое событие — по времени окончания,
+// серия — по окончании последнего экземпляра, если ни один из её изменённых экземпляров overrides
+// не заканчивается позже (бесконечная серия не заканчивается). Изменённый экземпляр отдельно не истекает:
+// он архивируется и удаляется вместе со своей серией.
+func expired(e storage.Event, overrides []storage.Event, beforeTime int64) bool {
+	switch {
+	case e.RecurringEventID != "":
+		return false
+	case !e.IsRecurring():
+		return e.EndTime < beforeTime
+	case e.RecurrenceEnd == 0 || e.RecurrenceEnd >= beforeTime:
+		return false
+	}
+	return !slices.ContainsFunc(overrides, func(o storage.Event) bool { return o.EndTime >= beforeTime })
+}
+
+// overridesOf возвращает изменённые экземпляры серии id из events (s.events или s.trash), упорядоченные по ID.
+func overridesOf(events map[string]storage.Event, id string) []storage.Event {
+	var result []storage.Event
+	for _, e := range events {
+		if e.RecurringEventID == id {
+			result = append(result, e)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result
+}
+
+// ListExpiredEvents возвращает не более limit событий и серий, закончившихся раньше beforeTime, с ID больше
+// afterID, упорядоченных по ID. Изменённые экземпляры серии следуют сразу за ней и в limit не учитываются.
+// В выборку попадают и события в корзине.
+func (s *Storage) ListExpiredEvents(ctx context.Context, beforeTime int64, afterID string, limit int) ([]storage.Event, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	type unit struct {
+		event     storage.Event
+		overrides []storage.Event
+	}
+	var units []unit
+	for _, events := range []map[string]storage.Event{s.events, s.trash} {
+		for id, e := range events {
+			if id <= afterID || e.RecurringEventID != "" {
+				continue
+			}
+			overrides := overridesOf(events, id)
+			if expired(e, overrides, beforeTime) {
+				units = append(units, unit{e, overrides})
+			}
+		}
+	}
+	sort.Slice(units, func(i, j int) bool { return units[i].event.ID < units[j].event.ID })
+	if len(units) > limit {
+		units = units[:limit]
+	}
+	var result []storage.Event
+	for _, u := range units {
+		result = append(result, u.event)
+		result = append(result, u.overrides...)
+	}
+	return result, nil
+}
+
+// DeleteExpiredEvents удаляет навсегда события и серии из ids, которые по-прежнему закончились раньше beforeTime,
+// вместе с изменёнными экземплярами серий и возвращает число удалённых событий из ids.
+// Удаление в ленту изменений не попадает.
+func (s *Storage) DeleteExpiredEvents(ctx context.Context, beforeTime int64, ids []string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	deleted := 0
+	for _, id := range slices.Compact(slices.Sorted(slices.Values(ids))) {
+		if e, ok := s.events[id]; ok && expired(e, overridesOf(s.events, id), beforeTime) {
+			s.remove(id)
+			deleted++
+		} else if e, ok := s.trash[id]; ok && expired(e, overridesOf(s.trash, id), beforeTime) {
+			delete(s.trash, id)
+			deleted++
+		}
+	}
+	s.removeOrphanOverrides()
+	return deleted, nil
+}
+
+// removeOrphanOverrides удаляет изменённые экземпляры удалённых серий (аналог ON DELETE CASCADE
+// в SQL хранилище). Вызывается под блокировкой на запись.
+func (s *Storage) removeOrphanOverrides() {
+	for id, e := range s.events {
+		if _, ok := s.events[e.RecurringEventID]; e.RecurringEventID != "" && !ok {
+			s.remove(id)
+		}
+	}
+	for id, e := range s.trash {
+		if _, ok := s.trash[e.RecurringEventID]; e.RecurringEventID != "" && !ok {
+			delete(s.trash, id)
+		}
+	}
+}
+
+// ListExpiredNotifications возвращает не более limit записей о напоминаниях на экземпляры, начавшиеся
+// раньше beforeTime, с ID больше afterID, упорядоченных по ID.
+func (s *Storage) ListExpiredNotifications(ctx context.Context, beforeTime int64, afterID string, limit int) ([]storage.NotificationRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var result []storage.NotificationRecord
+	for _, n := range s.reminders {
+		if n.ID > afterID && n.Reminder.EventTime < beforeTime {
+			result = append(result, n)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+// DeleteExpiredNotifications удаляет записи о напоминаниях из ids на экземпляры, начавшиеся раньше beforeTime,
+// и возвращает их число.
+func (s *Storage) DeleteExpiredNotifications(ctx context.Context, beforeTime int64, ids []string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	deleted := 0
+	for key, n := range s.reminders {
+		if n.Reminder.EventTime < beforeTime && slices.Contains(ids, n.ID) {
+			delete(s.reminders, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// DeleteOldChanges удаляет изменения из ленты изменений, записанные раньше beforeTime.
+// Отправленные сообщения outbox in-memory хранилище не хранит.
+func (s *Storage) DeleteOldChanges(ctx context.Context, beforeTime int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.changes = slices.DeleteFunc(s.changes, func(c storage.Change) bool { return c.ChangedAt < beforeTime })
+	return nil
+}
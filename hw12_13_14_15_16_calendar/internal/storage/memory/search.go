@@ -0,0 +1,201 @@
+package memorystorage
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/storage"
+)
+
+// snippetWords — число слов во фрагменте описания в результатах поиска.
+const snippetWords = 20
+
+// textIndex — обратный индекс слов заголовков и описаний событий: слово в нижнем регистре → ID событий.
+// Слова не приводятся к основе, поэтому конфигурация поиска (SearchQuery.Language) не учитывается.
+type textIndex map[string]map[string]struct{}
+
+// insert добавляет слова события в индекс.
+func (ix textIndex) insert(e storage.Event) {
+	for _, word := range eventWords(e) {
+		if ix[word] == nil {
+			ix[word] = make(map[string]struct{})
+		}
+		ix[word][e.ID] = struct{}{}
+	}
+}
+
+// remove удаляет слова события из индекса; слово без событий удаляется.
+func (ix textIndex) remove(e storage.Event) {
+	for _, word := range eventWords(e) {
+		delete(ix[word], e.ID)
+		if len(ix[word]) == 0 {
+			delete(ix, word)
+		}
+	}
+}
+
+// eventWords возвращает различные слова заголовка и описания события.
+func eventWords(e storage.Event) []string {
+	seen := make(map[string]struct{})
+	var words []string
+	for _, t := range append(tokenize(e.Title), tokenize(e.Description)...) {
+		if _, ok := seen[t.word]; !ok {
+			seen[t.word] = struct{}{}
+			words = append(words, t.word)
+		}
+	}
+	return words
+}
+
+// token — слово текста: последовательность букв и цифр, её начало и конец в байтах.
+type token struct {
+	word       string // слово в нижнем регистре
+	start, end int
+}
+
+// tokenize разбивает текст на слова.
+func tokenize(text string) []token {
+	var tokens []token
+	start := -1
+	for i, r := range text {
+		isWord := unicode.IsLetter(r) || unicode.IsDigit(r)
+		switch {
+		case isWord && start < 0:
+			start = i
+		case !isWord && start >= 0:
+			tokens = append(tokens, token{word: strings.ToLower(text[start:i]), start: start, end: i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, token{word: strings.ToLower(text[start:]), start: start, end: len(text)})
+	}
+	return tokens
+}
+
+// SearchEvents ищет видимые пользователю события, заголовок или описание которых содержат все слова запроса.
+// Слово в заголовке весит вдвое больше, чем в описании; результаты упорядочены по убыванию релевантности,
+// затем по времени начала и ID.
+func (s *Storage) SearchEvents(ctx context.Context, userID string, query storage.SearchQuery) ([]storage.SearchResult, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	terms := make(map[string]bool)
+	for _, t := range tokenize(query.Text) {
+		terms[t.word] = true
+	}
+	if len(terms) == 0 {
+		return nil, nil
+	}
+	// Проверяем события с самым редким словом запроса
+	var candidates map[string]struct{}
+	for term := range terms {
+		ids := s.words[term]
+		if len(ids) == 0 {
+			return nil, nil
+		}
+		if candidates == nil || len(ids) < len(candidates) {
+			candidates = ids
+		}
+	}
+
+	var result []storage.SearchResult
+	for id := range candidates {
+		e := s.events[id]
+		if !visibleTo(e, userID) {
+			continue
+		}
+		if rank := searchRank(e, terms); rank > 0 {
+			result = append(result, storage.SearchResult{
+				Event:   e,
+				Rank:    rank,
+				Title:   highlight(e.Title, terms, 0),
+				Snippet: highlight(e.Description, terms, snippetWords),
+			})
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if a.Rank != b.Rank {
+			return a.Rank > b.Rank
+		}
+		if a.Event.StartTime != b.Event.StartTime {
+			return a.Event.StartTime < b.Event.StartTime
+		}
+		return a.Event.ID < b.Event.ID
+	})
+	if len(result) > query.Limit {
+		result = result[:query.Limit]
+	}
+	return result, nil
+}
+
+// visibleTo сообщает, видно ли событие пользователю: он владелец или участник, не отказавшийся от приглашения.
+func visibleTo(e storage.Event, userID string) bool {
+	if e.UserID == userID {
+		return true
+	}
+	for _, a := range e.Attendees {
+		if a.UserID == userID && a.Status != storage.AttendeeDeclined {
+			return true
+		}
+	}
+	return false
+}
+
+// searchRank возвращает релевантность события: сумму вхождений слов запроса, где вхождение в заголовок
+// считается дважды. Возвращает 0, если какого-то слова в событии нет.
+func searchRank(e storage.Event, terms map[string]bool) float64 {
+	counts := make(map[string]int, len(terms))
+	for _, t := range tokenize(e.Title) {
+		if terms[t.word] {
+			counts[t.word] += 2
+		}
+	}
+	for _, t := range tokenize(e.Description) {
+		if terms[t.word] {
+			counts[t.word]++
+		}
+	}
+	if len(counts) < len(terms) {
+		return 0
+	}
+	rank := 0
+	for _, n := range counts {
+		rank += n
+	}
+	return float64(rank)
+}
+
+// highlight выделяет в тексте слова запроса метками storage.HighlightStart и storage.HighlightStop.
+// Если maxWords > 0, возвращается только фрагмент из maxWords слов, начинающийся с первого совпадения
+// (или с начала текста, если совпадений нет).
+func highlight(text string, terms map[string]bool, maxWords int) string {
+	tokens := tokenize(text)
+	start, end := 0, len(text)
+	if maxWords > 0 && len(tokens) > maxWords {
+		first := 0
+		for i, t := range tokens {
+			if terms[t.word] {
+				first = min(i, len(tokens)-maxWords)
+				break
+			}
+		}
+		tokens = tokens[first : first+maxWords]
+		start, end = tokens[0].start, tokens[len(tokens)-1].end
+	}
+	var b strings.Builder
+	pos := start
+	for _, t := range tokens {
+		if !terms[t.word] {
+			continue
+		}
+		b.WriteString(text[pos:t.start])
+		b.WriteString(storage.HighlightStart + text[t.start:t.end] + storage.HighlightStop)
+		pos = t.end
+	}
+	b.WriteString(text[pos:end])
+	return b.String()
+}
@@ -4,89 +4,238 @@ package memorystorage
 
 import (
 	"context"
+	"slices"
+	"sort"
 	"sync"
+	"time"
 
-	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/app"
 	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/storage"
+	"github.com/google/uuid"
 )
 
 // Storage представляет in-memory хранилище событий с потокобезопасным доступом
 type Storage struct {
-	mu     sync.RWMutex             // мьютекс для синхронизации доступа к данным
-	events map[string]storage.Event // карта событий, ключ - ID события
+	mu        sync.RWMutex                               // мьютекс для синхронизации доступа к данным
+	relayMu   sync.Mutex                                 // не даёт одновременно публиковать сообщения outbox (см. RelayOutbox)
+	events    map[string]storage.Event                   // карта событий, ключ - ID события
+	byUser    map[string]*timeIndex                      // интервальные индексы событий, ключ - ID пользователя
+	attending map[string]*timeIndex                      // индексы событий, в которых пользователь участвует (кроме отказов), ключ - ID участника
+	overrides map[string]map[string]struct{}             // изменённые экземпляры, ключ - ID повторяющегося события
+	words     textIndex                                  // обратный индекс слов заголовков и описаний для SearchEvents
+	trash     map[string]storage.Event                   // события в корзине (не попадают в индексы), ключ - ID события
+	reminders map[reminderKey]storage.NotificationRecord // записи о запланированных напоминаниях
+	outbox    []storage.OutboxMessage                    // неотправленные сообщения outbox в порядке записи
+	changes   []storage.Change                           // лента изменений событий в порядке ревизий
+	audit     []storage.AuditEntry                       // журнал аудита в порядке записи
+	leases    map[string]lease                           // аренды лидерства, ключ - имя аренды
+	webhooks  map[string]storage.Webhook                 // адреса для напоминаний, ключ - ID адреса
+	revision  int64                                      // ревизия последнего изменения
+	changed   chan struct{}                              // закрывается при записи изменения (см. Changed)
+}
+
+// reminderKey идентифицирует напоминание: событие, время начала экземпляра, смещение уведомления, канал и получателя.
+type reminderKey struct {
+	eventID      string
+	eventTime    int64
+	notifyBefore int64
+	channel      string
+	userID       string
 }
 
 // New создает новый экземпляр in-memory хранилища
 func New() *Storage {
 	return &Storage{
-		events: make(map[string]storage.Event),
+		events:    make(map[string]storage.Event),
+		byUser:    make(map[string]*timeIndex),
+		attending: make(map[string]*timeIndex),
+		overrides: make(map[string]map[string]struct{}),
+		words:     make(textIndex),
+		trash:     make(map[string]storage.Event),
+		reminders: make(map[reminderKey]storage.NotificationRecord),
+		leases:    make(map[string]lease),
+		webhooks:  make(map[string]storage.Webhook),
+		changed:   make(chan struct{}),
+	}
+}
+
+// put сохраняет событие и обновляет индексы. Вызывается под блокировкой на запись.
+func (s *Storage) put(event storage.Event) {
+	if old, ok := s.events[event.ID]; ok {
+		s.remove(old.ID)
+	}
+	event.ExDates = slices.Clone(event.ExDates)
+	event.Reminders = slices.Clone(event.Reminders)
+	event.Attendees = slices.Clone(event.Attendees)
+	s.events[event.ID] = event
+
+	indexFor(s.byUser, event.UserID).insert(event)
+	s.words.insert(event)
+	for _, a := range event.Attendees {
+		if a.Status != storage.AttendeeDeclined {
+			indexFor(s.attending, a.UserID).insert(event)
+		}
+	}
+	if event.RecurringEventID != "" {
+		if s.overrides[event.RecurringEventID] == nil {
+			s.overrides[event.RecurringEventID] = make(map[string]struct{})
+		}
+		s.overrides[event.RecurringEventID][event.ID] = struct{}{}
+	}
+}
+
+// remove удаляет событие и его записи в индексах. Вызывается под блокировкой на запись.
+func (s *Storage) remove(id string) {
+	event, ok := s.events[id]
+	if !ok {
+		return
+	}
+	delete(s.events, id)
+	removeFromIndex(s.byUser, event.UserID, event)
+	s.words.remove(event)
+	for _, a := range event.Attendees {
+		removeFromIndex(s.attending, a.UserID, event)
+	}
+	if event.RecurringEventID != "" {
+		delete(s.overrides[event.RecurringEventID], id)
+		if len(s.overrides[event.RecurringEventID]) == 0 {
+			delete(s.overrides, event.RecurringEventID)
+		}
+	}
+}
+
+// indexFor возвращает индекс пользователя, создавая его при необходимости.
+func indexFor(indexes map[string]*timeIndex, userID string) *timeIndex {
+	ix, ok := indexes[userID]
+	if !ok {
+		ix = newTimeIndex()
+		indexes[userID] = ix
 	}
+	return ix
 }
 
-// CreateEvent создает новое событие в хранилище.
+// removeFromIndex удаляет событие из индекса пользователя; пустой индекс удаляется.
+func removeFromIndex(indexes map[string]*timeIndex, userID string, event storage.Event) {
+	if ix, ok := indexes[userID]; ok {
+		ix.remove(event)
+		if ix.empty() {
+			delete(indexes, userID)
+		}
+	}
+}
+
+// CreateEvent создает новое событие в хранилище с версией storage.InitialVersion.
+// Возвращает storage.ErrAlreadyExists, если ID занят событием (в том числе в корзине).
 // Проверяет, что время не занято другим событием того же пользователя.
 func (s *Storage) CreateEvent(ctx context.Context, event storage.Event) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	// Проверка на занятость времени (простая: совпадение времени старта)
-	for _, e := range s.events {
-		if e.UserID == event.UserID && e.StartTime == event.StartTime {
-			return app.ErrDateBusy
-		}
+	if _, ok := s.events[event.ID]; ok {
+		return storage.AlreadyExistsError(event.ID)
 	}
-	s.events[event.ID] = event
+	if _, ok := s.trash[event.ID]; ok {
+		return storage.AlreadyExistsError(event.ID)
+	}
+	if err := s.checkConflicts(event); err != nil {
+		return err
+	}
+	event.Version = storage.InitialVersion
+	s.put(event)
+	s.recordChange(storage.ChangeCreated, event.ID, event.ChangeUsers())
+	s.recordAudit(ctx)
 	return nil
 }
 
-// UpdateEvent обновляет существующее событие в хранилище.
-// Проверяет существование события и занятость времени другими событиями.
+// UpdateEvent обновляет существующее событие в хранилище и увеличивает его версию.
+// Проверяет существование события, его версию (event.Version — версия, на которой основано
+// изменение, 0 — без проверки) и занятость времени другими событиями.
 func (s *Storage) UpdateEvent(ctx context.Context, event storage.Event) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	// Проверяем, что событие существует
-	if _, ok := s.events[event.ID]; !ok {
-		return context.Canceled // или custom not found error
+	// Проверяем, что событие существует и не изменилось с момента чтения
+	old, ok := s.events[event.ID]
+	if !ok {
+		return storage.NotFoundError(event.ID)
+	}
+	if event.Version != 0 && event.Version != old.Version {
+		return storage.VersionConflictError(event.ID, old.Version)
 	}
 
-	// Проверка на занятость времени (кроме текущего события)
-	for id, e := range s.events {
-		if id != event.ID && e.UserID == event.UserID && e.StartTime == event.StartTime {
-			return app.ErrDateBusy
-		}
+	if err := s.checkConflicts(event); err != nil {
+		return err
 	}
-	s.events[event.ID] = event
+	event.Version = old.Version + 1
+	s.put(event)
+	s.recordChange(storage.ChangeUpdated, event.ID, old.ChangeUsers(), event.ChangeUsers())
+	s.recordAudit(ctx)
 	return nil
 }
 
-// DeleteEvent удаляет событие по ID из хранилища.
-// Возвращает ошибку, если событие не найдено.
-func (s *Storage) DeleteEvent(ctx context.Context, id string) error {
+// checkConflicts проверяет, что событие не пересекается по времени с другими событиями пользователя.
+// Правила совпадают с ограничением events_no_overlap в SQL хранилище: сравниваются полуинтервалы
+// [StartTime, EndTime) событий без правила повторения, пустые интервалы ни с чем не пересекаются.
+// Вызывается под блокировкой на запись.
+func (s *Storage) checkConflicts(event storage.Event) error {
+	if event.IsRecurring() || event.StartTime >= event.EndTime {
+		return nil
+	}
+	ix, ok := s.byUser[event.UserID]
+	if !ok {
+		return nil
+	}
+	var ids []string
+	for _, id := range ix.candidates(event.StartTime, event.EndTime) {
+		if id != event.ID && overlaps(s.events[id], event) {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	return &storage.ConflictError{EventIDs: ids}
+}
+
+// overlaps сообщает, пересекаются ли непустые полуинтервалы [StartTime, EndTime) двух событий.
+func overlaps(a, b storage.Event) bool {
+	return a.StartTime < a.EndTime && b.StartTime < b.EndTime &&
+		a.StartTime < b.EndTime && b.StartTime < a.EndTime
+}
+
+// DeleteEvent удаляет событие по ID из хранилища навсегда, минуя корзину, если его версия равна version
+// (0 — без проверки).
+// Возвращает storage.ErrNotFound, если событие не найдено, и storage.ErrVersionConflict, если версия другая.
+func (s *Storage) DeleteEvent(ctx context.Context, id string, version int64) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	if _, ok := s.events[id]; !ok {
-		return context.Canceled // или custom not found error
+	old, ok := s.events[id]
+	if !ok {
+		return storage.NotFoundError(id)
 	}
-	delete(s.events, id)
+	if version != 0 && version != old.Version {
+		return storage.VersionConflictError(id, old.Version)
+	}
+	s.remove(id)
+	s.recordChange(storage.ChangeDeleted, id, old.ChangeUsers())
+	s.recordAudit(ctx)
 	return nil
 }
 
 // GetEvent возвращает событие по ID.
-// Возвращает ошибку, если событие не найдено.
+// Возвращает storage.ErrNotFound, если событие не найдено.
 func (s *Storage) GetEvent(ctx context.Context, id string) (storage.Event, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
 	event, ok := s.events[id]
 	if !ok {
-		return storage.Event{}, context.Canceled // или custom not found error
+		return storage.Event{}, storage.NotFoundError(id)
 	}
 	return event, nil
 }
 
-// ListEvents возвращает все события указанного пользователя.
+// ListEvents возвращает все события указанного пользователя, отсортированные по времени начала и ID.
 // Использует read lock для оптимизации производительности.
 func (s *Storage) ListEvents(ctx context.Context, userID string) ([]storage.Event, error) {
 	s.mu.RLock()
@@ -98,37 +247,360 @@ func (s *Storage) ListEvents(ctx context.Context, userID string) ([]storage.Even
 			result = append(result, e)
 		}
 	}
+	sortEvents(result)
 	return result, nil
 }
 
-// GetEventsForNotification возвращает события, требующие уведомления.
+// ListEventsInRange возвращает события пользователя, пересекающиеся с диапазоном [start, end),
+// повторяющиеся события, серия которых пересекается с диапазоном, и все изменённые экземпляры
+// таких серий. Событие нулевой длительности попадает в диапазон, если начинается внутри него.
+// Результат отсортирован по времени начала. Разовые события выбираются по интервальному индексу.
+func (s *Storage) ListEventsInRange(ctx context.Context, userID string, start, end int64) ([]storage.Event, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ix, ok := s.byUser[userID]
+	if !ok {
+		return nil, nil
+	}
+	seen := make(map[string]struct{})
+	var result []storage.Event
+	add := func(e storage.Event) {
+		if _, dup := seen[e.ID]; !dup {
+			seen[e.ID] = struct{}{}
+			result = append(result, e)
+		}
+	}
+	for _, id := range ix.candidates(start, end) {
+		if e := s.events[id]; inRange(e.StartTime, e.EndTime, start, end) {
+			add(e)
+		}
+	}
+	for _, e := range s.seriesInRange(ix, start, end) {
+		add(e)
+	}
+	sortEvents(result)
+	return result, nil
+}
+
+// ListEventsPage возвращает страницу событий пользователя за диапазон [start, end): не более limit
+// разовых событий после курсора after, а также повторяющиеся события, серия которых пересекается
+// с диапазоном, вместе с их изменёнными экземплярами. Условия попадания в диапазон — как в ListEventsInRange.
+// В выборку попадают события пользователя и события, в которых он участвует и не отказался от приглашения.
+func (s *Storage) ListEventsPage(ctx context.Context, userID string, start, end int64, after storage.Cursor, limit int) (storage.EventPage, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var (
+		page   storage.EventPage
+		single []storage.Event
+	)
+	seen := make(map[string]struct{})
+	for _, ix := range []*timeIndex{s.byUser[userID], s.attending[userID]} {
+		if ix == nil {
+			continue
+		}
+		for _, id := range ix.candidates(start, end) {
+			e := s.events[id]
+			if _, dup := seen[id]; dup || e.RecurringEventID != "" || !inRange(e.StartTime, e.EndTime, start, end) {
+				continue
+			}
+			seen[id] = struct{}{}
+			single = append(single, e)
+		}
+		for _, e := range s.seriesInRange(ix, start, end) {
+			if _, dup := seen[e.ID]; !dup {
+				seen[e.ID] = struct{}{}
+				page.Series = append(page.Series, e)
+			}
+		}
+	}
+	sortEvents(single)
+	page.Total = len(single)
+	for _, e := range single {
+		if len(page.Events) == limit {
+			break
+		}
+		if after.Before(e) {
+			page.Events = append(page.Events, e)
+		}
+	}
+	sortEvents(page.Series)
+	return page, nil
+}
+
+// seriesInRange возвращает повторяющиеся события пользователя, серия которых пересекается
+// с диапазоном [start, end), и их изменённые экземпляры. Вызывается под блокировкой на чтение.
+func (s *Storage) seriesInRange(ix *timeIndex, start, end int64) []storage.Event {
+	var result []storage.Event
+	for id := range ix.series {
+		e := s.events[id]
+		if e.StartTime >= end || (e.RecurrenceEnd != 0 && e.RecurrenceEnd < start) {
+			continue
+		}
+		result = append(result, e)
+		for overrideID := range s.overrides[id] {
+			result = append(result, s.events[overrideID])
+		}
+	}
+	return result
+}
+
+// sortEvents упорядочивает события по времени начала, затем по ID.
+func sortEvents(events []storage.Event) {
+	sort.Slice(events, func(i, j int) bool {
+		if events[i].StartTime != events[j].StartTime {
+			return events[i].StartTime < events[j].StartTime
+		}
+		return events[i].ID < events[j].ID
+	})
+}
+
+// inRange сообщает, пересекается ли [startTime, endTime) с диапазоном [start, end).
+// Событие нулевой длительности попадает в диапазон, если начинается внутри него.
+func inRange(startTime, endTime, start, end int64) bool {
+	if startTime == endTime {
+		return startTime >= start && startTime < end
+	}
+	return startTime < end && endTime > start
+}
+
+// SetAttendeeStatus сохраняет ответ участника на приглашение в событие и его изменённые экземпляры
+// и увеличивает их версии.
+// Возвращает storage.ErrNotFound, если события нет или пользователь в нём не участвует.
+func (s *Storage) SetAttendeeStatus(ctx context.Context, eventID, userID string, status storage.AttendeeStatus) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	event, ok := s.events[eventID]
+	if !ok {
+		return storage.ErrNotFound
+	}
+	if _, ok := event.Attendee(userID); !ok {
+		return storage.ErrNotFound
+	}
+	ids := []string{eventID}
+	for id := range s.overrides[eventID] {
+		ids = append(ids, id)
+	}
+	for _, id := range ids {
+		e := s.events[id]
+		e.Attendees = slices.Clone(e.Attendees)
+		for i := range e.Attendees {
+			if e.Attendees[i].UserID == userID {
+				e.Attendees[i].Status = status
+			}
+		}
+		e.Version++
+		s.put(e)
+		s.recordChange(storage.ChangeUpdated, id, e.ChangeUsers())
+	}
+	s.recordAudit(ctx)
+	return nil
+}
+
+// ListAttendedEvents возвращает события, в которых участвует пользователь (с любым ответом),
+// без изменённых экземпляров серий, отсортированные по времени начала и ID.
+func (s *Storage) ListAttendedEvents(ctx context.Context, userID string) ([]storage.Event, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var result []storage.Event
+	for _, e := range s.events {
+		if _, ok := e.Attendee(userID); ok && e.RecurringEventID == "" {
+			result = append(result, e)
+		}
+	}
+	sortEvents(result)
+	return result, nil
+}
+
+// GetEventsForNotification возвращает события, требующие уведомления, отсортированные по времени начала.
+// Событие возвращается, если наступило время хотя бы одного из его напоминаний.
+// Повторяющиеся события возвращаются целиком, если серия ещё не закончилась:
+// выбор конкретных экземпляров и напоминаний выполняет слой бизнес-логики.
 func (s *Storage) GetEventsForNotification(ctx context.Context, currentTime int64) ([]storage.Event, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
 	var result []storage.Event
 	for _, e := range s.events {
-		if e.NotifyBefore != nil {
-			// Событие требует уведомления, если:
-			// (start_time - notify_before) <= current_time < start_time
-			notifyTime := e.StartTime - *e.NotifyBefore
-			if notifyTime <= currentTime && currentTime < e.StartTime {
+		// Напоминание наступило, если (start_time - offset) <= current_time
+		due := slices.ContainsFunc(e.Reminders, func(r storage.EventReminder) bool {
+			return e.StartTime-r.Offset <= currentTime
+		})
+		if !due {
+			continue
+		}
+		if e.IsRecurring() {
+			if e.RecurrenceEnd == 0 || currentTime < e.RecurrenceEnd {
 				result = append(result, e)
 			}
+			continue
+		}
+		if currentTime < e.StartTime {
+			result = append(result, e)
 		}
 	}
+	sortEvents(result)
 	return result, nil
 }
 
-// DeleteOldEvents удаляет события, произошедшие более указанного времени назад.
-func (s *Storage) DeleteOldEvents(ctx context.Context, beforeTime int64) error {
+// EnqueueReminder отмечает напоминание как запланированное и записывает его в outbox.
+// Возвращает false, если напоминание уже было запланировано ранее.
+func (s *Storage) EnqueueReminder(ctx context.Context, reminder storage.Reminder) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := reminderKey{reminder.EventID, reminder.EventTime, reminder.NotifyBefore, reminder.Channel, reminder.UserID}
+	if _, ok := s.reminders[key]; ok {
+		return false, nil
+	}
+	now := time.Now().Unix()
+	s.reminders[key] = storage.NotificationRecord{
+		ID:        uuid.New().String(),
+		Reminder:  reminder,
+		Status:    storage.NotificationScheduled,
+		CreatedAt: now,
+	}
+	s.outbox = append(s.outbox, storage.OutboxMessage{
+		ID:        uuid.New().String(),
+		Reminder:  reminder,
+		CreatedAt: now,
+	})
+	return true, nil
+}
+
+// RecordNotificationAttempt учитывает попытку доставки напоминания на адрес endpoint с ошибкой attemptErr
+// (пусто — попытка удалась, адрес добавляется в DeliveredTo). Если записи о напоминании нет, она создаётся
+// со статусом storage.NotificationScheduled.
+func (s *Storage) RecordNotificationAttempt(ctx context.Context, reminder storage.Reminder, endpoint, attemptErr string) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	for id, e := range s.events {
-		if e.StartTime < beforeTime {
-			delete(s.events, id)
+	now := time.Now().Unix()
+	key := reminderKey{reminder.EventID, reminder.EventTime, reminder.NotifyBefore, reminder.Channel, reminder.UserID}
+	n, ok := s.reminders[key]
+	if !ok {
+		n = storage.NotificationRecord{
+			ID:        uuid.New().String(),
+			Reminder:  reminder,
+			Status:    storage.NotificationScheduled,
+			CreatedAt: now,
 		}
 	}
+	n.Attempts++
+	n.LastError = attemptErr
+	n.LastAttempt = now
+	if attemptErr == "" && !slices.Contains(n.DeliveredTo, endpoint) {
+		n.DeliveredTo = append(slices.Clone(n.DeliveredTo), endpoint)
+	}
+	s.reminders[key] = n
 	return nil
 }
+
+// DeliveredEndpoints возвращает адреса, на которые напоминание уже доставлено.
+func (s *Storage) DeliveredEndpoints(ctx context.Context, reminder storage.Reminder) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	key := reminderKey{reminder.EventID, reminder.EventTime, reminder.NotifyBefore, reminder.Channel, reminder.UserID}
+	return slices.Clone(s.reminders[key].DeliveredTo), nil
+}
+
+// PendingOutbox возвращает не более limit неотправленных сообщений outbox в порядке записи.
+func (s *Storage) PendingOutbox(ctx context.Context, limit int) ([]storage.OutboxMessage, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	n := min(limit, len(s.outbox))
+	return slices.Clone(s.outbox[:n]), nil
+}
+
+// RelayOutbox публикует не более limit неотправленных сообщений outbox в порядке записи и удаляет
+// отправленные. Одновременные вызовы выполняются по очереди, поэтому сообщение не публикуется дважды.
+// При ошибке публикации попытка учитывается, обработка останавливается, а ошибка возвращается.
+// Возвращает число отправленных сообщений.
+func (s *Storage) RelayOutbox(ctx context.Context, limit int,
+	publish func(context.Context, storage.OutboxMessage) error,
+) (int, error) {
+	s.relayMu.Lock()
+	defer s.relayMu.Unlock()
+
+	messages, _ := s.PendingOutbox(ctx, limit)
+	for i, msg := range messages {
+		if err := publish(ctx, msg); err != nil {
+			s.mu.Lock()
+			for j := range s.outbox {
+				if s.outbox[j].ID == msg.ID {
+					s.outbox[j].Attempts++
+				}
+			}
+			s.mu.Unlock()
+			return i, err
+		}
+		s.mu.Lock()
+		s.outbox = slices.DeleteFunc(s.outbox, func(m storage.OutboxMessage) bool { return m.ID == msg.ID })
+		s.mu.Unlock()
+	}
+	return len(messages), nil
+}
+
+// recordChange записывает изменение события в ленту и будит ожидающих подписчиков.
+// Получатели — объединение переданных списков пользователей без повторов.
+// Вызывается под блокировкой на запись.
+func (s *Storage) recordChange(typ storage.ChangeType, eventID string, users ...[]string) {
+	var userIDs []string
+	for _, u := range users {
+		userIDs = append(userIDs, u...)
+	}
+	slices.Sort(userIDs)
+	s.revision++
+	s.changes = append(s.changes, storage.Change{
+		Revision:  s.revision,
+		Type:      typ,
+		EventID:   eventID,
+		UserIDs:   slices.Compact(userIDs),
+		ChangedAt: time.Now().Unix(),
+	})
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+// ListChanges возвращает не более limit изменений, касающихся пользователя, с ревизией больше after,
+// в порядке ревизий.
+func (s *Storage) ListChanges(ctx context.Context, userID string, after int64, limit int) ([]storage.Change, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	i := sort.Search(len(s.changes), func(i int) bool { return s.changes[i].Revision > after })
+	var result []storage.Change
+	for _, c := range s.changes[i:] {
+		if len(result) == limit {
+			break
+		}
+		if c.Touches(userID) {
+			result = append(result, c)
+		}
+	}
+	return result, nil
+}
+
+// LastChangeRevision возвращает ревизию последнего записанного изменения; 0 — изменений не было.
+// Изменения в памяти записываются под общей блокировкой, поэтому подходит ревизия последнего
+// изменения любого пользователя.
+func (s *Storage) LastChangeRevision(ctx context.Context, _ string) (int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.revision, nil
+}
+
+// Changed возвращает канал, который закроется при записи следующего изменения в ленту.
+// Позволяет подписчикам ленты не опрашивать хранилище.
+func (s *Storage) Changed() <-chan struct{} {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.changed
+}
@@ -3,6 +3,8 @@ package memorystorage
 
 import (
 	"context"
+	"errors"
+	"slices"
 	"sync"
 	"testing"
 
@@ -51,7 +53,7 @@ func TestStorageCRUD(t *testing.T) {
 	}
 
 	// Тест удаления события
-	if err := s.DeleteEvent(ctx, event.ID); err != nil {
+	if err := s.DeleteEvent(ctx, event.ID, 0); err != nil {
 		t.Fatalf("DeleteEvent failed: %v", err)
 	}
 	_, err = s.GetEvent(ctx, event.ID)
@@ -60,13 +62,58 @@ func TestStorageCRUD(t *testing.T) {
 	}
 }
 
+// TestStorageNotFound проверяет, что операции с отсутствующим событием возвращают storage.ErrNotFound.
+func TestStorageNotFound(t *testing.T) {
+	s := New()
+	ctx := context.Background()
+	if _, err := s.GetEvent(ctx, "missing"); !errors.Is(err, storage.ErrNotFound) {
+		t.Fatalf("GetEvent: expected storage.ErrNotFound, got %v", err)
+	}
+	if err := s.UpdateEvent(ctx, storage.Event{ID: "missing", UserID: "u"}); !errors.Is(err, storage.ErrNotFound) {
+		t.Fatalf("UpdateEvent: expected storage.ErrNotFound, got %v", err)
+	}
+	if err := s.DeleteEvent(ctx, "missing", 0); !errors.Is(err, storage.ErrNotFound) {
+		t.Fatalf("DeleteEvent: expected storage.ErrNotFound, got %v", err)
+	}
+}
+
+// TestStorageAlreadyExists проверяет, что событие с занятым ID не создаётся и не заменяет чужое событие.
+func TestStorageAlreadyExists(t *testing.T) {
+	s := New()
+	ctx := context.Background()
+	original := storage.Event{ID: "1", Title: "B's meeting", UserID: "userB", StartTime: 1000, EndTime: 2000}
+	if err := s.CreateEvent(ctx, original); err != nil {
+		t.Fatalf("CreateEvent failed: %v", err)
+	}
+
+	taken := storage.Event{ID: "1", Title: "A's meeting", UserID: "userA", StartTime: 3000, EndTime: 4000}
+	if err := s.CreateEvent(ctx, taken); !errors.Is(err, storage.ErrAlreadyExists) {
+		t.Fatalf("expected storage.ErrAlreadyExists, got %v", err)
+	}
+	got, err := s.GetEvent(ctx, original.ID)
+	if err != nil || got.UserID != original.UserID || got.Title != original.Title {
+		t.Fatalf("original event was replaced: %+v, err=%v", got, err)
+	}
+	if list, _ := s.ListEvents(ctx, taken.UserID); len(list) != 0 {
+		t.Fatalf("event was created for the second user: %+v", list)
+	}
+
+	// ID события в корзине тоже занят
+	if err := s.TrashEvent(ctx, original.ID, 0, 5000); err != nil {
+		t.Fatalf("TrashEvent failed: %v", err)
+	}
+	if err := s.CreateEvent(ctx, taken); !errors.Is(err, storage.ErrAlreadyExists) {
+		t.Fatalf("expected storage.ErrAlreadyExists for trashed ID, got %v", err)
+	}
+}
+
 // TestStorageErrDateBusy тестирует бизнес-логику проверки занятости времени
-// Проверяет, что нельзя создать два события с одинаковым временем для одного пользователя
+// Проверяет, что нельзя создать два пересекающихся по времени события для одного пользователя
 func TestStorageErrDateBusy(t *testing.T) {
 	s := New()
 	ctx := context.Background()
-	e1 := storage.Event{ID: "1", UserID: "u", StartTime: 1000}
-	e2 := storage.Event{ID: "2", UserID: "u", StartTime: 1000}
+	e1 := storage.Event{ID: "1", UserID: "u", StartTime: 1000, EndTime: 2000}
+	e2 := storage.Event{ID: "2", UserID: "u", StartTime: 1000, EndTime: 1500}
 
 	// Создаем первое событие
 	if err := s.CreateEvent(ctx, e1); err != nil {
@@ -74,11 +121,55 @@ func TestStorageErrDateBusy(t *testing.T) {
 	}
 
 	// Пытаемся создать второе событие в то же время - должно вернуть ошибку
-	if err := s.CreateEvent(ctx, e2); err != app.ErrDateBusy {
+	if err := s.CreateEvent(ctx, e2); !errors.Is(err, app.ErrDateBusy) {
 		t.Fatalf("expected ErrDateBusy, got %v", err)
 	}
 }
 
+// TestStorageOverlap проверяет определение пересечения полуинтервалов [StartTime, EndTime)
+func TestStorageOverlap(t *testing.T) {
+	s := New()
+	ctx := context.Background()
+	base := storage.Event{ID: "base", UserID: "u", StartTime: 1000, EndTime: 2000}
+	if err := s.CreateEvent(ctx, base); err != nil {
+		t.Fatalf("CreateEvent failed: %v", err)
+	}
+
+	cases := []struct {
+		name     string
+		event    storage.Event
+		conflict bool
+	}{
+		{"inside", storage.Event{ID: "a", UserID: "u", StartTime: 1200, EndTime: 1300}, true},
+		{"covers", storage.Event{ID: "b", UserID: "u", StartTime: 500, EndTime: 2500}, true},
+		{"tail overlap", storage.Event{ID: "c", UserID: "u", StartTime: 1999, EndTime: 2100}, true},
+		{"adjacent before", storage.Event{ID: "d", UserID: "u", StartTime: 500, EndTime: 1000}, false},
+		{"adjacent after", storage.Event{ID: "e", UserID: "u", StartTime: 2000, EndTime: 2100}, false},
+		{"other user", storage.Event{ID: "f", UserID: "other", StartTime: 1000, EndTime: 2000}, false},
+		{"empty interval", storage.Event{ID: "g", UserID: "u", StartTime: 1500, EndTime: 1500}, false},
+	}
+	for _, tc := range cases {
+		err := s.CreateEvent(ctx, tc.event)
+		if !tc.conflict {
+			if err != nil {
+				t.Fatalf("%s: unexpected error %v", tc.name, err)
+			}
+			_ = s.DeleteEvent(ctx, tc.event.ID, 0)
+			continue
+		}
+		var conflict *app.ConflictError
+		if !errors.As(err, &conflict) || len(conflict.EventIDs) != 1 || conflict.EventIDs[0] != "base" {
+			t.Fatalf("%s: expected conflict with base, got %v", tc.name, err)
+		}
+	}
+
+	// Обновление события не конфликтует само с собой
+	base.EndTime = 2200
+	if err := s.UpdateEvent(ctx, base); err != nil {
+		t.Fatalf("UpdateEvent failed: %v", err)
+	}
+}
+
 // TestStorageConcurrency тестирует потокобезопасность хранилища
 // Проверяет, что хранилище корректно работает при одновременном доступе из нескольких горутин
 func TestStorageConcurrency(t *testing.T) {
@@ -107,3 +198,579 @@ func TestStorageConcurrency(t *testing.T) {
 		t.Fatalf("expected %d events, got %d", n, len(list))
 	}
 }
+
+// TestStorageExpiredRecurringEvents проверяет, что очистка не удаляет незавершённые серии повторяющихся событий
+// и серии с изменёнными экземплярами, которые ещё не закончились
+func TestStorageExpiredRecurringEvents(t *testing.T) {
+	s := New()
+	ctx := context.Background()
+	events := []storage.Event{
+		{ID: "single", UserID: "u", StartTime: 100, EndTime: 200},
+		{ID: "long", UserID: "u", StartTime: 99000, EndTime: 101000},
+		{ID: "endless", UserID: "u", StartTime: 300, EndTime: 400, RRule: "FREQ=DAILY"},
+		{ID: "finished", UserID: "u", StartTime: 500, EndTime: 600, RRule: "FREQ=DAILY;COUNT=2", RecurrenceEnd: 87000},
+		{ID: "override", UserID: "u", StartTime: 90000, EndTime: 90100, RecurringEventID: "finished", RecurrenceID: 86900},
+		{ID: "moved", UserID: "u", StartTime: 700, EndTime: 800, RRule: "FREQ=DAILY;COUNT=2", RecurrenceEnd: 87200},
+		{ID: "moved-later", UserID: "u", StartTime: 200000, EndTime: 200100, RecurringEventID: "moved", RecurrenceID: 87100},
+	}
+	for _, e := range events {
+		if err := s.CreateEvent(ctx, e); err != nil {
+			t.Fatalf("CreateEvent failed: %v", err)
+		}
+	}
+
+	// Изменённый экземпляр архивируется вместе с серией; событие истекает по времени окончания
+	expiredEvents, _ := s.ListExpiredEvents(ctx, 100000, "", 10)
+	var ids []string
+	for _, e := range expiredEvents {
+		ids = append(ids, e.ID)
+	}
+	if !slices.Equal(ids, []string{"finished", "override", "single"}) {
+		t.Fatalf("unexpected expired events: %v", ids)
+	}
+	if n, err := s.DeleteExpiredEvents(ctx, 100000, append(ids, "long", "moved")); err != nil || n != 2 {
+		t.Fatalf("expected 2 deleted events, got %d, err=%v", n, err)
+	}
+	list, _ := s.ListEvents(ctx, "u")
+	ids = nil
+	for _, e := range list {
+		ids = append(ids, e.ID)
+	}
+	slices.Sort(ids)
+	if !slices.Equal(ids, []string{"endless", "long", "moved", "moved-later"}) {
+		t.Fatalf("unexpected events left: %v", ids)
+	}
+}
+
+// TestStorageListEventsInRange проверяет выборку событий, пересекающихся с диапазоном, и её порядок
+func TestStorageListEventsInRange(t *testing.T) {
+	s := New()
+	ctx := context.Background()
+	events := []storage.Event{
+		{ID: "late", UserID: "u", StartTime: 1500, EndTime: 1600},
+		{ID: "spans-start", UserID: "u", StartTime: 500, EndTime: 1100},
+		{ID: "before", UserID: "u", StartTime: 100, EndTime: 500},
+		{ID: "after", UserID: "u", StartTime: 2000, EndTime: 2100},
+		{ID: "point", UserID: "u", StartTime: 1000, EndTime: 1000},
+		{ID: "series", UserID: "u", StartTime: 50, EndTime: 60, RRule: "FREQ=DAILY"},
+		{ID: "moved", UserID: "u", StartTime: 5000, EndTime: 5010, RecurringEventID: "series", RecurrenceID: 86450},
+		{ID: "finished", UserID: "u", StartTime: 10, EndTime: 20, RRule: "FREQ=DAILY;COUNT=1", RecurrenceEnd: 20},
+		{ID: "other", UserID: "other", StartTime: 1200, EndTime: 1300},
+	}
+	for _, e := range events {
+		if err := s.CreateEvent(ctx, e); err != nil {
+			t.Fatalf("CreateEvent %s failed: %v", e.ID, err)
+		}
+	}
+
+	list, err := s.ListEventsInRange(ctx, "u", 1000, 2000)
+	if err != nil {
+		t.Fatalf("ListEventsInRange failed: %v", err)
+	}
+	var ids []string
+	for _, e := range list {
+		ids = append(ids, e.ID)
+	}
+	want := []string{"series", "spans-start", "point", "late", "moved"}
+	if !slices.Equal(ids, want) {
+		t.Fatalf("expected %v, got %v", want, ids)
+	}
+
+	// После удаления событие пропадает из индекса
+	if err := s.DeleteEvent(ctx, "spans-start", 0); err != nil {
+		t.Fatalf("DeleteEvent failed: %v", err)
+	}
+	list, _ = s.ListEventsInRange(ctx, "u", 1000, 1001)
+	if len(list) != 3 || list[0].ID != "series" || list[1].ID != "point" || list[2].ID != "moved" {
+		t.Fatalf("unexpected events after delete: %+v", list)
+	}
+}
+
+// TestStorageListEventsPage проверяет постраничную выборку разовых событий по ключу (StartTime, ID).
+func TestStorageListEventsPage(t *testing.T) {
+	s := New()
+	ctx := context.Background()
+	events := []storage.Event{
+		{ID: "c", UserID: "u", StartTime: 1200, EndTime: 1200},
+		{ID: "b", UserID: "u", StartTime: 1200, EndTime: 1200},
+		{ID: "a", UserID: "u", StartTime: 1100, EndTime: 1150},
+		{ID: "d", UserID: "u", StartTime: 1500, EndTime: 1600},
+		{ID: "outside", UserID: "u", StartTime: 2000, EndTime: 2100},
+		{ID: "series", UserID: "u", StartTime: 50, EndTime: 60, RRule: "FREQ=DAILY"},
+		{ID: "moved", UserID: "u", StartTime: 1300, EndTime: 1310, RecurringEventID: "series", RecurrenceID: 86450},
+	}
+	for _, e := range events {
+		if err := s.CreateEvent(ctx, e); err != nil {
+			t.Fatalf("CreateEvent %s failed: %v", e.ID, err)
+		}
+	}
+
+	var got []string
+	after := storage.Cursor{}
+	for {
+		page, err := s.ListEventsPage(ctx, "u", 1000, 2000, after, 2)
+		if err != nil {
+			t.Fatalf("ListEventsPage failed: %v", err)
+		}
+		if page.Total != 4 {
+			t.Fatalf("expected total 4, got %d", page.Total)
+		}
+		if len(page.Series) != 2 || page.Series[0].ID != "series" || page.Series[1].ID != "moved" {
+			t.Fatalf("unexpected series: %+v", page.Series)
+		}
+		for _, e := range page.Events {
+			got = append(got, e.ID)
+		}
+		if len(page.Events) < 2 {
+			break
+		}
+		last := page.Events[len(page.Events)-1]
+		after = storage.Cursor{StartTime: last.StartTime, ID: last.ID}
+	}
+	want := []string{"a", "b", "c", "d"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+// TestStorageEventsForNotification проверяет, что каждое напоминание события учитывается отдельно
+func TestStorageEventsForNotification(t *testing.T) {
+	s := New()
+	ctx := context.Background()
+	reminders := []storage.EventReminder{{Offset: 3600, Channel: "email"}, {Offset: 600}}
+	events := []storage.Event{
+		{ID: "soon", UserID: "u", StartTime: 1500, EndTime: 1600, Reminders: reminders},
+		{ID: "later", UserID: "u", StartTime: 5000, EndTime: 5100, Reminders: reminders},
+		{ID: "silent", UserID: "u", StartTime: 1200, EndTime: 1300},
+		{ID: "series", UserID: "u", StartTime: 1800, EndTime: 1900, RRule: "FREQ=DAILY", Reminders: reminders},
+	}
+	for _, e := range events {
+		if err := s.CreateEvent(ctx, e); err != nil {
+			t.Fatalf("CreateEvent failed: %v", err)
+		}
+	}
+	// Хранилище хранит копию напоминаний
+	reminders[0].Offset = 1
+
+	list, err := s.GetEventsForNotification(ctx, 1000)
+	if err != nil {
+		t.Fatalf("GetEventsForNotification failed: %v", err)
+	}
+	ids := make([]string, 0, len(list))
+	for _, e := range list {
+		ids = append(ids, e.ID)
+	}
+	if !slices.Equal(ids, []string{"soon", "series"}) {
+		t.Fatalf("unexpected events for notification: %v", ids)
+	}
+	if list[0].Reminders[0].Offset != 3600 || len(list[0].Reminders) != 2 {
+		t.Fatalf("unexpected reminders: %+v", list[0].Reminders)
+	}
+}
+
+// TestStorageAttendees проверяет выборку событий участника и сохранение его ответа на приглашение
+func TestStorageAttendees(t *testing.T) {
+	s := New()
+	ctx := context.Background()
+	attendees := []storage.Attendee{{UserID: "guest", Status: storage.AttendeeNeedsAction}}
+	events := []storage.Event{
+		{ID: "own", UserID: "guest", StartTime: 100, EndTime: 200},
+		{ID: "meeting", UserID: "owner", StartTime: 300, EndTime: 400, Attendees: attendees},
+		{ID: "series", UserID: "owner", StartTime: 500, EndTime: 600, RRule: "FREQ=DAILY", Attendees: attendees},
+		{ID: "override", UserID: "owner", StartTime: 700, EndTime: 800, RecurringEventID: "series", RecurrenceID: 86900,
+			Attendees: attendees},
+		{ID: "private", UserID: "owner", StartTime: 900, EndTime: 1000},
+	}
+	for _, e := range events {
+		if err := s.CreateEvent(ctx, e); err != nil {
+			t.Fatalf("CreateEvent failed: %v", err)
+		}
+	}
+
+	page, err := s.ListEventsPage(ctx, "guest", 0, 1000, storage.Cursor{}, 10)
+	if err != nil {
+		t.Fatalf("ListEventsPage failed: %v", err)
+	}
+	if len(page.Events) != 2 || page.Events[0].ID != "own" || page.Events[1].ID != "meeting" || page.Total != 2 {
+		t.Fatalf("unexpected single events: %+v", page.Events)
+	}
+	if len(page.Series) != 2 || page.Series[0].ID != "series" || page.Series[1].ID != "override" {
+		t.Fatalf("unexpected series: %+v", page.Series)
+	}
+
+	// Ответ на приглашение в серию сохраняется и в изменённых экземплярах
+	if err := s.SetAttendeeStatus(ctx, "series", "guest", storage.AttendeeDeclined); err != nil {
+		t.Fatalf("SetAttendeeStatus failed: %v", err)
+	}
+	if err := s.SetAttendeeStatus(ctx, "private", "guest", storage.AttendeeAccepted); !errors.Is(err, app.ErrNotFound) {
+		t.Fatalf("expected app.ErrNotFound for uninvited user, got %v", err)
+	}
+	override, _ := s.GetEvent(ctx, "override")
+	if override.Attendees[0].Status != storage.AttendeeDeclined {
+		t.Fatalf("override attendee status not updated: %+v", override.Attendees)
+	}
+	page, _ = s.ListEventsPage(ctx, "guest", 0, 1000, storage.Cursor{}, 10)
+	if len(page.Series) != 0 {
+		t.Fatalf("declined series must be hidden, got %+v", page.Series)
+	}
+
+	attended, err := s.ListAttendedEvents(ctx, "guest")
+	if err != nil || len(attended) != 2 || attended[0].ID != "meeting" || attended[1].ID != "series" {
+		t.Fatalf("unexpected attended events: %+v, err=%v", attended, err)
+	}
+}
+
+func TestStorageChanges(t *testing.T) {
+	s := New()
+	ctx := context.Background()
+	changed := s.Changed()
+	event := storage.Event{ID: "meeting", UserID: "owner", StartTime: 100, EndTime: 200,
+		Attendees: []storage.Attendee{{UserID: "guest", Status: storage.AttendeeNeedsAction}}}
+	if err := s.CreateEvent(ctx, event); err != nil {
+		t.Fatalf("CreateEvent failed: %v", err)
+	}
+	select {
+	case <-changed:
+	default:
+		t.Fatal("Changed channel must be closed after a change")
+	}
+
+	// Исключённый участник получает изменение, после которого события в его календаре нет
+	event.Attendees = nil
+	if err := s.UpdateEvent(ctx, event); err != nil {
+		t.Fatalf("UpdateEvent failed: %v", err)
+	}
+	if err := s.DeleteEvent(ctx, "meeting", 0); err != nil {
+		t.Fatalf("DeleteEvent failed: %v", err)
+	}
+
+	changes, err := s.ListChanges(ctx, "guest", 0, 10)
+	if err != nil || len(changes) != 2 || changes[0].Type != storage.ChangeCreated || changes[1].Type != storage.ChangeUpdated {
+		t.Fatalf("unexpected guest changes: %+v, err=%v", changes, err)
+	}
+	changes, _ = s.ListChanges(ctx, "owner", changes[0].Revision, 10)
+	if len(changes) != 2 || changes[1].Type != storage.ChangeDeleted || changes[1].EventID != "meeting" {
+		t.Fatalf("unexpected owner changes: %+v", changes)
+	}
+	if last, _ := s.LastChangeRevision(ctx, "owner"); last != changes[1].Revision {
+		t.Fatalf("expected last revision %d, got %d", changes[1].Revision, last)
+	}
+	if changes, _ := s.ListChanges(ctx, "owner", 0, 1); len(changes) != 1 {
+		t.Fatalf("limit not applied: %+v", changes)
+	}
+}
+
+func TestStorageVersions(t *testing.T) {
+	s := New()
+	ctx := context.Background()
+	event := storage.Event{ID: "meeting", UserID: "owner", StartTime: 100, EndTime: 200,
+		Attendees: []storage.Attendee{{UserID: "guest", Status: storage.AttendeeNeedsAction}}}
+	if err := s.CreateEvent(ctx, event); err != nil {
+		t.Fatalf("CreateEvent failed: %v", err)
+	}
+	got, _ := s.GetEvent(ctx, "meeting")
+	if got.Version != storage.InitialVersion {
+		t.Fatalf("expected initial version, got %d", got.Version)
+	}
+
+	got.Title = "Moved"
+	if err := s.UpdateEvent(ctx, got); err != nil {
+		t.Fatalf("UpdateEvent failed: %v", err)
+	}
+	// Изменение, основанное на устаревшей версии, отклоняется
+	stale := got
+	stale.Title = "Lost"
+	if err := s.UpdateEvent(ctx, stale); !errors.Is(err, storage.ErrVersionConflict) {
+		t.Fatalf("expected storage.ErrVersionConflict, got %v", err)
+	}
+	if err := s.SetAttendeeStatus(ctx, "meeting", "guest", storage.AttendeeAccepted); err != nil {
+		t.Fatalf("SetAttendeeStatus failed: %v", err)
+	}
+	got, _ = s.GetEvent(ctx, "meeting")
+	if got.Version != 3 || got.Title != "Moved" {
+		t.Fatalf("unexpected event after writes: %+v", got)
+	}
+
+	if err := s.DeleteEvent(ctx, "meeting", 2); !errors.Is(err, storage.ErrVersionConflict) {
+		t.Fatalf("expected storage.ErrVersionConflict, got %v", err)
+	}
+	if err := s.DeleteEvent(ctx, "meeting", 3); err != nil {
+		t.Fatalf("DeleteEvent failed: %v", err)
+	}
+}
+
+func TestStorageTrash(t *testing.T) {
+	s := New()
+	ctx := context.Background()
+	series := storage.Event{ID: "series", UserID: "owner", StartTime: 100, EndTime: 200, RRule: "FREQ=DAILY",
+		Reminders: []storage.EventReminder{{Offset: 50}}}
+	override := storage.Event{ID: "override", UserID: "owner", StartTime: 1000, EndTime: 1100,
+		RecurringEventID: "series", RecurrenceID: 86500}
+	for _, e := range []storage.Event{series, override} {
+		if err := s.CreateEvent(ctx, e); err != nil {
+			t.Fatalf("CreateEvent failed: %v", err)
+		}
+	}
+
+	if err := s.TrashEvent(ctx, "series", 2, 5000); !errors.Is(err, storage.ErrVersionConflict) {
+		t.Fatalf("expected storage.ErrVersionConflict, got %v", err)
+	}
+	if err := s.TrashEvent(ctx, "series", storage.InitialVersion, 5000); err != nil {
+		t.Fatalf("TrashEvent failed: %v", err)
+	}
+	// Событие в корзине скрыто из выборок и уведомлений вместе с изменёнными экземплярами
+	if _, err := s.GetEvent(ctx, "override"); !errors.Is(err, storage.ErrNotFound) {
+		t.Fatalf("expected storage.ErrNotFound for trashed override, got %v", err)
+	}
+	if events, _ := s.ListEventsInRange(ctx, "owner", 0, 2000); len(events) != 0 {
+		t.Fatalf("trashed events must not be listed: %+v", events)
+	}
+	if events, _ := s.GetEventsForNotification(ctx, 60); len(events) != 0 {
+		t.Fatalf("trashed events must not be notified: %+v", events)
+	}
+	trash, err := s.ListTrash(ctx, "owner")
+	if err != nil || len(trash) != 1 || trash[0].ID != "series" || trash[0].DeletedAt != 5000 {
+		t.Fatalf("unexpected trash: %+v, err=%v", trash, err)
+	}
+
+	// Время изменённого экземпляра заняли, пока серия была в корзине
+	if err := s.CreateEvent(ctx, storage.Event{ID: "busy", UserID: "owner", StartTime: 1050, EndTime: 1150}); err != nil {
+		t.Fatalf("CreateEvent failed: %v", err)
+	}
+	var conflict *storage.ConflictError
+	if err := s.RestoreEvent(ctx, "series"); !errors.As(err, &conflict) || !slices.Equal(conflict.EventIDs, []string{"busy"}) {
+		t.Fatalf("expected conflict with busy, got %v", err)
+	}
+	if err := s.DeleteEvent(ctx, "busy", 0); err != nil {
+		t.Fatalf("DeleteEvent failed: %v", err)
+	}
+	if err := s.RestoreEvent(ctx, "series"); err != nil {
+		t.Fatalf("RestoreEvent failed: %v", err)
+	}
+	got, err := s.GetEvent(ctx, "override")
+	if err != nil || got.DeletedAt != 0 || got.Version != 3 {
+		t.Fatalf("unexpected restored override: %+v, err=%v", got, err)
+	}
+	if trash, _ := s.ListTrash(ctx, "owner"); len(trash) != 0 {
+		t.Fatalf("trash must be empty after restore: %+v", trash)
+	}
+
+	if err := s.TrashEvent(ctx, "series", 0, 5000); err != nil {
+		t.Fatalf("TrashEvent failed: %v", err)
+	}
+	if n, err := s.PurgeTrash(ctx, 5000); err != nil || n != 0 {
+		t.Fatalf("events trashed at the cutoff must be kept: %d, err=%v", n, err)
+	}
+	if n, err := s.PurgeTrash(ctx, 5001); err != nil || n != 1 {
+		t.Fatalf("expected 1 purged event, got %d, err=%v", n, err)
+	}
+	if _, err := s.GetTrashedEvent(ctx, "override"); !errors.Is(err, storage.ErrNotFound) {
+		t.Fatalf("override must be purged with its series, got %v", err)
+	}
+	if err := s.PurgeEvent(ctx, "series"); !errors.Is(err, storage.ErrNotFound) {
+		t.Fatalf("expected storage.ErrNotFound, got %v", err)
+	}
+}
+
+func TestStorageAudit(t *testing.T) {
+	s := New()
+	ctx := context.Background()
+	entries := []storage.AuditEntry{
+		{EventID: "e1", OwnerID: "owner", ActorID: "owner", Action: storage.AuditCreated, CreatedAt: 100},
+		{EventID: "e2", OwnerID: "owner", ActorID: "owner", Action: storage.AuditCreated, CreatedAt: 150},
+		{EventID: "e1", OwnerID: "owner", ActorID: "guest", Action: storage.AuditUpdated, CreatedAt: 200,
+			Changes: []storage.FieldChange{{Field: "attendees", Before: "guest=needs-action", After: "guest=accepted"}}},
+		{EventID: "e1", OwnerID: "owner", ActorID: "owner", Action: storage.AuditDeleted, CreatedAt: 300},
+	}
+	for _, e := range entries {
+		if err := s.AppendAudit(ctx, e); err != nil {
+			t.Fatalf("AppendAudit failed: %v", err)
+		}
+	}
+
+	history, err := s.ListEventAudit(ctx, "e1", 10)
+	if err != nil || len(history) != 3 || history[0].Seq != 4 || history[1].Seq != 3 || history[2].Seq != 1 {
+		t.Fatalf("unexpected history: %+v, err=%v", history, err)
+	}
+	if !slices.Equal(history[1].Changes, entries[2].Changes) {
+		t.Fatalf("unexpected changes: %+v", history[1].Changes)
+	}
+	if history, _ := s.ListEventAudit(ctx, "e1", 2); len(history) != 2 || history[1].Action != storage.AuditUpdated {
+		t.Fatalf("limit must keep the newest entries: %+v", history)
+	}
+	activity, err := s.ListUserAudit(ctx, "owner", 10)
+	if err != nil || len(activity) != 3 || activity[0].EventID != "e1" || activity[1].EventID != "e2" {
+		t.Fatalf("unexpected activity: %+v, err=%v", activity, err)
+	}
+
+	// Запись, переданная с изменением, сохраняется вместе с ним, а с отклонённым изменением — нет
+	event := storage.Event{ID: "e3", UserID: "owner", StartTime: 100, EndTime: 200}
+	created := storage.AuditEntry{EventID: "e3", OwnerID: "owner", ActorID: "owner", Action: storage.AuditCreated, CreatedAt: 400}
+	if err := s.CreateEvent(storage.WithAudit(ctx, created), event); err != nil {
+		t.Fatalf("CreateEvent failed: %v", err)
+	}
+	updated := storage.AuditEntry{EventID: "e3", OwnerID: "owner", ActorID: "owner", Action: storage.AuditUpdated, CreatedAt: 500}
+	event.Version = 5
+	if err := s.UpdateEvent(storage.WithAudit(ctx, updated), event); !errors.Is(err, storage.ErrVersionConflict) {
+		t.Fatalf("expected storage.ErrVersionConflict, got %v", err)
+	}
+	history, err = s.ListEventAudit(ctx, "e3", 10)
+	if err != nil || len(history) != 1 || history[0].Action != storage.AuditCreated || history[0].Seq != 5 {
+		t.Fatalf("unexpected history: %+v, err=%v", history, err)
+	}
+}
+
+func TestStorageSearch(t *testing.T) {
+	s := New()
+	ctx := context.Background()
+	events := []storage.Event{
+		{ID: "q3", UserID: "owner", StartTime: 100, EndTime: 200, Title: "Q3 budget review",
+			Description: "Discuss the budget for marketing and hiring in the third quarter."},
+		{ID: "notes", UserID: "owner", StartTime: 300, EndTime: 400, Title: "Planning",
+			Description: "Q3 goals; budget draft is attached."},
+		{ID: "other", UserID: "stranger", StartTime: 100, EndTime: 200, Title: "Q3 budget"},
+		{ID: "invited", UserID: "stranger", StartTime: 500, EndTime: 600, Title: "Budget sync, Q3",
+			Attendees: []storage.Attendee{{UserID: "owner", Status: storage.AttendeeNeedsAction}}},
+	}
+	for _, e := range events {
+		if err := s.CreateEvent(ctx, e); err != nil {
+			t.Fatalf("CreateEvent failed: %v", err)
+		}
+	}
+
+	query := storage.SearchQuery{Text: "q3 BUDGET", Limit: 10}
+	results, err := s.SearchEvents(ctx, "owner", query)
+	if err != nil {
+		t.Fatalf("SearchEvents failed: %v", err)
+	}
+	ids := make([]string, 0, len(results))
+	for _, r := range results {
+		ids = append(ids, r.Event.ID)
+	}
+	// Совпадение в заголовке важнее, чужие события без приглашения не видны
+	if !slices.Equal(ids, []string{"q3", "invited", "notes"}) {
+		t.Fatalf("unexpected results: %v", ids)
+	}
+	if results[0].Title != "<b>Q3</b> <b>budget</b> review" ||
+		results[0].Snippet != "Discuss the <b>budget</b> for marketing and hiring in the third quarter." {
+		t.Fatalf("unexpected highlight: %q, %q", results[0].Title, results[0].Snippet)
+	}
+	if results, _ := s.SearchEvents(ctx, "owner", storage.SearchQuery{Text: "budget", Limit: 1}); len(results) != 1 {
+		t.Fatalf("limit must be applied: %+v", results)
+	}
+
+	// Индекс следует за изменением и удалением событий
+	renamed := events[0]
+	renamed.Title = "Quarter review"
+	renamed.Description = ""
+	if err := s.UpdateEvent(ctx, renamed); err != nil {
+		t.Fatalf("UpdateEvent failed: %v", err)
+	}
+	if err := s.DeleteEvent(ctx, "notes", 0); err != nil {
+		t.Fatalf("DeleteEvent failed: %v", err)
+	}
+	results, _ = s.SearchEvents(ctx, "owner", query)
+	if len(results) != 1 || results[0].Event.ID != "invited" {
+		t.Fatalf("unexpected results after update: %+v", results)
+	}
+	if results, _ := s.SearchEvents(ctx, "owner", storage.SearchQuery{Text: "quarter", Limit: 10}); len(results) != 1 || results[0].Snippet != "" {
+		t.Fatalf("unexpected results for renamed event: %+v", results)
+	}
+}
+
+func TestHighlightSnippet(t *testing.T) {
+	text := "one two three four five six seven eight nine ten budget eleven twelve"
+	got := highlight(text, map[string]bool{"budget": true}, 3)
+	if got != "<b>budget</b> eleven twelve" {
+		t.Fatalf("unexpected snippet: %q", got)
+	}
+	// Фрагмент у конца текста сдвигается назад, чтобы в нём было maxWords слов
+	if got := highlight(text, map[string]bool{"ten": true}, 5); got != "nine <b>ten</b> budget eleven twelve" {
+		t.Fatalf("unexpected snippet: %q", got)
+	}
+}
+
+func TestStorageExpiredEvents(t *testing.T) {
+	s := New()
+	ctx := context.Background()
+	events := []storage.Event{
+		{ID: "a-old", UserID: "owner", StartTime: 100, EndTime: 200},
+		{ID: "b-series", UserID: "owner", StartTime: 100, EndTime: 200, RRule: "FREQ=DAILY;COUNT=2", RecurrenceEnd: 86500},
+		{ID: "c-override", UserID: "owner", StartTime: 300, EndTime: 400, RecurringEventID: "b-series", RecurrenceID: 86500},
+		{ID: "d-trashed", UserID: "owner", StartTime: 500, EndTime: 600},
+		{ID: "e-new", UserID: "owner", StartTime: 200000, EndTime: 200100},
+	}
+	for _, e := range events {
+		if err := s.CreateEvent(ctx, e); err != nil {
+			t.Fatalf("CreateEvent failed: %v", err)
+		}
+	}
+	if err := s.TrashEvent(ctx, "d-trashed", 0, 1000); err != nil {
+		t.Fatalf("TrashEvent failed: %v", err)
+	}
+
+	// Пачки по ID, включая корзину; экземпляр серии следует за серией и удаляется вместе с ней
+	first, err := s.ListExpiredEvents(ctx, 100000, "", 2)
+	if err != nil || len(first) != 3 || first[0].ID != "a-old" || first[1].ID != "b-series" || first[2].ID != "c-override" {
+		t.Fatalf("unexpected first batch: %+v, err=%v", first, err)
+	}
+	next, _ := s.ListExpiredEvents(ctx, 100000, "b-series", 10)
+	if len(next) != 1 || next[0].ID != "d-trashed" {
+		t.Fatalf("unexpected second batch: %+v", next)
+	}
+	if n, err := s.DeleteExpiredEvents(ctx, 100000, []string{"a-old", "b-series", "e-new"}); err != nil || n != 2 {
+		t.Fatalf("expected 2 deleted events, got %d, err=%v", n, err)
+	}
+	if _, err := s.GetEvent(ctx, "c-override"); !errors.Is(err, storage.ErrNotFound) {
+		t.Fatalf("override must be deleted with its series, got %v", err)
+	}
+	if n, _ := s.DeleteExpiredEvents(ctx, 100000, []string{"d-trashed"}); n != 1 {
+		t.Fatalf("trashed event must be deleted, got %d", n)
+	}
+	if left, _ := s.ListExpiredEvents(ctx, 1<<40, "", 10); len(left) != 1 || left[0].ID != "e-new" {
+		t.Fatalf("unexpected events left: %+v", left)
+	}
+}
+
+func TestStorageWebhooks(t *testing.T) {
+	s := New()
+	ctx := context.Background()
+	first := storage.Webhook{ID: "w1", UserID: "user1", URL: "https://a.example.com", Secret: "s1", CreatedAt: 100}
+	second := storage.Webhook{ID: "w2", UserID: "user1", URL: "https://b.example.com", Secret: "s2", CreatedAt: 200}
+	for _, w := range []storage.Webhook{second, first} {
+		if err := s.CreateWebhook(ctx, w); err != nil {
+			t.Fatalf("CreateWebhook failed: %v", err)
+		}
+	}
+	if err := s.CreateWebhook(ctx, storage.Webhook{ID: "w3", UserID: "user1", URL: first.URL}); !errors.Is(err, storage.ErrWebhookExists) {
+		t.Fatalf("expected ErrWebhookExists, got %v", err)
+	}
+	list, err := s.ListWebhooks(ctx, "user1")
+	if err != nil || len(list) != 2 || list[0] != first || list[1] != second {
+		t.Fatalf("unexpected webhooks: %+v, err=%v", list, err)
+	}
+	if err := s.DeleteWebhook(ctx, "w1"); err != nil {
+		t.Fatalf("DeleteWebhook failed: %v", err)
+	}
+	if _, err := s.GetWebhook(ctx, "w1"); !errors.Is(err, storage.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	// Попытки доставки учитываются в записи о напоминании
+	reminder := storage.Reminder{EventID: "e1", UserID: "user1", Title: "Standup", EventTime: 1000, NotifyBefore: 60}
+	if _, err := s.EnqueueReminder(ctx, reminder); err != nil {
+		t.Fatalf("EnqueueReminder failed: %v", err)
+	}
+	_ = s.RecordNotificationAttempt(ctx, reminder, "https://a.example.com", "")
+	_ = s.RecordNotificationAttempt(ctx, reminder, "https://b.example.com", "timeout")
+	_ = s.RecordNotificationAttempt(ctx, reminder, "https://b.example.com", "")
+	records, _ := s.ListExpiredNotifications(ctx, 2000, "", 10)
+	if len(records) != 1 || records[0].Attempts != 3 || records[0].LastError != "" || records[0].LastAttempt == 0 {
+		t.Fatalf("unexpected notification records: %+v", records)
+	}
+	// Адреса, на которые напоминание доставлено, не отправляются повторно
+	delivered, _ := s.DeliveredEndpoints(ctx, reminder)
+	if !slices.Equal(delivered, []string{"https://a.example.com", "https://b.example.com"}) {
+		t.Fatalf("unexpected delivered endpoints: %v", delivered)
+	}
+}
@@ -0,0 +1,160 @@
+package memorystorage
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sort"
+
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/storage"
+)
+
+// TrashEvent перемещает событие и его изменённые экземпляры в корзину со временем удаления deletedAt,
+// если версия события равна version (0 — без проверки), увеличивает их версии и записывает удаление
+// в ленту изменений. Возвращает storage.ErrNotFound, если события нет или оно уже в корзине,
+// и storage.ErrVersionConflict, если версия другая.
+func (s *Storage) TrashEvent(ctx context.Context, id string, version, deletedAt int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	event, ok := s.events[id]
+	if !ok {
+		return storage.NotFoundError(id)
+	}
+	if version != 0 && version != event.Version {
+		return storage.VersionConflictError(id, event.Version)
+	}
+	ids := []string{id}
+	for overrideID := range s.overrides[id] {
+		ids = append(ids, overrideID)
+	}
+	slices.Sort(ids[1:])
+	for _, id := range ids {
+		e := s.events[id]
+		s.remove(id)
+		e.DeletedAt = deletedAt
+		e.Version++
+		s.trash[id] = e
+		s.recordChange(storage.ChangeDeleted, id, e.ChangeUsers())
+	}
+	s.recordAudit(ctx)
+	return nil
+}
+
+// GetTrashedEvent возвращает событие из корзины по ID.
+// Возвращает storage.ErrNotFound, если события нет в корзине.
+func (s *Storage) GetTrashedEvent(ctx context.Context, id string) (storage.Event, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	event, ok := s.trash[id]
+	if !ok {
+		return storage.Event{}, storage.NotFoundError(id)
+	}
+	return event, nil
+}
+
+// ListTrash возвращает события пользователя в корзине без изменённых экземпляров серий,
+// от удалённых последними к удалённым первыми.
+func (s *Storage) ListTrash(ctx context.Context, userID string) ([]storage.Event, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var result []storage.Event
+	for _, e := range s.trash {
+		if e.UserID == userID && e.RecurringEventID == "" {
+			result = append(result, e)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].DeletedAt != result[j].DeletedAt {
+			return result[i].DeletedAt > result[j].DeletedAt
+		}
+		return result[i].ID < result[j].ID
+	})
+	return result, nil
+}
+
+// RestoreEvent возвращает событие и его изменённые экземпляры из корзины, увеличивает их версии
+// и записывает их создание в ленту изменений. Возвращает storage.ErrNotFound, если события нет в корзине,
+// и *storage.ConflictError, если за время нахождения в корзине его время заняли другие события.
+func (s *Storage) RestoreEvent(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	event, ok := s.trash[id]
+	if !ok {
+		return storage.NotFoundError(id)
+	}
+	restored := []storage.Event{event}
+	for _, e := range s.trash {
+		if e.RecurringEventID == id {
+			restored = append(restored, e)
+		}
+	}
+	sortEvents(restored[1:])
+
+	var conflicts []string
+	for _, e := range restored {
+		var conflict *storage.ConflictError
+		if errors.As(s.checkConflicts(e), &conflict) {
+			for _, conflictID := range conflict.EventIDs {
+				if !slices.Contains(conflicts, conflictID) {
+					conflicts = append(conflicts, conflictID)
+				}
+			}
+		}
+	}
+	if len(conflicts) > 0 {
+		return &storage.ConflictError{EventIDs: conflicts}
+	}
+	for _, e := range restored {
+		delete(s.trash, e.ID)
+		e.DeletedAt = 0
+		e.Version++
+		s.put(e)
+		s.recordChange(storage.ChangeCreated, e.ID, e.ChangeUsers())
+	}
+	s.recordAudit(ctx)
+	return nil
+}
+
+// PurgeEvent удаляет событие из корзины навсегда вместе с его изменёнными экземплярами.
+// Возвращает storage.ErrNotFound, если события нет в корзине.
+func (s *Storage) PurgeEvent(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.trash[id]; !ok {
+		return storage.NotFoundError(id)
+	}
+	s.purge(id)
+	s.recordAudit(ctx)
+	return nil
+}
+
+// PurgeTrash удаляет навсегда события, перемещённые в корзину раньше beforeTime, и возвращает их число
+// (изменённые экземпляры удаляются вместе с серией и не учитываются).
+func (s *Storage) PurgeTrash(ctx context.Context, beforeTime int64) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	purged := 0
+	for id, e := range s.trash {
+		if e.RecurringEventID == "" && e.DeletedAt < beforeTime {
+			s.purge(id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+// purge удаляет событие и его изменённые экземпляры из корзины. Вызывается под блокировкой на запись.
+func (s *Storage) purge(id string) {
+	delete(s.trash, id)
+	for overrideID, e := range s.trash {
+		if e.RecurringEventID == id {
+			delete(s.trash, overrideID)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package memorystorage
+
+import (
+	"context"
+	"sort"
+
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/storage"
+)
+
+// CreateWebhook регистрирует HTTP-адрес пользователя для напоминаний.
+// Повторная регистрация того же адреса возвращает storage.ErrWebhookExists.
+func (s *Storage) CreateWebhook(ctx context.Context, webhook storage.Webhook) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, w := range s.webhooks {
+		if w.UserID == webhook.UserID && w.URL == webhook.URL {
+			return storage.ErrWebhookExists
+		}
+	}
+	s.webhooks[webhook.ID] = webhook
+	return nil
+}
+
+// GetWebhook возвращает зарегистрированный адрес по ID.
+func (s *Storage) GetWebhook(ctx context.Context, id string) (storage.Webhook, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	w, ok := s.webhooks[id]
+	if !ok {
+		return storage.Webhook{}, storage.NotFoundError(id)
+	}
+	return w, nil
+}
+
+// ListWebhooks возвращает адреса пользователя в порядке регистрации.
+func (s *Storage) ListWebhooks(ctx context.Context, userID string) ([]storage.Webhook, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var result []storage.Webhook
+	for _, w := range s.webhooks {
+		if w.UserID == userID {
+			result = append(result, w)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].CreatedAt != result[j].CreatedAt {
+			return result[i].CreatedAt < result[j].CreatedAt
+		}
+		return result[i].ID < result[j].ID
+	})
+	return result, nil
+}
+
+// DeleteWebhook удаляет зарегистрированный адрес.
+func (s *Storage) DeleteWebhook(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.webhooks[id]; !ok {
+		return storage.NotFoundError(id)
+	}
+	delete(s.webhooks, id)
+	return nil
+}
@@ -0,0 +1,27 @@
+package storage
+
+import "errors"
+
+// Статусы записи о напоминании в таблице notifications.
+const (
+	NotificationScheduled  = "scheduled"  // напоминание записано в outbox планировщиком
+	NotificationProcessing = "processing" // напоминание отправляет рассыльщик
+	NotificationProcessed  = "processed"  // уведомление отправлено рассыльщиком
+)
+
+// ErrNotificationClaimed — напоминание отправляет другой рассыльщик, и срок его захвата не истёк.
+var ErrNotificationClaimed = errors.New("notification is claimed by another sender")
+
+// NotificationRecord — запись о напоминании в таблице notifications: по ней планировщик не ставит
+// напоминание в outbox повторно, а рассыльщик отмечает отправку.
+type NotificationRecord struct {
+	ID          string   // ID записи
+	Reminder    Reminder // напоминание
+	Status      string   // NotificationScheduled, NotificationProcessing или NotificationProcessed
+	CreatedAt   int64    // время записи (Unix timestamp)
+	ProcessedAt int64    // время отправки (Unix timestamp); 0 — ещё не отправлено
+	Attempts    int      // число попыток доставки рассыльщиком
+	LastError   string   // ошибка последней попытки доставки; пусто — попытка удалась
+	LastAttempt int64    // время последней попытки доставки (Unix timestamp); 0 — попыток не было
+	DeliveredTo []string // адреса, на которые напоминание уже доставлено (webhook с несколькими адресами)
+}
@@ -0,0 +1,11 @@
+package storage
+
+// OutboxMessage — сообщение transactional outbox: напоминание, которое нужно опубликовать в очередь.
+// Сообщение записывается в той же транзакции, что и отметка о напоминании, и публикуется отдельно,
+// поэтому сбой между записью и публикацией не теряет напоминание.
+type OutboxMessage struct {
+	ID        string   // ID сообщения
+	Reminder  Reminder // публикуемое напоминание
+	CreatedAt int64    // время записи сообщения (Unix timestamp)
+	Attempts  int      // число неудачных попыток публикации
+}
@@ -0,0 +1,33 @@
+package storage
+
+// Cursor — позиция в выборке событий, упорядоченной по времени начала и ID.
+// Нулевой курсор указывает на начало выборки.
+type Cursor struct {
+	StartTime int64  // время начала последнего прочитанного события
+	ID        string // ID последнего прочитанного события
+}
+
+// IsZero сообщает, что курсор указывает на начало выборки.
+func (c Cursor) IsZero() bool {
+	return c.ID == ""
+}
+
+// Before сообщает, что событие e следует за курсором в порядке (StartTime, ID).
+func (c Cursor) Before(e Event) bool {
+	if c.IsZero() {
+		return true
+	}
+	if c.StartTime != e.StartTime {
+		return c.StartTime < e.StartTime
+	}
+	return c.ID < e.ID
+}
+
+// EventPage — страница выборки событий пользователя за диапазон времени.
+// Постранично читаются только разовые события: повторяющиеся события разворачиваются в экземпляры
+// слоем бизнес-логики, поэтому возвращаются целиком вместе с изменёнными экземплярами.
+type EventPage struct {
+	Events []Event // разовые события после курсора, упорядоченные по (StartTime, ID)
+	Series []Event // повторяющиеся события, серия которых пересекается с диапазоном, и их изменённые экземпляры
+	Total  int     // число разовых событий в диапазоне без учёта курсора
+}
@@ -0,0 +1,13 @@
+package storage
+
+// Reminder — напоминание об экземпляре события.
+// Напоминание однозначно определяется событием, временем начала экземпляра, смещением уведомления,
+// каналом доставки и получателем: после переноса события или изменения напоминания это уже другое напоминание.
+type Reminder struct {
+	EventID      string // ID события (для экземпляра серии — ID повторяющегося события)
+	UserID       string // ID получателя: владельца события или участника, принявшего приглашение
+	Title        string // заголовок события
+	EventTime    int64  // время начала экземпляра (Unix timestamp)
+	NotifyBefore int64  // за сколько секунд до начала отправляется уведомление
+	Channel      string // канал доставки; пусто — канал по умолчанию
+}
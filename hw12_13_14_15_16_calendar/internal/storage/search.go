@@ -0,0 +1,32 @@
+package storage
+
+// SearchLanguage — конфигурация полнотекстового поиска: по ней слова запроса и событий приводятся к основе.
+type SearchLanguage string
+
+// Конфигурации поиска.
+const (
+	SearchAnyLanguage SearchLanguage = ""        // русская и английская: событие подходит, если совпадение есть в любой из них
+	SearchRussian     SearchLanguage = "russian" // русская
+	SearchEnglish     SearchLanguage = "english" // английская
+)
+
+// Метки начала и конца совпадения в SearchResult.Title и SearchResult.Snippet.
+const (
+	HighlightStart = "<b>"
+	HighlightStop  = "</b>"
+)
+
+// SearchQuery — запрос полнотекстового поиска по заголовку и описанию событий.
+type SearchQuery struct {
+	Text     string         // слова запроса; событие должно содержать их все
+	Language SearchLanguage // конфигурация поиска
+	Limit    int            // наибольшее число результатов
+}
+
+// SearchResult — событие, найденное полнотекстовым поиском.
+type SearchResult struct {
+	Event   Event
+	Rank    float64 // релевантность: чем больше, тем выше событие в результатах
+	Title   string  // заголовок с выделенными совпадениями
+	Snippet string  // фрагмент описания с выделенными совпадениями; пусто, если описания нет
+}
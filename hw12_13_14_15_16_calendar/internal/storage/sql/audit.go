@@ -0,0 +1,94 @@
+package sqlstorage
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/storage"
+	"github.com/jmoiron/sqlx"
+)
+
+// auditChange — изменение поля события в колонке changes таблицы event_audit.
+type auditChange struct {
+	Field  string `json:"field"`
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+// AppendAudit добавляет запись в журнал аудита (таблица event_audit).
+func (s *Storage) AppendAudit(ctx context.Context, entry storage.AuditEntry) error {
+	return domainError(appendAudit(ctx, s.db, entry))
+}
+
+// recordAudit записывает в рамках транзакции tx запись журнала аудита, переданную с изменением
+// (см. storage.WithAudit); без записи ничего не делает.
+func recordAudit(ctx context.Context, tx *sqlx.Tx) error {
+	entry, ok := storage.AuditFromContext(ctx)
+	if !ok {
+		return nil
+	}
+	return appendAudit(ctx, tx, entry)
+}
+
+// appendAudit добавляет запись в журнал аудита через db — базу данных или транзакцию.
+func appendAudit(ctx context.Context, db sqlx.ExecerContext, entry storage.AuditEntry) error {
+	changes := make([]auditChange, 0, len(entry.Changes))
+	for _, c := range entry.Changes {
+		changes = append(changes, auditChange(c))
+	}
+	data, err := json.Marshal(changes)
+	if err != nil {
+		return err
+	}
+	_, err = db.ExecContext(ctx, `
+		INSERT INTO event_audit (event_id, owner_id, actor_id, service, action, changes, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, entry.EventID, entry.OwnerID, entry.ActorID, entry.Service, string(entry.Action), data, entry.CreatedAt)
+	return err
+}
+
+// ListEventAudit возвращает не более limit последних записей журнала аудита события, от новых к старым.
+// Запрос использует индекс idx_event_audit_event.
+func (s *Storage) ListEventAudit(ctx context.Context, eventID string, limit int) ([]storage.AuditEntry, error) {
+	entries, err := s.queryAudit(ctx, `WHERE event_id = $1 ORDER BY seq DESC LIMIT $2`, eventID, limit)
+	if isInvalidText(err) {
+		return nil, nil
+	}
+	return entries, err
+}
+
+// ListUserAudit возвращает не более limit последних действий пользователя из журнала аудита, от новых к старым.
+// Запрос использует индекс idx_event_audit_actor.
+func (s *Storage) ListUserAudit(ctx context.Context, actorID string, limit int) ([]storage.AuditEntry, error) {
+	return s.queryAudit(ctx, `WHERE actor_id = $1 ORDER BY seq DESC LIMIT $2`, actorID, limit)
+}
+
+// queryAudit читает записи журнала аудита с условием и порядком where.
+func (s *Storage) queryAudit(ctx context.Context, where string, args ...any) ([]storage.AuditEntry, error) {
+	rows, err := s.db.QueryxContext(ctx, `
+		SELECT seq, event_id, owner_id, actor_id, service, action, changes, created_at
+		FROM event_audit `+where, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+	var entries []storage.AuditEntry
+	for rows.Next() {
+		var (
+			e       storage.AuditEntry
+			data    []byte
+			changes []auditChange
+		)
+		if err := rows.Scan(&e.Seq, &e.EventID, &e.OwnerID, &e.ActorID, &e.Service, &e.Action, &data, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, &changes); err != nil {
+			return nil, err
+		}
+		for _, c := range changes {
+			e.Changes = append(e.Changes, storage.FieldChange(c))
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
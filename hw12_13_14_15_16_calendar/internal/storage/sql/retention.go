@@ -0,0 +1,110 @@
+package sqlstorage
+
+import (
+	"context"
+
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/storage"
+	"github.com/lib/pq"
+)
+
+// expiredEvent — условие окончания события или серии раньше $1: разовое событие — по времени окончания,
+// серия — по окончании последнего экземпляра, если ни один её изменённый экземпляр не заканчивается позже
+// (бесконечная серия не заканчивается). Изменённый экземпляр отдельно не истекает: он архивируется
+// и удаляется вместе с серией.
+const expiredEvent = `(recurring_event_id IS NULL AND (
+	(rrule = '' AND end_time < $1) OR
+	(rrule <> '' AND recurrence_end < $1 AND NOT EXISTS (
+		SELECT 1 FROM events o WHERE o.recurring_event_id = events.id AND o.end_time >= $1))))`
+
+// minUUID — ID перед первым UUID: курсор afterID для первой пачки.
+const minUUID = "00000000-0000-0000-0000-000000000000"
+
+// ListExpiredEvents возвращает не более limit событий и серий, закончившихся раньше beforeTime, с ID больше
+// afterID, упорядоченных по ID. Изменённые экземпляры серии следуют сразу за ней и в limit не учитываются.
+// В выборку попадают и события в корзине.
+func (s *Storage) ListExpiredEvents(ctx context.Context, beforeTime int64, afterID string, limit int) ([]storage.Event, error) {
+	if afterID == "" {
+		afterID = minUUID
+	}
+	return s.queryEvents(ctx, `
+		WITH expired AS (
+			SELECT id FROM events
+			WHERE `+expiredEvent+` AND id > $2::uuid
+			ORDER BY id
+			LIMIT $3
+		)
+		SELECT `+eventColumns+`
+		FROM events
+		WHERE id IN (SELECT id FROM expired) OR recurring_event_id IN (SELECT id FROM expired)
+		ORDER BY COALESCE(recurring_event_id, id), recurring_event_id IS NOT NULL, id
+	`, beforeTime, afterID, limit)
+}
+
+// DeleteExpiredEvents удаляет навсегда события и серии из ids, которые по-прежнему закончились раньше beforeTime,
+// вместе с изменёнными экземплярами серий (ON DELETE CASCADE) и возвращает число удалённых событий из ids.
+// Удаление в ленту изменений не попадает.
+func (s *Storage) DeleteExpiredEvents(ctx context.Context, beforeTime int64, ids []string) (int, error) {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM events WHERE `+expiredEvent+` AND id = ANY($2::uuid[])`,
+		beforeTime, pq.Array(ids))
+	if err != nil {
+		return 0, err
+	}
+	cnt, err := res.RowsAffected()
+	return int(cnt), err
+}
+
+// ListExpiredNotifications возвращает не более limit записей о напоминаниях на экземпляры, начавшиеся
+// раньше beforeTime, с ID больше afterID, упорядоченных по ID. Запрос использует индекс idx_notifications_event_time.
+func (s *Storage) ListExpiredNotifications(ctx context.Context, beforeTime int64, afterID string, limit int) ([]storage.NotificationRecord, error) {
+	if afterID == "" {
+		afterID = minUUID
+	}
+	rows, err := s.db.QueryxContext(ctx, `
+		SELECT id, event_id, user_id, title, event_time, notify_before, channel, status, created_at,
+		       COALESCE(processed_at, 0), attempts, last_error, COALESCE(last_attempt_at, 0), delivered_to
+		FROM notifications
+		WHERE event_time < $1 AND id > $2::uuid
+		ORDER BY id
+		LIMIT $3
+	`, beforeTime, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+	var records []storage.NotificationRecord
+	for rows.Next() {
+		var (
+			n         storage.NotificationRecord
+			delivered pq.StringArray
+		)
+		if err := rows.Scan(&n.ID, &n.Reminder.EventID, &n.Reminder.UserID, &n.Reminder.Title, &n.Reminder.EventTime,
+			&n.Reminder.NotifyBefore, &n.Reminder.Channel, &n.Status, &n.CreatedAt, &n.ProcessedAt,
+			&n.Attempts, &n.LastError, &n.LastAttempt, &delivered); err != nil {
+			return nil, err
+		}
+		n.DeliveredTo = delivered
+		records = append(records, n)
+	}
+	return records, rows.Err()
+}
+
+// DeleteExpiredNotifications удаляет записи о напоминаниях из ids на экземпляры, начавшиеся раньше beforeTime,
+// и возвращает их число.
+func (s *Storage) DeleteExpiredNotifications(ctx context.Context, beforeTime int64, ids []string) (int, error) {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM notifications WHERE event_time < $1 AND id = ANY($2::uuid[])`,
+		beforeTime, pq.Array(ids))
+	if err != nil {
+		return 0, err
+	}
+	cnt, err := res.RowsAffected()
+	return int(cnt), err
+}
+
+// DeleteOldChanges удаляет изменения из ленты изменений и отправленные сообщения outbox, записанные раньше beforeTime.
+func (s *Storage) DeleteOldChanges(ctx context.Context, beforeTime int64) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM outbox WHERE sent_at < $1`, beforeTime); err != nil {
+		return err
+	}
+	_, err := s.db.ExecContext(ctx, `DELETE FROM event_changes WHERE changed_at < $1`, beforeTime)
+	return err
+}
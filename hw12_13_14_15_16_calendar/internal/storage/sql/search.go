@@ -0,0 +1,96 @@
+package sqlstorage
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/storage"
+)
+
+// searchColumns — колонки таблицы events с векторами полнотекстового поиска по конфигурациям.
+var searchColumns = map[storage.SearchLanguage]string{
+	storage.SearchRussian: "search_ru",
+	storage.SearchEnglish: "search_en",
+}
+
+// Параметры ts_headline для заголовка (выделяется целиком) и фрагмента описания.
+var (
+	titleHeadline   = fmt.Sprintf("HighlightAll=true, StartSel=%s, StopSel=%s", storage.HighlightStart, storage.HighlightStop)
+	snippetHeadline = fmt.Sprintf("MaxWords=20, MinWords=10, StartSel=%s, StopSel=%s", storage.HighlightStart, storage.HighlightStop)
+)
+
+// SearchEvents ищет видимые пользователю события, заголовок или описание которых соответствуют запросу
+// (синтаксис websearch_to_tsquery: все слова, "фраза", or, -слово). Без конфигурации событие подходит,
+// если совпадение есть в русской или английской конфигурации. Результаты упорядочены по ts_rank_cd,
+// затем по времени начала и ID; запрос использует индексы idx_events_search_ru и idx_events_search_en.
+func (s *Storage) SearchEvents(ctx context.Context, userID string, query storage.SearchQuery) ([]storage.SearchResult, error) {
+	languages := []storage.SearchLanguage{storage.SearchRussian, storage.SearchEnglish}
+	if query.Language != storage.SearchAnyLanguage {
+		languages = []storage.SearchLanguage{query.Language}
+	}
+	matches := make([]string, 0, len(languages))
+	ranks := make([]string, 0, len(languages))
+	for _, lang := range languages {
+		column, ok := searchColumns[lang]
+		if !ok {
+			return nil, fmt.Errorf("unknown search language %q", lang)
+		}
+		tsquery := fmt.Sprintf("websearch_to_tsquery('%s', $2)", lang)
+		matches = append(matches, column+" @@ "+tsquery)
+		ranks = append(ranks, fmt.Sprintf("ts_rank_cd(%s, %s)", column, tsquery))
+	}
+	// Выделение совпадений дорогое, поэтому ts_headline вызывается только для отобранных событий
+	headlineQuery := fmt.Sprintf("'%s', $2", languages[0])
+	rows, err := s.db.QueryxContext(ctx, `
+		SELECT `+eventColumns+`, rank,
+			ts_headline('`+string(languages[0])+`', title, websearch_to_tsquery(`+headlineQuery+`), '`+titleHeadline+`'),
+			ts_headline('`+string(languages[0])+`', coalesce(description, ''), websearch_to_tsquery(`+headlineQuery+`), '`+snippetHeadline+`')
+		FROM (
+			SELECT `+eventColumns+`, GREATEST(`+strings.Join(ranks, ", ")+`) AS rank
+			FROM events
+			WHERE `+visibleToUser+` AND (`+strings.Join(matches, " OR ")+`)
+			ORDER BY rank DESC, start_time, id
+			LIMIT $3
+		) found
+		ORDER BY rank DESC, start_time, id
+	`, userID, query.Text, query.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var results []storage.SearchResult
+	for rows.Next() {
+		var r storage.SearchResult
+		r.Event, err = scanEvent(scanFunc(func(dest ...any) error {
+			return rows.Scan(append(dest, &r.Rank, &r.Title, &r.Snippet)...)
+		}))
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	events := make([]storage.Event, len(results))
+	for i, r := range results {
+		events[i] = r.Event
+	}
+	if err := s.attachDetails(ctx, events); err != nil {
+		return nil, err
+	}
+	for i := range results {
+		results[i].Event = events[i]
+	}
+	return results, nil
+}
+
+// scanFunc позволяет передать в scanEvent строку результата с дополнительными колонками после eventColumns.
+type scanFunc func(dest ...any) error
+
+// Scan вызывает f.
+func (f scanFunc) Scan(dest ...any) error {
+	return f(dest...)
+}
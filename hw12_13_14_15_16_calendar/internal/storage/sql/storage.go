@@ -6,17 +6,20 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"slices"
+	"time"
 
 	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/storage"
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 )
 
-// Ошибки, специфичные для SQL хранилища
+// Ошибки SQL хранилища — общие ошибки хранилища, оставлены для совместимости.
 var (
-	ErrNotFound   = errors.New("event not found")  // событие не найдено
-	ErrValidation = errors.New("validation error") // ошибка валидации
+	ErrNotFound   = storage.ErrNotFound   // событие не найдено
+	ErrValidation = storage.ErrValidation // ошибка валидации
 )
 
 // Storage представляет PostgreSQL хранилище событий
@@ -43,133 +46,843 @@ func (s *Storage) Close(ctx context.Context) error {
 	return s.db.Close()
 }
 
-// CreateEvent создает новое событие в базе данных.
-// Автоматически генерирует UUID, если ID не указан.
+// eventColumns — список колонок таблицы events в порядке, ожидаемом scanEvent.
+// Напоминания и участники хранятся в таблицах event_reminders и event_attendees
+// и загружаются отдельными запросами (см. attachDetails).
+// События в корзине (deleted_at IS NOT NULL) читаются только методами корзины.
+const eventColumns = `id, title, description, user_id, start_time, end_time,
+	rrule, exdates, recurrence_end, recurring_event_id, recurrence_id, version, deleted_at, time_zone`
+
+// CreateEvent создает новое событие в базе данных с версией storage.InitialVersion вместе с его
+// напоминаниями и участниками и записывает изменение в ленту изменений. Автоматически генерирует UUID, если ID не указан.
 func (s *Storage) CreateEvent(ctx context.Context, event storage.Event) error {
 	if event.ID == "" {
 		event.ID = uuid.New().String()
 	}
-	_, err := s.db.ExecContext(ctx, `INSERT INTO events (id, title, description, user_id, start_time, end_time, notify_before) VALUES ($1, $2, $3, $4, $5, $6, $7)`,
-		event.ID, event.Title, event.Description, event.UserID, event.StartTime, event.EndTime, event.NotifyBefore)
-	return err
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := recordChange(ctx, tx, storage.ChangeCreated, event); err != nil {
+		return domainError(err)
+	}
+	_, err = tx.ExecContext(ctx, `INSERT INTO events (`+eventColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, NULL, $13)`,
+		event.ID, event.Title, event.Description, event.UserID, event.StartTime, event.EndTime,
+		event.RRule, pq.Array(exDates(event)), nullInt64(event.RecurrenceEnd),
+		nullString(event.RecurringEventID), nullInt64(event.RecurrenceID), storage.InitialVersion, event.TimeZone)
+	if isExclusionViolation(err) {
+		return s.conflictError(ctx, event)
+	}
+	if err != nil {
+		return domainError(err)
+	}
+	if err := saveDetails(ctx, tx, event); err != nil {
+		return domainError(err)
+	}
+	if err := recordAudit(ctx, tx); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
-// UpdateEvent обновляет существующее событие в базе данных.
-// Возвращает ErrNotFound, если событие не найдено.
+// UpdateEvent обновляет существующее событие в базе данных, увеличивает его версию, заменяет
+// напоминания и участников и записывает изменение в ленту изменений. Если event.Version не 0, событие
+// обновляется, только если его версия не изменилась (проверка и запись — одним UPDATE).
+// Возвращает storage.ErrNotFound, если событие не найдено, и storage.ErrVersionConflict, если версия другая.
 func (s *Storage) UpdateEvent(ctx context.Context, event storage.Event) error {
-	res, err := s.db.ExecContext(ctx, `UPDATE events SET title=$1, description=$2, user_id=$3, start_time=$4, end_time=$5, notify_before=$6 WHERE id=$7`,
-		event.Title, event.Description, event.UserID, event.StartTime, event.EndTime, event.NotifyBefore, event.ID)
+	tx, err := s.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return err
 	}
+	defer func() { _ = tx.Rollback() }()
+
+	// Изменение записывается до обновления, чтобы в нём остались и прежние участники события
+	if err := recordChange(ctx, tx, storage.ChangeUpdated, event); err != nil {
+		if isInvalidText(err) {
+			return storage.NotFoundError(event.ID)
+		}
+		return domainError(err)
+	}
+
+	res, err := tx.ExecContext(ctx, `UPDATE events SET title=$1, description=$2, user_id=$3, start_time=$4, end_time=$5,
+		rrule=$6, exdates=$7, recurrence_end=$8, recurring_event_id=$9, recurrence_id=$10, time_zone=$13, version=version+1
+		WHERE id=$11 AND deleted_at IS NULL AND ($12::bigint = 0 OR version = $12)`,
+		event.Title, event.Description, event.UserID, event.StartTime, event.EndTime,
+		event.RRule, pq.Array(exDates(event)), nullInt64(event.RecurrenceEnd),
+		nullString(event.RecurringEventID), nullInt64(event.RecurrenceID), event.ID, event.Version, event.TimeZone)
+	if isExclusionViolation(err) {
+		return s.conflictError(ctx, event)
+	}
+	if isInvalidText(err) {
+		return storage.NotFoundError(event.ID)
+	}
+	if err != nil {
+		return domainError(err)
+	}
 	cnt, _ := res.RowsAffected()
 	if cnt == 0 {
-		return ErrNotFound
+		return versionError(ctx, tx, event.ID)
 	}
-	return nil
+	if err := saveDetails(ctx, tx, event); err != nil {
+		return domainError(err)
+	}
+	if err := recordAudit(ctx, tx); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
-// DeleteEvent удаляет событие по ID из базы данных.
-// Возвращает ErrNotFound, если событие не найдено.
-func (s *Storage) DeleteEvent(ctx context.Context, id string) error {
-	res, err := s.db.ExecContext(ctx, `DELETE FROM events WHERE id=$1`, id)
+// DeleteEvent удаляет событие по ID из базы данных навсегда, минуя корзину, если его версия равна
+// version (0 — без проверки), и записывает изменение в ленту изменений.
+// Возвращает storage.ErrNotFound, если событие не найдено, и storage.ErrVersionConflict, если версия другая.
+func (s *Storage) DeleteEvent(ctx context.Context, id string, version int64) error {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	err = recordChange(ctx, tx, storage.ChangeDeleted, storage.Event{ID: id})
+	if isInvalidText(err) {
+		return storage.NotFoundError(id)
+	}
+	if err != nil {
+		return err
+	}
+	res, err := tx.ExecContext(ctx, `DELETE FROM events WHERE id=$1 AND deleted_at IS NULL AND ($2::bigint = 0 OR version = $2)`, id, version)
 	if err != nil {
 		return err
 	}
 	cnt, _ := res.RowsAffected()
 	if cnt == 0 {
-		return ErrNotFound
+		return versionError(ctx, tx, id)
 	}
-	return nil
+	if err := recordAudit(ctx, tx); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
 // GetEvent возвращает событие по ID из базы данных.
-// Возвращает ErrNotFound, если событие не найдено.
-// Обрабатывает nullable поле notify_before.
+// Возвращает storage.ErrNotFound, если событие не найдено или находится в корзине.
 func (s *Storage) GetEvent(ctx context.Context, id string) (storage.Event, error) {
-	var e storage.Event
-	row := s.db.QueryRowxContext(ctx, `SELECT id, title, description, user_id, start_time, end_time, notify_before FROM events WHERE id=$1`, id)
-	var notifyBefore sql.NullInt64
-	if err := row.Scan(&e.ID, &e.Title, &e.Description, &e.UserID, &e.StartTime, &e.EndTime, &notifyBefore); err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return e, ErrNotFound
-		}
+	return s.getEvent(ctx, `SELECT `+eventColumns+` FROM events WHERE id=$1 AND deleted_at IS NULL`, id)
+}
+
+// getEvent выполняет запрос одного события по ID и загружает его напоминания и участников.
+func (s *Storage) getEvent(ctx context.Context, query, id string) (storage.Event, error) {
+	row := s.db.QueryRowxContext(ctx, query, id)
+	e, err := scanEvent(row)
+	if errors.Is(err, sql.ErrNoRows) || isInvalidText(err) {
+		return storage.Event{}, storage.NotFoundError(id)
+	}
+	if err != nil {
 		return e, err
 	}
-	if notifyBefore.Valid {
-		e.NotifyBefore = &notifyBefore.Int64
+	events := []storage.Event{e}
+	if err := s.attachDetails(ctx, events); err != nil {
+		return e, err
 	}
-	return e, nil
+	return events[0], nil
 }
 
-// ListEvents возвращает все события указанного пользователя.
-// Обрабатывает nullable поле notify_before для каждого события.
+// ListEvents возвращает все события указанного пользователя, отсортированные по времени начала и ID.
 func (s *Storage) ListEvents(ctx context.Context, userID string) ([]storage.Event, error) {
-	var events []storage.Event
-	rows, err := s.db.QueryxContext(ctx, `SELECT id, title, description, user_id, start_time, end_time, notify_before FROM events WHERE user_id=$1`, userID)
+	return s.queryEvents(ctx, `SELECT `+eventColumns+` FROM events WHERE user_id=$1 AND deleted_at IS NULL
+		ORDER BY start_time, id`, userID)
+}
+
+// ListEventsInRange возвращает события пользователя, пересекающиеся с диапазоном [start, end),
+// повторяющиеся события, серия которых пересекается с диапазоном, и все изменённые экземпляры
+// таких серий. Событие нулевой длительности попадает в диапазон, если начинается внутри него.
+// Результат отсортирован по времени начала. Запрос использует индекс idx_events_user_start_end.
+func (s *Storage) ListEventsInRange(ctx context.Context, userID string, start, end int64) ([]storage.Event, error) {
+	return s.queryEvents(ctx, `
+		WITH series AS (
+			SELECT id FROM events
+			WHERE user_id = $1 AND rrule <> '' AND deleted_at IS NULL
+			  AND start_time < $3
+			  AND (recurrence_end IS NULL OR recurrence_end >= $2)
+		)
+		SELECT `+eventColumns+`
+		FROM events
+		WHERE user_id = $1 AND deleted_at IS NULL
+		  AND (
+		    (rrule = '' AND start_time < $3
+		      AND (end_time > $2 OR (end_time = start_time AND start_time >= $2)))
+		    OR id IN (SELECT id FROM series)
+		    OR recurring_event_id IN (SELECT id FROM series)
+		  )
+		ORDER BY start_time, id
+	`, userID, start, end)
+}
+
+// visibleToUser — условие видимости события пользователю $1: событие не в корзине, а пользователь —
+// его владелец или участник, не отказавшийся от приглашения.
+const visibleToUser = `deleted_at IS NULL AND (user_id = $1 OR id IN (
+	SELECT event_id FROM event_attendees WHERE user_id = $1 AND status <> 'declined'))`
+
+// singleInRange — условие попадания видимого пользователю разового события (не экземпляра серии) в диапазон [$2, $3).
+const singleInRange = visibleToUser + ` AND rrule = '' AND recurring_event_id IS NULL
+	AND start_time < $3 AND (end_time > $2 OR (end_time = start_time AND start_time >= $2))`
+
+// ListEventsPage возвращает страницу событий пользователя за диапазон [start, end): не более limit
+// разовых событий после курсора after, а также повторяющиеся события, серия которых пересекается
+// с диапазоном, вместе с их изменёнными экземплярами. Условия попадания в диапазон — как в ListEventsInRange.
+// В выборку попадают события пользователя и события, в которых он участвует и не отказался от приглашения.
+// Разовые события читаются по ключу (start_time, id) с использованием индекса idx_events_user_start_id.
+func (s *Storage) ListEventsPage(ctx context.Context, userID string, start, end int64, after storage.Cursor, limit int) (storage.EventPage, error) {
+	var page storage.EventPage
+	if err := s.db.GetContext(ctx, &page.Total,
+		`SELECT count(*) FROM events WHERE `+singleInRange, userID, start, end); err != nil {
+		return page, err
+	}
+
+	var err error
+	if after.IsZero() {
+		page.Events, err = s.queryEvents(ctx, `SELECT `+eventColumns+` FROM events WHERE `+singleInRange+`
+			ORDER BY start_time, id LIMIT $4`, userID, start, end, limit)
+	} else {
+		page.Events, err = s.queryEvents(ctx, `SELECT `+eventColumns+` FROM events WHERE `+singleInRange+`
+			AND (start_time, id) > ($4, $5::uuid)
+			ORDER BY start_time, id LIMIT $6`, userID, start, end, after.StartTime, after.ID, limit)
+	}
+	if err != nil {
+		return page, err
+	}
+
+	page.Series, err = s.queryEvents(ctx, `
+		WITH series AS (
+			SELECT id FROM events
+			WHERE `+visibleToUser+` AND rrule <> ''
+			  AND start_time < $3
+			  AND (recurrence_end IS NULL OR recurrence_end >= $2)
+		)
+		SELECT `+eventColumns+`
+		FROM events
+		WHERE (id IN (SELECT id FROM series) OR recurring_event_id IN (SELECT id FROM series))
+		  AND deleted_at IS NULL
+		ORDER BY start_time, id
+	`, userID, start, end)
+	return page, err
+}
+
+// SetAttendeeStatus сохраняет ответ участника на приглашение в событие и его изменённые экземпляры,
+// увеличивает их версии и записывает их изменение в ленту изменений.
+// Возвращает storage.ErrNotFound, если события нет (или оно в корзине) или пользователь в нём не участвует.
+func (s *Storage) SetAttendeeStatus(ctx context.Context, eventID, userID string, status storage.AttendeeStatus) error {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	res, err := tx.ExecContext(ctx, `
+		UPDATE event_attendees SET status = $3
+		WHERE user_id = $2
+		  AND event_id IN (SELECT id FROM events WHERE (id = $1 OR recurring_event_id = $1) AND deleted_at IS NULL)
+	`, eventID, userID, status)
+	if isInvalidText(err) {
+		return storage.NotFoundError(eventID)
+	}
+	if err != nil {
+		return err
+	}
+	if cnt, _ := res.RowsAffected(); cnt == 0 {
+		return storage.NotFoundError(eventID)
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE events SET version = version + 1 WHERE id = $1 OR recurring_event_id = $1`,
+		eventID); err != nil {
+		return err
+	}
+	if err := recordSeriesChange(ctx, tx, storage.ChangeUpdated, eventID); err != nil {
+		return err
+	}
+	if err := recordAudit(ctx, tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ListAttendedEvents возвращает события, в которых участвует пользователь (с любым ответом),
+// без изменённых экземпляров серий, отсортированные по времени начала и ID.
+func (s *Storage) ListAttendedEvents(ctx context.Context, userID string) ([]storage.Event, error) {
+	return s.queryEvents(ctx, `
+		SELECT `+eventColumns+`
+		FROM events
+		WHERE recurring_event_id IS NULL AND deleted_at IS NULL
+		  AND id IN (SELECT event_id FROM event_attendees WHERE user_id = $1)
+		ORDER BY start_time, id
+	`, userID)
+}
+
+// GetEventsForNotification возвращает события, требующие уведомления.
+// Событие требует уведомления, если оно не в корзине и наступило время хотя бы одного из его напоминаний:
+// (start_time - offset_seconds) <= current_time < start_time.
+// Повторяющиеся события возвращаются целиком, если серия ещё не закончилась:
+// выбор конкретных экземпляров и напоминаний выполняет слой бизнес-логики.
+func (s *Storage) GetEventsForNotification(ctx context.Context, currentTime int64) ([]storage.Event, error) {
+	query := `
+		SELECT ` + eventColumns + `
+		FROM events e
+		WHERE deleted_at IS NULL
+		  AND EXISTS (
+		    SELECT 1 FROM event_reminders r
+		    WHERE r.event_id = e.id AND e.start_time - r.offset_seconds <= $1
+		  )
+		  AND (
+		    (rrule = '' AND start_time > $1)
+		    OR (rrule <> '' AND (recurrence_end IS NULL OR recurrence_end > $1))
+		  )
+		ORDER BY start_time ASC
+	`
+	return s.queryEvents(ctx, query, currentTime)
+}
+
+// lockChanges упорядочивает запись в ленту изменений пользователей userIDs: транзакционные
+// advisory-блокировки по ключу каждого пользователя держатся до конца транзакции tx, поэтому ревизии
+// изменений одного пользователя фиксируются в порядке выдачи и подписчик, прочитавший ревизию N,
+// не пропустит зафиксированное позже изменение с меньшей ревизией. Изменения разных пользователей
+// друг друга не ждут. Блокировки берутся в порядке ID пользователей, чтобы транзакции не ждали друг друга по кругу.
+func lockChanges(ctx context.Context, tx *sqlx.Tx, userIDs []string) error {
+	userIDs = slices.Sorted(slices.Values(userIDs))
+	for _, userID := range slices.Compact(userIDs) {
+		_, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtextextended('event_changes:' || $1, 0))`, userID)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recordChange записывает изменение события в ленту изменений в рамках транзакции tx.
+// Получатели — владелец и участники event, а также владелец и участники, сохранённые в базе на момент вызова.
+func recordChange(ctx context.Context, tx *sqlx.Tx, typ storage.ChangeType, event storage.Event) error {
+	var users pq.StringArray
+	err := tx.QueryRowxContext(ctx, `
+		SELECT COALESCE(array_agg(DISTINCT u ORDER BY u), ARRAY[]::text[])
+		FROM unnest($2::text[]
+		    || ARRAY(SELECT user_id FROM events WHERE id = $1::uuid)
+		    || ARRAY(SELECT user_id FROM event_attendees WHERE event_id = $1::uuid)) AS u
+		WHERE u <> ''
+	`, event.ID, pq.Array(event.ChangeUsers())).Scan(&users)
+	if err != nil {
+		return err
+	}
+	if err := lockChanges(ctx, tx, users); err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO event_changes (event_id, change_type, user_ids, changed_at)
+		VALUES ($1, $2, $3, $4)
+	`, event.ID, string(typ), users, time.Now().Unix())
+	return err
+}
+
+// recordSeriesChange записывает в ленту изменений изменение события eventID и всех его изменённых
+// экземпляров в рамках транзакции tx. Получатели — владелец и участники, сохранённые в базе на момент вызова.
+func recordSeriesChange(ctx context.Context, tx *sqlx.Tx, typ storage.ChangeType, eventID string) error {
+	var users []string
+	err := tx.SelectContext(ctx, &users, `
+		SELECT e.user_id FROM events e WHERE e.id = $1 OR e.recurring_event_id = $1
+		UNION
+		SELECT a.user_id FROM event_attendees a JOIN events e ON e.id = a.event_id
+		WHERE e.id = $1 OR e.recurring_event_id = $1
+	`, eventID)
+	if err != nil {
+		return err
+	}
+	if err := lockChanges(ctx, tx, users); err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO event_changes (event_id, change_type, user_ids, changed_at)
+		SELECT e.id, $2,
+		       ARRAY(SELECT e.user_id UNION SELECT a.user_id FROM event_attendees a WHERE a.event_id = e.id ORDER BY 1), $3
+		FROM events e
+		WHERE e.id = $1 OR e.recurring_event_id = $1
+		ORDER BY e.start_time, e.id
+	`, eventID, string(typ), time.Now().Unix())
+	return err
+}
+
+// ListChanges возвращает не более limit изменений, касающихся пользователя, с ревизией больше after,
+// в порядке ревизий. Запрос использует индекс idx_event_changes_users.
+func (s *Storage) ListChanges(ctx context.Context, userID string, after int64, limit int) ([]storage.Change, error) {
+	rows, err := s.db.QueryxContext(ctx, `
+		SELECT revision, change_type, event_id, user_ids, changed_at
+		FROM event_changes
+		WHERE user_ids @> ARRAY[$1::text] AND revision > $2
+		ORDER BY revision
+		LIMIT $3
+	`, userID, after, limit)
 	if err != nil {
 		return nil, err
 	}
 	defer func() { _ = rows.Close() }()
+	var changes []storage.Change
 	for rows.Next() {
-		var e storage.Event
-		var notifyBefore sql.NullInt64
-		if err := rows.Scan(&e.ID, &e.Title, &e.Description, &e.UserID, &e.StartTime, &e.EndTime, &notifyBefore); err != nil {
+		var (
+			c     storage.Change
+			users pq.StringArray
+		)
+		if err := rows.Scan(&c.Revision, &c.Type, &c.EventID, &users, &c.ChangedAt); err != nil {
 			return nil, err
 		}
-		if notifyBefore.Valid {
-			e.NotifyBefore = &notifyBefore.Int64
+		c.UserIDs = users
+		changes = append(changes, c)
+	}
+	return changes, rows.Err()
+}
+
+// LastChangeRevision возвращает ревизию последнего записанного изменения, касающегося пользователя;
+// 0 — таких изменений нет. Изменения пользователя упорядочены (см. lockChanges), поэтому после этой
+// ревизии подписчик получит все изменения, зафиксированные позже. Ревизия изменений других
+// пользователей для этого не годится: их транзакции не ждут транзакций пользователя.
+func (s *Storage) LastChangeRevision(ctx context.Context, userID string) (int64, error) {
+	var revision int64
+	err := s.db.GetContext(ctx, &revision,
+		`SELECT COALESCE(max(revision), 0) FROM event_changes WHERE user_ids @> ARRAY[$1::text]`, userID)
+	return revision, err
+}
+
+// EnqueueReminder в одной транзакции отмечает напоминание как запланированное (запись со статусом
+// scheduled в таблице notifications) и записывает его в outbox. Уникальный индекс idx_notifications_reminder
+// гарантирует, что напоминание попадёт в outbox один раз даже при нескольких планировщиках.
+// Возвращает false, если напоминание уже было запланировано.
+func (s *Storage) EnqueueReminder(ctx context.Context, reminder storage.Reminder) (bool, error) {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	res, err := tx.ExecContext(ctx, `
+		INSERT INTO notifications (id, event_id, user_id, title, event_time, notify_before, channel, status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, 'scheduled')
+		ON CONFLICT (event_id, event_time, notify_before, channel, user_id) DO NOTHING
+	`, uuid.New().String(), reminder.EventID, reminder.UserID, reminder.Title, reminder.EventTime,
+		reminder.NotifyBefore, reminder.Channel)
+	if err != nil {
+		return false, err
+	}
+	if cnt, _ := res.RowsAffected(); cnt == 0 {
+		return false, nil
+	}
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO outbox (id, event_id, user_id, title, event_time, notify_before, channel, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`, uuid.New().String(), reminder.EventID, reminder.UserID, reminder.Title, reminder.EventTime,
+		reminder.NotifyBefore, reminder.Channel, time.Now().Unix()); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+// RecordNotificationAttempt учитывает попытку доставки напоминания на адрес endpoint с ошибкой attemptErr
+// (пусто — попытка удалась, адрес добавляется в delivered_to). Если записи о напоминании нет, она создаётся
+// со статусом storage.NotificationScheduled.
+func (s *Storage) RecordNotificationAttempt(ctx context.Context, reminder storage.Reminder, endpoint, attemptErr string) error {
+	var delivered []string
+	if attemptErr == "" {
+		delivered = []string{endpoint}
+	}
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO notifications (id, event_id, user_id, title, event_time, notify_before, channel, status,
+			attempts, last_error, last_attempt_at, delivered_to)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, 'scheduled', 1, $8, $9, $10::text[])
+		ON CONFLICT (event_id, event_time, notify_before, channel, user_id)
+		DO UPDATE SET attempts = notifications.attempts + 1, last_error = EXCLUDED.last_error,
+			last_attempt_at = EXCLUDED.last_attempt_at,
+			delivered_to = notifications.delivered_to || ARRAY(
+				SELECT unnest(EXCLUDED.delivered_to) EXCEPT SELECT unnest(notifications.delivered_to))
+	`, uuid.New().String(), reminder.EventID, reminder.UserID, reminder.Title, reminder.EventTime,
+		reminder.NotifyBefore, reminder.Channel, attemptErr, time.Now().Unix(), pq.Array(delivered))
+	return err
+}
+
+// DeliveredEndpoints возвращает адреса, на которые напоминание уже доставлено.
+func (s *Storage) DeliveredEndpoints(ctx context.Context, reminder storage.Reminder) ([]string, error) {
+	var delivered pq.StringArray
+	err := s.db.GetContext(ctx, &delivered, `
+		SELECT delivered_to FROM notifications
+		WHERE event_id = $1 AND event_time = $2 AND notify_before = $3 AND channel = $4 AND user_id = $5
+	`, reminder.EventID, reminder.EventTime, reminder.NotifyBefore, reminder.Channel, reminder.UserID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return delivered, err
+}
+
+// ClaimNotification захватывает напоминание для отправки: запись о нём переводится в статус
+// storage.NotificationProcessing (если записи нет, она создаётся). Захват действует lease: если рассыльщик
+// не завершил отправку за это время (например, упал), напоминание может захватить другой.
+// Возвращает false, если напоминание уже отправлено, и storage.ErrNotificationClaimed, если его
+// отправляет другой рассыльщик.
+func (s *Storage) ClaimNotification(ctx context.Context, reminder storage.Reminder, lease time.Duration) (bool, error) {
+	now := time.Now().Unix()
+	res, err := s.db.ExecContext(ctx, `
+		INSERT INTO notifications (id, event_id, user_id, title, event_time, notify_before, channel, status, claimed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, 'processing', $8)
+		ON CONFLICT (event_id, event_time, notify_before, channel, user_id)
+		DO UPDATE SET status = 'processing', claimed_at = EXCLUDED.claimed_at
+		WHERE notifications.status <> 'processed'
+		  AND (notifications.status <> 'processing' OR notifications.claimed_at IS NULL OR notifications.claimed_at <= $9)
+	`, uuid.New().String(), reminder.EventID, reminder.UserID, reminder.Title, reminder.EventTime,
+		reminder.NotifyBefore, reminder.Channel, now, now-int64(lease/time.Second))
+	if err != nil {
+		return false, err
+	}
+	if cnt, _ := res.RowsAffected(); cnt > 0 {
+		return true, nil
+	}
+	var status string
+	err = s.db.GetContext(ctx, &status, `
+		SELECT status FROM notifications
+		WHERE event_id = $1 AND event_time = $2 AND notify_before = $3 AND channel = $4 AND user_id = $5
+	`, reminder.EventID, reminder.EventTime, reminder.NotifyBefore, reminder.Channel, reminder.UserID)
+	if err != nil {
+		return false, err
+	}
+	if status == storage.NotificationProcessed {
+		return false, nil
+	}
+	return false, storage.ErrNotificationClaimed
+}
+
+// CompleteNotification отмечает захваченное напоминание отправленным.
+func (s *Storage) CompleteNotification(ctx context.Context, reminder storage.Reminder) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE notifications SET status = 'processed', processed_at = $6, claimed_at = NULL
+		WHERE event_id = $1 AND event_time = $2 AND notify_before = $3 AND channel = $4 AND user_id = $5
+	`, reminder.EventID, reminder.EventTime, reminder.NotifyBefore, reminder.Channel, reminder.UserID, time.Now().Unix())
+	return err
+}
+
+// ReleaseNotification снимает захват напоминания, которое не удалось отправить, чтобы повторная
+// обработка сообщения могла захватить его снова.
+func (s *Storage) ReleaseNotification(ctx context.Context, reminder storage.Reminder) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE notifications SET status = 'scheduled', claimed_at = NULL
+		WHERE event_id = $1 AND event_time = $2 AND notify_before = $3 AND channel = $4 AND user_id = $5
+		  AND status = 'processing'
+	`, reminder.EventID, reminder.EventTime, reminder.NotifyBefore, reminder.Channel, reminder.UserID)
+	return err
+}
+
+// PendingOutbox возвращает не более limit неотправленных сообщений outbox в порядке записи.
+func (s *Storage) PendingOutbox(ctx context.Context, limit int) ([]storage.OutboxMessage, error) {
+	return pendingOutbox(ctx, s.db, limit, false)
+}
+
+// RelayOutbox публикует не более limit неотправленных сообщений outbox в порядке записи и в той же транзакции
+// отмечает их отправленными. Выбранные сообщения блокируются до конца транзакции (FOR UPDATE SKIP LOCKED),
+// поэтому одновременный запуск на другой реплике их пропускает, а после сбоя блокировка снимается вместе
+// с транзакцией. При ошибке публикации попытка учитывается, обработка останавливается, а ошибка возвращается.
+// Возвращает число отправленных сообщений.
+func (s *Storage) RelayOutbox(ctx context.Context, limit int,
+	publish func(context.Context, storage.OutboxMessage) error,
+) (int, error) {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	messages, err := pendingOutbox(ctx, tx, limit, true)
+	if err != nil {
+		return 0, err
+	}
+	for i, msg := range messages {
+		if publishErr := publish(ctx, msg); publishErr != nil {
+			if _, err := tx.ExecContext(ctx, `UPDATE outbox SET attempts = attempts + 1, last_error = $1 WHERE id = $2`,
+				publishErr.Error(), msg.ID); err != nil {
+				return 0, errors.Join(publishErr, err)
+			}
+			if err := tx.Commit(); err != nil {
+				return 0, errors.Join(publishErr, err)
+			}
+			return i, publishErr
+		}
+		if _, err := tx.ExecContext(ctx, `UPDATE outbox SET sent_at = $1 WHERE id = $2`, time.Now().Unix(), msg.ID); err != nil {
+			return 0, err
 		}
-		events = append(events, e)
 	}
-	return events, nil
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return len(messages), nil
 }
 
-// GetEventsForNotification возвращает события, требующие уведомления.
-// Событие требует уведомления, если:
-// - у него установлено поле notify_before
-// - текущее время + notify_before >= start_time
-// - уведомление еще не было отправлено (можно добавить поле в БД, но для простоты проверяем только время)
-func (s *Storage) GetEventsForNotification(ctx context.Context, currentTime int64) ([]storage.Event, error) {
-	var events []storage.Event
-	// Выбираем события, где notify_before не NULL и
-	// (start_time - notify_before) <= current_time < start_time
+// pendingOutbox выбирает не более limit неотправленных сообщений outbox в порядке записи; с lock выбранные
+// сообщения блокируются до конца транзакции, а заблокированные другими транзакциями пропускаются.
+func pendingOutbox(ctx context.Context, db sqlx.QueryerContext, limit int, lock bool) ([]storage.OutboxMessage, error) {
 	query := `
-		SELECT id, title, description, user_id, 
-		       start_time,
-		       end_time,
-		       notify_before
-		FROM events
-		WHERE notify_before IS NOT NULL
-		  AND (start_time - notify_before) <= $1
-		  AND start_time > $1
-		ORDER BY start_time ASC
+		SELECT id, event_id, user_id, title, event_time, notify_before, channel, created_at, attempts
+		FROM outbox
+		WHERE sent_at IS NULL
+		ORDER BY seq
+		LIMIT $1
 	`
-	rows, err := s.db.QueryxContext(ctx, query, currentTime)
+	if lock {
+		query += ` FOR UPDATE SKIP LOCKED`
+	}
+	rows, err := db.QueryxContext(ctx, query, limit)
 	if err != nil {
 		return nil, err
 	}
 	defer func() { _ = rows.Close() }()
-
+	var messages []storage.OutboxMessage
 	for rows.Next() {
-		var e storage.Event
-		var notifyBefore sql.NullInt64
-		if err := rows.Scan(&e.ID, &e.Title, &e.Description, &e.UserID, &e.StartTime, &e.EndTime, &notifyBefore); err != nil {
+		var m storage.OutboxMessage
+		if err := rows.Scan(&m.ID, &m.Reminder.EventID, &m.Reminder.UserID, &m.Reminder.Title, &m.Reminder.EventTime,
+			&m.Reminder.NotifyBefore, &m.Reminder.Channel, &m.CreatedAt, &m.Attempts); err != nil {
 			return nil, err
 		}
-		if notifyBefore.Valid {
-			e.NotifyBefore = &notifyBefore.Int64
+		messages = append(messages, m)
+	}
+	return messages, rows.Err()
+}
+
+// conflictError возвращает storage.ConflictError со списком событий, пересекающихся с event.
+// Условие совпадает с ограничением events_no_overlap.
+func (s *Storage) conflictError(ctx context.Context, event storage.Event) error {
+	var ids []string
+	err := s.db.SelectContext(ctx, &ids, `
+		SELECT id FROM events
+		WHERE user_id = $1 AND id <> $2 AND rrule = '' AND deleted_at IS NULL
+		  AND int8range(start_time, end_time) && int8range($3, $4)
+		ORDER BY start_time, id
+	`, event.UserID, event.ID, event.StartTime, event.EndTime)
+	if err != nil {
+		return err
+	}
+	return &storage.ConflictError{EventIDs: ids}
+}
+
+// versionError объясняет, почему UPDATE или DELETE по ID и версии не затронул ни одной строки:
+// события нет (storage.ErrNotFound) или его версия другая (storage.ErrVersionConflict).
+func versionError(ctx context.Context, tx *sqlx.Tx, id string) error {
+	var version int64
+	err := tx.QueryRowxContext(ctx, `SELECT version FROM events WHERE id=$1 AND deleted_at IS NULL`, id).Scan(&version)
+	if errors.Is(err, sql.ErrNoRows) {
+		return storage.NotFoundError(id)
+	}
+	if err != nil {
+		return err
+	}
+	return storage.VersionConflictError(id, version)
+}
+
+// isExclusionViolation сообщает, нарушено ли ограничение-исключение (пересечение событий по времени).
+func isExclusionViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23P01"
+}
+
+// isInvalidText сообщает, что PostgreSQL не смог разобрать значение, например ID не в формате UUID.
+// Событие с таким ID не может существовать.
+func isInvalidText(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "22P02"
+}
+
+// domainError переводит отказ PostgreSQL принять данные (неверный формат значения, нарушение
+// ограничений CHECK и NOT NULL) в storage.ErrValidation, а нарушение уникальности (например, занятый ID
+// события) — в storage.ErrAlreadyExists; остальные ошибки возвращаются как есть.
+func domainError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code {
+		case "22P02", "23502", "23514":
+			return fmt.Errorf("%w: %s", storage.ErrValidation, pqErr.Message)
+		case "23505":
+			return fmt.Errorf("%w: %s", storage.ErrAlreadyExists, pqErr.Message)
+		}
+	}
+	return err
+}
+
+// scanEvent читает событие из строки результата, обрабатывая nullable поля.
+func scanEvent(row interface{ Scan(dest ...any) error }) (storage.Event, error) {
+	var e storage.Event
+	var recurrenceEnd, recurrenceID, deletedAt sql.NullInt64
+	var recurringEventID sql.NullString
+	var exdates pq.Int64Array
+	if err := row.Scan(&e.ID, &e.Title, &e.Description, &e.UserID, &e.StartTime, &e.EndTime,
+		&e.RRule, &exdates, &recurrenceEnd, &recurringEventID, &recurrenceID, &e.Version, &deletedAt, &e.TimeZone); err != nil {
+		return e, err
+	}
+	if len(exdates) > 0 {
+		e.ExDates = exdates
+	}
+	e.RecurrenceEnd = recurrenceEnd.Int64
+	e.RecurringEventID = recurringEventID.String
+	e.RecurrenceID = recurrenceID.Int64
+	e.DeletedAt = deletedAt.Int64
+	return e, nil
+}
+
+// scanEvents читает все события из результата запроса и закрывает его.
+func scanEvents(rows *sqlx.Rows) ([]storage.Event, error) {
+	defer func() { _ = rows.Close() }()
+	var events []storage.Event
+	for rows.Next() {
+		e, err := scanEvent(rows)
+		if err != nil {
+			return nil, err
 		}
 		events = append(events, e)
 	}
-	return events, nil
+	return events, rows.Err()
 }
 
-// DeleteOldEvents удаляет события, произошедшие более указанного времени назад.
-func (s *Storage) DeleteOldEvents(ctx context.Context, beforeTime int64) error {
-	_, err := s.db.ExecContext(ctx, `
-		DELETE FROM events 
-		WHERE start_time < $1
-	`, beforeTime)
-	return err
+// queryEvents выполняет запрос событий и загружает их напоминания и участников.
+func (s *Storage) queryEvents(ctx context.Context, query string, args ...any) ([]storage.Event, error) {
+	rows, err := s.db.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	events, err := scanEvents(rows)
+	if err != nil {
+		return nil, err
+	}
+	return events, s.attachDetails(ctx, events)
+}
+
+// attachDetails загружает напоминания и участников событий.
+func (s *Storage) attachDetails(ctx context.Context, events []storage.Event) error {
+	if err := s.attachReminders(ctx, events); err != nil {
+		return err
+	}
+	return s.attachAttendees(ctx, events)
+}
+
+// attachReminders загружает напоминания событий одним запросом к таблице event_reminders.
+// Напоминания упорядочены от самого раннего (наибольшее смещение) к самому позднему, затем по каналу.
+func (s *Storage) attachReminders(ctx context.Context, events []storage.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+	index, ids := eventIndex(events)
+	rows, err := s.db.QueryxContext(ctx, `
+		SELECT event_id, offset_seconds, channel FROM event_reminders
+		WHERE event_id = ANY($1::uuid[])
+		ORDER BY event_id, offset_seconds DESC, channel
+	`, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = rows.Close() }()
+	for rows.Next() {
+		var (
+			eventID  string
+			reminder storage.EventReminder
+		)
+		if err := rows.Scan(&eventID, &reminder.Offset, &reminder.Channel); err != nil {
+			return err
+		}
+		if i, ok := index[eventID]; ok {
+			events[i].Reminders = append(events[i].Reminders, reminder)
+		}
+	}
+	return rows.Err()
+}
+
+// attachAttendees загружает участников событий одним запросом к таблице event_attendees.
+// Участники упорядочены по ID пользователя.
+func (s *Storage) attachAttendees(ctx context.Context, events []storage.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+	index, ids := eventIndex(events)
+	rows, err := s.db.QueryxContext(ctx, `
+		SELECT event_id, user_id, status FROM event_attendees
+		WHERE event_id = ANY($1::uuid[])
+		ORDER BY event_id, user_id
+	`, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = rows.Close() }()
+	for rows.Next() {
+		var (
+			eventID  string
+			attendee storage.Attendee
+		)
+		if err := rows.Scan(&eventID, &attendee.UserID, &attendee.Status); err != nil {
+			return err
+		}
+		if i, ok := index[eventID]; ok {
+			events[i].Attendees = append(events[i].Attendees, attendee)
+		}
+	}
+	return rows.Err()
+}
+
+// eventIndex возвращает позиции событий по ID и список их ID.
+func eventIndex(events []storage.Event) (map[string]int, []string) {
+	index := make(map[string]int, len(events))
+	ids := make([]string, 0, len(events))
+	for i, e := range events {
+		index[e.ID] = i
+		ids = append(ids, e.ID)
+	}
+	return index, ids
+}
+
+// saveDetails заменяет напоминания и участников события в рамках транзакции tx.
+func saveDetails(ctx context.Context, tx *sqlx.Tx, event storage.Event) error {
+	if err := saveReminders(ctx, tx, event); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM event_attendees WHERE event_id = $1`, event.ID); err != nil {
+		return err
+	}
+	for _, a := range event.Attendees {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO event_attendees (event_id, user_id, status) VALUES ($1, $2, $3)
+			ON CONFLICT (event_id, user_id) DO UPDATE SET status = EXCLUDED.status
+		`, event.ID, a.UserID, a.Status); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// saveReminders заменяет напоминания события в таблице event_reminders в рамках транзакции tx.
+func saveReminders(ctx context.Context, tx *sqlx.Tx, event storage.Event) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM event_reminders WHERE event_id = $1`, event.ID); err != nil {
+		return err
+	}
+	for _, r := range event.Reminders {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO event_reminders (event_id, offset_seconds, channel) VALUES ($1, $2, $3)
+			ON CONFLICT DO NOTHING
+		`, event.ID, r.Offset, r.Channel); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// exDates возвращает исключённые экземпляры события, не допуская NULL в колонке exdates.
+func exDates(e storage.Event) []int64 {
+	if e.ExDates == nil {
+		return []int64{}
+	}
+	return e.ExDates
+}
+
+// nullInt64 преобразует нулевое значение в NULL.
+func nullInt64(v int64) sql.NullInt64 {
+	return sql.NullInt64{Int64: v, Valid: v != 0}
+}
+
+// nullString преобразует пустую строку в NULL.
+func nullString(v string) sql.NullString {
+	return sql.NullString{String: v, Valid: v != ""}
 }
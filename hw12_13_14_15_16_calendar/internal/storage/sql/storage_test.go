@@ -3,12 +3,17 @@ package sqlstorage
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/app"
 	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/storage"
+	"github.com/google/uuid"
 	_ "github.com/lib/pq"
 	"github.com/pressly/goose/v3"
 )
@@ -44,7 +49,13 @@ func setupTestStorage(t *testing.T) *Storage {
 		t.Fatalf("failed to connect to db: %v", err)
 	}
 	// Очищаем таблицу перед тестом
-	_, _ = s.db.Exec("DELETE FROM events")
+	_, _ = s.db.Exec("DELETE FROM events") // напоминания событий удаляются каскадно
+	_, _ = s.db.Exec("DELETE FROM notifications")
+	_, _ = s.db.Exec("DELETE FROM outbox")
+	_, _ = s.db.Exec("DELETE FROM event_changes")
+	_, _ = s.db.Exec("DELETE FROM event_audit")
+	_, _ = s.db.Exec("DELETE FROM leases")
+	_, _ = s.db.Exec("DELETE FROM webhooks")
 	return s
 }
 
@@ -78,8 +89,14 @@ func TestSQLStorageCRUD(t *testing.T) {
 	if err := s.UpdateEvent(ctx, got); err != nil {
 		t.Fatalf("UpdateEvent failed: %v", err)
 	}
+	// Занятый ID, даже у другого пользователя, — storage.ErrAlreadyExists
+	duplicate := event
+	duplicate.ID, duplicate.UserID = id, "user2"
+	if err := s.CreateEvent(ctx, duplicate); !errors.Is(err, storage.ErrAlreadyExists) {
+		t.Fatalf("CreateEvent with taken ID: expected storage.ErrAlreadyExists, got %v", err)
+	}
 	// Delete
-	if err := s.DeleteEvent(ctx, id); err != nil {
+	if err := s.DeleteEvent(ctx, id, 0); err != nil {
 		t.Fatalf("DeleteEvent failed: %v", err)
 	}
 	_, err = s.GetEvent(ctx, id)
@@ -91,8 +108,758 @@ func TestSQLStorageCRUD(t *testing.T) {
 func TestSQLStorageNotFound(t *testing.T) {
 	s := setupTestStorage(t)
 	ctx := context.Background()
-	_, err := s.GetEvent(ctx, "nonexistent")
-	if err == nil {
-		t.Fatalf("expected not found error")
+	// ID не в формате UUID и несуществующий UUID одинаково означают отсутствие события
+	for _, id := range []string{"nonexistent", uuid.NewString()} {
+		if _, err := s.GetEvent(ctx, id); !errors.Is(err, storage.ErrNotFound) {
+			t.Fatalf("GetEvent(%q): expected storage.ErrNotFound, got %v", id, err)
+		}
+		if err := s.UpdateEvent(ctx, storage.Event{ID: id, UserID: "u", StartTime: 1, EndTime: 2}); !errors.Is(err, storage.ErrNotFound) {
+			t.Fatalf("UpdateEvent(%q): expected storage.ErrNotFound, got %v", id, err)
+		}
+		if err := s.DeleteEvent(ctx, id, 0); !errors.Is(err, storage.ErrNotFound) {
+			t.Fatalf("DeleteEvent(%q): expected storage.ErrNotFound, got %v", id, err)
+		}
+	}
+
+	// Значение, которое отклоняет схема, — ошибка валидации
+	err := s.CreateEvent(ctx, storage.Event{ID: "not-a-uuid", UserID: "u", StartTime: 1, EndTime: 2})
+	if !errors.Is(err, storage.ErrValidation) {
+		t.Fatalf("expected storage.ErrValidation, got %v", err)
+	}
+}
+
+func TestSQLStorageOverlap(t *testing.T) {
+	s := setupTestStorage(t)
+	ctx := context.Background()
+	base := storage.Event{ID: uuid.NewString(), Title: "base", UserID: "overlap", StartTime: 1000, EndTime: 2000}
+	if err := s.CreateEvent(ctx, base); err != nil {
+		t.Fatalf("CreateEvent failed: %v", err)
+	}
+
+	err := s.CreateEvent(ctx, storage.Event{ID: uuid.NewString(), Title: "inside", UserID: "overlap", StartTime: 1500, EndTime: 2500})
+	var conflict *app.ConflictError
+	if !errors.As(err, &conflict) || len(conflict.EventIDs) != 1 || conflict.EventIDs[0] != base.ID {
+		t.Fatalf("expected conflict with %s, got %v", base.ID, err)
+	}
+
+	adjacent := storage.Event{ID: uuid.NewString(), Title: "adjacent", UserID: "overlap", StartTime: 2000, EndTime: 3000}
+	if err := s.CreateEvent(ctx, adjacent); err != nil {
+		t.Fatalf("adjacent event should not conflict: %v", err)
+	}
+
+	// Продление события до пересечения с соседним тоже запрещено
+	base.EndTime = 2100
+	if err := s.UpdateEvent(ctx, base); !errors.Is(err, app.ErrDateBusy) {
+		t.Fatalf("expected ErrDateBusy on update, got %v", err)
+	}
+}
+
+func TestSQLStorageListEventsInRange(t *testing.T) {
+	s := setupTestStorage(t)
+	ctx := context.Background()
+	ids := map[string]string{}
+	for _, e := range []storage.Event{
+		{Title: "late", StartTime: 1500, EndTime: 1600},
+		{Title: "spans-start", StartTime: 500, EndTime: 1100},
+		{Title: "before", StartTime: 100, EndTime: 500},
+		{Title: "after", StartTime: 2000, EndTime: 2100},
+		{Title: "point", StartTime: 1000, EndTime: 1000},
+	} {
+		e.ID = uuid.NewString()
+		e.UserID = "range"
+		ids[e.Title] = e.ID
+		if err := s.CreateEvent(ctx, e); err != nil {
+			t.Fatalf("CreateEvent %s failed: %v", e.Title, err)
+		}
+	}
+
+	list, err := s.ListEventsInRange(ctx, "range", 1000, 2000)
+	if err != nil {
+		t.Fatalf("ListEventsInRange failed: %v", err)
+	}
+	var got []string
+	for _, e := range list {
+		got = append(got, e.Title)
+	}
+	want := []string{"spans-start", "point", "late"}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+	}
+}
+
+func TestSQLStorageListEventsPage(t *testing.T) {
+	s := setupTestStorage(t)
+	ctx := context.Background()
+	seriesID := uuid.NewString()
+	for _, e := range []storage.Event{
+		{Title: "c", StartTime: 1200, EndTime: 1200},
+		{Title: "b", StartTime: 1200, EndTime: 1200},
+		{Title: "a", StartTime: 1100, EndTime: 1150},
+		{Title: "d", StartTime: 1500, EndTime: 1600},
+		{Title: "outside", StartTime: 2000, EndTime: 2100},
+		{ID: seriesID, Title: "series", StartTime: 50, EndTime: 60, RRule: "FREQ=DAILY"},
+		{Title: "moved", StartTime: 1300, EndTime: 1310, RecurringEventID: seriesID, RecurrenceID: 86450},
+	} {
+		if e.ID == "" {
+			e.ID = uuid.NewString()
+		}
+		e.UserID = "pages"
+		if err := s.CreateEvent(ctx, e); err != nil {
+			t.Fatalf("CreateEvent %s failed: %v", e.Title, err)
+		}
+	}
+
+	var got []storage.Event
+	after := storage.Cursor{}
+	for {
+		page, err := s.ListEventsPage(ctx, "pages", 1000, 2000, after, 2)
+		if err != nil {
+			t.Fatalf("ListEventsPage failed: %v", err)
+		}
+		if page.Total != 4 {
+			t.Fatalf("expected total 4, got %d", page.Total)
+		}
+		if len(page.Series) != 2 || page.Series[0].Title != "series" || page.Series[1].Title != "moved" {
+			t.Fatalf("unexpected series: %+v", page.Series)
+		}
+		got = append(got, page.Events...)
+		if len(page.Events) < 2 {
+			break
+		}
+		last := page.Events[len(page.Events)-1]
+		after = storage.Cursor{StartTime: last.StartTime, ID: last.ID}
+	}
+	if len(got) != 4 || got[0].Title != "a" || got[3].Title != "d" {
+		t.Fatalf("unexpected pages: %+v", got)
+	}
+	// События с одинаковым временем начала упорядочены по ID
+	if got[1].StartTime != got[2].StartTime || got[1].ID > got[2].ID {
+		t.Fatalf("events with equal start time are not ordered by id: %+v", got[1:3])
+	}
+}
+
+func TestSQLStorageEnqueueReminder(t *testing.T) {
+	s := setupTestStorage(t)
+	ctx := context.Background()
+	reminder := storage.Reminder{
+		EventID: uuid.NewString(), UserID: "user1", Title: "Meeting", EventTime: 2000, NotifyBefore: 600,
+	}
+
+	enqueued, err := s.EnqueueReminder(ctx, reminder)
+	if err != nil || !enqueued {
+		t.Fatalf("first EnqueueReminder: enqueued=%v, err=%v", enqueued, err)
+	}
+	enqueued, err = s.EnqueueReminder(ctx, reminder)
+	if err != nil || enqueued {
+		t.Fatalf("second EnqueueReminder: enqueued=%v, err=%v", enqueued, err)
+	}
+
+	// Другое смещение — другое напоминание
+	other := reminder
+	other.NotifyBefore = 60
+	if enqueued, err := s.EnqueueReminder(ctx, other); err != nil || !enqueued {
+		t.Fatalf("EnqueueReminder with another offset: enqueued=%v, err=%v", enqueued, err)
+	}
+
+	pending, err := s.PendingOutbox(ctx, 10)
+	if err != nil {
+		t.Fatalf("PendingOutbox failed: %v", err)
+	}
+	if len(pending) != 2 || pending[0].Reminder != reminder || pending[1].Reminder != other {
+		t.Fatalf("unexpected outbox: %+v", pending)
+	}
+
+	// Неудачная попытка учитывается и останавливает публикацию
+	errBroker := errors.New("broker is unavailable")
+	sent, err := s.RelayOutbox(ctx, 10, func(context.Context, storage.OutboxMessage) error { return errBroker })
+	if sent != 0 || !errors.Is(err, errBroker) {
+		t.Fatalf("RelayOutbox with failing publish: sent=%d, err=%v", sent, err)
+	}
+	pending, err = s.PendingOutbox(ctx, 10)
+	if err != nil || len(pending) != 2 || pending[0].Attempts != 1 || pending[1].Attempts != 0 {
+		t.Fatalf("unexpected outbox after failed publish: %+v, err=%v", pending, err)
+	}
+
+	// Сообщения, заблокированные другой публикацией, пропускаются; отправленное сообщение больше не выбирается
+	var published []storage.Reminder
+	sent, err = s.RelayOutbox(ctx, 1, func(ctx context.Context, msg storage.OutboxMessage) error {
+		concurrent, err := s.RelayOutbox(ctx, 10, func(_ context.Context, msg storage.OutboxMessage) error {
+			published = append(published, msg.Reminder)
+			return nil
+		})
+		if err != nil || concurrent != 1 {
+			t.Fatalf("concurrent RelayOutbox: sent=%d, err=%v", concurrent, err)
+		}
+		published = append(published, msg.Reminder)
+		return nil
+	})
+	if err != nil || sent != 1 {
+		t.Fatalf("RelayOutbox: sent=%d, err=%v", sent, err)
+	}
+	if len(published) != 2 || published[0] != other || published[1] != reminder {
+		t.Fatalf("unexpected published reminders: %+v", published)
+	}
+	pending, err = s.PendingOutbox(ctx, 10)
+	if err != nil || len(pending) != 0 {
+		t.Fatalf("unexpected outbox after send: %+v, err=%v", pending, err)
+	}
+}
+
+func TestSQLStorageEventReminders(t *testing.T) {
+	s := setupTestStorage(t)
+	ctx := context.Background()
+	event := storage.Event{
+		ID: uuid.NewString(), Title: "Meeting", UserID: "user1", StartTime: 5000, EndTime: 5600,
+		Reminders: []storage.EventReminder{{Offset: 3600, Channel: "email"}, {Offset: 600}},
+	}
+	if err := s.CreateEvent(ctx, event); err != nil {
+		t.Fatalf("CreateEvent failed: %v", err)
+	}
+	got, err := s.GetEvent(ctx, event.ID)
+	if err != nil || !slices.Equal(got.Reminders, event.Reminders) {
+		t.Fatalf("GetEvent: reminders=%+v, err=%v", got.Reminders, err)
+	}
+
+	// Событие попадает в выборку, когда наступает время любого из напоминаний
+	for _, tc := range []struct {
+		now  int64
+		want int
+	}{{1000, 0}, {1400, 1}, {4500, 1}, {5000, 0}} {
+		list, err := s.GetEventsForNotification(ctx, tc.now)
+		if err != nil || len(list) != tc.want {
+			t.Fatalf("GetEventsForNotification(%d): got %d events, err=%v", tc.now, len(list), err)
+		}
+	}
+
+	// Обновление заменяет список напоминаний
+	event.Reminders = []storage.EventReminder{{Offset: 60, Channel: "webhook"}}
+	if err := s.UpdateEvent(ctx, event); err != nil {
+		t.Fatalf("UpdateEvent failed: %v", err)
+	}
+	list, err := s.ListEvents(ctx, event.UserID)
+	if err != nil || len(list) != 1 || !slices.Equal(list[0].Reminders, event.Reminders) {
+		t.Fatalf("ListEvents after update: %+v, err=%v", list, err)
+	}
+
+	// Напоминания с одинаковым смещением, но разными каналами ставятся отдельно
+	reminder := storage.Reminder{EventID: event.ID, UserID: "user1", Title: "Meeting", EventTime: 5000, NotifyBefore: 600}
+	for _, channel := range []string{"", "email"} {
+		reminder.Channel = channel
+		if enqueued, err := s.EnqueueReminder(ctx, reminder); err != nil || !enqueued {
+			t.Fatalf("EnqueueReminder(%q): enqueued=%v, err=%v", channel, enqueued, err)
+		}
+	}
+	pending, err := s.PendingOutbox(ctx, 10)
+	if err != nil || len(pending) != 2 || pending[1].Reminder != reminder {
+		t.Fatalf("unexpected outbox: %+v, err=%v", pending, err)
+	}
+}
+
+func TestSQLStorageAttendees(t *testing.T) {
+	s := setupTestStorage(t)
+	ctx := context.Background()
+	event := storage.Event{
+		ID: uuid.NewString(), Title: "Meeting", UserID: "owner", StartTime: 300, EndTime: 400,
+		Attendees: []storage.Attendee{{UserID: "guest", Status: storage.AttendeeNeedsAction}},
+	}
+	private := storage.Event{ID: uuid.NewString(), Title: "Private", UserID: "owner", StartTime: 500, EndTime: 600}
+	for _, e := range []storage.Event{event, private} {
+		if err := s.CreateEvent(ctx, e); err != nil {
+			t.Fatalf("CreateEvent failed: %v", err)
+		}
+	}
+
+	page, err := s.ListEventsPage(ctx, "guest", 0, 1000, storage.Cursor{}, 10)
+	if err != nil || len(page.Events) != 1 || page.Events[0].ID != event.ID || page.Total != 1 {
+		t.Fatalf("ListEventsPage for attendee: %+v, err=%v", page, err)
+	}
+	if !slices.Equal(page.Events[0].Attendees, event.Attendees) {
+		t.Fatalf("unexpected attendees: %+v", page.Events[0].Attendees)
+	}
+
+	if err := s.SetAttendeeStatus(ctx, private.ID, "guest", storage.AttendeeAccepted); !errors.Is(err, app.ErrNotFound) {
+		t.Fatalf("expected app.ErrNotFound for uninvited user, got %v", err)
+	}
+	if err := s.SetAttendeeStatus(ctx, event.ID, "guest", storage.AttendeeDeclined); err != nil {
+		t.Fatalf("SetAttendeeStatus failed: %v", err)
+	}
+	// Отказавшийся участник не видит событие, но приглашение остаётся
+	page, err = s.ListEventsPage(ctx, "guest", 0, 1000, storage.Cursor{}, 10)
+	if err != nil || len(page.Events) != 0 {
+		t.Fatalf("declined event must be hidden: %+v, err=%v", page.Events, err)
+	}
+	attended, err := s.ListAttendedEvents(ctx, "guest")
+	if err != nil || len(attended) != 1 || attended[0].Attendees[0].Status != storage.AttendeeDeclined {
+		t.Fatalf("unexpected attended events: %+v, err=%v", attended, err)
+	}
+}
+
+func TestSQLStorageChanges(t *testing.T) {
+	s := setupTestStorage(t)
+	ctx := context.Background()
+	start, err := s.LastChangeRevision(ctx, "owner")
+	if err != nil {
+		t.Fatalf("LastChangeRevision failed: %v", err)
+	}
+	event := storage.Event{
+		ID: uuid.NewString(), Title: "Meeting", UserID: "owner", StartTime: 300, EndTime: 400,
+		Attendees: []storage.Attendee{{UserID: "guest", Status: storage.AttendeeNeedsAction}},
+	}
+	if err := s.CreateEvent(ctx, event); err != nil {
+		t.Fatalf("CreateEvent failed: %v", err)
+	}
+	if err := s.SetAttendeeStatus(ctx, event.ID, "guest", storage.AttendeeAccepted); err != nil {
+		t.Fatalf("SetAttendeeStatus failed: %v", err)
+	}
+	// Исключённый участник получает изменение, после которого события в его календаре нет
+	event.Attendees = nil
+	if err := s.UpdateEvent(ctx, event); err != nil {
+		t.Fatalf("UpdateEvent failed: %v", err)
+	}
+	if err := s.DeleteEvent(ctx, event.ID, 0); err != nil {
+		t.Fatalf("DeleteEvent failed: %v", err)
+	}
+
+	changes, err := s.ListChanges(ctx, "guest", start, 10)
+	if err != nil || len(changes) != 3 {
+		t.Fatalf("unexpected guest changes: %+v, err=%v", changes, err)
+	}
+	changes, err = s.ListChanges(ctx, "owner", start, 10)
+	if err != nil || len(changes) != 4 || changes[0].Type != storage.ChangeCreated || changes[3].Type != storage.ChangeDeleted {
+		t.Fatalf("unexpected owner changes: %+v, err=%v", changes, err)
+	}
+	for i := 1; i < len(changes); i++ {
+		if changes[i].Revision <= changes[i-1].Revision || changes[i].EventID != event.ID {
+			t.Fatalf("changes out of order: %+v", changes)
+		}
+	}
+	if last, _ := s.LastChangeRevision(ctx, "owner"); last != changes[3].Revision {
+		t.Fatalf("expected last revision %d, got %d", changes[3].Revision, last)
+	}
+}
+
+func TestSQLStorageVersions(t *testing.T) {
+	s := setupTestStorage(t)
+	ctx := context.Background()
+	event := storage.Event{
+		ID: uuid.NewString(), Title: "Meeting", UserID: "owner", StartTime: 300, EndTime: 400,
+		Attendees: []storage.Attendee{{UserID: "guest", Status: storage.AttendeeNeedsAction}},
+	}
+	if err := s.CreateEvent(ctx, event); err != nil {
+		t.Fatalf("CreateEvent failed: %v", err)
+	}
+	got, err := s.GetEvent(ctx, event.ID)
+	if err != nil || got.Version != storage.InitialVersion {
+		t.Fatalf("expected initial version: %+v, err=%v", got, err)
+	}
+
+	got.Title = "Moved"
+	if err := s.UpdateEvent(ctx, got); err != nil {
+		t.Fatalf("UpdateEvent failed: %v", err)
+	}
+	// Изменение, основанное на устаревшей версии, отклоняется
+	stale := got
+	stale.Title = "Lost"
+	if err := s.UpdateEvent(ctx, stale); !errors.Is(err, storage.ErrVersionConflict) {
+		t.Fatalf("expected storage.ErrVersionConflict, got %v", err)
+	}
+	if err := s.SetAttendeeStatus(ctx, event.ID, "guest", storage.AttendeeAccepted); err != nil {
+		t.Fatalf("SetAttendeeStatus failed: %v", err)
+	}
+	got, err = s.GetEvent(ctx, event.ID)
+	if err != nil || got.Version != 3 || got.Title != "Moved" {
+		t.Fatalf("unexpected event after writes: %+v, err=%v", got, err)
+	}
+
+	if err := s.DeleteEvent(ctx, event.ID, 2); !errors.Is(err, storage.ErrVersionConflict) {
+		t.Fatalf("expected storage.ErrVersionConflict, got %v", err)
+	}
+	if err := s.DeleteEvent(ctx, event.ID, 3); err != nil {
+		t.Fatalf("DeleteEvent failed: %v", err)
+	}
+}
+
+func TestSQLStorageTrash(t *testing.T) {
+	s := setupTestStorage(t)
+	ctx := context.Background()
+	series := storage.Event{ID: uuid.NewString(), Title: "Standup", UserID: "owner", StartTime: 100, EndTime: 200,
+		RRule: "FREQ=DAILY", Reminders: []storage.EventReminder{{Offset: 50}}}
+	override := storage.Event{ID: uuid.NewString(), Title: "Moved standup", UserID: "owner", StartTime: 1000, EndTime: 1100,
+		RecurringEventID: series.ID, RecurrenceID: 86500}
+	for _, e := range []storage.Event{series, override} {
+		if err := s.CreateEvent(ctx, e); err != nil {
+			t.Fatalf("CreateEvent failed: %v", err)
+		}
+	}
+
+	if err := s.TrashEvent(ctx, series.ID, 2, 5000); !errors.Is(err, storage.ErrVersionConflict) {
+		t.Fatalf("expected storage.ErrVersionConflict, got %v", err)
+	}
+	if err := s.TrashEvent(ctx, series.ID, storage.InitialVersion, 5000); err != nil {
+		t.Fatalf("TrashEvent failed: %v", err)
+	}
+	// Событие в корзине скрыто из выборок и уведомлений вместе с изменёнными экземплярами
+	if _, err := s.GetEvent(ctx, override.ID); !errors.Is(err, storage.ErrNotFound) {
+		t.Fatalf("expected storage.ErrNotFound for trashed override, got %v", err)
+	}
+	if events, _ := s.ListEventsInRange(ctx, "owner", 0, 2000); len(events) != 0 {
+		t.Fatalf("trashed events must not be listed: %+v", events)
+	}
+	if events, _ := s.GetEventsForNotification(ctx, 60); len(events) != 0 {
+		t.Fatalf("trashed events must not be notified: %+v", events)
+	}
+	trash, err := s.ListTrash(ctx, "owner")
+	if err != nil || len(trash) != 1 || trash[0].ID != series.ID || trash[0].DeletedAt != 5000 {
+		t.Fatalf("unexpected trash: %+v, err=%v", trash, err)
+	}
+
+	// Время изменённого экземпляра заняли, пока серия была в корзине
+	busy := storage.Event{ID: uuid.NewString(), Title: "Busy", UserID: "owner", StartTime: 1050, EndTime: 1150}
+	if err := s.CreateEvent(ctx, busy); err != nil {
+		t.Fatalf("CreateEvent failed: %v", err)
+	}
+	var conflict *storage.ConflictError
+	if err := s.RestoreEvent(ctx, series.ID); !errors.As(err, &conflict) || !slices.Equal(conflict.EventIDs, []string{busy.ID}) {
+		t.Fatalf("expected conflict with busy, got %v", err)
+	}
+	if err := s.DeleteEvent(ctx, busy.ID, 0); err != nil {
+		t.Fatalf("DeleteEvent failed: %v", err)
+	}
+	if err := s.RestoreEvent(ctx, series.ID); err != nil {
+		t.Fatalf("RestoreEvent failed: %v", err)
+	}
+	got, err := s.GetEvent(ctx, override.ID)
+	if err != nil || got.DeletedAt != 0 || got.Version != 3 {
+		t.Fatalf("unexpected restored override: %+v, err=%v", got, err)
+	}
+	changes, _ := s.ListChanges(ctx, "owner", 0, 10)
+	if len(changes) != 8 || changes[2].Type != storage.ChangeDeleted || changes[7].Type != storage.ChangeCreated {
+		t.Fatalf("unexpected changes: %+v", changes)
+	}
+
+	if err := s.TrashEvent(ctx, series.ID, 0, 5000); err != nil {
+		t.Fatalf("TrashEvent failed: %v", err)
+	}
+	if n, err := s.PurgeTrash(ctx, 5000); err != nil || n != 0 {
+		t.Fatalf("events trashed at the cutoff must be kept: %d, err=%v", n, err)
+	}
+	if n, err := s.PurgeTrash(ctx, 5001); err != nil || n != 1 {
+		t.Fatalf("expected 1 purged event, got %d, err=%v", n, err)
+	}
+	if _, err := s.GetTrashedEvent(ctx, override.ID); !errors.Is(err, storage.ErrNotFound) {
+		t.Fatalf("override must be purged with its series, got %v", err)
+	}
+	if err := s.PurgeEvent(ctx, series.ID); !errors.Is(err, storage.ErrNotFound) {
+		t.Fatalf("expected storage.ErrNotFound, got %v", err)
+	}
+}
+
+func TestSQLStorageAudit(t *testing.T) {
+	s := setupTestStorage(t)
+	ctx := context.Background()
+	eventID := uuid.NewString()
+	entries := []storage.AuditEntry{
+		{EventID: eventID, OwnerID: "owner", ActorID: "owner", Action: storage.AuditCreated, CreatedAt: 100,
+			Changes: []storage.FieldChange{{Field: "title", After: "Standup"}}},
+		{EventID: eventID, OwnerID: "owner", ActorID: "guest", Action: storage.AuditUpdated, CreatedAt: 200,
+			Changes: []storage.FieldChange{{Field: "attendees", Before: "guest=needs-action", After: "guest=accepted"}}},
+		{EventID: eventID, OwnerID: "owner", ActorID: "owner", Service: "scheduler", Action: storage.AuditDeleted, CreatedAt: 300},
+	}
+	for _, e := range entries {
+		if err := s.AppendAudit(ctx, e); err != nil {
+			t.Fatalf("AppendAudit failed: %v", err)
+		}
+	}
+
+	history, err := s.ListEventAudit(ctx, eventID, 10)
+	if err != nil || len(history) != 3 {
+		t.Fatalf("unexpected history: %+v, err=%v", history, err)
+	}
+	if history[0].Action != storage.AuditDeleted || history[0].Service != "scheduler" || len(history[0].Changes) != 0 {
+		t.Fatalf("unexpected newest entry: %+v", history[0])
+	}
+	if history[1].Seq <= history[2].Seq || !slices.Equal(history[1].Changes, entries[1].Changes) {
+		t.Fatalf("unexpected history order or changes: %+v", history)
+	}
+	if history, _ := s.ListEventAudit(ctx, eventID, 1); len(history) != 1 || history[0].CreatedAt != 300 {
+		t.Fatalf("limit must keep the newest entry: %+v", history)
+	}
+	if history, err := s.ListEventAudit(ctx, "not-a-uuid", 10); err != nil || len(history) != 0 {
+		t.Fatalf("invalid id must have empty history: %+v, err=%v", history, err)
+	}
+
+	activity, err := s.ListUserAudit(ctx, "owner", 10)
+	if err != nil || len(activity) != 2 || activity[0].CreatedAt != 300 || activity[1].CreatedAt != 100 {
+		t.Fatalf("unexpected activity: %+v, err=%v", activity, err)
+	}
+
+	// Запись, переданная с изменением, сохраняется в его транзакции, а с отклонённым изменением — нет
+	event := storage.Event{ID: uuid.NewString(), UserID: "owner", StartTime: 100, EndTime: 200}
+	created := storage.AuditEntry{EventID: event.ID, OwnerID: "owner", ActorID: "owner", Action: storage.AuditCreated, CreatedAt: 400}
+	if err := s.CreateEvent(storage.WithAudit(ctx, created), event); err != nil {
+		t.Fatalf("CreateEvent failed: %v", err)
+	}
+	updated := storage.AuditEntry{EventID: event.ID, OwnerID: "owner", ActorID: "owner", Action: storage.AuditUpdated, CreatedAt: 500}
+	event.Version = 5
+	if err := s.UpdateEvent(storage.WithAudit(ctx, updated), event); !errors.Is(err, storage.ErrVersionConflict) {
+		t.Fatalf("expected storage.ErrVersionConflict, got %v", err)
+	}
+	deleted := storage.AuditEntry{EventID: event.ID, OwnerID: "owner", ActorID: "owner", Action: storage.AuditDeleted, CreatedAt: 600}
+	if err := s.TrashEvent(storage.WithAudit(ctx, deleted), event.ID, 0, 600); err != nil {
+		t.Fatalf("TrashEvent failed: %v", err)
+	}
+	history, err = s.ListEventAudit(ctx, event.ID, 10)
+	if err != nil || len(history) != 2 || history[0].Action != storage.AuditDeleted || history[1].Action != storage.AuditCreated {
+		t.Fatalf("unexpected history: %+v, err=%v", history, err)
+	}
+}
+
+func TestSQLStorageSearch(t *testing.T) {
+	s := setupTestStorage(t)
+	ctx := context.Background()
+	budget := storage.Event{ID: uuid.NewString(), UserID: "owner", StartTime: 100, EndTime: 200,
+		Title: "Q3 budget review", Description: "Discuss budgets for marketing and hiring."}
+	ru := storage.Event{ID: uuid.NewString(), UserID: "owner", StartTime: 300, EndTime: 400,
+		Title: "Обсуждение бюджета", Description: "Бюджет на третий квартал"}
+	other := storage.Event{ID: uuid.NewString(), UserID: "stranger", StartTime: 100, EndTime: 200, Title: "Q3 budget"}
+	for _, e := range []storage.Event{budget, ru, other} {
+		if err := s.CreateEvent(ctx, e); err != nil {
+			t.Fatalf("CreateEvent failed: %v", err)
+		}
+	}
+
+	// Слова приводятся к основе: budget находит budgets, бюджеты — бюджета и бюджет
+	results, err := s.SearchEvents(ctx, "owner", storage.SearchQuery{Text: "budget", Limit: 10})
+	if err != nil || len(results) != 1 || results[0].Event.ID != budget.ID {
+		t.Fatalf("unexpected results: %+v, err=%v", results, err)
+	}
+	if results[0].Title != "Q3 <b>budget</b> review" || !strings.Contains(results[0].Snippet, "<b>budgets</b>") {
+		t.Fatalf("unexpected highlight: %q, %q", results[0].Title, results[0].Snippet)
+	}
+	results, err = s.SearchEvents(ctx, "owner", storage.SearchQuery{Text: "бюджеты", Language: storage.SearchRussian, Limit: 10})
+	if err != nil || len(results) != 1 || results[0].Event.ID != ru.ID || results[0].Rank <= 0 {
+		t.Fatalf("unexpected russian results: %+v, err=%v", results, err)
+	}
+	if results, _ := s.SearchEvents(ctx, "owner", storage.SearchQuery{Text: "budget -marketing", Limit: 10}); len(results) != 0 {
+		t.Fatalf("excluded word must filter events: %+v", results)
+	}
+
+	if err := s.DeleteEvent(ctx, budget.ID, 0); err != nil {
+		t.Fatalf("DeleteEvent failed: %v", err)
+	}
+	if results, _ := s.SearchEvents(ctx, "owner", storage.SearchQuery{Text: "budget", Limit: 10}); len(results) != 0 {
+		t.Fatalf("deleted events must not be found: %+v", results)
+	}
+}
+
+func TestSQLStorageRetention(t *testing.T) {
+	s := setupTestStorage(t)
+	ctx := context.Background()
+	oldEvent := storage.Event{ID: uuid.NewString(), Title: "Old", UserID: "owner", StartTime: 100, EndTime: 200,
+		Reminders: []storage.EventReminder{{Offset: 60}}}
+	newEvent := storage.Event{ID: uuid.NewString(), Title: "New", UserID: "owner", StartTime: 200000, EndTime: 200100}
+	for _, e := range []storage.Event{oldEvent, newEvent} {
+		if err := s.CreateEvent(ctx, e); err != nil {
+			t.Fatalf("CreateEvent failed: %v", err)
+		}
+	}
+	for _, eventTime := range []int64{100, 200000} {
+		reminder := storage.Reminder{EventID: oldEvent.ID, UserID: "owner", Title: "Old", EventTime: eventTime, NotifyBefore: 60}
+		if _, err := s.EnqueueReminder(ctx, reminder); err != nil {
+			t.Fatalf("EnqueueReminder failed: %v", err)
+		}
+	}
+
+	expired, err := s.ListExpiredEvents(ctx, 100000, "", 10)
+	if err != nil || len(expired) != 1 || expired[0].ID != oldEvent.ID || len(expired[0].Reminders) != 1 {
+		t.Fatalf("unexpected expired events: %+v, err=%v", expired, err)
+	}
+	if next, _ := s.ListExpiredEvents(ctx, 100000, oldEvent.ID, 10); len(next) != 0 {
+		t.Fatalf("cursor must skip listed events: %+v", next)
+	}
+	if n, err := s.DeleteExpiredEvents(ctx, 100000, []string{oldEvent.ID, newEvent.ID}); err != nil || n != 1 {
+		t.Fatalf("expected 1 deleted event, got %d, err=%v", n, err)
+	}
+	if _, err := s.GetEvent(ctx, newEvent.ID); err != nil {
+		t.Fatalf("new event must be kept: %v", err)
+	}
+
+	// Серия истекает вместе с изменёнными экземплярами и только если все они закончились
+	finished := storage.Event{ID: uuid.NewString(), Title: "Finished", UserID: "owner", StartTime: 500, EndTime: 600,
+		RRule: "FREQ=DAILY;COUNT=2", RecurrenceEnd: 87000}
+	override := storage.Event{ID: uuid.NewString(), Title: "Moved", UserID: "owner", StartTime: 90000, EndTime: 90100,
+		RecurringEventID: finished.ID, RecurrenceID: 86900}
+	moved := storage.Event{ID: uuid.NewString(), Title: "Moved later", UserID: "owner", StartTime: 700, EndTime: 800,
+		RRule: "FREQ=DAILY;COUNT=2", RecurrenceEnd: 87200}
+	movedLater := storage.Event{ID: uuid.NewString(), Title: "Moved later", UserID: "owner", StartTime: 300000, EndTime: 300100,
+		RecurringEventID: moved.ID, RecurrenceID: 87100}
+	long := storage.Event{ID: uuid.NewString(), Title: "Long", UserID: "owner", StartTime: 99000, EndTime: 101000}
+	for _, e := range []storage.Event{finished, override, moved, movedLater, long} {
+		if err := s.CreateEvent(ctx, e); err != nil {
+			t.Fatalf("CreateEvent failed: %v", err)
+		}
+	}
+	expired, err = s.ListExpiredEvents(ctx, 100000, "", 10)
+	if err != nil || len(expired) != 2 || expired[0].ID != finished.ID || expired[1].ID != override.ID {
+		t.Fatalf("unexpected expired series: %+v, err=%v", expired, err)
+	}
+	if n, err := s.DeleteExpiredEvents(ctx, 100000, []string{finished.ID, moved.ID, long.ID}); err != nil || n != 1 {
+		t.Fatalf("expected 1 deleted series, got %d, err=%v", n, err)
+	}
+	if _, err := s.GetEvent(ctx, override.ID); !errors.Is(err, storage.ErrNotFound) {
+		t.Fatalf("override must be deleted with its series, got %v", err)
+	}
+	for _, id := range []string{moved.ID, movedLater.ID, long.ID} {
+		if _, err := s.GetEvent(ctx, id); err != nil {
+			t.Fatalf("event %s must be kept: %v", id, err)
+		}
+	}
+
+	notifications, err := s.ListExpiredNotifications(ctx, 100000, "", 10)
+	if err != nil || len(notifications) != 1 || notifications[0].Reminder.EventTime != 100 ||
+		notifications[0].Status != storage.NotificationScheduled || notifications[0].ProcessedAt != 0 {
+		t.Fatalf("unexpected expired notifications: %+v, err=%v", notifications, err)
+	}
+	if n, err := s.DeleteExpiredNotifications(ctx, 100000, []string{notifications[0].ID}); err != nil || n != 1 {
+		t.Fatalf("expected 1 deleted notification, got %d, err=%v", n, err)
+	}
+	if left, _ := s.ListExpiredNotifications(ctx, 1<<40, "", 10); len(left) != 1 || left[0].Reminder.EventTime != 200000 {
+		t.Fatalf("unexpected notifications left: %+v", left)
+	}
+}
+
+func TestSQLStorageLease(t *testing.T) {
+	s := setupTestStorage(t)
+	ctx := context.Background()
+	acquire := func(holder string, ttl time.Duration) bool {
+		t.Helper()
+		ok, err := s.AcquireLease(ctx, "scheduler", holder, ttl)
+		if err != nil {
+			t.Fatalf("AcquireLease failed: %v", err)
+		}
+		return ok
+	}
+	if !acquire("a", time.Minute) || !acquire("a", time.Minute) {
+		t.Fatal("holder must acquire and renew a free lease")
+	}
+	if acquire("b", time.Minute) {
+		t.Fatal("lease held by another replica must not be acquired")
+	}
+	// Истёкшая аренда переходит к другой реплике
+	if !acquire("a", time.Millisecond) {
+		t.Fatal("holder must renew its lease")
+	}
+	time.Sleep(20 * time.Millisecond)
+	if !acquire("b", time.Minute) || acquire("a", time.Minute) {
+		t.Fatal("expired lease must move to another replica")
+	}
+	if err := s.ReleaseLease(ctx, "scheduler", "a"); err != nil {
+		t.Fatalf("ReleaseLease failed: %v", err)
+	}
+	if acquire("a", time.Minute) {
+		t.Fatal("release by a former holder must not free the lease")
+	}
+	if err := s.ReleaseLease(ctx, "scheduler", "b"); err != nil {
+		t.Fatalf("ReleaseLease failed: %v", err)
+	}
+	if !acquire("a", time.Minute) {
+		t.Fatal("released lease must be acquired")
+	}
+}
+
+func TestSQLStorageWebhooks(t *testing.T) {
+	s := setupTestStorage(t)
+	ctx := context.Background()
+	first := storage.Webhook{ID: uuid.NewString(), UserID: "user1", URL: "https://a.example.com", Secret: "s1", CreatedAt: 100}
+	second := storage.Webhook{ID: uuid.NewString(), UserID: "user1", URL: "https://b.example.com", Secret: "s2", CreatedAt: 200}
+	for _, w := range []storage.Webhook{second, first} {
+		if err := s.CreateWebhook(ctx, w); err != nil {
+			t.Fatalf("CreateWebhook failed: %v", err)
+		}
+	}
+	dup := storage.Webhook{ID: uuid.NewString(), UserID: "user1", URL: first.URL, Secret: "s3", CreatedAt: 300}
+	if err := s.CreateWebhook(ctx, dup); !errors.Is(err, storage.ErrWebhookExists) {
+		t.Fatalf("expected ErrWebhookExists, got %v", err)
+	}
+	list, err := s.ListWebhooks(ctx, "user1")
+	if err != nil || len(list) != 2 || list[0] != first || list[1] != second {
+		t.Fatalf("unexpected webhooks: %+v, err=%v", list, err)
+	}
+	if err := s.DeleteWebhook(ctx, first.ID); err != nil {
+		t.Fatalf("DeleteWebhook failed: %v", err)
+	}
+	if err := s.DeleteWebhook(ctx, first.ID); !errors.Is(err, storage.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	if _, err := s.GetWebhook(ctx, "not-a-uuid"); !errors.Is(err, storage.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	// Попытки доставки учитываются в записи о напоминании, даже если планировщик её не создал
+	reminder := storage.Reminder{EventID: uuid.NewString(), UserID: "user1", Title: "Standup", EventTime: 1000, NotifyBefore: 60}
+	for _, attempt := range []struct{ endpoint, err string }{
+		{"https://a.example.com", ""},
+		{"https://b.example.com", "timeout"},
+		{"https://b.example.com", ""},
+		{"https://a.example.com", ""},
+	} {
+		if err := s.RecordNotificationAttempt(ctx, reminder, attempt.endpoint, attempt.err); err != nil {
+			t.Fatalf("RecordNotificationAttempt failed: %v", err)
+		}
+	}
+	records, err := s.ListExpiredNotifications(ctx, 2000, "", 10)
+	if err != nil || len(records) != 1 || records[0].Attempts != 4 || records[0].LastError != "" ||
+		records[0].LastAttempt == 0 || records[0].Status != storage.NotificationScheduled {
+		t.Fatalf("unexpected notification records: %+v, err=%v", records, err)
+	}
+	// Адреса, на которые напоминание доставлено, учитываются по одному разу
+	delivered, err := s.DeliveredEndpoints(ctx, reminder)
+	if err != nil || !slices.Equal(delivered, []string{"https://a.example.com", "https://b.example.com"}) {
+		t.Fatalf("unexpected delivered endpoints: %v, err=%v", delivered, err)
+	}
+	reminder.NotifyBefore = 120
+	if delivered, err := s.DeliveredEndpoints(ctx, reminder); err != nil || len(delivered) != 0 {
+		t.Fatalf("expected no delivered endpoints, got %v, err=%v", delivered, err)
+	}
+}
+
+func TestSQLStorageNotificationClaim(t *testing.T) {
+	s := setupTestStorage(t)
+	ctx := context.Background()
+	reminder := storage.Reminder{EventID: uuid.NewString(), UserID: "user1", Title: "Standup", EventTime: 1000, NotifyBefore: 60}
+
+	// Напоминание захватывает один рассыльщик; пока захват действует, другой получает ErrNotificationClaimed
+	if claimed, err := s.ClaimNotification(ctx, reminder, time.Minute); err != nil || !claimed {
+		t.Fatalf("expected claim, got %v, err=%v", claimed, err)
+	}
+	if _, err := s.ClaimNotification(ctx, reminder, time.Minute); !errors.Is(err, storage.ErrNotificationClaimed) {
+		t.Fatalf("expected ErrNotificationClaimed, got %v", err)
+	}
+	// После неудачной отправки захват снимается
+	if err := s.ReleaseNotification(ctx, reminder); err != nil {
+		t.Fatalf("ReleaseNotification failed: %v", err)
+	}
+	if claimed, err := s.ClaimNotification(ctx, reminder, time.Minute); err != nil || !claimed {
+		t.Fatalf("expected claim after release, got %v, err=%v", claimed, err)
+	}
+	// Истёкший захват можно перехватить
+	if claimed, err := s.ClaimNotification(ctx, reminder, 0); err != nil || !claimed {
+		t.Fatalf("expected claim after timeout, got %v, err=%v", claimed, err)
+	}
+	if err := s.CompleteNotification(ctx, reminder); err != nil {
+		t.Fatalf("CompleteNotification failed: %v", err)
+	}
+	// Отправленное напоминание не захватывается снова
+	if claimed, err := s.ClaimNotification(ctx, reminder, 0); err != nil || claimed {
+		t.Fatalf("expected processed notification not to be claimed, got %v, err=%v", claimed, err)
+	}
+	records, err := s.ListExpiredNotifications(ctx, 2000, "", 10)
+	if err != nil || len(records) != 1 || records[0].Status != storage.NotificationProcessed || records[0].ProcessedAt == 0 {
+		t.Fatalf("unexpected notification records: %+v, err=%v", records, err)
 	}
 }
@@ -0,0 +1,163 @@
+package sqlstorage
+
+import (
+	"context"
+
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/storage"
+)
+
+// TrashEvent перемещает событие и его изменённые экземпляры в корзину со временем удаления deletedAt,
+// если версия события равна version (0 — без проверки), увеличивает их версии и записывает удаление
+// в ленту изменений. Возвращает storage.ErrNotFound, если события нет или оно уже в корзине,
+// и storage.ErrVersionConflict, если версия другая.
+func (s *Storage) TrashEvent(ctx context.Context, id string, version, deletedAt int64) error {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	res, err := tx.ExecContext(ctx, `
+		UPDATE events SET deleted_at = $3, version = version + 1
+		WHERE id = $1 AND deleted_at IS NULL AND ($2::bigint = 0 OR version = $2)
+	`, id, version, deletedAt)
+	if isInvalidText(err) {
+		return storage.NotFoundError(id)
+	}
+	if err != nil {
+		return err
+	}
+	if cnt, _ := res.RowsAffected(); cnt == 0 {
+		return versionError(ctx, tx, id)
+	}
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE events SET deleted_at = $2, version = version + 1
+		WHERE recurring_event_id = $1 AND deleted_at IS NULL
+	`, id, deletedAt); err != nil {
+		return err
+	}
+	if err := recordSeriesChange(ctx, tx, storage.ChangeDeleted, id); err != nil {
+		return err
+	}
+	if err := recordAudit(ctx, tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetTrashedEvent возвращает событие из корзины по ID.
+// Возвращает storage.ErrNotFound, если события нет в корзине.
+func (s *Storage) GetTrashedEvent(ctx context.Context, id string) (storage.Event, error) {
+	return s.getEvent(ctx, `SELECT `+eventColumns+` FROM events WHERE id=$1 AND deleted_at IS NOT NULL`, id)
+}
+
+// ListTrash возвращает события пользователя в корзине без изменённых экземпляров серий,
+// от удалённых последними к удалённым первыми. Запрос использует индекс idx_events_trash.
+func (s *Storage) ListTrash(ctx context.Context, userID string) ([]storage.Event, error) {
+	return s.queryEvents(ctx, `
+		SELECT `+eventColumns+`
+		FROM events
+		WHERE user_id = $1 AND deleted_at IS NOT NULL AND recurring_event_id IS NULL
+		ORDER BY deleted_at DESC, id
+	`, userID)
+}
+
+// RestoreEvent возвращает событие и его изменённые экземпляры из корзины, увеличивает их версии
+// и записывает их создание в ленту изменений. Возвращает storage.ErrNotFound, если события нет в корзине,
+// и *storage.ConflictError, если за время нахождения в корзине его время заняли другие события.
+func (s *Storage) RestoreEvent(ctx context.Context, id string) error {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	res, err := tx.ExecContext(ctx, `
+		UPDATE events SET deleted_at = NULL, version = version + 1
+		WHERE id = $1 AND deleted_at IS NOT NULL
+	`, id)
+	if isExclusionViolation(err) {
+		return s.restoreConflictError(ctx, id)
+	}
+	if isInvalidText(err) {
+		return storage.NotFoundError(id)
+	}
+	if err != nil {
+		return err
+	}
+	if cnt, _ := res.RowsAffected(); cnt == 0 {
+		return storage.NotFoundError(id)
+	}
+	_, err = tx.ExecContext(ctx, `
+		UPDATE events SET deleted_at = NULL, version = version + 1
+		WHERE recurring_event_id = $1 AND deleted_at IS NOT NULL
+	`, id)
+	if isExclusionViolation(err) {
+		return s.restoreConflictError(ctx, id)
+	}
+	if err != nil {
+		return err
+	}
+	if err := recordSeriesChange(ctx, tx, storage.ChangeCreated, id); err != nil {
+		return err
+	}
+	if err := recordAudit(ctx, tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// restoreConflictError возвращает storage.ConflictError со списком событий, пересекающихся с событием id
+// в корзине или его изменёнными экземплярами. Условие совпадает с ограничением events_no_overlap.
+func (s *Storage) restoreConflictError(ctx context.Context, id string) error {
+	var ids []string
+	err := s.db.SelectContext(ctx, &ids, `
+		SELECT e.id FROM events e
+		JOIN events t ON t.user_id = e.user_id
+		  AND int8range(t.start_time, t.end_time) && int8range(e.start_time, e.end_time)
+		WHERE (t.id = $1 OR t.recurring_event_id = $1) AND t.deleted_at IS NOT NULL AND t.rrule = ''
+		  AND e.deleted_at IS NULL AND e.rrule = ''
+		GROUP BY e.id, e.start_time
+		ORDER BY e.start_time, e.id
+	`, id)
+	if err != nil {
+		return err
+	}
+	return &storage.ConflictError{EventIDs: ids}
+}
+
+// PurgeEvent удаляет событие из корзины навсегда вместе с его изменёнными экземплярами.
+// Возвращает storage.ErrNotFound, если события нет в корзине.
+func (s *Storage) PurgeEvent(ctx context.Context, id string) error {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	res, err := tx.ExecContext(ctx, `DELETE FROM events WHERE id = $1 AND deleted_at IS NOT NULL`, id)
+	if isInvalidText(err) {
+		return storage.NotFoundError(id)
+	}
+	if err != nil {
+		return err
+	}
+	if cnt, _ := res.RowsAffected(); cnt == 0 {
+		return storage.NotFoundError(id)
+	}
+	if err := recordAudit(ctx, tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// PurgeTrash удаляет навсегда события, перемещённые в корзину раньше beforeTime, и возвращает их число
+// (изменённые экземпляры удаляются вместе с серией и не учитываются). Запрос использует индекс idx_events_deleted_at.
+func (s *Storage) PurgeTrash(ctx context.Context, beforeTime int64) (int, error) {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM events WHERE deleted_at < $1 AND recurring_event_id IS NULL`, beforeTime)
+	if err != nil {
+		return 0, err
+	}
+	cnt, err := res.RowsAffected()
+	return int(cnt), err
+}
@@ -0,0 +1,74 @@
+package sqlstorage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/IvanAndreevichPle/hw12_13_14_15_16_calendar/internal/storage"
+	"github.com/lib/pq"
+)
+
+// webhookColumns — список колонок таблицы webhooks в порядке полей storage.Webhook.
+const webhookColumns = `id, user_id, url, secret, created_at`
+
+// CreateWebhook регистрирует HTTP-адрес пользователя для напоминаний.
+// Повторная регистрация того же адреса возвращает storage.ErrWebhookExists.
+func (s *Storage) CreateWebhook(ctx context.Context, webhook storage.Webhook) error {
+	_, err := s.db.ExecContext(ctx, `INSERT INTO webhooks (`+webhookColumns+`) VALUES ($1, $2, $3, $4, $5)`,
+		webhook.ID, webhook.UserID, webhook.URL, webhook.Secret, webhook.CreatedAt)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return storage.ErrWebhookExists
+	}
+	return domainError(err)
+}
+
+// GetWebhook возвращает зарегистрированный адрес по ID.
+func (s *Storage) GetWebhook(ctx context.Context, id string) (storage.Webhook, error) {
+	var w storage.Webhook
+	err := s.db.QueryRowxContext(ctx, `SELECT `+webhookColumns+` FROM webhooks WHERE id = $1`, id).
+		Scan(&w.ID, &w.UserID, &w.URL, &w.Secret, &w.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) || isInvalidText(err) {
+		return storage.Webhook{}, storage.NotFoundError(id)
+	}
+	return w, err
+}
+
+// ListWebhooks возвращает адреса пользователя в порядке регистрации.
+func (s *Storage) ListWebhooks(ctx context.Context, userID string) ([]storage.Webhook, error) {
+	rows, err := s.db.QueryxContext(ctx, `
+		SELECT `+webhookColumns+`
+		FROM webhooks
+		WHERE user_id = $1
+		ORDER BY created_at, id
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+	var result []storage.Webhook
+	for rows.Next() {
+		var w storage.Webhook
+		if err := rows.Scan(&w.ID, &w.UserID, &w.URL, &w.Secret, &w.CreatedAt); err != nil {
+			return nil, err
+		}
+		result = append(result, w)
+	}
+	return result, rows.Err()
+}
+
+// DeleteWebhook удаляет зарегистрированный адрес.
+func (s *Storage) DeleteWebhook(ctx context.Context, id string) error {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM webhooks WHERE id = $1`, id)
+	if isInvalidText(err) {
+		return storage.NotFoundError(id)
+	}
+	if err != nil {
+		return err
+	}
+	if cnt, _ := res.RowsAffected(); cnt == 0 {
+		return storage.NotFoundError(id)
+	}
+	return nil
+}
@@ -0,0 +1,13 @@
+package storage
+
+// Webhook — HTTP-адрес, зарегистрированный пользователем для получения напоминаний.
+type Webhook struct {
+	ID        string // ID адреса
+	UserID    string // ID пользователя
+	URL       string // адрес, на который рассыльщик отправляет напоминания POST-запросом
+	Secret    string // ключ подписи запросов HMAC-SHA256
+	CreatedAt int64  // время регистрации (Unix timestamp)
+}
+
+// ErrWebhookExists — пользователь уже зарегистрировал этот адрес.
+var ErrWebhookExists = NewKindError(ErrValidation, "webhook is already registered")
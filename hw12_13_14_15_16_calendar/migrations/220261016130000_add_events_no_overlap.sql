@@ -0,0 +1,37 @@
+-- +goose Up
+-- Запрет пересечения событий пользователя по времени: полуинтервалы [start_time, end_time)
+-- разовых событий и изменённых экземпляров серий не должны пересекаться.
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+-- Ограничение нельзя добавить, если пересекающиеся события уже есть: миграция останавливается
+-- со списком таких пар, чтобы их перенесли или удалили вручную, а не потеряли молча.
+-- +goose StatementBegin
+DO $$
+DECLARE
+    conflicts TEXT;
+BEGIN
+    SELECT string_agg(format('%s: %s & %s', user_id, id, other_id), '; ')
+    INTO conflicts
+    FROM (
+        SELECT a.user_id, a.id, b.id AS other_id
+        FROM events a
+        JOIN events b ON b.user_id = a.user_id AND b.id > a.id
+        WHERE a.rrule = '' AND b.rrule = ''
+          AND int8range(a.start_time, a.end_time) && int8range(b.start_time, b.end_time)
+        ORDER BY a.user_id, a.id, b.id
+        LIMIT 20
+    ) pairs;
+    IF conflicts IS NOT NULL THEN
+        RAISE EXCEPTION 'overlapping events must be resolved before adding events_no_overlap: %', conflicts;
+    END IF;
+END $$;
+-- +goose StatementEnd
+
+ALTER TABLE events
+    ADD CONSTRAINT events_no_overlap EXCLUDE USING gist (
+        user_id WITH =,
+        int8range(start_time, end_time) WITH &&
+    ) WHERE (rrule = '');
+
+-- +goose Down
+ALTER TABLE events DROP CONSTRAINT IF EXISTS events_no_overlap;
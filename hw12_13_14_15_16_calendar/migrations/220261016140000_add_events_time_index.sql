@@ -0,0 +1,6 @@
+-- +goose Up
+-- Индекс для выборки событий пользователя по диапазону времени (ListEventsInRange)
+CREATE INDEX IF NOT EXISTS idx_events_user_start_end ON events(user_id, start_time, end_time);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_events_user_start_end;
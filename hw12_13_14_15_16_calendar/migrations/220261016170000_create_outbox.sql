@@ -0,0 +1,20 @@
+-- +goose Up
+-- Transactional outbox: напоминания, записанные вместе с отметкой в notifications и ожидающие публикации в очередь
+CREATE TABLE IF NOT EXISTS outbox (
+    id UUID PRIMARY KEY,
+    seq BIGSERIAL NOT NULL, -- порядок записи сообщений
+    event_id UUID NOT NULL,
+    user_id TEXT NOT NULL,
+    title TEXT NOT NULL,
+    event_time BIGINT NOT NULL,
+    notify_before BIGINT NOT NULL,
+    created_at BIGINT NOT NULL,
+    sent_at BIGINT,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT
+);
+CREATE INDEX IF NOT EXISTS idx_outbox_pending ON outbox(seq) WHERE sent_at IS NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_outbox_pending;
+DROP TABLE IF EXISTS outbox;
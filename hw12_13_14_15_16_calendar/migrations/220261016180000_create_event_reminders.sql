@@ -0,0 +1,37 @@
+-- +goose Up
+-- Напоминания события: смещение до начала (в секундах) и канал доставки; у события может быть несколько напоминаний
+CREATE TABLE IF NOT EXISTS event_reminders (
+    event_id UUID NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    offset_seconds BIGINT NOT NULL CHECK (offset_seconds > 0),
+    channel TEXT NOT NULL DEFAULT '', -- пусто — канал по умолчанию
+    PRIMARY KEY (event_id, offset_seconds, channel)
+);
+
+INSERT INTO event_reminders (event_id, offset_seconds)
+SELECT id, notify_before FROM events WHERE notify_before > 0
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE events DROP COLUMN IF EXISTS notify_before;
+
+-- Напоминания с одинаковым смещением, но разными каналами — разные напоминания
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS channel TEXT NOT NULL DEFAULT '';
+ALTER TABLE outbox ADD COLUMN IF NOT EXISTS channel TEXT NOT NULL DEFAULT '';
+DROP INDEX IF EXISTS idx_notifications_reminder;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_notifications_reminder ON notifications(event_id, event_time, notify_before, channel);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_notifications_reminder;
+DELETE FROM notifications a
+USING notifications b
+WHERE a.event_id = b.event_id AND a.event_time = b.event_time AND a.notify_before = b.notify_before
+  AND a.ctid > b.ctid;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_notifications_reminder ON notifications(event_id, event_time, notify_before);
+ALTER TABLE outbox DROP COLUMN IF EXISTS channel;
+ALTER TABLE notifications DROP COLUMN IF EXISTS channel;
+
+ALTER TABLE events ADD COLUMN IF NOT EXISTS notify_before INTEGER;
+UPDATE events e SET notify_before = r.offset_seconds
+FROM (SELECT event_id, min(offset_seconds) AS offset_seconds FROM event_reminders GROUP BY event_id) r
+WHERE e.id = r.event_id;
+
+DROP TABLE IF EXISTS event_reminders;
@@ -0,0 +1,16 @@
+-- +goose Up
+-- Лента изменений событий для подписки WatchEvents; user_ids — владелец и участники до и после изменения
+CREATE TABLE IF NOT EXISTS event_changes (
+    revision BIGSERIAL PRIMARY KEY,
+    event_id UUID NOT NULL,
+    change_type TEXT NOT NULL CHECK (change_type IN ('created', 'updated', 'deleted')),
+    user_ids TEXT[] NOT NULL,
+    changed_at BIGINT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_event_changes_users ON event_changes USING GIN (user_ids);
+CREATE INDEX IF NOT EXISTS idx_event_changes_changed_at ON event_changes(changed_at);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_event_changes_changed_at;
+DROP INDEX IF EXISTS idx_event_changes_users;
+DROP TABLE IF EXISTS event_changes;
@@ -0,0 +1,7 @@
+-- +goose Up
+-- Версия события для оптимистичной блокировки: увеличивается при каждой записи события,
+-- UpdateEvent и DeleteEvent с ожидаемой версией не затирают чужие изменения
+ALTER TABLE events ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+
+-- +goose Down
+ALTER TABLE events DROP COLUMN IF EXISTS version;
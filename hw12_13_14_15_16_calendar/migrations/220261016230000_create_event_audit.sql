@@ -0,0 +1,20 @@
+-- +goose Up
+-- Журнал аудита изменений событий: кто (actor_id, service), когда и как изменил событие.
+-- Записи не ссылаются на events, чтобы история оставалась после удаления события навсегда.
+CREATE TABLE IF NOT EXISTS event_audit (
+    seq BIGSERIAL PRIMARY KEY,
+    event_id UUID NOT NULL,
+    owner_id TEXT NOT NULL,
+    actor_id TEXT NOT NULL,
+    service TEXT NOT NULL DEFAULT '',
+    action TEXT NOT NULL CHECK (action IN ('created', 'updated', 'deleted', 'restored', 'purged')),
+    changes JSONB NOT NULL DEFAULT '[]',
+    created_at BIGINT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_event_audit_event ON event_audit(event_id, seq);
+CREATE INDEX IF NOT EXISTS idx_event_audit_actor ON event_audit(actor_id, seq);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_event_audit_actor;
+DROP INDEX IF EXISTS idx_event_audit_event;
+DROP TABLE IF EXISTS event_audit;
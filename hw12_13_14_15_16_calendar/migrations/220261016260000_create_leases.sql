@@ -0,0 +1,11 @@
+-- +goose Up
+-- Аренды лидерства: реплика-лидер продлевает свою аренду, остальные захватывают её после истечения.
+-- Сроки сравниваются по часам базы данных, поэтому расхождение часов реплик не влияет на выбор лидера.
+CREATE TABLE IF NOT EXISTS leases (
+    name TEXT PRIMARY KEY,
+    holder TEXT NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS leases;
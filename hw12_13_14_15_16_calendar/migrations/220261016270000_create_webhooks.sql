@@ -0,0 +1,21 @@
+-- +goose Up
+-- HTTP-адреса, зарегистрированные пользователями для получения напоминаний
+CREATE TABLE IF NOT EXISTS webhooks (
+    id UUID PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    url TEXT NOT NULL,
+    secret TEXT NOT NULL,
+    created_at BIGINT NOT NULL,
+    UNIQUE (user_id, url)
+);
+
+-- Попытки доставки напоминаний рассыльщиком
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS attempts INT NOT NULL DEFAULT 0;
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS last_error TEXT NOT NULL DEFAULT '';
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS last_attempt_at BIGINT;
+
+-- +goose Down
+ALTER TABLE notifications DROP COLUMN IF EXISTS last_attempt_at;
+ALTER TABLE notifications DROP COLUMN IF EXISTS last_error;
+ALTER TABLE notifications DROP COLUMN IF EXISTS attempts;
+DROP TABLE IF EXISTS webhooks;
@@ -0,0 +1,6 @@
+-- +goose Up
+-- Адреса, на которые напоминание уже доставлено: при повторной обработке сообщения они пропускаются
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS delivered_to TEXT[] NOT NULL DEFAULT '{}';
+
+-- +goose Down
+ALTER TABLE notifications DROP COLUMN IF EXISTS delivered_to;